@@ -32,6 +32,10 @@ import (
 )
 
 const (
+	// OptionAdopt is adopt option. Used for labelling imported cluster objects as managed by ksonnet.
+	OptionAdopt = "adopt"
+	// OptionAPIVersion is apiVersion option. Used for selecting the objects patched by environments.
+	OptionAPIVersion = "api-version"
 	// OptionApp is app option.
 	OptionApp = "app"
 	// OptionAppRoot is the root directory of the application.
@@ -40,21 +44,31 @@ const (
 	OptionArguments = "arguments"
 	// OptionAsString is asString. Used for setting values as strings.
 	OptionAsString = "as-string"
+	// OptionBundle is bundle option. Used for adding a registry from a bundle.
+	OptionBundle = "bundle"
+	// OptionCascade is cascade option. Used for removing the packages which depend on a package.
+	OptionCascade = "cascade"
 	// OptionClientConfig is clientConfig option.
 	OptionClientConfig = "client-config"
 	// OptionComponentName is a componentName option.
 	OptionComponentName = "component-name"
 	// OptionComponentNames is componentNames option.
 	OptionComponentNames = "component-names"
+	// OptionConfirmEnv is confirmEnv option. Used for confirming changes to protected environments.
+	OptionConfirmEnv = "confirm-env"
+	// OptionContext is a kubeconfig context option.
+	OptionContext = "context"
 	// OptionCreate is create option.
 	OptionCreate = "create"
+	// OptionDetails is details option. Used for printing the details of search results.
+	OptionDetails = "details"
 	// OptionDryRun is dryRun option.
 	OptionDryRun = "dry-run"
 	// OptionEnvName is envName option.
 	OptionEnvName = "env-name"
-	// OptionEnvName1 is envName1. Used for param diff.
+	// OptionEnvName1 is envName1. Used for param diff and promote.
 	OptionEnvName1 = "env-name-1"
-	// OptionEnvName2 is envName1. Used for param diff.
+	// OptionEnvName2 is envName1. Used for param diff and promote.
 	OptionEnvName2 = "env-name-2"
 	// OptionExtVarFiles is jsonnet ext var files.
 	OptionExtVarFiles = "ext-vars-files"
@@ -64,6 +78,10 @@ const (
 	OptionForce = "force"
 	// OptionFormat is format option.
 	OptionFormat = "format"
+	// OptionFrozen is frozen option. Used for checking vendored packages against ks.lock.
+	OptionFrozen = "frozen"
+	// OptionFromCluster is fromCluster option. Used for importing the objects of an environment.
+	OptionFromCluster = "from-cluster"
 	// OptionFs is fs option.
 	OptionFs = "fs"
 	// OptionGcTag is gcTag option.
@@ -72,37 +90,73 @@ const (
 	OptionGlobal = "global"
 	// OptionGracePeriod is gracePeriod option.
 	OptionGracePeriod = "grace-period"
+	// OptionGroupBy is groupBy option. Used for grouping imported objects into components.
+	OptionGroupBy = "group-by"
 	// OptionHTTPClient is the http.Client for outbound network requests.
 	OptionHTTPClient = "http-client"
+	// OptionInCluster is in-cluster option. Used for environments deployed from within their cluster.
+	OptionInCluster = "in-cluster"
 	// OptionInstalled is for listing installed packages.
 	OptionInstalled = "only-installed"
 	// OptionJPaths is jsonnet paths.
 	OptionJPaths = "jpaths"
+	// OptionJUnit is jUnit option. Used for the path of a JUnit XML test report.
+	OptionJUnit = "junit"
+	// OptionKind is kind option. Used for selecting the objects patched by environments.
+	OptionKind = "kind"
+	// OptionKinds is kinds option. Used for limiting the kinds of imported cluster objects.
+	OptionKinds = "kinds"
 	// OptionPkgName is (an optionally qualified) name of a package.
 	OptionPkgName = "pkg-name"
+	// OptionPkgNames is a list of (optionally versioned) package names.
+	OptionPkgNames = "pkg-names"
+	// OptionKubeconfig is a kubeconfig path option.
+	OptionKubeconfig = "kubeconfig"
 	// OptionName is name option.
 	OptionName = "name"
+	// OptionNameTemplate is nameTemplate option. Used for naming imported components.
+	OptionNameTemplate = "name-template"
 	// OptionModule is component module option.
 	OptionModule = "module"
 	// OptionNamespace is a cluster namespace option
 	OptionNamespace = "namespace"
 	// OptionNewRoot is init new root path option.
 	OptionNewRoot = "root-path"
+	// OptionNewComponentName is newComponentName option. Used for moving components.
+	OptionNewComponentName = "new-component-name"
 	// OptionNewEnvName is newEnvName option. Used for renaming environments.
 	OptionNewEnvName = "new-env-name"
+	// OptionOffline is offline option. Used for validating without communicating with clusters.
+	OptionOffline = "offline"
+	// OptionOnCollision is onCollision option. Used for handling names of imported components which are taken.
+	OptionOnCollision = "on-collision"
 	// OptionOutput is output option.
 	OptionOutput = "output"
 	// OptionOverride is override option.
 	OptionOverride = "override"
 	// OptionPackageName is packageName option.
 	OptionPackageName = "package-name"
+	// OptionParallel is parallel option. Used for running against environment destinations in parallel.
+	OptionParallel = "parallel"
+	// OptionParamOnly is paramOnly option. Used for promoting only parameters.
+	OptionParamOnly = "param-only"
+	// OptionPatchType is patchType option. Used for scaffolding environment patches.
+	OptionPatchType = "patch-type"
 	// OptionPath is path option.
 	OptionPath = "path"
+	// OptionPrivateKey is privateKey option. Used for the path of a registry signing key.
+	OptionPrivateKey = "private-key"
+	// OptionPublicKeys is publicKeys option. Used for the trusted keys of a registry.
+	OptionPublicKeys = "public-keys"
 	// OptionQuery is query option.
 	OptionQuery = "query"
+	// OptionRefresh is refresh option. Used for rebuilding the package search index.
+	OptionRefresh = "refresh"
 	// OptionResolveImage is resolve image option. It is used to resolve docker image references
 	// when setting parameters.
 	OptionResolveImage = "resolve-image"
+	// OptionSelector is selector option. Used for selecting cluster objects by label.
+	OptionSelector = "selector"
 	// OptionServer is server option.
 	OptionServer = "server"
 	// OptionServerURI is serverURI option.
@@ -127,6 +181,8 @@ const (
 	OptionTLSSkipVerify = "tls-skip-verify"
 	// OptionUnset is unset option.
 	OptionUnset = "unset"
+	// OptionUpdate is update option. Used for updating the golden files of tests.
+	OptionUpdate = "update"
 	// OptionURI is uri option. Used for setting registry URI.
 	OptionURI = "URI"
 	// OptionWithoutModules is without modules option.
@@ -142,6 +198,8 @@ const (
 	OutputWide = "wide"
 	// OutputJSON is JSON output
 	OutputJSON = "json"
+	// OutputSARIF is SARIF output
+	OutputSARIF = "sarif"
 )
 
 var (
@@ -326,6 +384,21 @@ func (o *optionLoader) LoadStringSlice(name string) []string {
 	return a
 }
 
+func (o *optionLoader) LoadOptionalStringSlice(name string) []string {
+	i := o.loadOptional(name)
+	if i == nil {
+		return nil
+	}
+
+	a, ok := i.([]string)
+	if !ok {
+		o.err = newInvalidOptionError(name)
+		return nil
+	}
+
+	return a
+}
+
 func (o *optionLoader) LoadClientConfig() *client.Config {
 	i := o.load(OptionClientConfig)
 	if i == nil {
@@ -43,10 +43,14 @@ type Apply struct {
 	create         bool
 	dryRun         bool
 	envName        string
+	frozen         bool
 	gcTag          string
+	parallel       bool
 	skipGc         bool
 
-	runApplyFn runApplyFn
+	guard       *envGuard
+	lockCheckFn func(app.App) error
+	runApplyFn  runApplyFn
 }
 
 // RunApply runs `apply`
@@ -59,12 +63,17 @@ func newApply(m map[string]interface{}, opts ...applyOpt) (*Apply, error) {
 		componentNames: ol.LoadStringSlice(OptionComponentNames),
 		create:         ol.LoadBool(OptionCreate),
 		dryRun:         ol.LoadBool(OptionDryRun),
+		frozen:         ol.LoadOptionalBool(OptionFrozen),
 		gcTag:          ol.LoadString(OptionGcTag),
+		parallel:       ol.LoadOptionalBool(OptionParallel),
 		skipGc:         ol.LoadBool(OptionSkipGc),
 
-		runApplyFn: cluster.RunApply,
+		lockCheckFn: checkLock,
+		runApplyFn:  cluster.RunApply,
 	}
 
+	a.guard = newEnvGuard(a.app, ol.LoadOptionalString(OptionConfirmEnv))
+
 	if ol.err != nil {
 		return nil, ol.err
 	}
@@ -81,9 +90,25 @@ func newApply(m map[string]interface{}, opts ...applyOpt) (*Apply, error) {
 }
 
 func (a *Apply) run() error {
+	if a.frozen {
+		if err := a.lockCheckFn(a.app); err != nil {
+			return err
+		}
+	}
+
+	if !a.dryRun {
+		if err := a.guard.check(a.envName, "apply", true); err != nil {
+			return err
+		}
+	}
+
+	return runForDestinations(a.app, a.clientConfig, a.envName, a.parallel, a.runDestination)
+}
+
+func (a *Apply) runDestination(_ *app.EnvironmentDestinationSpec, ksApp app.App, clientConfig *client.Config) error {
 	config := cluster.ApplyConfig{
-		App:            a.app,
-		ClientConfig:   a.clientConfig,
+		App:            ksApp,
+		ClientConfig:   clientConfig,
 		ComponentNames: a.componentNames,
 		Create:         a.create,
 		DryRun:         a.dryRun,
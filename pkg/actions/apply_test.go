@@ -18,6 +18,7 @@ package actions
 import (
 	"testing"
 
+	"github.com/ksonnet/ksonnet/pkg/app"
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/client"
 	"github.com/ksonnet/ksonnet/pkg/cluster"
@@ -50,6 +51,7 @@ func TestApply(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			withApp(t, func(appMock *amocks.App) {
 				appMock.On("CurrentEnvironment").Return(tc.currentName)
+				appMock.On("Environment", "default").Return(&app.EnvironmentConfig{}, nil)
 
 				in := map[string]interface{}{
 					OptionApp:            appMock,
@@ -0,0 +1,168 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/pipeline"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// RunComponentConvert runs `component convert`
+func RunComponentConvert(m map[string]interface{}) error {
+	cc, err := NewComponentConvert(m)
+	if err != nil {
+		return err
+	}
+
+	return cc.Run()
+}
+
+// ComponentConvert converts a YAML or JSON component to Jsonnet.
+type ComponentConvert struct {
+	app    app.App
+	name   string
+	format string
+
+	convertFn func(app.App, string) (component.Component, error)
+	renderFn  func(a app.App, envName, componentName string) (string, error)
+}
+
+// NewComponentConvert creates an instance of ComponentConvert.
+func NewComponentConvert(m map[string]interface{}) (*ComponentConvert, error) {
+	ol := newOptionLoader(m)
+
+	cc := &ComponentConvert{
+		app:    ol.LoadApp(),
+		name:   ol.LoadString(OptionComponentName),
+		format: ol.LoadString(OptionFormat),
+
+		convertFn: component.ConvertToJsonnet,
+		renderFn:  renderComponent,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return cc, nil
+}
+
+// Run converts the component. The component is rendered in every environment
+// before and after the conversion. If the output of any environment changes,
+// the conversion is reverted.
+func (cc *ComponentConvert) Run() error {
+	if cc.format != component.TypeJsonnet {
+		return errors.Errorf("unable to convert components to %q; only %q is supported", cc.format, component.TypeJsonnet)
+	}
+
+	envNames, err := cc.envNames()
+	if err != nil {
+		return err
+	}
+
+	before := make(map[string]string)
+	for _, envName := range envNames {
+		if before[envName], err = cc.renderFn(cc.app, envName, cc.name); err != nil {
+			return errors.Wrapf(err, "rendering component %q in environment %q", cc.name, envName)
+		}
+	}
+
+	snapshot, err := cc.snapshot()
+	if err != nil {
+		return err
+	}
+
+	c, err := cc.convertFn(cc.app, cc.name)
+	if err != nil {
+		return err
+	}
+
+	for _, envName := range envNames {
+		after, err := cc.renderFn(cc.app, envName, c.Name(true))
+		if err == nil && after != before[envName] {
+			err = errors.New("rendered output changed")
+		}
+
+		if err != nil {
+			if rerr := snapshot.restore(); rerr != nil {
+				return errors.Wrapf(rerr, "reverting conversion of %q", cc.name)
+			}
+			return errors.Wrapf(err, "converted component %q does not match the original in environment %q; conversion reverted",
+				cc.name, envName)
+		}
+	}
+
+	log.Infof("Converted component %q to %s", cc.name, cc.format)
+	return nil
+}
+
+func (cc *ComponentConvert) envNames() ([]string, error) {
+	envs, err := cc.app.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// snapshot snapshots the files changed by a conversion.
+func (cc *ComponentConvert) snapshot() (*fileSnapshot, error) {
+	source, err := component.Path(cc.app, cc.name)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(source)
+	base := strings.TrimSuffix(filepath.Base(source), filepath.Ext(source))
+
+	s := newFileSnapshot(cc.app.Fs())
+	for _, path := range []string{source, filepath.Join(dir, "params.libsonnet"), filepath.Join(dir, base+"."+component.TypeJsonnet)} {
+		if err := s.addFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// renderComponent renders a component in an environment as YAML.
+func renderComponent(a app.App, envName, componentName string) (string, error) {
+	r, err := pipeline.New(a, envName).YAML([]string{componentName})
+	if err != nil {
+		return "", err
+	}
+
+	data, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
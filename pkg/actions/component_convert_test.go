@@ -0,0 +1,130 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentConvert(t *testing.T) {
+	cases := []struct {
+		name     string
+		format   string
+		after    string
+		isErr    bool
+		reverted bool
+	}{
+		{
+			name:   "convert to jsonnet",
+			format: "jsonnet",
+			after:  "rendered",
+		},
+		{
+			name:     "rendered output changed",
+			format:   "jsonnet",
+			after:    "changed",
+			isErr:    true,
+			reverted: true,
+		},
+		{
+			name:   "unsupported format",
+			format: "yaml",
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				fs := appMock.Fs()
+				require.NoError(t, afero.WriteFile(fs, "/components/params.libsonnet", []byte("{}"), 0644))
+				require.NoError(t, afero.WriteFile(fs, "/components/deployment.yaml", []byte("kind: Deployment"), 0644))
+
+				envs := app.EnvironmentConfigs{
+					"default": &app.EnvironmentConfig{Name: "default"},
+					"prod":    &app.EnvironmentConfig{Name: "prod"},
+				}
+				appMock.On("Environments").Return(envs, nil)
+
+				in := map[string]interface{}{
+					OptionApp:           appMock,
+					OptionComponentName: "deployment",
+					OptionFormat:        tc.format,
+				}
+
+				a, err := NewComponentConvert(in)
+				require.NoError(t, err)
+
+				var converted bool
+				a.convertFn = func(ksApp app.App, name string) (component.Component, error) {
+					assert.Equal(t, "deployment", name)
+					converted = true
+
+					require.NoError(t, afero.WriteFile(fs, "/components/deployment.jsonnet", []byte("{}"), 0644))
+					require.NoError(t, afero.WriteFile(fs, "/components/params.libsonnet", []byte("{components: {}}"), 0644))
+					require.NoError(t, fs.Remove("/components/deployment.yaml"))
+
+					return component.NewJsonnet(ksApp, "", "/components/deployment.jsonnet", "/components/params.libsonnet"), nil
+				}
+
+				var rendered []string
+				a.renderFn = func(_ app.App, envName, componentName string) (string, error) {
+					assert.Equal(t, "deployment", componentName)
+					rendered = append(rendered, envName)
+					if converted {
+						return tc.after, nil
+					}
+					return "rendered", nil
+				}
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+					assert.Equal(t, []string{"default", "prod", "default", "prod"}, rendered)
+				}
+
+				if tc.reverted {
+					exists, err := afero.Exists(fs, "/components/deployment.jsonnet")
+					require.NoError(t, err)
+					assert.False(t, exists)
+
+					data, err := afero.ReadFile(fs, "/components/deployment.yaml")
+					require.NoError(t, err)
+					assert.Equal(t, "kind: Deployment", string(data))
+
+					data, err = afero.ReadFile(fs, "/components/params.libsonnet")
+					require.NoError(t, err)
+					assert.Equal(t, "{}", string(data))
+				}
+			})
+		})
+	}
+}
+
+func TestComponentConvert_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewComponentConvert(in)
+	require.Error(t, err)
+}
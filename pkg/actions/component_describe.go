@@ -0,0 +1,479 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/diff"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	componentStatusInSync      = "in sync"
+	componentStatusNotDeployed = "not deployed"
+	componentStatusOutOfSync   = "out of sync"
+	componentStatusUnknown     = "unknown"
+)
+
+var (
+	// reImport matches the paths of Jsonnet imports.
+	reImport = regexp.MustCompile(`\bimport(?:str)?\s*["']([^"']+)["']`)
+)
+
+// RunComponentDescribe runs `component describe`
+func RunComponentDescribe(m map[string]interface{}) error {
+	cd, err := NewComponentDescribe(m)
+	if err != nil {
+		return err
+	}
+
+	return cd.Run()
+}
+
+// ComponentDescribe describes a component.
+type ComponentDescribe struct {
+	app          app.App
+	clientConfig *client.Config
+	name         string
+	envName      string
+	output       string
+
+	resolveFn func(a app.App, path string) (component.Module, component.Component, error)
+	objectsFn func(a app.App, envName, componentName string) ([]*unstructured.Unstructured, error)
+	statusFn  func(a app.App, clientConfig *client.Config, envName, componentName string) (string, error)
+	out       io.Writer
+}
+
+// NewComponentDescribe creates an instance of ComponentDescribe.
+func NewComponentDescribe(m map[string]interface{}) (*ComponentDescribe, error) {
+	ol := newOptionLoader(m)
+
+	cd := &ComponentDescribe{
+		app:          ol.LoadApp(),
+		clientConfig: ol.LoadClientConfig(),
+		name:         ol.LoadString(OptionComponentName),
+		envName:      ol.LoadOptionalString(OptionEnvName),
+		output:       ol.LoadOptionalString(OptionOutput),
+
+		resolveFn: component.ResolvePath,
+		objectsFn: renderComponentObjects,
+		statusFn:  componentStatus,
+		out:       os.Stdout,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return cd, nil
+}
+
+// componentDescription is the description of a component.
+type componentDescription struct {
+	Name         string                       `json:"name"`
+	Source       string                       `json:"source"`
+	Type         string                       `json:"type"`
+	Module       string                       `json:"module"`
+	Environments []string                     `json:"environments"`
+	Params       map[string]map[string]string `json:"params"`
+	Packages     []string                     `json:"packages"`
+	EnvName      string                       `json:"env,omitempty"`
+	Objects      []componentObject            `json:"objects,omitempty"`
+	Status       string                       `json:"status,omitempty"`
+}
+
+// componentObject identifies an object rendered by a component.
+type componentObject struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace,omitempty"`
+}
+
+// Run describes the component. Its objects and whether it is deployed are
+// described for the selected environment, or the current one if it targets the
+// component.
+func (cd *ComponentDescribe) Run() error {
+	f, err := table.DetectFormat(cd.output)
+	if err != nil {
+		return errors.Wrap(err, "detecting output format")
+	}
+
+	m, c, err := cd.resolveFn(cd.app, cd.name)
+	if err != nil {
+		return err
+	}
+
+	if c == nil {
+		return errors.Errorf("%q is a module, not a component", cd.name)
+	}
+
+	source, err := componentSource(cd.app, m, c)
+	if err != nil {
+		return err
+	}
+
+	d := &componentDescription{
+		Name:   c.Name(true),
+		Source: source,
+		Type:   c.Type(),
+		Module: m.Name(),
+		Params: make(map[string]map[string]string),
+	}
+
+	if rel, err := filepath.Rel(cd.app.Root(), source); err == nil {
+		d.Source = rel
+	}
+
+	if d.Environments, err = targetingEnvironments(cd.app, m.Name()); err != nil {
+		return err
+	}
+
+	for _, envName := range d.Environments {
+		params, err := c.Params(envName)
+		if err != nil {
+			return errors.Wrapf(err, "reading params for environment %q", envName)
+		}
+
+		d.Params[envName] = make(map[string]string)
+		for _, p := range params {
+			d.Params[envName][p.Key] = p.Value
+		}
+	}
+
+	if d.EnvName, err = cd.selectEnv(d.Environments); err != nil {
+		return err
+	}
+
+	if d.Packages, err = importedPackages(cd.app, d.EnvName, source); err != nil {
+		return err
+	}
+
+	if d.EnvName != "" {
+		objects, err := cd.objectsFn(cd.app, d.EnvName, d.Name)
+		if err != nil {
+			return err
+		}
+
+		for _, obj := range objects {
+			d.Objects = append(d.Objects, componentObject{
+				APIVersion: obj.GetAPIVersion(),
+				Kind:       obj.GetKind(),
+				Name:       obj.GetName(),
+				Namespace:  obj.GetNamespace(),
+			})
+		}
+
+		d.Status, err = cd.statusFn(cd.app, cd.clientConfig, d.EnvName, d.Name)
+		if err != nil {
+			log.WithError(err).Warnf("Unable to check whether %s is deployed in %s", d.Name, d.EnvName)
+			d.Status = componentStatusUnknown
+		}
+	}
+
+	if f == table.FormatJSON {
+		enc := json.NewEncoder(cd.out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(d)
+	}
+
+	return cd.print(d)
+}
+
+// selectEnv selects the environment the objects of the component are
+// described for. An environment which was asked for has to target the
+// component.
+func (cd *ComponentDescribe) selectEnv(envNames []string) (string, error) {
+	envName := cd.envName
+	if envName == "" {
+		envName = cd.app.CurrentEnvironment()
+	}
+
+	for _, name := range envNames {
+		if name == envName {
+			return envName, nil
+		}
+	}
+
+	if cd.envName != "" {
+		return "", errors.Errorf("environment %q does not target component %q", cd.envName, cd.name)
+	}
+
+	return "", nil
+}
+
+func (cd *ComponentDescribe) print(d *componentDescription) error {
+	fmt.Fprintln(cd.out, "COMPONENT:")
+	fmt.Fprintln(cd.out, d.Name)
+	fmt.Fprintln(cd.out)
+	fmt.Fprintln(cd.out, "SOURCE:")
+	fmt.Fprintln(cd.out, d.Source)
+	fmt.Fprintln(cd.out)
+	fmt.Fprintln(cd.out, "TYPE:")
+	fmt.Fprintln(cd.out, d.Type)
+	fmt.Fprintln(cd.out)
+	fmt.Fprintln(cd.out, "MODULE:")
+	fmt.Fprintln(cd.out, d.Module)
+	fmt.Fprintln(cd.out)
+	fmt.Fprintln(cd.out, "ENVIRONMENTS:")
+	printList(cd.out, d.Environments)
+	fmt.Fprintln(cd.out)
+
+	fmt.Fprintln(cd.out, "PARAMETERS:")
+	if err := cd.printParams(d); err != nil {
+		return err
+	}
+	fmt.Fprintln(cd.out)
+
+	fmt.Fprintln(cd.out, "PACKAGES:")
+	printList(cd.out, d.Packages)
+
+	if d.EnvName == "" {
+		return nil
+	}
+
+	fmt.Fprintln(cd.out)
+	fmt.Fprintf(cd.out, "OBJECTS (%s):\n", d.EnvName)
+	if len(d.Objects) == 0 {
+		fmt.Fprintln(cd.out, "  none")
+	} else {
+		var rows [][]string
+		for _, obj := range d.Objects {
+			rows = append(rows, []string{obj.APIVersion, obj.Kind, obj.Name, obj.Namespace})
+		}
+
+		t := table.New("componentObjects", cd.out)
+		t.SetHeader([]string{"apiversion", "kind", "name", "namespace"})
+		t.AppendBulk(rows)
+		if err := t.Render(); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintln(cd.out)
+
+	fmt.Fprintf(cd.out, "STATUS (%s):\n", d.EnvName)
+	fmt.Fprintln(cd.out, d.Status)
+
+	return nil
+}
+
+// printParams prints the parameters of the component with a column for each
+// environment.
+func (cd *ComponentDescribe) printParams(d *componentDescription) error {
+	keys := make(map[string]bool)
+	for _, params := range d.Params {
+		for key := range params {
+			keys[key] = true
+		}
+	}
+
+	if len(keys) == 0 {
+		fmt.Fprintln(cd.out, "  none")
+		return nil
+	}
+
+	var rows [][]string
+	for key := range keys {
+		row := []string{key}
+		for _, envName := range d.Environments {
+			row = append(row, d.Params[envName][key])
+		}
+		rows = append(rows, row)
+	}
+
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i][0] < rows[j][0]
+	})
+
+	t := table.New("componentParams", cd.out)
+	t.SetHeader(append([]string{"param"}, d.Environments...))
+	t.AppendBulk(rows)
+	return t.Render()
+}
+
+func printList(w io.Writer, items []string) {
+	if len(items) == 0 {
+		fmt.Fprintln(w, "  none")
+		return
+	}
+
+	for _, item := range items {
+		fmt.Fprintf(w, "  %s\n", item)
+	}
+}
+
+// componentSource returns the path of the file a component is defined in.
+func componentSource(a app.App, m component.Module, c component.Component) (string, error) {
+	name := c.Name(false)
+	if m.Name() != "/" {
+		name = filepath.Join(strings.Replace(m.Name(), ".", string(filepath.Separator), -1), name)
+	}
+
+	return component.Path(a, name)
+}
+
+// targetingEnvironments returns the names of the environments which target a
+// module. Environments without targets target the root module.
+func targetingEnvironments(a app.App, moduleName string) ([]string, error) {
+	envs, err := a.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for envName := range envs {
+		env, err := a.Environment(envName)
+		if err != nil {
+			return nil, err
+		}
+
+		targets := env.Targets
+		if len(targets) == 0 {
+			targets = []string{"/"}
+		}
+
+		for _, target := range targets {
+			if target == "" {
+				target = "/"
+			}
+
+			if target == moduleName {
+				names = append(names, envName)
+				break
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// importedPackages returns the packages imported by a component source. The
+// packages installed in the environment, if one is given, take precedence over
+// the ones installed globally.
+func importedPackages(a app.App, envName, source string) ([]string, error) {
+	if filepath.Ext(source) != "."+component.TypeJsonnet {
+		return nil, nil
+	}
+
+	data, err := afero.ReadFile(a.Fs(), source)
+	if err != nil {
+		return nil, err
+	}
+
+	globalLibs, err := a.Libraries()
+	if err != nil {
+		return nil, err
+	}
+
+	libs := app.LibraryConfigs{}
+	for name, lib := range globalLibs {
+		libs[name] = lib
+	}
+
+	if envName != "" {
+		e, err := a.Environment(envName)
+		if err != nil {
+			return nil, err
+		}
+		for name, lib := range e.Libraries {
+			libs[name] = lib
+		}
+	}
+
+	found := make(map[string]bool)
+	for _, match := range reImport.FindAllStringSubmatch(string(data), -1) {
+		for _, lib := range libs {
+			prefix := lib.Registry + "/" + lib.Name + "/"
+			if !strings.HasPrefix(match[1], prefix) {
+				continue
+			}
+
+			name := lib.Registry + "/" + lib.Name
+			if lib.Version != "" {
+				name += "@" + lib.Version
+			}
+			found[name] = true
+		}
+	}
+
+	var names []string
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// componentStatus reports whether the objects of a component are deployed to
+// the cluster of an environment, and whether they match the local objects.
+func componentStatus(a app.App, clientConfig *client.Config, envName, componentName string) (string, error) {
+	remote, err := diff.Remote(a, clientConfig, envName, []string{componentName})
+	if err != nil {
+		return "", err
+	}
+
+	remoteData, err := ioutil.ReadAll(remote)
+	if err != nil {
+		return "", err
+	}
+
+	if len(remoteData) == 0 {
+		return componentStatusNotDeployed, nil
+	}
+
+	local, err := diff.Local(a, envName, []string{componentName})
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = remote.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+
+	r, err := diff.Text(remote, local)
+	if err != nil {
+		return "", err
+	}
+
+	changes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	if len(changes) != 0 {
+		return componentStatusOutOfSync, nil
+	}
+
+	return componentStatusInSync, nil
+}
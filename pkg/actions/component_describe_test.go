@@ -0,0 +1,249 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	cmocks "github.com/ksonnet/ksonnet/pkg/component/mocks"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestComponentDescribe(t *testing.T) {
+	source := `local params = std.extVar("__ksonnet/params").components.ui;
+local redis = import "incubator/redis/redis.libsonnet";
+local k = import "k.libsonnet";
+
+redis.parts.deployment.nonPersistent(params.name, "secret")
+`
+
+	cases := []struct {
+		name         string
+		envName      string
+		currentEnv   string
+		output       string
+		isModule     bool
+		statusErr    error
+		expectedFile string
+		isErr        bool
+	}{
+		{
+			name:         "in general",
+			envName:      "prod",
+			expectedFile: "component/describe/output.txt",
+		},
+		{
+			name:         "with json output",
+			envName:      "prod",
+			output:       "json",
+			expectedFile: "component/describe/output.json",
+		},
+		{
+			name:         "current environment",
+			currentEnv:   "prod",
+			expectedFile: "component/describe/output.txt",
+		},
+		{
+			name:         "current environment does not target component",
+			currentEnv:   "staging",
+			expectedFile: "component/describe/no-env.txt",
+		},
+		{
+			name:         "status unknown",
+			envName:      "prod",
+			statusErr:    errors.New("cluster unreachable"),
+			expectedFile: "component/describe/unknown.txt",
+		},
+		{
+			name:    "environment does not target component",
+			envName: "staging",
+			isErr:   true,
+		},
+		{
+			name:     "module",
+			isModule: true,
+			isErr:    true,
+		},
+		{
+			name:   "invalid output",
+			output: "invalid",
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				fs := appMock.Fs()
+				require.NoError(t, afero.WriteFile(fs, "/components/frontend/ui.jsonnet", []byte(source), 0644))
+
+				envs := app.EnvironmentConfigs{
+					"default": &app.EnvironmentConfig{Name: "default", Targets: []string{"/", "frontend"}},
+					"prod":    &app.EnvironmentConfig{Name: "prod", Targets: []string{"frontend"}},
+					"staging": &app.EnvironmentConfig{Name: "staging"},
+				}
+				appMock.On("Environments").Return(envs, nil)
+				for name, env := range envs {
+					appMock.On("Environment", name).Return(env, nil)
+				}
+				appMock.On("CurrentEnvironment").Return(tc.currentEnv)
+
+				libs := app.LibraryConfigs{
+					"redis": &app.LibraryConfig{Name: "redis", Registry: "incubator", Version: "0.1.0"},
+					"nginx": &app.LibraryConfig{Name: "nginx", Registry: "incubator"},
+				}
+				appMock.On("Libraries").Return(libs, nil)
+
+				m := &cmocks.Module{}
+				m.On("Name").Return("frontend")
+
+				c := &cmocks.Component{}
+				c.On("Name", false).Return("ui")
+				c.On("Name", true).Return("frontend.ui")
+				c.On("Type").Return("jsonnet")
+				c.On("Params", "default").Return([]component.ModuleParameter{
+					{Component: "frontend.ui", Key: "name", Value: `"ui"`},
+					{Component: "frontend.ui", Key: "replicas", Value: "1"},
+				}, nil)
+				c.On("Params", "prod").Return([]component.ModuleParameter{
+					{Component: "frontend.ui", Key: "name", Value: `"ui"`},
+					{Component: "frontend.ui", Key: "replicas", Value: "3"},
+				}, nil)
+
+				in := map[string]interface{}{
+					OptionApp:           appMock,
+					OptionClientConfig:  &client.Config{},
+					OptionComponentName: "frontend.ui",
+					OptionEnvName:       tc.envName,
+					OptionOutput:        tc.output,
+				}
+
+				a, err := NewComponentDescribe(in)
+				require.NoError(t, err)
+
+				a.resolveFn = func(_ app.App, path string) (component.Module, component.Component, error) {
+					assert.Equal(t, "frontend.ui", path)
+					if tc.isModule {
+						return m, nil, nil
+					}
+					return m, c, nil
+				}
+
+				a.objectsFn = func(_ app.App, envName, componentName string) ([]*unstructured.Unstructured, error) {
+					assert.Equal(t, "prod", envName)
+					assert.Equal(t, "frontend.ui", componentName)
+
+					return []*unstructured.Unstructured{
+						{Object: map[string]interface{}{
+							"apiVersion": "apps/v1beta1",
+							"kind":       "Deployment",
+							"metadata":   map[string]interface{}{"name": "ui", "namespace": "prod"},
+						}},
+						{Object: map[string]interface{}{
+							"apiVersion": "v1",
+							"kind":       "Service",
+							"metadata":   map[string]interface{}{"name": "ui"},
+						}},
+					}, nil
+				}
+
+				a.statusFn = func(_ app.App, _ *client.Config, envName, componentName string) (string, error) {
+					assert.Equal(t, "prod", envName)
+					assert.Equal(t, "frontend.ui", componentName)
+
+					if tc.statusErr != nil {
+						return "", tc.statusErr
+					}
+					return componentStatusOutOfSync, nil
+				}
+
+				var buf bytes.Buffer
+				a.out = &buf
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+
+				require.NoError(t, err)
+				assertOutput(t, tc.expectedFile, buf.String())
+			})
+		})
+	}
+}
+
+func Test_importedPackages(t *testing.T) {
+	source := `local redis = import "incubator/redis/redis.libsonnet";
+local memcached = import "incubator/memcached/memcached.libsonnet";
+{}
+`
+
+	cases := []struct {
+		name     string
+		envName  string
+		expected []string
+	}{
+		{
+			name:     "global packages",
+			expected: []string{"incubator/redis@0.1.0"},
+		},
+		{
+			name:     "environment packages",
+			envName:  "prod",
+			expected: []string{"incubator/memcached@1.0.0", "incubator/redis@0.2.0"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				require.NoError(t, afero.WriteFile(appMock.Fs(), "/components/ui.jsonnet", []byte(source), 0644))
+
+				appMock.On("Libraries").Return(app.LibraryConfigs{
+					"redis": &app.LibraryConfig{Name: "redis", Registry: "incubator", Version: "0.1.0"},
+				}, nil)
+				appMock.On("Environment", "prod").Return(&app.EnvironmentConfig{
+					Name: "prod",
+					Libraries: app.LibraryConfigs{
+						"memcached": &app.LibraryConfig{Name: "memcached", Registry: "incubator", Version: "1.0.0"},
+						"redis":     &app.LibraryConfig{Name: "redis", Registry: "incubator", Version: "0.2.0"},
+					},
+				}, nil)
+
+				packages, err := importedPackages(appMock, tc.envName, "/components/ui.jsonnet")
+				require.NoError(t, err)
+
+				assert.Equal(t, tc.expected, packages)
+			})
+		})
+	}
+}
+
+func TestComponentDescribe_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewComponentDescribe(in)
+	require.Error(t, err)
+}
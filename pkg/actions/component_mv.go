@@ -0,0 +1,243 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"sort"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/params"
+	"github.com/ksonnet/ksonnet/pkg/pipeline"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RunComponentMv runs `component mv`
+func RunComponentMv(m map[string]interface{}) error {
+	cm, err := NewComponentMv(m)
+	if err != nil {
+		return err
+	}
+
+	return cm.Run()
+}
+
+// ComponentMv moves a component to another module or name.
+type ComponentMv struct {
+	app     app.App
+	name    string
+	newName string
+
+	guard    *envGuard
+	moveFn   func(a app.App, from, to string) (component.Component, error)
+	renderFn func(a app.App, envName, componentName string) ([]*unstructured.Unstructured, error)
+}
+
+// NewComponentMv creates an instance of ComponentMv.
+func NewComponentMv(m map[string]interface{}) (*ComponentMv, error) {
+	ol := newOptionLoader(m)
+
+	cm := &ComponentMv{
+		app:     ol.LoadApp(),
+		name:    ol.LoadString(OptionComponentName),
+		newName: ol.LoadString(OptionNewComponentName),
+
+		moveFn:   component.Move,
+		renderFn: renderComponentObjects,
+	}
+
+	cm.guard = newEnvGuard(cm.app, ol.LoadOptionalString(OptionConfirmEnv))
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return cm, nil
+}
+
+// Run moves the component. The component is rendered in every environment, for
+// each of its destinations, before and after the move. If any output changes apart
+// from the component label, the move is reverted.
+func (cm *ComponentMv) Run() error {
+	envs, err := cm.app.Environments()
+	if err != nil {
+		return err
+	}
+
+	envNames := make([]string, 0, len(envs))
+	targets := make(map[string][]string)
+	for envName, env := range envs {
+		envNames = append(envNames, envName)
+		targets[envName] = append([]string(nil), env.Targets...)
+	}
+	sort.Strings(envNames)
+
+	// The move rewrites the params of every environment.
+	for _, envName := range envNames {
+		if err = cm.guard.check(envName, "change", false); err != nil {
+			return err
+		}
+	}
+
+	renders := renderDestinations(cm.app, envNames, envs)
+
+	before := make([][]*unstructured.Unstructured, len(renders))
+	for i, rd := range renders {
+		if before[i], err = cm.renderFn(rd.app, rd.envName, cm.name); err != nil {
+			return errors.Wrapf(err, "rendering component %q in %s", cm.name, rd)
+		}
+	}
+
+	snapshot := newFileSnapshot(cm.app.Fs())
+	if err = snapshot.addDir(filepath.Join(cm.app.Root(), "components")); err != nil {
+		return err
+	}
+	for _, envName := range envNames {
+		if err = snapshot.addFile(filepath.Join(cm.app.Root(), "environments", envName, "params.libsonnet")); err != nil {
+			return err
+		}
+		for _, path := range params.DestinationParamsPaths(cm.app, envs[envName]) {
+			if err = snapshot.addFile(path); err != nil {
+				return err
+			}
+		}
+	}
+
+	c, err := cm.moveFn(cm.app, cm.name, cm.newName)
+	if err == nil {
+		err = cm.verify(renders, c.Name(true), before)
+	}
+
+	if err != nil {
+		if rerr := cm.restore(snapshot, targets); rerr != nil {
+			return errors.Wrapf(rerr, "reverting move of %q", cm.name)
+		}
+		return errors.Wrapf(err, "moving component %q to %q; move reverted", cm.name, cm.newName)
+	}
+
+	return nil
+}
+
+func (cm *ComponentMv) verify(renders []renderDestination, newName string, before [][]*unstructured.Unstructured) error {
+	for i, rd := range renders {
+		after, err := cm.renderFn(rd.app, rd.envName, newName)
+		if err != nil {
+			return errors.Wrapf(err, "rendering component %q in %s", newName, rd)
+		}
+
+		if !sameObjects(before[i], after) {
+			return errors.Errorf("rendered output of %s changed", rd)
+		}
+	}
+
+	return nil
+}
+
+// renderDestination is an environment, pinned to one of its destinations, that
+// a component is rendered in.
+type renderDestination struct {
+	app     app.App
+	envName string
+	dest    *app.EnvironmentDestinationSpec
+}
+
+func (rd renderDestination) String() string {
+	if rd.dest == nil {
+		return fmt.Sprintf("environment %q", rd.envName)
+	}
+	return fmt.Sprintf("environment %q (destination %s)", rd.envName, rd.dest)
+}
+
+// renderDestinations returns the destinations of environments, as each of them
+// can have its own params.
+func renderDestinations(a app.App, envNames []string, envs app.EnvironmentConfigs) []renderDestination {
+	var renders []renderDestination
+	for _, envName := range envNames {
+		dests := envs[envName].Destinations
+		if len(dests) < 2 {
+			renders = append(renders, renderDestination{app: a, envName: envName})
+			continue
+		}
+
+		for _, dest := range dests {
+			renders = append(renders, renderDestination{
+				app:     app.WithDestination(a, envName, dest),
+				envName: envName,
+				dest:    dest,
+			})
+		}
+	}
+
+	return renders
+}
+
+func (cm *ComponentMv) restore(snapshot *fileSnapshot, targets map[string][]string) error {
+	if err := snapshot.restore(); err != nil {
+		return err
+	}
+
+	envs, err := cm.app.Environments()
+	if err != nil {
+		return err
+	}
+
+	for envName, env := range envs {
+		if reflect.DeepEqual(env.Targets, targets[envName]) || len(env.Targets)+len(targets[envName]) == 0 {
+			continue
+		}
+
+		log.Debugf("restoring targets for environment %q", envName)
+		if err := cm.app.UpdateTargets(envName, targets[envName], cm.app.IsEnvOverride(envName)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// sameObjects reports whether two sets of objects are equal, ignoring their
+// component labels.
+func sameObjects(a, b []*unstructured.Unstructured) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for i := range a {
+		x, y := a[i].DeepCopy(), b[i].DeepCopy()
+		for _, o := range []*unstructured.Unstructured{x, y} {
+			labels := o.GetLabels()
+			delete(labels, metadata.LabelComponent)
+			o.SetLabels(labels)
+		}
+
+		if !reflect.DeepEqual(x.Object, y.Object) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// renderComponentObjects renders a component in an environment.
+func renderComponentObjects(a app.App, envName, componentName string) ([]*unstructured.Unstructured, error) {
+	return pipeline.New(a, envName).Objects([]string{componentName})
+}
@@ -0,0 +1,263 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestComponentMv(t *testing.T) {
+	object := func(componentName, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Service",
+			"metadata": map[string]interface{}{
+				"name": name,
+				"labels": map[string]interface{}{
+					"ksonnet.io/component": componentName,
+				},
+			},
+		}}
+	}
+
+	cases := []struct {
+		name     string
+		after    string
+		moveErr  error
+		isErr    bool
+		reverted bool
+	}{
+		{
+			name:  "move component",
+			after: "guestbook",
+		},
+		{
+			name:     "rendered output changed",
+			after:    "changed",
+			isErr:    true,
+			reverted: true,
+		},
+		{
+			name:     "move failed",
+			moveErr:  errors.New("failed"),
+			isErr:    true,
+			reverted: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				fs := appMock.Fs()
+				require.NoError(t, afero.WriteFile(fs, "/components/params.libsonnet", []byte("{guestbook: {}}"), 0644))
+				require.NoError(t, afero.WriteFile(fs, "/components/guestbook.jsonnet", []byte("{}"), 0644))
+				require.NoError(t, afero.WriteFile(fs, "/environments/default/params.libsonnet", []byte(`{"guestbook": {}}`), 0644))
+
+				envs := app.EnvironmentConfigs{
+					"default": &app.EnvironmentConfig{Name: "default", Targets: []string{"/"}},
+				}
+				appMock.On("Environments").Return(envs, nil)
+				appMock.On("Environment", "default").Return(envs["default"], nil)
+				appMock.On("IsEnvOverride", "default").Return(false)
+				appMock.On("UpdateTargets", "default", []string{"/"}, false).Return(nil)
+
+				in := map[string]interface{}{
+					OptionApp:              appMock,
+					OptionComponentName:    "guestbook",
+					OptionNewComponentName: "frontend.guestbook",
+				}
+
+				a, err := NewComponentMv(in)
+				require.NoError(t, err)
+
+				var moved bool
+				a.moveFn = func(ksApp app.App, from, to string) (component.Component, error) {
+					assert.Equal(t, "guestbook", from)
+					assert.Equal(t, "frontend.guestbook", to)
+					moved = true
+
+					require.NoError(t, fs.MkdirAll("/components/frontend", 0755))
+					require.NoError(t, afero.WriteFile(fs, "/components/frontend/params.libsonnet", []byte("{guestbook: {}}"), 0644))
+					require.NoError(t, afero.WriteFile(fs, "/components/frontend/guestbook.jsonnet", []byte("{}"), 0644))
+					require.NoError(t, afero.WriteFile(fs, "/components/params.libsonnet", []byte("{}"), 0644))
+					require.NoError(t, afero.WriteFile(fs, "/environments/default/params.libsonnet", []byte(`{"frontend.guestbook": {}}`), 0644))
+					require.NoError(t, fs.Remove("/components/guestbook.jsonnet"))
+					envs["default"].Targets = []string{"/", "frontend"}
+
+					if tc.moveErr != nil {
+						return nil, tc.moveErr
+					}
+
+					return component.NewJsonnet(ksApp, "frontend", "/components/frontend/guestbook.jsonnet",
+						"/components/frontend/params.libsonnet"), nil
+				}
+
+				var rendered []string
+				a.renderFn = func(_ app.App, envName, componentName string) ([]*unstructured.Unstructured, error) {
+					rendered = append(rendered, componentName)
+					if moved {
+						return []*unstructured.Unstructured{object(componentName, tc.after)}, nil
+					}
+					return []*unstructured.Unstructured{object(componentName, "guestbook")}, nil
+				}
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+					assert.Equal(t, []string{"guestbook", "frontend.guestbook"}, rendered)
+					appMock.AssertNotCalled(t, "UpdateTargets", "default", []string{"/"}, false)
+				}
+
+				if tc.reverted {
+					exists, err := afero.Exists(fs, "/components/frontend")
+					require.NoError(t, err)
+					assert.False(t, exists)
+
+					data, err := afero.ReadFile(fs, "/components/guestbook.jsonnet")
+					require.NoError(t, err)
+					assert.Equal(t, "{}", string(data))
+
+					data, err = afero.ReadFile(fs, "/components/params.libsonnet")
+					require.NoError(t, err)
+					assert.Equal(t, "{guestbook: {}}", string(data))
+
+					data, err = afero.ReadFile(fs, "/environments/default/params.libsonnet")
+					require.NoError(t, err)
+					assert.Equal(t, `{"guestbook": {}}`, string(data))
+
+					appMock.AssertCalled(t, "UpdateTargets", "default", []string{"/"}, false)
+				}
+			})
+		})
+	}
+}
+
+func TestComponentMv_destinations(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		fs := appMock.Fs()
+		require.NoError(t, afero.WriteFile(fs, "/components/params.libsonnet", []byte("{guestbook: {}}"), 0644))
+		require.NoError(t, afero.WriteFile(fs, "/components/guestbook.jsonnet", []byte("{}"), 0644))
+		require.NoError(t, afero.WriteFile(fs, "/environments/default/params.libsonnet", []byte(`{"guestbook": {}}`), 0644))
+		require.NoError(t, afero.WriteFile(fs, "/environments/default/eu-west.libsonnet", []byte(`{"guestbook": {}}`), 0644))
+
+		envs := app.EnvironmentConfigs{
+			"default": &app.EnvironmentConfig{
+				Name:    "default",
+				Path:    "default",
+				Targets: []string{"/"},
+				Destinations: []*app.EnvironmentDestinationSpec{
+					{Name: "us-east"},
+					{Name: "eu-west", Params: "eu-west.libsonnet"},
+				},
+			},
+		}
+		appMock.On("Environments").Return(envs, nil)
+		appMock.On("Environment", "default").Return(envs["default"], nil)
+
+		in := map[string]interface{}{
+			OptionApp:              appMock,
+			OptionComponentName:    "guestbook",
+			OptionNewComponentName: "ui",
+		}
+
+		a, err := NewComponentMv(in)
+		require.NoError(t, err)
+
+		var moved bool
+		a.moveFn = func(ksApp app.App, from, to string) (component.Component, error) {
+			moved = true
+			require.NoError(t, afero.WriteFile(fs, "/environments/default/eu-west.libsonnet", []byte(`{"ui": {}}`), 0644))
+			return component.NewJsonnet(ksApp, "/", "/components/ui.jsonnet", "/components/params.libsonnet"), nil
+		}
+
+		var rendered []string
+		a.renderFn = func(ksApp app.App, envName, componentName string) ([]*unstructured.Unstructured, error) {
+			e, err := ksApp.Environment(envName)
+			require.NoError(t, err)
+			rendered = append(rendered, e.Destination.Name)
+
+			name := "guestbook"
+			if moved && e.Destination.Name == "eu-west" {
+				name = "changed"
+			}
+			return []*unstructured.Unstructured{{Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata":   map[string]interface{}{"name": name},
+			}}}, nil
+		}
+
+		err = a.Run()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `rendered output of environment "default" (destination eu-west) changed`)
+		assert.Equal(t, []string{"us-east", "eu-west", "us-east", "eu-west"}, rendered)
+
+		data, err := afero.ReadFile(fs, "/environments/default/eu-west.libsonnet")
+		require.NoError(t, err)
+		assert.Equal(t, `{"guestbook": {}}`, string(data))
+	})
+}
+
+func TestComponentMv_protected(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		envs := app.EnvironmentConfigs{
+			"default": &app.EnvironmentConfig{Name: "default"},
+			"prod":    &app.EnvironmentConfig{Name: "prod", Protected: true},
+		}
+		appMock.On("Environments").Return(envs, nil)
+		appMock.On("Environment", "default").Return(envs["default"], nil)
+		appMock.On("Environment", "prod").Return(envs["prod"], nil)
+
+		in := map[string]interface{}{
+			OptionApp:              appMock,
+			OptionComponentName:    "guestbook",
+			OptionNewComponentName: "ui",
+		}
+
+		a, err := NewComponentMv(in)
+		require.NoError(t, err)
+
+		a.guard.isTerminalFn = func() bool { return false }
+		a.moveFn = func(ksApp app.App, from, to string) (component.Component, error) {
+			t.Fatal("component moved in a protected environment")
+			return nil, nil
+		}
+		a.renderFn = func(ksApp app.App, envName, componentName string) ([]*unstructured.Unstructured, error) {
+			return nil, nil
+		}
+
+		err = a.Run()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `environment "prod" is protected`)
+	})
+}
+
+func TestComponentMv_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewComponentMv(in)
+	require.Error(t, err)
+}
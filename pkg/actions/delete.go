@@ -42,7 +42,9 @@ type Delete struct {
 	componentNames []string
 	envName        string
 	gracePeriod    int64
+	parallel       bool
 
+	guard       *envGuard
 	runDeleteFn runDeleteFn
 }
 
@@ -55,10 +57,13 @@ func newDelete(m map[string]interface{}, opts ...deleteOpt) (*Delete, error) {
 		clientConfig:   ol.LoadClientConfig(),
 		componentNames: ol.LoadStringSlice(OptionComponentNames),
 		gracePeriod:    ol.LoadInt64(OptionGracePeriod),
+		parallel:       ol.LoadOptionalBool(OptionParallel),
 
 		runDeleteFn: cluster.RunDelete,
 	}
 
+	d.guard = newEnvGuard(d.app, ol.LoadOptionalString(OptionConfirmEnv))
+
 	if ol.err != nil {
 		return nil, ol.err
 	}
@@ -75,9 +80,17 @@ func newDelete(m map[string]interface{}, opts ...deleteOpt) (*Delete, error) {
 }
 
 func (d *Delete) run() error {
+	if err := d.guard.check(d.envName, "delete", false); err != nil {
+		return err
+	}
+
+	return runForDestinations(d.app, d.clientConfig, d.envName, d.parallel, d.runDestination)
+}
+
+func (d *Delete) runDestination(_ *app.EnvironmentDestinationSpec, ksApp app.App, clientConfig *client.Config) error {
 	config := cluster.DeleteConfig{
-		App:            d.app,
-		ClientConfig:   d.clientConfig,
+		App:            ksApp,
+		ClientConfig:   clientConfig,
 		ComponentNames: d.componentNames,
 		EnvName:        d.envName,
 		GracePeriod:    d.gracePeriod,
@@ -0,0 +1,95 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"sync"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// destinationFn runs an action against a single environment destination.
+type destinationFn func(dest *app.EnvironmentDestinationSpec, a app.App, config *client.Config) error
+
+// runForDestinations runs fn once for each destination of an environment.
+// Environments with a single destination run fn with the original app and
+// client configuration. Otherwise, each run gets a view of the app pinned to
+// one destination and its own copy of the client configuration.
+func runForDestinations(a app.App, config *client.Config, envName string, parallel bool, fn destinationFn) error {
+	env, err := a.Environment(envName)
+	if err != nil {
+		return err
+	}
+
+	if len(env.Destinations) < 2 {
+		return fn(env.Destination, a, config)
+	}
+
+	dests := env.Destinations
+	errs := make([]error, len(dests))
+
+	run := func(i int) {
+		dest := dests[i]
+
+		var destConfig *client.Config
+		if config != nil {
+			destConfig = config.Copy()
+		}
+
+		errs[i] = fn(dest, app.WithDestination(a, envName, dest), destConfig)
+	}
+
+	if parallel {
+		var wg sync.WaitGroup
+		for i := range dests {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				run(i)
+			}(i)
+		}
+		wg.Wait()
+	} else {
+		for i := range dests {
+			run(i)
+		}
+	}
+
+	var failed int
+	for i, dest := range dests {
+		logger := log.WithFields(log.Fields{
+			"environment": envName,
+			"destination": dest.String(),
+		})
+
+		if errs[i] != nil {
+			failed++
+			logger.WithError(errs[i]).Error("failed")
+			continue
+		}
+
+		logger.Info("succeeded")
+	}
+
+	if failed > 0 {
+		return errors.Errorf("%d of %d destinations of environment %q failed", failed, len(dests), envName)
+	}
+
+	return nil
+}
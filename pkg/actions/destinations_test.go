@@ -0,0 +1,108 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_runForDestinations(t *testing.T) {
+	cases := []struct {
+		name     string
+		parallel bool
+	}{
+		{name: "sequential"},
+		{name: "parallel", parallel: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				env := &app.EnvironmentConfig{
+					Name: "default",
+					Destinations: []*app.EnvironmentDestinationSpec{
+						{Name: "us", Server: "http://us.example.com", Namespace: "default"},
+						{Name: "eu", Server: "http://eu.example.com", Namespace: "default"},
+						{Name: "ap", Server: "http://ap.example.com", Namespace: "default"},
+					},
+				}
+				appMock.On("Environment", "default").Return(env, nil)
+
+				config := &client.Config{}
+
+				var mu sync.Mutex
+				servers := make(map[string]string)
+
+				err := runForDestinations(appMock, config, "default", tc.parallel,
+					func(dest *app.EnvironmentDestinationSpec, a app.App, c *client.Config) error {
+						assert.False(t, c == config, "client config is shared")
+
+						e, err := a.Environment("default")
+						if !assert.NoError(t, err) {
+							return err
+						}
+
+						mu.Lock()
+						servers[dest.Name] = e.Destination.Server
+						mu.Unlock()
+
+						if dest.Name == "eu" {
+							return errors.New("failed")
+						}
+						return nil
+					})
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "1 of 3 destinations")
+
+				expected := map[string]string{
+					"us": "http://us.example.com",
+					"eu": "http://eu.example.com",
+					"ap": "http://ap.example.com",
+				}
+				assert.Equal(t, expected, servers)
+			})
+		})
+	}
+}
+
+func Test_runForDestinations_single(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		dest := &app.EnvironmentDestinationSpec{Server: "http://example.com", Namespace: "default"}
+		appMock.On("Environment", "default").Return(&app.EnvironmentConfig{Destination: dest}, nil)
+
+		config := &client.Config{}
+		var called int
+
+		err := runForDestinations(appMock, config, "default", false,
+			func(d *app.EnvironmentDestinationSpec, a app.App, c *client.Config) error {
+				called++
+				assert.Equal(t, dest, d)
+				assert.Equal(t, app.App(appMock), a)
+				assert.True(t, c == config)
+				return nil
+			})
+		require.NoError(t, err)
+		assert.Equal(t, 1, called)
+	})
+}
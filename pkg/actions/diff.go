@@ -55,6 +55,7 @@ type Diff struct {
 	src1         string
 	src2         string
 	components   []string
+	parallel     bool
 
 	diffFn func(app.App, *client.Config, []string, *diff.Location, *diff.Location) (io.Reader, error)
 
@@ -71,6 +72,7 @@ func NewDiff(m map[string]interface{}) (*Diff, error) {
 		src1:         ol.LoadString(OptionSrc1),
 		src2:         ol.LoadOptionalString(OptionSrc2),
 		components:   ol.LoadStringSlice(OptionComponentNames),
+		parallel:     ol.LoadOptionalBool(OptionParallel),
 
 		diffFn: diff.DefaultDiff,
 
@@ -93,12 +95,85 @@ func (d *Diff) Run() error {
 	}
 	location2 := diff.NewLocation(d.src2)
 
-	r, err := d.diffFn(d.app, d.clientConfig, d.components, location1, location2)
+	if location1.Err() != nil || location1.EnvName() != location2.EnvName() {
+		return d.diffOnce(d.app, d.clientConfig, location1, location2)
+	}
+
+	env, err := d.app.Environment(location1.EnvName())
 	if err != nil {
 		return err
 	}
 
+	if len(env.Destinations) < 2 {
+		return d.diffOnce(d.app, d.clientConfig, location1, location2)
+	}
+
+	// Both locations refer to an environment with multiple destinations:
+	// compare each of them.
+	outputs := make([]string, len(env.Destinations))
+	err = runForDestinations(d.app, d.clientConfig, location1.EnvName(), d.parallel,
+		func(dest *app.EnvironmentDestinationSpec, ksApp app.App, clientConfig *client.Config) error {
+			out, err := d.colorize(ksApp, clientConfig, location1, location2)
+			if err != nil {
+				return err
+			}
+
+			for i := range env.Destinations {
+				if *env.Destinations[i] == *dest {
+					outputs[i] = out
+				}
+			}
+			return nil
+		})
+	if err != nil {
+		return err
+	}
+
+	var found bool
+	for i, out := range outputs {
+		if out == "" {
+			continue
+		}
+
+		found = true
+		fmt.Fprintf(d.out, "destination %s:\n%s\n", env.Destinations[i], out)
+	}
+
+	if found {
+		return ErrDiffFound
+	}
+
+	return nil
+}
+
+func (d *Diff) diffOnce(ksApp app.App, clientConfig *client.Config, location1, location2 *diff.Location) error {
+	out, err := d.colorize(ksApp, clientConfig, location1, location2)
+	if err != nil {
+		return err
+	}
+
+	if out != "" {
+		fmt.Fprintln(d.out, out)
+		return ErrDiffFound
+	}
+
+	return nil
+}
+
+// colorize runs the diff and returns its colorized output.
+func (d *Diff) colorize(ksApp app.App, clientConfig *client.Config, location1, location2 *diff.Location) (string, error) {
+	r, err := d.diffFn(ksApp, clientConfig, d.components, location1, location2)
+	if err != nil {
+		return "", err
+	}
+
+	return colorizeDiff(r)
+}
+
+// colorizeDiff colors the added and removed lines of a diff.
+func colorizeDiff(r io.Reader) (string, error) {
 	var buf bytes.Buffer
+	var err error
 
 	scanner := bufio.NewScanner(r)
 	for scanner.Scan() {
@@ -108,12 +183,12 @@ func (d *Diff) Run() error {
 		case strings.HasPrefix(t, "+"):
 			_, err = diffAddColor.Fprintln(&buf, t)
 			if err != nil {
-				return err
+				return "", err
 			}
 		case strings.HasPrefix(t, "-"):
 			_, err = diffRemoveColor.Fprintln(&buf, t)
 			if err != nil {
-				return err
+				return "", err
 			}
 		default:
 			fmt.Fprintln(&buf, t)
@@ -121,13 +196,8 @@ func (d *Diff) Run() error {
 	}
 
 	if err := scanner.Err(); err != nil {
-		return err
+		return "", err
 	}
 
-	if s := buf.String(); s != "" {
-		fmt.Fprintln(d.out, s)
-		return ErrDiffFound
-	}
-
-	return nil
+	return buf.String(), nil
 }
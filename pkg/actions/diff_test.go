@@ -67,6 +67,8 @@ func TestDiff(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			withApp(t, func(appMock *amocks.App) {
+				appMock.On("Environment", "default").Return(&app.EnvironmentConfig{}, nil)
+
 				in := map[string]interface{}{
 					OptionApp:            appMock,
 					OptionClientConfig:   &client.Config{},
@@ -16,8 +16,11 @@
 package actions
 
 import (
+	"path/filepath"
+
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/pkg/errors"
 )
 
 // RunEnvAdd runs `env add`
@@ -38,6 +41,9 @@ type EnvAdd struct {
 	namespace   string
 	k8sSpecFlag string
 	isOverride  bool
+	context     string
+	kubeconfig  string
+	inCluster   bool
 
 	envCreateFn func(a app.App, d env.Destination, name, k8sSpecFlag string, overrideData, paramsData []byte, isOverride bool) error
 }
@@ -53,6 +59,9 @@ func NewEnvAdd(m map[string]interface{}) (*EnvAdd, error) {
 		namespace:   ol.LoadString(OptionModule),
 		k8sSpecFlag: ol.LoadString(OptionSpecFlag),
 		isOverride:  ol.LoadBool(OptionOverride),
+		context:     ol.LoadOptionalString(OptionContext),
+		kubeconfig:  ol.LoadOptionalString(OptionKubeconfig),
+		inCluster:   ol.LoadOptionalBool(OptionInCluster),
 
 		envCreateFn: env.Create,
 	}
@@ -66,7 +75,25 @@ func NewEnvAdd(m map[string]interface{}) (*EnvAdd, error) {
 
 // Run assigns targets to an environment.
 func (ea *EnvAdd) Run() error {
-	destination := env.NewDestination(ea.server, ea.namespace)
+	var opts []env.DestinationOpt
+	if ea.context != "" {
+		// The kubeconfig path is stored absolute, as the environment is used
+		// from other working directories.
+		kubeconfig := ea.kubeconfig
+		if kubeconfig != "" {
+			var err error
+			if kubeconfig, err = filepath.Abs(kubeconfig); err != nil {
+				return errors.Wrapf(err, "resolving kubeconfig path %s", ea.kubeconfig)
+			}
+		}
+
+		opts = append(opts, env.DestinationContext(ea.context, kubeconfig))
+	}
+	if ea.inCluster {
+		opts = append(opts, env.DestinationInCluster())
+	}
+
+	destination := env.NewDestination(ea.server, ea.namespace, opts...)
 
 	return ea.envCreateFn(
 		ea.app,
@@ -16,6 +16,8 @@
 package actions
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
@@ -63,6 +65,63 @@ func TestEnvAdd(t *testing.T) {
 	})
 }
 
+func TestEnvAdd_context(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:        appMock,
+			OptionEnvName:    "prod",
+			OptionServer:     "http://example.com",
+			OptionModule:     "prod",
+			OptionSpecFlag:   "flag",
+			OptionOverride:   false,
+			OptionContext:    "prod-admin",
+			OptionKubeconfig: "/kube/config",
+		}
+
+		a, err := NewEnvAdd(in)
+		require.NoError(t, err)
+
+		a.envCreateFn = func(a app.App, d env.Destination, name, specFlag string, od, pd []byte, override bool) error {
+			expectedDest := env.NewDestination("http://example.com", "prod",
+				env.DestinationContext("prod-admin", "/kube/config"))
+			assert.Equal(t, expectedDest, d)
+			return nil
+		}
+
+		err = a.Run()
+		require.NoError(t, err)
+	})
+}
+
+func TestEnvAdd_relative_kubeconfig(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:        appMock,
+			OptionEnvName:    "prod",
+			OptionServer:     "http://example.com",
+			OptionModule:     "prod",
+			OptionSpecFlag:   "flag",
+			OptionOverride:   false,
+			OptionContext:    "prod-admin",
+			OptionKubeconfig: filepath.Join("kube", "config"),
+		}
+
+		a, err := NewEnvAdd(in)
+		require.NoError(t, err)
+
+		wd, err := os.Getwd()
+		require.NoError(t, err)
+
+		a.envCreateFn = func(a app.App, d env.Destination, name, specFlag string, od, pd []byte, override bool) error {
+			assert.Equal(t, filepath.Join(wd, "kube", "config"), d.Kubeconfig())
+			return nil
+		}
+
+		err = a.Run()
+		require.NoError(t, err)
+	})
+}
+
 func TestEnvAdd_requires_app(t *testing.T) {
 	in := make(map[string]interface{})
 	_, err := NewEnvAdd(in)
@@ -0,0 +1,74 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/env"
+)
+
+// RunEnvClone runs `env clone`
+func RunEnvClone(m map[string]interface{}) error {
+	ec, err := NewEnvClone(m)
+	if err != nil {
+		return err
+	}
+
+	return ec.Run()
+}
+
+// EnvClone creates an environment from an existing one.
+type EnvClone struct {
+	app       app.App
+	envName   string
+	newName   string
+	server    string
+	namespace string
+
+	envCloneFn func(env.CloneConfig) error
+}
+
+// NewEnvClone creates an instance of EnvClone.
+func NewEnvClone(m map[string]interface{}) (*EnvClone, error) {
+	ol := newOptionLoader(m)
+
+	ec := &EnvClone{
+		app:       ol.LoadApp(),
+		envName:   ol.LoadString(OptionEnvName),
+		newName:   ol.LoadString(OptionNewEnvName),
+		server:    ol.LoadOptionalString(OptionServer),
+		namespace: ol.LoadOptionalString(OptionNamespace),
+
+		envCloneFn: env.Clone,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return ec, nil
+}
+
+// Run clones the environment.
+func (ec *EnvClone) Run() error {
+	return ec.envCloneFn(env.CloneConfig{
+		App:       ec.app,
+		From:      ec.envName,
+		To:        ec.newName,
+		Server:    ec.server,
+		Namespace: ec.namespace,
+	})
+}
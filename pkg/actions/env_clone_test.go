@@ -0,0 +1,61 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"testing"
+
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEnvClone(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:        appMock,
+			OptionEnvName:    "staging",
+			OptionNewEnvName: "prod",
+			OptionServer:     "http://prod.example.com",
+			OptionNamespace:  "prod",
+		}
+
+		a, err := NewEnvClone(in)
+		require.NoError(t, err)
+
+		a.envCloneFn = func(config env.CloneConfig) error {
+			expected := env.CloneConfig{
+				App:       appMock,
+				From:      "staging",
+				To:        "prod",
+				Server:    "http://prod.example.com",
+				Namespace: "prod",
+			}
+			assert.Equal(t, expected, config)
+			return nil
+		}
+
+		err = a.Run()
+		require.NoError(t, err)
+	})
+}
+
+func TestEnvClone_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewEnvClone(in)
+	require.Error(t, err)
+}
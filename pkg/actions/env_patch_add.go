@@ -0,0 +1,136 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/ksonnet/ksonnet/pkg/pipeline"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RunEnvPatchAdd runs `env patch add`
+func RunEnvPatchAdd(m map[string]interface{}) error {
+	epa, err := NewEnvPatchAdd(m)
+	if err != nil {
+		return err
+	}
+
+	return epa.Run()
+}
+
+// EnvPatchAdd scaffolds a patch for an object rendered in an environment.
+type EnvPatchAdd struct {
+	app        app.App
+	envName    string
+	kind       string
+	name       string
+	apiVersion string
+	patchType  string
+
+	guard         *envGuard
+	objectsFn     func(a app.App, envName string) ([]*unstructured.Unstructured, error)
+	createPatchFn func(a app.App, envName string, target env.PatchTarget, patchType string) (string, error)
+}
+
+// NewEnvPatchAdd creates an instance of EnvPatchAdd.
+func NewEnvPatchAdd(m map[string]interface{}) (*EnvPatchAdd, error) {
+	ol := newOptionLoader(m)
+
+	epa := &EnvPatchAdd{
+		app:        ol.LoadApp(),
+		envName:    ol.LoadString(OptionEnvName),
+		kind:       ol.LoadString(OptionKind),
+		name:       ol.LoadString(OptionName),
+		apiVersion: ol.LoadOptionalString(OptionAPIVersion),
+		patchType:  ol.LoadOptionalString(OptionPatchType),
+
+		objectsFn:     renderEnvObjects,
+		createPatchFn: env.CreatePatch,
+	}
+
+	epa.guard = newEnvGuard(epa.app, ol.LoadOptionalString(OptionConfirmEnv))
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	if epa.patchType == "" {
+		epa.patchType = env.PatchTypeStrategic
+	}
+
+	return epa, nil
+}
+
+// Run scaffolds the patch. The object it targets is looked up in the rendered
+// environment, which fills in its apiVersion and namespace.
+func (epa *EnvPatchAdd) Run() error {
+	if err := epa.guard.check(epa.envName, "patch", false); err != nil {
+		return err
+	}
+
+	objects, err := epa.objectsFn(epa.app, epa.envName)
+	if err != nil {
+		return errors.Wrapf(err, "rendering environment %q", epa.envName)
+	}
+
+	var matches []*unstructured.Unstructured
+	for _, obj := range objects {
+		if !strings.EqualFold(obj.GetKind(), epa.kind) || obj.GetName() != epa.name {
+			continue
+		}
+
+		if epa.apiVersion != "" && obj.GetAPIVersion() != epa.apiVersion {
+			continue
+		}
+
+		matches = append(matches, obj)
+	}
+
+	switch len(matches) {
+	case 0:
+		return errors.Errorf("environment %q has no %s named %q", epa.envName, epa.kind, epa.name)
+	case 1:
+	default:
+		return errors.Errorf("environment %q has more than one %s named %q; select one with an api version",
+			epa.envName, epa.kind, epa.name)
+	}
+
+	obj := matches[0]
+	target := env.PatchTarget{
+		APIVersion: obj.GetAPIVersion(),
+		Kind:       obj.GetKind(),
+		Name:       obj.GetName(),
+		Namespace:  obj.GetNamespace(),
+	}
+
+	path, err := epa.createPatchFn(epa.app, epa.envName, target, epa.patchType)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Created patch %s", path)
+	return nil
+}
+
+// renderEnvObjects renders the objects of an environment.
+func renderEnvObjects(a app.App, envName string) ([]*unstructured.Unstructured, error) {
+	return pipeline.New(a, envName).Objects(nil)
+}
@@ -0,0 +1,157 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestEnvPatchAdd(t *testing.T) {
+	object := func(apiVersion, kind, name string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": apiVersion,
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name": name,
+			},
+		}}
+	}
+
+	objects := []*unstructured.Unstructured{
+		object("v1", "Service", "guestbook"),
+		object("apps/v1beta1", "Deployment", "guestbook"),
+		object("extensions/v1beta1", "Deployment", "guestbook"),
+	}
+
+	cases := []struct {
+		name       string
+		kind       string
+		apiVersion string
+		patchType  string
+		expected   env.PatchTarget
+		isErr      bool
+	}{
+		{
+			name:     "patch object",
+			kind:     "service",
+			expected: env.PatchTarget{APIVersion: "v1", Kind: "Service", Name: "guestbook"},
+		},
+		{
+			name:       "patch object with api version",
+			kind:       "Deployment",
+			apiVersion: "apps/v1beta1",
+			patchType:  env.PatchTypeJSON,
+			expected:   env.PatchTarget{APIVersion: "apps/v1beta1", Kind: "Deployment", Name: "guestbook"},
+		},
+		{
+			name:  "object matches multiple api versions",
+			kind:  "Deployment",
+			isErr: true,
+		},
+		{
+			name:  "object does not exist",
+			kind:  "ConfigMap",
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				appMock.On("Environment", "default").Return(&app.EnvironmentConfig{Name: "default"}, nil)
+
+				in := map[string]interface{}{
+					OptionApp:        appMock,
+					OptionEnvName:    "default",
+					OptionKind:       tc.kind,
+					OptionName:       "guestbook",
+					OptionAPIVersion: tc.apiVersion,
+					OptionPatchType:  tc.patchType,
+				}
+
+				a, err := NewEnvPatchAdd(in)
+				require.NoError(t, err)
+
+				a.objectsFn = func(_ app.App, envName string) ([]*unstructured.Unstructured, error) {
+					assert.Equal(t, "default", envName)
+					return objects, nil
+				}
+
+				var created bool
+				a.createPatchFn = func(_ app.App, envName string, target env.PatchTarget, patchType string) (string, error) {
+					created = true
+					assert.Equal(t, "default", envName)
+					assert.Equal(t, tc.expected, target)
+
+					expectedType := tc.patchType
+					if expectedType == "" {
+						expectedType = env.PatchTypeStrategic
+					}
+					assert.Equal(t, expectedType, patchType)
+					return "/environments/default/patches/patch.yaml", nil
+				}
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+
+				require.NoError(t, err)
+				assert.True(t, created)
+			})
+		})
+	}
+}
+
+func TestEnvPatchAdd_protected(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		appMock.On("Environment", "prod").Return(&app.EnvironmentConfig{Name: "prod", Protected: true}, nil)
+
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionEnvName: "prod",
+			OptionKind:    "service",
+			OptionName:    "guestbook",
+		}
+
+		a, err := NewEnvPatchAdd(in)
+		require.NoError(t, err)
+
+		a.guard.isTerminalFn = func() bool { return false }
+		a.createPatchFn = func(_ app.App, envName string, target env.PatchTarget, patchType string) (string, error) {
+			t.Fatal("patch created in a protected environment")
+			return "", nil
+		}
+
+		err = a.Run()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "--confirm-env=prod")
+	})
+}
+
+func TestEnvPatchAdd_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewEnvPatchAdd(in)
+	require.Error(t, err)
+}
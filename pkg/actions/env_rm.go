@@ -38,6 +38,7 @@ type EnvRm struct {
 	envName    string
 	isOverride bool
 
+	guard       *envGuard
 	envDeleteFn envDeleteFn
 }
 
@@ -53,6 +54,8 @@ func NewEnvRm(m map[string]interface{}) (*EnvRm, error) {
 		envDeleteFn: env.Delete,
 	}
 
+	ea.guard = newEnvGuard(ea.app, ol.LoadOptionalString(OptionConfirmEnv))
+
 	if ol.err != nil {
 		return nil, ol.err
 	}
@@ -62,6 +65,10 @@ func NewEnvRm(m map[string]interface{}) (*EnvRm, error) {
 
 // Run assigns targets to an environment.
 func (er *EnvRm) Run() error {
+	if err := er.guard.check(er.envName, "remove", false); err != nil {
+		return err
+	}
+
 	return er.envDeleteFn(
 		er.app,
 		er.envName,
@@ -29,6 +29,8 @@ func TestEnvRm(t *testing.T) {
 		aName := "my-app"
 		aIsOverride := false
 
+		appMock.On("Environment", aName).Return(&app.EnvironmentConfig{Name: aName}, nil)
+
 		in := map[string]interface{}{
 			OptionApp:      appMock,
 			OptionEnvName:  aName,
@@ -63,6 +63,7 @@ type EnvSet struct {
 	newAPISpec string
 	isOverride bool
 
+	guard       *envGuard
 	envRenameFn envRenameFn
 	saveFn      saveFn
 }
@@ -84,6 +85,8 @@ func NewEnvSet(m map[string]interface{}) (*EnvSet, error) {
 		saveFn:      save,
 	}
 
+	es.guard = newEnvGuard(es.app, ol.LoadOptionalString(OptionConfirmEnv))
+
 	if ol.err != nil {
 		return nil, ol.err
 	}
@@ -98,6 +101,10 @@ func (es *EnvSet) Run() error {
 		return err
 	}
 
+	if err = es.guard.check(es.envName, "change", false); err != nil {
+		return err
+	}
+
 	if err := es.updateName(es.isOverride); err != nil {
 		return err
 	}
@@ -146,7 +153,11 @@ func (es *EnvSet) updateEnvConfig(env app.EnvironmentConfig, namespace, server,
 		destination = &app.EnvironmentDestinationSpec{}
 	}
 	if server != "" {
+		// An explicit server replaces kubeconfig context based resolution.
 		destination.Server = server
+		destination.Context = ""
+		destination.Kubeconfig = ""
+		destination.InCluster = false
 	}
 	if namespace != "" {
 		destination.Namespace = namespace
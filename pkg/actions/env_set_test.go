@@ -191,6 +191,31 @@ func TestEnvSet(t *testing.T) {
 	})
 }
 
+func TestEnvSet_protected(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		appMock.On("Environment", "prod").Return(&app.EnvironmentConfig{Name: "prod", Protected: true}, nil)
+
+		in := map[string]interface{}{
+			OptionApp:        appMock,
+			OptionEnvName:    "prod",
+			OptionNewEnvName: "production",
+		}
+
+		a, err := NewEnvSet(in)
+		require.NoError(t, err)
+
+		a.guard.isTerminalFn = func() bool { return false }
+		a.envRenameFn = func(a app.App, from, to string, override bool) error {
+			t.Errorf("unexpected call: rename")
+			return nil
+		}
+
+		err = a.Run()
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `environment "prod" is protected`)
+	})
+}
+
 func TestEnvSet_requires_app(t *testing.T) {
 	in := make(map[string]interface{})
 	_, err := NewEnvSet(in)
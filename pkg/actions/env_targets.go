@@ -37,6 +37,7 @@ type EnvTargets struct {
 	modules    []string
 	cm         component.Manager
 	isOverride bool
+	guard      *envGuard
 }
 
 // NewEnvTargets creates an instance of EnvTargets.
@@ -52,6 +53,8 @@ func NewEnvTargets(m map[string]interface{}) (*EnvTargets, error) {
 		cm: component.DefaultManager,
 	}
 
+	et.guard = newEnvGuard(et.app, ol.LoadOptionalString(OptionConfirmEnv))
+
 	if ol.err != nil {
 		return nil, ol.err
 	}
@@ -66,6 +69,10 @@ func (et *EnvTargets) Run() error {
 		return err
 	}
 
+	if err = et.guard.check(et.envName, "change", false); err != nil {
+		return err
+	}
+
 	for _, module := range et.modules {
 		_, err := et.cm.Module(et.app, module)
 		if err != nil {
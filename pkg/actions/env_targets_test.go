@@ -27,6 +27,29 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+func TestEnvTargets_protected(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		env := &app.EnvironmentConfig{Name: "prod", Protected: true}
+		appMock.On("Environment", "prod").Return(env, nil)
+
+		in := map[string]interface{}{
+			OptionApp:      appMock,
+			OptionEnvName:  "prod",
+			OptionModule:   []string{"/"},
+			OptionOverride: false,
+		}
+
+		a, err := NewEnvTargets(in)
+		require.NoError(t, err)
+
+		a.guard.isTerminalFn = func() bool { return false }
+
+		err = a.Run()
+		require.Error(t, err)
+		appMock.AssertNotCalled(t, "UpdateTargets", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
 func TestEnvTargets(t *testing.T) {
 	withApp(t, func(appMock *amocks.App) {
 		envName := "default"
@@ -38,6 +38,7 @@ type EnvUpdate struct {
 	app     app.App
 	envName string
 
+	guard      *envGuard
 	httpClient *http.Client
 	genLibFn   func(app.App, string, string, *http.Client) error
 }
@@ -54,6 +55,8 @@ func newEnvUpdate(m map[string]interface{}) (*EnvUpdate, error) {
 		genLibFn:   genLib,
 	}
 
+	eu.guard = newEnvGuard(eu.app, ol.LoadOptionalString(OptionConfirmEnv))
+
 	if ol.err != nil {
 		return nil, ol.err
 	}
@@ -67,6 +70,10 @@ func (eu *EnvUpdate) run() error {
 		return err
 	}
 
+	if err = eu.guard.check(eu.envName, "update", false); err != nil {
+		return err
+	}
+
 	k8sSpecFlag := fmt.Sprintf("version:%s", envSpec.KubernetesVersion)
 
 	libPath, err := eu.app.LibPath(eu.envName)
@@ -29,13 +29,15 @@ import (
 
 	"github.com/ksonnet/ksonnet/metadata/params"
 	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
 	"github.com/ksonnet/ksonnet/pkg/component"
 	"github.com/ksonnet/ksonnet/pkg/prototype"
 	"github.com/ksonnet/ksonnet/pkg/schema"
-	utilstrings "github.com/ksonnet/ksonnet/pkg/util/strings"
 	utilyaml "github.com/ksonnet/ksonnet/pkg/util/yaml"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 // RunImport runs `import`
@@ -48,26 +50,57 @@ func RunImport(m map[string]interface{}) error {
 	return i.Run()
 }
 
-// Import imports files or directories into ksonnet.
+// Import imports files, directories or the objects of a cluster into ksonnet.
 type Import struct {
 	app    app.App
 	module string
 	path   string
 
+	nameTemplate string
+	groupBy      string
+	onCollision  string
+
+	fromCluster  string
+	clientConfig *client.Config
+	selector     string
+	kinds        []string
+	adopt        bool
+	gcTag        string
+
+	yamlObjects []*importedObject
+	names       map[string]bool
+
 	createComponentFn func(a app.App, module, name, text string, p params.Params, templateType prototype.TemplateType) (string, error)
+	exportFn          func(cluster.ExportConfig, ...cluster.ExportOpts) ([]*unstructured.Unstructured, error)
+	adoptFn           func(cluster.AdoptConfig, ...cluster.AdoptOpts) error
 }
 
 // NewImport creates an instance of Import. `module` is the name of the component and
-// entity is the file or directory to import.
+// entity is the file or directory to import. If `fromCluster` is set, the objects in
+// the namespace of that environment are imported instead.
 func NewImport(m map[string]interface{}) (*Import, error) {
 	ol := newOptionLoader(m)
 
 	i := &Import{
-		app:    ol.LoadApp(),
-		module: ol.LoadString(OptionModule),
-		path:   ol.LoadString(OptionPath),
+		app:          ol.LoadApp(),
+		module:       ol.LoadString(OptionModule),
+		path:         ol.LoadOptionalString(OptionPath),
+		nameTemplate: ol.LoadOptionalString(OptionNameTemplate),
+		groupBy:      ol.LoadOptionalString(OptionGroupBy),
+		onCollision:  ol.LoadOptionalString(OptionOnCollision),
+		fromCluster:  ol.LoadOptionalString(OptionFromCluster),
+		selector:     ol.LoadOptionalString(OptionSelector),
+		kinds:        ol.LoadOptionalStringSlice(OptionKinds),
+		adopt:        ol.LoadOptionalBool(OptionAdopt),
+		gcTag:        ol.LoadOptionalString(OptionGcTag),
 
 		createComponentFn: component.Create,
+		exportFn:          cluster.RunExport,
+		adoptFn:           cluster.RunAdopt,
+	}
+
+	if i.fromCluster != "" {
+		i.clientConfig = ol.LoadClientConfig()
 	}
 
 	if ol.err != nil {
@@ -79,15 +112,33 @@ func NewImport(m map[string]interface{}) (*Import, error) {
 
 // Run runs the import process.
 func (i *Import) Run() error {
+	if err := i.validateNaming(); err != nil {
+		return err
+	}
+
+	if i.fromCluster != "" {
+		if i.path != "" {
+			return errors.New("path and cluster can not be imported at the same time")
+		}
+		return i.handleCluster()
+	}
+
 	if i.path == "" {
 		return errors.New("path is required")
 	}
 
+	var err error
 	if strings.HasPrefix(i.path, "http") {
-		return i.handleURL()
+		err = i.handleURL()
+	} else {
+		err = i.handleLocal()
 	}
 
-	return i.handleLocal()
+	if err != nil {
+		return err
+	}
+
+	return i.createYAMLComponents()
 }
 
 func (i *Import) handleURL() error {
@@ -199,17 +250,18 @@ func (i *Import) importFile(fileName string) error {
 	default:
 		return errors.Errorf("unable to handle components of type %s", templateType)
 	case prototype.YAML:
-		return i.createYAML(fileName, base, ext)
+		return i.readYAML(fileName, base, ext)
 	case prototype.JSON, prototype.Jsonnet:
 		return i.createComponent(fileName, base, ext, templateType)
 	}
 }
 
-func (i *Import) createYAML(fileName, base, ext string) error {
+func (i *Import) readYAML(fileName, base, ext string) error {
 	f, err := i.app.Fs().Open(fileName)
 	if err != nil {
 		return errors.Wrapf(err, "opening %q", fileName)
 	}
+	defer f.Close()
 
 	readers, err := utilyaml.Decode(f)
 	if err != nil {
@@ -241,10 +293,27 @@ func (i *Import) createYAML(fileName, base, ext string) error {
 			return errors.Errorf("unable to find metadata name of object in %s", fileName)
 		}
 
-		componentName := fmt.Sprintf("%s-%s-%s", strings.ToLower(ts.Kind()), name, utilstrings.LowerRand(5))
-		if err = i.createComponentFromData(componentName, string(data), prototype.YAML); err != nil {
-			return err
+		obj := &importedObject{
+			file: strings.TrimSuffix(base, ext),
+			kind: ts.RawKind,
+			name: name,
+			data: data,
 		}
+
+		if val, err := props.Value([]string{"metadata", "namespace"}); err == nil {
+			obj.namespace, _ = val.(string)
+		}
+
+		if val, err := props.Value([]string{"metadata", "labels"}); err == nil {
+			if labels, ok := val.(map[interface{}]interface{}); ok {
+				obj.labels = make(map[string]string)
+				for k, v := range labels {
+					obj.labels[fmt.Sprintf("%v", k)] = fmt.Sprintf("%v", v)
+				}
+			}
+		}
+
+		i.yamlObjects = append(i.yamlObjects, obj)
 	}
 
 	return nil
@@ -0,0 +1,334 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/prototype"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+var (
+	// appLabels are the labels which name the application of an object, in
+	// order of preference.
+	appLabels = []string{"app.kubernetes.io/name", "app", "k8s-app"}
+
+	// workloadKinds are kinds which run pods from a pod template.
+	workloadKinds = map[string][]string{
+		"CronJob":     {"spec", "jobTemplate", "spec", "template"},
+		"DaemonSet":   {"spec", "template"},
+		"Deployment":  {"spec", "template"},
+		"Job":         {"spec", "template"},
+		"ReplicaSet":  {"spec", "template"},
+		"StatefulSet": {"spec", "template"},
+	}
+
+	reInvalidComponentChars = regexp.MustCompile(`[^a-z0-9-]+`)
+)
+
+// clusterComponent is a group of related cluster objects imported as a
+// single component.
+type clusterComponent struct {
+	name    string
+	objects []*unstructured.Unstructured
+}
+
+// handleCluster imports the objects of an environment's namespace.
+func (i *Import) handleCluster() error {
+	objects, err := i.exportFn(cluster.ExportConfig{
+		App:          i.app,
+		ClientConfig: i.clientConfig,
+		EnvName:      i.fromCluster,
+		Selector:     i.selector,
+		Kinds:        i.kinds,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(objects) == 0 {
+		log.Warnf("No objects to import were found in environment %q", i.fromCluster)
+		return nil
+	}
+
+	var adopted []cluster.AdoptedObject
+	for _, c := range groupClusterObjects(objects) {
+		data, templateType, err := clusterComponentData(c)
+		if err != nil {
+			return errors.Wrapf(err, "generating component %s", c.name)
+		}
+
+		if c.name, err = i.uniqueName(c.name); err != nil {
+			return err
+		}
+
+		if err = i.createComponentFromData(c.name, data, templateType); err != nil {
+			return err
+		}
+
+		for _, obj := range c.objects {
+			adopted = append(adopted, cluster.AdoptedObject{Object: obj, Component: c.name})
+		}
+	}
+
+	if !i.adopt {
+		return nil
+	}
+
+	return i.adoptFn(cluster.AdoptConfig{
+		App:          i.app,
+		ClientConfig: i.clientConfig,
+		EnvName:      i.fromCluster,
+		GcTag:        i.gcTag,
+		Objects:      adopted,
+	})
+}
+
+// groupClusterObjects groups objects which belong to the same application.
+// Objects are grouped by their application label. Services without the
+// label join the workload their selector matches, and config objects join
+// the workload which references them. The remaining objects are imported
+// by themselves.
+func groupClusterObjects(objects []*unstructured.Unstructured) []*clusterComponent {
+	groups := make(map[string]*clusterComponent)
+	var order []string
+	assigned := make(map[*unstructured.Unstructured]string)
+
+	add := func(name string, obj *unstructured.Unstructured) {
+		c, ok := groups[name]
+		if !ok {
+			c = &clusterComponent{name: name}
+			groups[name] = c
+			order = append(order, name)
+		}
+		c.objects = append(c.objects, obj)
+		assigned[obj] = name
+	}
+
+	for _, obj := range objects {
+		if name := appName(obj); name != "" {
+			add(componentName(name), obj)
+		}
+	}
+
+	for _, obj := range objects {
+		if _, ok := assigned[obj]; ok || obj.GetKind() != "Service" {
+			continue
+		}
+
+		selector, _, _ := unstructured.NestedStringMap(obj.Object, "spec", "selector")
+		if len(selector) == 0 {
+			continue
+		}
+
+		for _, w := range objects {
+			name, ok := assigned[w]
+			if ok && matchesSelector(selector, templateLabels(w)) {
+				add(name, obj)
+				break
+			}
+		}
+	}
+
+	for _, w := range objects {
+		name, ok := assigned[w]
+		if !ok {
+			continue
+		}
+
+		for _, ref := range podSpecReferences(w) {
+			for _, obj := range objects {
+				if _, ok := assigned[obj]; ok {
+					continue
+				}
+				if obj.GetKind() == ref.kind && obj.GetName() == ref.name {
+					add(name, obj)
+				}
+			}
+		}
+	}
+
+	for _, obj := range objects {
+		if _, ok := assigned[obj]; !ok {
+			add(componentName(fmt.Sprintf("%s-%s", strings.ToLower(obj.GetKind()), obj.GetName())), obj)
+		}
+	}
+
+	var out []*clusterComponent
+	for _, name := range order {
+		out = append(out, groups[name])
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return out[i].name < out[j].name
+	})
+
+	return out
+}
+
+// appName returns the application an object belongs to.
+func appName(obj *unstructured.Unstructured) string {
+	for _, labels := range []map[string]string{obj.GetLabels(), templateLabels(obj)} {
+		for _, key := range appLabels {
+			if v := labels[key]; v != "" {
+				return v
+			}
+		}
+	}
+
+	return ""
+}
+
+func templateLabels(obj *unstructured.Unstructured) map[string]string {
+	path, ok := workloadKinds[obj.GetKind()]
+	if !ok {
+		return nil
+	}
+
+	labels, _, _ := unstructured.NestedStringMap(obj.Object, append(append([]string{}, path...), "metadata", "labels")...)
+	return labels
+}
+
+func matchesSelector(selector, labels map[string]string) bool {
+	if len(labels) == 0 {
+		return false
+	}
+
+	for k, v := range selector {
+		if labels[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+type objectReference struct {
+	kind string
+	name string
+}
+
+// podSpecReferences returns the objects referenced by the pod template of a workload.
+func podSpecReferences(obj *unstructured.Unstructured) []objectReference {
+	path, ok := workloadKinds[obj.GetKind()]
+	if !ok {
+		return nil
+	}
+
+	spec, ok, _ := unstructured.NestedMap(obj.Object, append(append([]string{}, path...), "spec")...)
+	if !ok {
+		return nil
+	}
+
+	var refs []objectReference
+	ref := func(kind string, m interface{}, fields ...string) {
+		if o, ok := m.(map[string]interface{}); ok {
+			if name, ok, _ := unstructured.NestedString(o, fields...); ok && name != "" {
+				refs = append(refs, objectReference{kind: kind, name: name})
+			}
+		}
+	}
+
+	ref("ServiceAccount", spec, "serviceAccountName")
+
+	for _, s := range nestedSlice(spec, "imagePullSecrets") {
+		ref("Secret", s, "name")
+	}
+
+	for _, v := range nestedSlice(spec, "volumes") {
+		ref("ConfigMap", v, "configMap", "name")
+		ref("Secret", v, "secret", "secretName")
+		ref("PersistentVolumeClaim", v, "persistentVolumeClaim", "claimName")
+	}
+
+	for _, field := range []string{"initContainers", "containers"} {
+		for _, c := range nestedSlice(spec, field) {
+			cm, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+
+			for _, e := range nestedSlice(cm, "envFrom") {
+				ref("ConfigMap", e, "configMapRef", "name")
+				ref("Secret", e, "secretRef", "name")
+			}
+			for _, e := range nestedSlice(cm, "env") {
+				ref("ConfigMap", e, "valueFrom", "configMapKeyRef", "name")
+				ref("Secret", e, "valueFrom", "secretKeyRef", "name")
+			}
+		}
+	}
+
+	return refs
+}
+
+func nestedSlice(m map[string]interface{}, field string) []interface{} {
+	s, _ := m[field].([]interface{})
+	return s
+}
+
+// componentName converts a name to a valid component name.
+func componentName(name string) string {
+	name = reInvalidComponentChars.ReplaceAllString(strings.ToLower(name), "-")
+	return strings.Trim(name, "-")
+}
+
+// clusterComponentData generates the source of a component. A single object is
+// imported as YAML. Multiple objects are imported as a Jsonnet list.
+func clusterComponentData(c *clusterComponent) (string, prototype.TemplateType, error) {
+	var objects []interface{}
+	for _, obj := range c.objects {
+		objects = append(objects, cluster.CleanObject(obj).Object)
+	}
+
+	if len(objects) == 1 {
+		data, err := yaml.Marshal(objects[0])
+		if err != nil {
+			return "", "", err
+		}
+
+		return string(data), prototype.YAML, nil
+	}
+
+	list := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      objects,
+	}
+
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return "", "", err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("local env = std.extVar(\"__ksonnet/environments\");\n")
+	fmt.Fprintf(&buf, "local params = std.extVar(\"__ksonnet/params\").components[%q];\n\n", c.name)
+	buf.Write(data)
+	buf.WriteString("\n")
+
+	return buf.String(), prototype.Jsonnet, nil
+}
@@ -16,20 +16,25 @@
 package actions
 
 import (
+	"encoding/json"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/ksonnet/ksonnet/metadata/params"
 	"github.com/ksonnet/ksonnet/pkg/app"
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
 	"github.com/ksonnet/ksonnet/pkg/prototype"
 	"github.com/stretchr/testify/require"
 )
@@ -61,7 +66,7 @@ func TestImport_http(t *testing.T) {
 		require.NoError(t, err)
 
 		a.createComponentFn = func(_ app.App, moduleName, name, text string, p params.Params, templateType prototype.TemplateType) (string, error) {
-			assert.Contains(t, name, "service-my-service-")
+			assert.Equal(t, "service-my-service", name)
 			assert.Equal(t, "", moduleName)
 			assert.Equal(t, string(serviceData), text)
 			assert.Equal(t, params.Params{}, p)
@@ -97,7 +102,7 @@ func TestImport_yaml_file(t *testing.T) {
 		require.NoError(t, err)
 
 		a.createComponentFn = func(_ app.App, moduleName, name, text string, p params.Params, templateType prototype.TemplateType) (string, error) {
-			assert.Contains(t, name, "service-my-service-")
+			assert.Equal(t, "service-my-service", name)
 			assert.Equal(t, "", moduleName)
 			assert.Equal(t, string(serviceData), text)
 			assert.Equal(t, params.Params{}, p)
@@ -193,7 +198,7 @@ func TestImport_directory(t *testing.T) {
 		require.NoError(t, err)
 
 		a.createComponentFn = func(_ app.App, moduleName, name, text string, p params.Params, templateType prototype.TemplateType) (string, error) {
-			assert.Contains(t, name, "service-my-service-")
+			assert.Equal(t, "service-my-service", name)
 			assert.Equal(t, "", moduleName)
 			assert.Equal(t, string(serviceData), text)
 			assert.Equal(t, params.Params{}, p)
@@ -235,3 +240,228 @@ func TestImport_requires_app(t *testing.T) {
 	_, err := NewImport(in)
 	require.Error(t, err)
 }
+
+func TestImport_from_cluster(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		data, err := ioutil.ReadFile(filepath.Join("testdata", "import", "cluster", "objects.json"))
+		require.NoError(t, err)
+
+		var items []map[string]interface{}
+		require.NoError(t, json.Unmarshal(data, &items))
+
+		var objects []*unstructured.Unstructured
+		for _, item := range items {
+			objects = append(objects, &unstructured.Unstructured{Object: item})
+		}
+
+		in := map[string]interface{}{
+			OptionApp:          appMock,
+			OptionModule:       "/",
+			OptionFromCluster:  "default",
+			OptionClientConfig: &client.Config{},
+			OptionSelector:     "tier=frontend",
+			OptionKinds:        []string{"deployment"},
+			OptionAdopt:        true,
+			OptionGcTag:        "tag",
+		}
+
+		a, err := NewImport(in)
+		require.NoError(t, err)
+
+		a.exportFn = func(config cluster.ExportConfig, opts ...cluster.ExportOpts) ([]*unstructured.Unstructured, error) {
+			assert.Equal(t, "default", config.EnvName)
+			assert.Equal(t, "tier=frontend", config.Selector)
+			assert.Equal(t, []string{"deployment"}, config.Kinds)
+			return objects, nil
+		}
+
+		created := make(map[string]prototype.TemplateType)
+		a.createComponentFn = func(_ app.App, moduleName, name, text string, p params.Params, templateType prototype.TemplateType) (string, error) {
+			created[name] = templateType
+			ext := "jsonnet"
+			if templateType == prototype.YAML {
+				ext = "yaml"
+			}
+			assertOutput(t, filepath.Join("import", "cluster", name+"."+ext), text)
+			return "/", nil
+		}
+
+		var adopted []string
+		a.adoptFn = func(config cluster.AdoptConfig, opts ...cluster.AdoptOpts) error {
+			assert.Equal(t, "tag", config.GcTag)
+			for _, ao := range config.Objects {
+				adopted = append(adopted, ao.Component+"/"+ao.Object.GetKind()+"/"+ao.Object.GetName())
+			}
+			return nil
+		}
+
+		err = a.Run()
+		require.NoError(t, err)
+
+		expectedCreated := map[string]prototype.TemplateType{
+			"guestbook":          prototype.Jsonnet,
+			"secret-db-password": prototype.YAML,
+		}
+		assert.Equal(t, expectedCreated, created)
+
+		expectedAdopted := []string{
+			"guestbook/Deployment/guestbook-ui",
+			"guestbook/Service/guestbook-ui",
+			"guestbook/ConfigMap/guestbook-config",
+			"secret-db-password/Secret/db.password",
+		}
+		assert.Equal(t, expectedAdopted, adopted)
+	})
+}
+
+func TestImport_path_and_cluster(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:          appMock,
+			OptionModule:       "/",
+			OptionPath:         "/file.yaml",
+			OptionFromCluster:  "default",
+			OptionClientConfig: &client.Config{},
+		}
+
+		a, err := NewImport(in)
+		require.NoError(t, err)
+
+		err = a.Run()
+		require.Error(t, err)
+	})
+}
+
+func TestImport_yaml_naming(t *testing.T) {
+	cases := []struct {
+		name         string
+		nameTemplate string
+		groupBy      string
+		onCollision  string
+		existing     []string
+		expected     map[string]int
+		isErr        bool
+	}{
+		{
+			name: "one component per object",
+			expected: map[string]int{
+				"deployment-guestbook-ui": 1,
+				"service-guestbook-ui":    1,
+				"configmap-settings-v1":   1,
+			},
+		},
+		{
+			name:         "name template",
+			nameTemplate: `{{.Namespace}}-{{.Name}}-{{.Kind | lower}}`,
+			expected: map[string]int{
+				"web-guestbook-ui-deployment": 1,
+				"guestbook-ui-service":        1,
+				"settings-v1-configmap":       1,
+			},
+		},
+		{
+			name:    "group by file",
+			groupBy: "file",
+			expected: map[string]int{
+				"guestbook": 3,
+			},
+		},
+		{
+			name:    "group by app label",
+			groupBy: "app-label",
+			expected: map[string]int{
+				"guestbook":             2,
+				"configmap-settings-v1": 1,
+			},
+		},
+		{
+			name:         "group by kind",
+			groupBy:      "kind",
+			nameTemplate: `{{.Group}}s`,
+			expected: map[string]int{
+				"deployments": 1,
+				"services":    1,
+				"configmaps":  1,
+			},
+		},
+		{
+			name:         "empty name",
+			nameTemplate: `{{index .Labels "app"}}`,
+			isErr:        true,
+		},
+		{
+			name:         "collision within import",
+			nameTemplate: `{{.Name}}`,
+			onCollision:  "suffix",
+			expected: map[string]int{
+				"guestbook-ui":   1,
+				"guestbook-ui-2": 1,
+				"settings-v1":    1,
+			},
+		},
+		{
+			name:     "collision fails",
+			existing: []string{"service-guestbook-ui.yaml"},
+			isErr:    true,
+		},
+		{
+			name:        "collision suffix",
+			onCollision: "suffix",
+			existing:    []string{"service-guestbook-ui.yaml", "service-guestbook-ui-2.jsonnet"},
+			expected: map[string]int{
+				"deployment-guestbook-ui": 1,
+				"service-guestbook-ui-3":  1,
+				"configmap-settings-v1":   1,
+			},
+		},
+		{
+			name:    "invalid group by",
+			groupBy: "namespace",
+			isErr:   true,
+		},
+		{
+			name:        "invalid collision handling",
+			onCollision: "ignore",
+			isErr:       true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				stageFile(t, appMock.Fs(), "import/guestbook.yaml", "/guestbook.yaml")
+				for _, name := range tc.existing {
+					stageFile(t, appMock.Fs(), "import/file.yaml", filepath.Join("/components", name))
+				}
+
+				in := map[string]interface{}{
+					OptionApp:          appMock,
+					OptionModule:       "/",
+					OptionPath:         "/guestbook.yaml",
+					OptionNameTemplate: tc.nameTemplate,
+					OptionGroupBy:      tc.groupBy,
+					OptionOnCollision:  tc.onCollision,
+				}
+
+				a, err := NewImport(in)
+				require.NoError(t, err)
+
+				created := make(map[string]int)
+				a.createComponentFn = func(_ app.App, moduleName, name, text string, p params.Params, templateType prototype.TemplateType) (string, error) {
+					assert.Equal(t, prototype.YAML, templateType)
+					created[name] = len(strings.Split(text, "---\n"))
+					return "/", nil
+				}
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, created)
+			})
+		})
+	}
+}
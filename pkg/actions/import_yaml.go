@@ -0,0 +1,234 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/prototype"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+const (
+	// ImportGroupByFile groups the objects of each imported file into a component.
+	ImportGroupByFile = "file"
+	// ImportGroupByAppLabel groups imported objects by their application label.
+	ImportGroupByAppLabel = "app-label"
+	// ImportGroupByKind groups imported objects by their kind.
+	ImportGroupByKind = "kind"
+
+	// ImportOnCollisionFail fails the import if a component name is taken.
+	ImportOnCollisionFail = "fail"
+	// ImportOnCollisionSuffix adds a numeric suffix to component names which are taken.
+	ImportOnCollisionSuffix = "suffix"
+
+	defaultImportNameTemplate = "{{.Group}}"
+)
+
+// importedObject is an object read from an imported YAML file.
+type importedObject struct {
+	file      string
+	kind      string
+	namespace string
+	name      string
+	labels    map[string]string
+	data      []byte
+}
+
+// importNameData is the data available to component name templates.
+type importNameData struct {
+	// Group is the name of the group of objects: the file, application label or
+	// kind they are grouped by, or `<kind>-<name>` for ungrouped objects.
+	Group     string
+	File      string
+	Kind      string
+	Namespace string
+	Name      string
+	Labels    map[string]string
+}
+
+var importTemplateFuncs = template.FuncMap{
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+func (i *Import) validateNaming() error {
+	switch i.groupBy {
+	case "", ImportGroupByFile, ImportGroupByAppLabel, ImportGroupByKind:
+	default:
+		return errors.Errorf("invalid group by %q; valid options are %s, %s and %s",
+			i.groupBy, ImportGroupByFile, ImportGroupByAppLabel, ImportGroupByKind)
+	}
+
+	switch i.onCollision {
+	case "", ImportOnCollisionFail, ImportOnCollisionSuffix:
+	default:
+		return errors.Errorf("invalid collision handling %q; valid options are %s and %s",
+			i.onCollision, ImportOnCollisionFail, ImportOnCollisionSuffix)
+	}
+
+	_, err := i.parseNameTemplate()
+	return err
+}
+
+func (i *Import) parseNameTemplate() (*template.Template, error) {
+	text := i.nameTemplate
+	if text == "" {
+		text = defaultImportNameTemplate
+	}
+
+	t, err := template.New("name").Funcs(importTemplateFuncs).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing name template")
+	}
+
+	return t, nil
+}
+
+// createYAMLComponents creates components for the imported YAML objects. Objects
+// in the same group are created as a single multi-document YAML component.
+func (i *Import) createYAMLComponents() error {
+	t, err := i.parseNameTemplate()
+	if err != nil {
+		return err
+	}
+
+	var groups []string
+	members := make(map[string][]*importedObject)
+	for _, obj := range i.yamlObjects {
+		group := i.importGroup(obj)
+		if _, ok := members[group]; !ok {
+			groups = append(groups, group)
+		}
+		members[group] = append(members[group], obj)
+	}
+
+	for _, group := range groups {
+		objects := members[group]
+		first := objects[0]
+
+		var buf bytes.Buffer
+		err := t.Execute(&buf, importNameData{
+			Group:     group,
+			File:      first.file,
+			Kind:      first.kind,
+			Namespace: first.namespace,
+			Name:      first.name,
+			Labels:    first.labels,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "generating name for %s %s", first.kind, first.name)
+		}
+
+		name, err := i.uniqueName(componentName(buf.String()))
+		if err != nil {
+			return err
+		}
+
+		var docs [][]byte
+		for _, obj := range objects {
+			docs = append(docs, obj.data)
+		}
+
+		data := bytes.Join(docs, []byte("---\n"))
+		if err = i.createComponentFromData(name, string(data), prototype.YAML); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// importGroup returns the group of an imported object.
+func (i *Import) importGroup(obj *importedObject) string {
+	switch i.groupBy {
+	case ImportGroupByFile:
+		return obj.file
+	case ImportGroupByKind:
+		return strings.ToLower(obj.kind)
+	case ImportGroupByAppLabel:
+		for _, key := range appLabels {
+			if v := obj.labels[key]; v != "" {
+				return v
+			}
+		}
+	}
+
+	return fmt.Sprintf("%s-%s", strings.ToLower(obj.kind), obj.name)
+}
+
+// uniqueName returns a component name which is not used by an existing component
+// or by a component created by this import.
+func (i *Import) uniqueName(name string) (string, error) {
+	if name == "" {
+		return "", errors.New("component name is empty")
+	}
+
+	if i.names == nil {
+		names, err := i.existingComponentNames()
+		if err != nil {
+			return "", err
+		}
+		i.names = names
+	}
+
+	unique := name
+	for n := 2; i.names[unique]; n++ {
+		if i.onCollision != ImportOnCollisionSuffix {
+			return "", errors.Errorf("component %q already exists; use --on-collision=%s to add a suffix",
+				name, ImportOnCollisionSuffix)
+		}
+		unique = fmt.Sprintf("%s-%d", name, n)
+	}
+
+	i.names[unique] = true
+	return unique, nil
+}
+
+func (i *Import) existingComponentNames() (map[string]bool, error) {
+	names := make(map[string]bool)
+
+	var moduleName string
+	switch i.module {
+	case "", "/":
+	default:
+		moduleName = i.module
+	}
+
+	fis, err := afero.ReadDir(i.app.Fs(), component.NewModule(i.app, moduleName).Dir())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return names, nil
+		}
+		return nil, err
+	}
+
+	for _, fi := range fis {
+		if fi.IsDir() || fi.Name() == "params.libsonnet" {
+			continue
+		}
+		names[strings.TrimSuffix(fi.Name(), filepath.Ext(fi.Name()))] = true
+	}
+
+	return names, nil
+}
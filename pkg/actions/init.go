@@ -126,11 +126,16 @@ func (i *Init) Run() error {
 func initIncubator(a app.App, httpClient *http.Client) (registry.Registry, error) {
 	gh := github.NewGitHub(httpClient)
 
-	return registry.NewGitHub(
+	r, err := registry.NewGitHub(
 		a,
 		&app.RegistryConfig{
 			Name:     "incubator",
 			Protocol: string(registry.ProtocolGitHub),
 			URI:      defaultIncubatorURI,
 		}, registry.GitHubClient(gh))
+	if err != nil {
+		return nil, err
+	}
+
+	return registry.WithMirror(a, r)
 }
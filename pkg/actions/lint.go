@@ -0,0 +1,159 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/lint"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+)
+
+// RunLint runs `lint`.
+func RunLint(m map[string]interface{}) error {
+	l, err := NewLint(m)
+	if err != nil {
+		return err
+	}
+
+	return l.Run()
+}
+
+type linter interface {
+	Rules() ([]lint.Rule, error)
+	Lint(rules []lint.Rule) ([]lint.Violation, error)
+}
+
+// Lint checks the objects of an environment against lint rules.
+type Lint struct {
+	app        app.App
+	envName    string
+	outputType string
+
+	out      io.Writer
+	linterFn func(a app.App, envName string) linter
+}
+
+// NewLint creates an instance of Lint.
+func NewLint(m map[string]interface{}) (*Lint, error) {
+	ol := newOptionLoader(m)
+
+	l := &Lint{
+		app:        ol.LoadApp(),
+		outputType: ol.LoadOptionalString(OptionOutput),
+
+		out: os.Stdout,
+		linterFn: func(a app.App, envName string) linter {
+			return lint.New(a, envName)
+		},
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	if err := setCurrentEnv(l.app, l, ol); err != nil {
+		return nil, err
+	}
+
+	return l, nil
+}
+
+// Run lints the objects of the environment. It returns an error if any
+// violation has the error severity.
+func (l *Lint) Run() error {
+	if l.outputType != OutputSARIF {
+		if _, err := table.DetectFormat(l.outputType); err != nil {
+			return errors.Wrap(err, "detecting output format")
+		}
+	}
+
+	linter := l.linterFn(l.app, l.envName)
+
+	rules, err := linter.Rules()
+	if err != nil {
+		return err
+	}
+
+	violations, err := linter.Lint(rules)
+	if err != nil {
+		return err
+	}
+
+	switch l.outputType {
+	case OutputSARIF:
+		err = lint.WriteSARIF(l.out, rules, violations)
+	case OutputJSON:
+		err = l.printJSON(violations)
+	default:
+		err = l.printTable(violations)
+	}
+	if err != nil {
+		return err
+	}
+
+	var errorCount int
+	for _, v := range violations {
+		if v.Severity == lint.SeverityError {
+			errorCount++
+		}
+	}
+
+	if errorCount > 0 {
+		return errors.Errorf("lint found %d errors", errorCount)
+	}
+
+	return nil
+}
+
+func (l *Lint) printJSON(violations []lint.Violation) error {
+	if violations == nil {
+		violations = []lint.Violation{}
+	}
+
+	b, err := json.MarshalIndent(violations, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(l.out, string(b))
+	return err
+}
+
+func (l *Lint) printTable(violations []lint.Violation) error {
+	if len(violations) == 0 {
+		_, err := fmt.Fprintf(l.out, "No lint violations in environment %q\n", l.envName)
+		return err
+	}
+
+	t := table.New("lint", l.out)
+	t.SetHeader([]string{"severity", "rule", "component", "object", "message"})
+
+	for _, v := range violations {
+		t.Append([]string{string(v.Severity), v.Rule, v.Component, v.ObjectName(), v.Message})
+	}
+
+	return t.Render()
+}
+
+func (l *Lint) setCurrentEnv(name string) {
+	l.envName = name
+}
@@ -0,0 +1,165 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/lint"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLinter struct {
+	rules      []lint.Rule
+	violations []lint.Violation
+	err        error
+}
+
+func (l *fakeLinter) Rules() ([]lint.Rule, error) {
+	return l.rules, nil
+}
+
+func (l *fakeLinter) Lint([]lint.Rule) ([]lint.Violation, error) {
+	return l.violations, l.err
+}
+
+func TestLint(t *testing.T) {
+	rules := []lint.Rule{
+		{Name: "host-path", Description: "Pods must not mount hostPath volumes", Severity: lint.SeverityError},
+		{Name: "probes", Description: "Containers of workloads must have readiness and liveness probes", Severity: lint.SeverityWarning},
+	}
+
+	violations := []lint.Violation{
+		{
+			Rule:      "host-path",
+			Severity:  lint.SeverityError,
+			Component: "agent",
+			Source:    "components/agent.jsonnet",
+			Kind:      "DaemonSet",
+			Namespace: "kube-system",
+			Name:      "agent",
+			Message:   `volume "docker" mounts host path "/var/run/docker.sock"`,
+		},
+		{
+			Rule:      "probes",
+			Severity:  lint.SeverityWarning,
+			Component: "web",
+			Source:    "components/web.jsonnet",
+			Kind:      "Deployment",
+			Name:      "web",
+			Message:   `container "web" does not have a livenessProbe`,
+		},
+	}
+
+	cases := []struct {
+		name         string
+		output       string
+		violations   []lint.Violation
+		lintErr      error
+		expectedFile string
+		isErr        bool
+	}{
+		{
+			name:         "table output",
+			violations:   violations,
+			expectedFile: "lint/output.txt",
+			isErr:        true,
+		},
+		{
+			name:         "json output",
+			output:       "json",
+			violations:   violations[1:],
+			expectedFile: "lint/output.json",
+		},
+		{
+			name:         "sarif output",
+			output:       "sarif",
+			violations:   violations[1:],
+			expectedFile: "lint/output.sarif",
+		},
+		{
+			name:         "no violations",
+			expectedFile: "lint/none.txt",
+		},
+		{
+			name:    "lint error",
+			lintErr: errors.New("failed"),
+			isErr:   true,
+		},
+		{
+			name:   "invalid output",
+			output: "xml",
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:     appMock,
+					OptionEnvName: "default",
+					OptionOutput:  tc.output,
+				}
+
+				a, err := NewLint(in)
+				require.NoError(t, err)
+
+				a.linterFn = func(_ app.App, envName string) linter {
+					require.Equal(t, "default", envName)
+					return &fakeLinter{rules: rules, violations: tc.violations, err: tc.lintErr}
+				}
+
+				var buf bytes.Buffer
+				a.out = &buf
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+
+				if tc.expectedFile != "" {
+					assertOutput(t, tc.expectedFile, buf.String())
+				}
+			})
+		})
+	}
+}
+
+func TestLint_current_env(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		appMock.On("CurrentEnvironment").Return("")
+
+		in := map[string]interface{}{
+			OptionApp: appMock,
+		}
+
+		_, err := NewLint(in)
+		require.Error(t, err)
+	})
+}
+
+func TestLint_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewLint(in)
+	require.Error(t, err)
+}
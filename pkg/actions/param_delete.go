@@ -46,6 +46,7 @@ type ParamDelete struct {
 	global  bool
 	envName string
 
+	guard             *envGuard
 	deleteEnvFn       deleteEnvFn
 	deleteEnvGlobalFn deleteEnvGlobalFn
 	getModuleFn       getModuleFn
@@ -69,6 +70,8 @@ func NewParamDelete(m map[string]interface{}) (*ParamDelete, error) {
 		getModuleFn:       component.GetModule,
 	}
 
+	pd.guard = newEnvGuard(pd.app, ol.LoadOptionalString(OptionConfirmEnv))
+
 	if ol.err != nil {
 		return nil, ol.err
 	}
@@ -83,6 +86,10 @@ func NewParamDelete(m map[string]interface{}) (*ParamDelete, error) {
 // Run runs the action.
 func (pd *ParamDelete) Run() error {
 	if pd.envName != "" {
+		if err := pd.guard.check(pd.envName, "change", false); err != nil {
+			return err
+		}
+
 		if pd.name != "" {
 			return pd.deleteEnvFn(pd.app, pd.envName, pd.name, pd.rawPath)
 		}
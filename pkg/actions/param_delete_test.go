@@ -114,6 +114,8 @@ func TestParamDelete_env(t *testing.T) {
 		name := "deployment"
 		path := "replicas"
 
+		appMock.On("Environment", "default").Return(&app.EnvironmentConfig{Name: "default"}, nil)
+
 		in := map[string]interface{}{
 			OptionApp:     appMock,
 			OptionName:    name,
@@ -141,6 +143,8 @@ func TestParamDelete_env_global(t *testing.T) {
 	withApp(t, func(appMock *amocks.App) {
 		path := "replicas"
 
+		appMock.On("Environment", "default").Return(&app.EnvironmentConfig{Name: "default"}, nil)
+
 		in := map[string]interface{}{
 			OptionApp:     appMock,
 			OptionPath:    path,
@@ -146,7 +146,12 @@ func (pd *ParamDiff) checkMissing(env1, env2 []component.ModuleParameter) [][]st
 }
 
 func (pd *ParamDiff) moduleParams(envName string) ([]component.ModuleParameter, error) {
-	modules, err := pd.modulesFromEnvFn(pd.app, envName)
+	return envModuleParams(pd.app, pd.modulesFromEnvFn, envName)
+}
+
+// envModuleParams returns the parameters of all modules in an environment.
+func envModuleParams(a app.App, modulesFromEnvFn func(app.App, string) ([]component.Module, error), envName string) ([]component.ModuleParameter, error) {
+	modules, err := modulesFromEnvFn(a, envName)
 	if err != nil {
 		return nil, err
 	}
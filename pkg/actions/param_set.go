@@ -48,6 +48,7 @@ type ParamSet struct {
 	asString     bool
 	resolveImage bool
 
+	guard          *envGuard
 	getModuleFn    getModuleFn
 	resolvePathFn  func(a app.App, path string) (component.Module, component.Component, error)
 	setEnvFn       func(ksApp app.App, envName, name, pName, value string) error
@@ -76,6 +77,8 @@ func NewParamSet(m map[string]interface{}) (*ParamSet, error) {
 		resolveImageFn: dockerregistry.ResolveImage,
 	}
 
+	ps.guard = newEnvGuard(ps.app, ol.LoadOptionalString(OptionConfirmEnv))
+
 	if ol.err != nil {
 		return nil, ol.err
 	}
@@ -102,6 +105,10 @@ func (ps *ParamSet) Run() error {
 	}
 
 	if ps.envName != "" {
+		if err := ps.guard.check(ps.envName, "change", false); err != nil {
+			return err
+		}
+
 		value := ps.rawValue
 		if ps.resolveImage {
 			digest, err := ps.resolveImageFn(value)
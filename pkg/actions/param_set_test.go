@@ -176,6 +176,8 @@ func TestParamSet_env(t *testing.T) {
 		path := "replicas"
 		value := "3"
 
+		appMock.On("Environment", "default").Return(&app.EnvironmentConfig{Name: "default"}, nil)
+
 		in := map[string]interface{}{
 			OptionApp:     appMock,
 			OptionName:    name,
@@ -207,6 +209,8 @@ func TestParamSet_env_resolveImage(t *testing.T) {
 		path := "image"
 		value := "foo/bar:latest"
 
+		appMock.On("Environment", "default").Return(&app.EnvironmentConfig{Name: "default"}, nil)
+
 		in := map[string]interface{}{
 			OptionApp:          appMock,
 			OptionName:         name,
@@ -241,6 +245,8 @@ func TestParamSet_envGlobal(t *testing.T) {
 		path := "replicas"
 		value := "3"
 
+		appMock.On("Environment", "default").Return(&app.EnvironmentConfig{Name: "default"}, nil)
+
 		in := map[string]interface{}{
 			OptionApp:     appMock,
 			OptionPath:    path,
@@ -20,9 +20,10 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/pkg"
 	"github.com/ksonnet/ksonnet/pkg/registry"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 )
 
-type libCacher func(app.App, registry.InstalledChecker, pkg.Descriptor, string, bool) (*app.LibraryConfig, error)
+type libCacher func(app.App, registry.InstalledChecker, pkg.Descriptor, string, string, bool) ([]*app.LibraryConfig, error)
 
 type libUpdater func(name string, env string, spec *app.LibraryConfig) (*app.LibraryConfig, error)
 
@@ -50,6 +51,7 @@ type PkgInstall struct {
 	libCacherFn  libCacher
 	libUpdateFn  libUpdater
 	envCheckerFn envChecker
+	lockUpdateFn func(fetched []*app.LibraryConfig) error
 }
 
 // NewPkgInstall creates an instance of PkgInstall.
@@ -74,8 +76,8 @@ func NewPkgInstall(m map[string]interface{}) (*PkgInstall, error) {
 		checker:    pm,
 		gc:         registry.NewGarbageCollector(a.Fs(), pm, a.VendorPath()),
 
-		libCacherFn: func(a app.App, checker registry.InstalledChecker, d pkg.Descriptor, customName string, force bool) (*app.LibraryConfig, error) {
-			return registry.CacheDependency(a, checker, d, customName, force, httpClient)
+		libCacherFn: func(a app.App, checker registry.InstalledChecker, d pkg.Descriptor, customName, envName string, force bool) ([]*app.LibraryConfig, error) {
+			return registry.CacheDependency(a, checker, d, customName, envName, force, httpClient)
 		},
 		libUpdateFn: a.UpdateLib,
 		envCheckerFn: func(name string) (bool, error) {
@@ -86,6 +88,9 @@ func NewPkgInstall(m map[string]interface{}) (*PkgInstall, error) {
 			exists := (env != nil)
 			return exists, nil
 		},
+		lockUpdateFn: func(fetched []*app.LibraryConfig) error {
+			return registry.UpdateLock(a, pm, fetched)
+		},
 	}
 
 	if ol.err != nil {
@@ -113,12 +118,35 @@ func (pi *PkgInstall) Run() error {
 		}
 	}
 
-	libCfg, err := pi.libCacherFn(pi.app, pi.checker, d, customName, pi.force)
+	libCfgs, err := pi.libCacherFn(pi.app, pi.checker, d, customName, pi.envName, pi.force)
 	if err != nil {
 		return err
 	}
 
-	oldCfg, err := pi.libUpdateFn(d.Name, pi.envName, libCfg)
+	for i, libCfg := range libCfgs {
+		// The first library is the package itself, the rest are its dependencies.
+		name := libCfg.Name
+		if i == 0 {
+			name = d.Name
+		} else {
+			log.Infof("Installing dependency %s/%s", libCfg.Registry, libCfg.Name)
+		}
+
+		if err := pi.update(name, libCfg); err != nil {
+			return err
+		}
+	}
+
+	if err := pi.lockUpdateFn(libCfgs); err != nil {
+		return errors.Wrapf(err, "updating %s", registry.LockFile)
+	}
+
+	return nil
+}
+
+// update records a library in the app and removes its replaced version.
+func (pi *PkgInstall) update(name string, libCfg *app.LibraryConfig) error {
+	oldCfg, err := pi.libUpdateFn(name, pi.envName, libCfg)
 	if err != nil {
 		return err
 	}
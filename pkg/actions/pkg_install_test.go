@@ -53,11 +53,12 @@ func TestPkgInstall(t *testing.T) {
 		}
 
 		var cacherCalled bool
-		fakeCacher := func(a app.App, checker registry.InstalledChecker, d pkg.Descriptor, cn string, force bool) (*app.LibraryConfig, error) {
+		fakeCacher := func(a app.App, checker registry.InstalledChecker, d pkg.Descriptor, cn, envName string, force bool) ([]*app.LibraryConfig, error) {
 			cacherCalled = true
 			require.Equal(t, expectedD, d)
 			require.Equal(t, "customName", cn)
-			return newLibCfg, nil
+			require.Equal(t, "", envName)
+			return []*app.LibraryConfig{newLibCfg}, nil
 		}
 
 		var updaterCalled bool
@@ -72,8 +73,16 @@ func TestPkgInstall(t *testing.T) {
 			return nil, nil
 		}
 
+		var lockUpdated bool
+		fakeLockUpdater := func(fetched []*app.LibraryConfig) error {
+			lockUpdated = true
+			assert.Equal(t, []*app.LibraryConfig{newLibCfg}, fetched)
+			return nil
+		}
+
 		a.libCacherFn = fakeCacher
 		a.libUpdateFn = fakeUpdater
+		a.lockUpdateFn = fakeLockUpdater
 
 		libraries := app.LibraryConfigs{}
 		appMock.On("Libraries").Return(libraries, nil)
@@ -90,6 +99,51 @@ func TestPkgInstall(t *testing.T) {
 		require.NoError(t, err)
 		assert.True(t, cacherCalled, "dependency cacher not called")
 		assert.True(t, updaterCalled, "library reference updater not called")
+		assert.True(t, lockUpdated, "lock not updated")
+	})
+}
+
+func TestPkgInstall_env_dependencies(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:           appMock,
+			OptionPkgName:       "main/app",
+			OptionName:          "",
+			OptionEnvName:       "stage",
+			OptionForce:         false,
+			OptionTLSSkipVerify: false,
+		}
+
+		a, err := NewPkgInstall(in)
+		require.NoError(t, err)
+
+		fetched := []*app.LibraryConfig{
+			{Registry: "main", Name: "app"},
+			{Registry: "main", Name: "k8s-util"},
+		}
+
+		a.envCheckerFn = func(string) (bool, error) {
+			return true, nil
+		}
+		a.libCacherFn = func(a app.App, checker registry.InstalledChecker, d pkg.Descriptor, cn, envName string, force bool) ([]*app.LibraryConfig, error) {
+			require.Equal(t, "stage", envName)
+			return fetched, nil
+		}
+
+		var recorded []string
+		a.libUpdateFn = func(name string, env string, spec *app.LibraryConfig) (*app.LibraryConfig, error) {
+			assert.Equal(t, "stage", env)
+			recorded = append(recorded, name)
+			return nil, nil
+		}
+		a.lockUpdateFn = func([]*app.LibraryConfig) error {
+			return nil
+		}
+
+		err = a.Run()
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"app", "k8s-util"}, recorded)
 	})
 }
 
@@ -117,7 +171,7 @@ func TestPkgInstall_invalid_env(t *testing.T) {
 		require.NoError(t, err)
 
 		var cacherCalled bool
-		fakeCacher := func(a app.App, checker registry.InstalledChecker, d pkg.Descriptor, cn string, force bool) (*app.LibraryConfig, error) {
+		fakeCacher := func(a app.App, checker registry.InstalledChecker, d pkg.Descriptor, cn, envName string, force bool) ([]*app.LibraryConfig, error) {
 			cacherCalled = true
 			return nil, errors.New("not implemented")
 		}
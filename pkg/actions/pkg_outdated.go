@@ -0,0 +1,97 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"io"
+	"os"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+)
+
+// RunPkgOutdated runs `pkg outdated`
+func RunPkgOutdated(m map[string]interface{}) error {
+	po, err := NewPkgOutdated(m)
+	if err != nil {
+		return err
+	}
+
+	return po.Run()
+}
+
+// PkgOutdated lists installed packages for which a newer version is available.
+type PkgOutdated struct {
+	app        app.App
+	outputType string
+
+	outdatedFn func() ([]registry.OutdatedLibrary, error)
+	out        io.Writer
+}
+
+// NewPkgOutdated creates an instance of PkgOutdated.
+func NewPkgOutdated(m map[string]interface{}) (*PkgOutdated, error) {
+	ol := newOptionLoader(m)
+
+	a := ol.LoadApp()
+	if ol.err != nil {
+		return nil, ol.err
+	}
+	httpClient := ol.LoadHTTPClient()
+
+	po := &PkgOutdated{
+		app:        a,
+		outputType: ol.LoadOptionalString(OptionOutput),
+
+		outdatedFn: func() ([]registry.OutdatedLibrary, error) {
+			return registry.Outdated(a, httpClient)
+		},
+		out: os.Stdout,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return po, nil
+}
+
+// Run lists outdated packages.
+func (po *PkgOutdated) Run() error {
+	outdated, err := po.outdatedFn()
+	if err != nil {
+		return err
+	}
+
+	var rows [][]string
+	for _, o := range outdated {
+		rows = append(rows, []string{o.Library.Registry, o.Library.Name, o.EnvName, o.Current, o.Latest})
+	}
+
+	t := table.New("pkgOutdated", po.out)
+
+	f, err := table.DetectFormat(po.outputType)
+	if err != nil {
+		return errors.Wrap(err, "detecting output format")
+	}
+	t.SetFormat(f)
+
+	t.SetHeader([]string{"registry", "name", "environment", "current", "latest"})
+	t.AppendBulk(rows)
+	return t.Render()
+}
@@ -0,0 +1,89 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPkgOutdated(t *testing.T) {
+	outdated := []registry.OutdatedLibrary{
+		{
+			Library: &app.LibraryConfig{Registry: "incubator", Name: "nginx", Version: "0.0.1"},
+			Current: "0.0.1",
+			Latest:  "0.0.2",
+		},
+		{
+			EnvName: "prod",
+			Library: &app.LibraryConfig{Registry: "helm-stable", Name: "redis", Version: "3.0.0"},
+			Current: "3.0.0",
+			Latest:  "3.2.1",
+		},
+	}
+
+	cases := []struct {
+		name       string
+		outputType string
+		outputName string
+	}{
+		{
+			name:       "table output",
+			outputName: "pkg/outdated/output.txt",
+		},
+		{
+			name:       "json output",
+			outputType: "json",
+			outputName: "pkg/outdated/output.json",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:    appMock,
+					OptionOutput: tc.outputType,
+				}
+
+				a, err := NewPkgOutdated(in)
+				require.NoError(t, err)
+
+				var buf bytes.Buffer
+				a.out = &buf
+				a.outdatedFn = func() ([]registry.OutdatedLibrary, error) {
+					return outdated, nil
+				}
+
+				err = a.Run()
+				require.NoError(t, err)
+
+				assertOutput(t, tc.outputName, buf.String())
+			})
+		})
+	}
+}
+
+func TestPkgOutdated_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewPkgOutdated(in)
+	require.Error(t, err)
+}
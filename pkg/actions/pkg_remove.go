@@ -16,6 +16,9 @@
 package actions
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/pkg"
 	"github.com/ksonnet/ksonnet/pkg/registry"
@@ -25,12 +28,15 @@ import (
 
 // PkgRemove removes packages
 type PkgRemove struct {
-	app         app.App
-	pkgName     string
-	envName     string
-	checker     registry.InstalledChecker
-	gc          registry.GarbageCollector
-	libUpdateFn libUpdater
+	app          app.App
+	pkgName      string
+	envName      string
+	cascade      bool
+	checker      registry.InstalledChecker
+	gc           registry.GarbageCollector
+	libUpdateFn  libUpdater
+	dependentsFn func(a app.App, envName, registryName, name string) ([]registry.Dependent, error)
+	lockUpdateFn func() error
 }
 
 // NewPkgRemove creates an instance of PkgInstall
@@ -45,11 +51,16 @@ func NewPkgRemove(m map[string]interface{}) (*PkgRemove, error) {
 	pm := registry.NewPackageManager(a)
 
 	pr := &PkgRemove{
-		app:         a,
-		pkgName:     ol.LoadString(OptionPkgName),
-		envName:     ol.LoadOptionalString(OptionEnvName),
-		libUpdateFn: a.UpdateLib,
-		gc:          registry.NewGarbageCollector(a.Fs(), pm, a.VendorPath()),
+		app:          a,
+		pkgName:      ol.LoadString(OptionPkgName),
+		envName:      ol.LoadOptionalString(OptionEnvName),
+		cascade:      ol.LoadOptionalBool(OptionCascade),
+		libUpdateFn:  a.UpdateLib,
+		gc:           registry.NewGarbageCollector(a.Fs(), pm, a.VendorPath()),
+		dependentsFn: registry.Dependents,
+		lockUpdateFn: func() error {
+			return registry.UpdateLock(a, pm, nil)
+		},
 	}
 
 	if ol.err != nil {
@@ -69,9 +80,98 @@ func RunPkgRemove(m map[string]interface{}) error {
 	return pr.Run()
 }
 
-// Run removes packages
+// Run removes packages. Packages which depend on the removed package are
+// removed as well with cascade. Otherwise they prevent its removal. Cascade
+// never removes packages outside of the environment the package is removed
+// from.
 func (pr *PkgRemove) Run() error {
-	oldCfg, err := pr.libUpdateFn(pr.pkgName, pr.envName, nil)
+	d, err := pkg.Parse(pr.pkgName)
+	if err != nil {
+		return err
+	}
+
+	dependents, err := pr.dependentsFn(pr.app, pr.envName, d.Registry, d.Name)
+	if err != nil {
+		return errors.Wrapf(err, "finding packages which depend on %s", pr.pkgName)
+	}
+
+	if len(dependents) > 0 && !pr.cascade {
+		var names []string
+		for _, dependent := range dependents {
+			names = append(names, dependentName(dependent))
+		}
+
+		return errors.Errorf("package %s is required by %s; remove them first or use --%s",
+			pr.pkgName, strings.Join(names, ", "), OptionCascade)
+	}
+
+	var targets []removeTarget
+	if err := pr.planRemoval(pr.pkgName, pr.envName, dependents, map[string]bool{}, &targets); err != nil {
+		return err
+	}
+
+	for _, target := range targets {
+		if target.id != pr.pkgName || target.envName != pr.envName {
+			log.Infof("Removing dependent package %s", target.name)
+		}
+		if err := pr.remove(target.id, target.envName); err != nil {
+			return err
+		}
+	}
+
+	if err := pr.lockUpdateFn(); err != nil {
+		return errors.Wrapf(err, "updating %s", registry.LockFile)
+	}
+
+	return nil
+}
+
+// removeTarget is a package to remove from an environment, or globally if
+// envName is empty.
+type removeTarget struct {
+	id      string
+	envName string
+	name    string
+}
+
+// planRemoval appends the packages depending on a package, and then the
+// package itself to targets. Dependents outside of the environment the
+// package is removed from are an error, so nothing is removed then.
+func (pr *PkgRemove) planRemoval(id, envName string, dependents []registry.Dependent, planned map[string]bool, targets *[]removeTarget) error {
+	planned[envName+":"+id] = true
+
+	for _, dependent := range dependents {
+		depID := fmt.Sprintf("%s/%s", dependent.Library.Registry, dependent.Library.Name)
+		if planned[dependent.EnvName+":"+depID] {
+			continue
+		}
+
+		if pr.envName != "" && dependent.EnvName != pr.envName {
+			return errors.Errorf("package %s is required by %s, which is outside of environment %s; remove it first",
+				id, dependentName(dependent), pr.envName)
+		}
+
+		next, err := pr.dependentsFn(pr.app, dependent.EnvName, dependent.Library.Registry, dependent.Library.Name)
+		if err != nil {
+			return errors.Wrapf(err, "finding packages which depend on %s", depID)
+		}
+
+		if err := pr.planRemoval(depID, dependent.EnvName, next, planned, targets); err != nil {
+			return err
+		}
+	}
+
+	name := id
+	if envName != "" {
+		name = fmt.Sprintf("%s (environment %s)", id, envName)
+	}
+	*targets = append(*targets, removeTarget{id: id, envName: envName, name: name})
+
+	return nil
+}
+
+func (pr *PkgRemove) remove(id, envName string) error {
+	oldCfg, err := pr.libUpdateFn(id, envName, nil)
 	if err != nil {
 		return err
 	}
@@ -93,3 +193,11 @@ func (pr *PkgRemove) Run() error {
 
 	return nil
 }
+
+func dependentName(d registry.Dependent) string {
+	name := fmt.Sprintf("%s/%s", d.Library.Registry, d.Library.Name)
+	if d.EnvName != "" {
+		name = fmt.Sprintf("%s (environment %s)", name, d.EnvName)
+	}
+	return name
+}
@@ -19,6 +19,7 @@ import (
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/registry"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -44,9 +45,122 @@ func TestPkgRemove(t *testing.T) {
 		}
 
 		a.libUpdateFn = fakeUpdater
+		a.dependentsFn = func(app.App, string, string, string) ([]registry.Dependent, error) {
+			return nil, nil
+		}
+
+		var lockUpdated bool
+		a.lockUpdateFn = func() error {
+			lockUpdated = true
+			return nil
+		}
 
 		err = a.Run()
 		require.NoError(t, err)
 		assert.True(t, updaterCalled, "library reference updater not called")
+		assert.True(t, lockUpdated, "lock not updated")
 	})
 }
+
+func TestPkgRemove_dependents(t *testing.T) {
+	dependents := map[string][]registry.Dependent{
+		"incubator/k8s-util": {
+			{Library: &app.LibraryConfig{Registry: "incubator", Name: "app"}},
+			{EnvName: "prod", Library: &app.LibraryConfig{Registry: "shared", Name: "logging"}},
+		},
+		"shared/logging": {
+			{EnvName: "prod", Library: &app.LibraryConfig{Registry: "incubator", Name: "app"}},
+		},
+		"incubator/apache": {
+			{EnvName: "prod", Library: &app.LibraryConfig{Registry: "incubator", Name: "app"}},
+			{EnvName: "stage", Library: &app.LibraryConfig{Registry: "incubator", Name: "app"}},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		pkgName  string
+		envName  string
+		cascade  bool
+		expected []string
+		errMsg   string
+	}{
+		{
+			name:   "refuse",
+			errMsg: "required by incubator/app, shared/logging (environment prod)",
+		},
+		{
+			name:    "cascade",
+			cascade: true,
+			expected: []string{
+				":incubator/app",
+				"prod:incubator/app",
+				"prod:shared/logging",
+				":incubator/k8s-util",
+			},
+		},
+		{
+			name:    "cascade outside of environment",
+			envName: "prod",
+			cascade: true,
+			errMsg:  "required by incubator/app, which is outside of environment prod",
+		},
+		{
+			name:     "cascade in environment",
+			pkgName:  "incubator/apache",
+			envName:  "stage",
+			cascade:  true,
+			expected: []string{"stage:incubator/app", "stage:incubator/apache"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				pkgName := tc.pkgName
+				if pkgName == "" {
+					pkgName = "incubator/k8s-util"
+				}
+
+				in := map[string]interface{}{
+					OptionApp:     appMock,
+					OptionPkgName: pkgName,
+					OptionCascade: tc.cascade,
+					OptionEnvName: tc.envName,
+				}
+
+				a, err := NewPkgRemove(in)
+				require.NoError(t, err)
+
+				var removed []string
+				a.libUpdateFn = func(name string, env string, spec *app.LibraryConfig) (*app.LibraryConfig, error) {
+					removed = append(removed, env+":"+name)
+					return nil, nil
+				}
+				a.dependentsFn = func(_ app.App, envName, registryName, name string) ([]registry.Dependent, error) {
+					var matched []registry.Dependent
+					for _, dependent := range dependents[registryName+"/"+name] {
+						if envName == "" || dependent.EnvName == "" || dependent.EnvName == envName {
+							matched = append(matched, dependent)
+						}
+					}
+					return matched, nil
+				}
+				a.lockUpdateFn = func() error {
+					return nil
+				}
+
+				err = a.Run()
+				if tc.errMsg != "" {
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), tc.errMsg)
+					assert.Empty(t, removed)
+					return
+				}
+
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, removed)
+			})
+		})
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+)
+
+// RunPkgSearch runs `pkg search`
+func RunPkgSearch(m map[string]interface{}) error {
+	ps, err := NewPkgSearch(m)
+	if err != nil {
+		return err
+	}
+
+	return ps.Run()
+}
+
+// PkgSearch searches the packages of all registries.
+type PkgSearch struct {
+	app        app.App
+	query      string
+	refresh    bool
+	details    bool
+	outputType string
+	httpClient *http.Client
+
+	out             io.Writer
+	loadSearchIndex func(a app.App, httpClient *http.Client, refresh bool) (*registry.SearchIndex, error)
+}
+
+// NewPkgSearch creates an instance of PkgSearch.
+func NewPkgSearch(m map[string]interface{}) (*PkgSearch, error) {
+	ol := newOptionLoader(m)
+
+	ps := &PkgSearch{
+		app:        ol.LoadApp(),
+		query:      ol.LoadOptionalString(OptionQuery),
+		refresh:    ol.LoadOptionalBool(OptionRefresh),
+		details:    ol.LoadOptionalBool(OptionDetails),
+		outputType: ol.LoadOptionalString(OptionOutput),
+		httpClient: ol.LoadHTTPClient(),
+
+		out:             os.Stdout,
+		loadSearchIndex: registry.LoadSearchIndex,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return ps, nil
+}
+
+// Run searches for packages. Without a query, it only refreshes the search
+// index.
+func (ps *PkgSearch) Run() error {
+	if ps.query == "" && !ps.refresh {
+		return errors.New("search query is required")
+	}
+
+	idx, err := ps.loadSearchIndex(ps.app, ps.httpClient, ps.refresh)
+	if err != nil {
+		return errors.Wrap(err, "loading package search index")
+	}
+
+	if ps.query == "" {
+		fmt.Fprintf(ps.out, "Indexed %d packages from %d registries\n", len(idx.Packages), len(idx.Registries))
+		return nil
+	}
+
+	results := idx.Search(ps.query)
+	if len(results) == 0 {
+		return fmt.Errorf("failed to find any packages for query %q", ps.query)
+	}
+
+	if ps.details {
+		return ps.printDetails(results)
+	}
+
+	var rows [][]string
+	for _, r := range results {
+		version := ""
+		if len(r.Versions) > 0 {
+			version = r.Versions[0]
+		}
+
+		rows = append(rows, []string{r.Registry, r.Name, version, r.Description})
+	}
+
+	t := table.New("pkgSearch", ps.out)
+	t.SetHeader([]string{"registry", "name", "version", "description"})
+
+	f, err := table.DetectFormat(ps.outputType)
+	if err != nil {
+		return errors.Wrap(err, "detecting output format")
+	}
+	t.SetFormat(f)
+
+	t.AppendBulk(rows)
+
+	return t.Render()
+}
+
+func (ps *PkgSearch) printDetails(results []registry.SearchResult) error {
+	t, err := template.New("pkg-search").Parse(pkgSearchTemplate)
+	if err != nil {
+		return err
+	}
+
+	for i, r := range results {
+		if i > 0 {
+			fmt.Fprintln(ps.out)
+		}
+
+		data := map[string]interface{}{
+			"Name":        r.Registry + "/" + r.Name,
+			"Description": r.Description,
+			"Versions":    strings.Join(r.Versions, ", "),
+			"Keywords":    strings.Join(r.Keywords, ", "),
+			"Prototypes":  r.Prototypes,
+			"QuickStart":  quickStart(r),
+		}
+
+		if err := t.Execute(ps.out, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// quickStart returns the commands of a package's quick start instructions.
+func quickStart(r registry.SearchResult) []string {
+	qs := r.QuickStart
+	if qs == nil || qs.Prototype == "" {
+		return nil
+	}
+
+	var lines []string
+	if qs.Comment != "" {
+		lines = append(lines, "# "+qs.Comment)
+	}
+
+	lines = append(lines, fmt.Sprintf("ks pkg install %s/%s", r.Registry, r.Name))
+
+	use := []string{"ks", "prototype", "use", qs.Prototype, qs.ComponentName}
+
+	var flags []string
+	for k := range qs.Flags {
+		flags = append(flags, k)
+	}
+	sort.Strings(flags)
+
+	for _, k := range flags {
+		use = append(use, fmt.Sprintf("--%s %s", k, qs.Flags[k]))
+	}
+
+	return append(lines, strings.Join(use, " "))
+}
+
+const pkgSearchTemplate = `PACKAGE NAME:
+{{.Name}}
+
+DESCRIPTION:
+{{.Description}}
+{{- if .Versions}}
+
+VERSIONS:
+{{.Versions}}
+{{- end}}
+{{- if .Keywords}}
+
+KEYWORDS:
+{{.Keywords}}
+{{- end}}
+{{- if .Prototypes}}
+
+PROTOTYPES:{{- range .Prototypes}}
+  {{.}}
+{{- end}}{{- end}}
+{{- if .QuickStart}}
+
+QUICK START:{{- range .QuickStart}}
+  {{.}}
+{{- end}}{{- end}}
+`
@@ -0,0 +1,158 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"net/http"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/parts"
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPkgSearch(t *testing.T) {
+	idx := &registry.SearchIndex{
+		Registries: map[string]string{
+			"incubator": "github.com/ksonnet/parts/tree/master/incubator",
+			"stable":    "https://kubernetes-charts.storage.googleapis.com",
+		},
+		Packages: []*registry.SearchEntry{
+			{
+				Registry:    "incubator",
+				Name:        "redis",
+				Description: "Redis server",
+				Keywords:    []string{"database", "cache"},
+				Prototypes:  []string{"io.ksonnet.pkg.redis-stateless"},
+				QuickStart: &parts.QuickStartSpec{
+					Prototype:     "io.ksonnet.pkg.redis-stateless",
+					ComponentName: "redis",
+					Flags: map[string]string{
+						"namespace": "default",
+						"name":      "redis",
+					},
+					Comment: "Run a Redis server",
+				},
+			},
+			{
+				Registry:    "stable",
+				Name:        "redis-ha",
+				Description: "Highly available Redis",
+				Versions:    []string{"2.0.1", "2.0.0"},
+				Prototypes:  []string{"io.ksonnet.pkg.stable-redis-ha"},
+			},
+			{
+				Registry:    "stable",
+				Name:        "mysql",
+				Description: "MySQL server",
+				Versions:    []string{"0.8.0"},
+			},
+		},
+	}
+
+	cases := []struct {
+		name       string
+		query      string
+		refresh    bool
+		details    bool
+		outputType string
+		outputFile string
+		isErr      bool
+	}{
+		{
+			name:       "output table",
+			query:      "redis",
+			outputFile: "pkg/search/output.txt",
+		},
+		{
+			name:       "output json",
+			query:      "redis",
+			outputType: "json",
+			outputFile: "pkg/search/output.json",
+		},
+		{
+			name:       "details",
+			query:      "redis",
+			details:    true,
+			outputFile: "pkg/search/details.txt",
+		},
+		{
+			name:       "refresh",
+			refresh:    true,
+			outputFile: "pkg/search/refresh.txt",
+		},
+		{
+			name:  "no results",
+			query: "postgres",
+			isErr: true,
+		},
+		{
+			name:  "no query",
+			isErr: true,
+		},
+		{
+			name:       "invalid output type",
+			query:      "redis",
+			outputType: "invalid",
+			isErr:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:           appMock,
+					OptionQuery:         tc.query,
+					OptionRefresh:       tc.refresh,
+					OptionDetails:       tc.details,
+					OptionOutput:        tc.outputType,
+					OptionTLSSkipVerify: false,
+				}
+
+				a, err := NewPkgSearch(in)
+				require.NoError(t, err)
+
+				var buf bytes.Buffer
+				a.out = &buf
+
+				a.loadSearchIndex = func(_ app.App, _ *http.Client, refresh bool) (*registry.SearchIndex, error) {
+					assert.Equal(t, tc.refresh, refresh)
+					return idx, nil
+				}
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				assertOutput(t, tc.outputFile, buf.String())
+			})
+		})
+	}
+}
+
+func TestPkgSearch_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewPkgSearch(in)
+	require.Error(t, err)
+}
@@ -0,0 +1,300 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/diff"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// RunPkgUpgrade runs `pkg upgrade`
+func RunPkgUpgrade(m map[string]interface{}) error {
+	pu, err := NewPkgUpgrade(m)
+	if err != nil {
+		return err
+	}
+
+	return pu.Run()
+}
+
+// PkgUpgrade upgrades installed packages.
+type PkgUpgrade struct {
+	app     app.App
+	pkgName string
+	version string
+	envName string
+
+	outdatedFn func() ([]registry.OutdatedLibrary, error)
+	installFn  func(d pkg.Descriptor, name, envName string) error
+	renderFn   func(envName string) (io.ReadSeeker, error)
+	out        io.Writer
+}
+
+// NewPkgUpgrade creates an instance of PkgUpgrade.
+func NewPkgUpgrade(m map[string]interface{}) (*PkgUpgrade, error) {
+	ol := newOptionLoader(m)
+
+	a := ol.LoadApp()
+	if ol.err != nil {
+		return nil, ol.err
+	}
+	httpClient := ol.LoadHTTPClient()
+
+	pu := &PkgUpgrade{
+		app:     a,
+		pkgName: ol.LoadOptionalString(OptionPkgName),
+		version: ol.LoadOptionalString(OptionVersion),
+		envName: ol.LoadOptionalString(OptionEnvName),
+
+		outdatedFn: func() ([]registry.OutdatedLibrary, error) {
+			return registry.Outdated(a, httpClient)
+		},
+		installFn: func(d pkg.Descriptor, name, envName string) error {
+			return RunPkgInstall(map[string]interface{}{
+				OptionApp:        a,
+				OptionHTTPClient: httpClient,
+				OptionPkgName:    d.String(),
+				OptionName:       name,
+				OptionEnvName:    envName,
+				OptionForce:      false,
+			})
+		},
+		renderFn: func(envName string) (io.ReadSeeker, error) {
+			return diff.Local(a, envName, nil)
+		},
+		out: os.Stdout,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return pu, nil
+}
+
+// upgradeTarget is a library to upgrade.
+type upgradeTarget struct {
+	lib     *app.LibraryConfig
+	envName string
+	version string
+}
+
+// Run upgrades packages. It shows how the rendered manifests of the affected
+// environments change.
+func (pu *PkgUpgrade) Run() error {
+	targets, err := pu.targets()
+	if err != nil {
+		return err
+	}
+
+	if len(targets) == 0 {
+		log.Info("All packages are up to date")
+		return nil
+	}
+
+	envNames, err := pu.affectedEnvironments()
+	if err != nil {
+		return err
+	}
+
+	before := make(map[string]io.ReadSeeker)
+	for _, envName := range envNames {
+		r, err := pu.renderFn(envName)
+		if err != nil {
+			log.Warnf("Unable to render environment %q before the upgrade: %v", envName, err)
+			continue
+		}
+		before[envName] = r
+	}
+
+	for _, target := range targets {
+		d := pkg.Descriptor{
+			Registry: target.lib.Registry,
+			Name:     target.lib.Name,
+			Version:  target.version,
+		}
+
+		log.Infof("Upgrading %s/%s from %s to %s", d.Registry, d.Name, target.lib.Version, d.Version)
+		if err := pu.installFn(d, target.lib.Name, target.envName); err != nil {
+			return errors.Wrapf(err, "upgrading %s", d)
+		}
+	}
+
+	for _, envName := range envNames {
+		r1, ok := before[envName]
+		if !ok {
+			continue
+		}
+
+		r2, err := pu.renderFn(envName)
+		if err != nil {
+			return errors.Wrapf(err, "rendering environment %q after the upgrade", envName)
+		}
+
+		if err := pu.showDiff(envName, r1, r2); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// targets returns the libraries to upgrade, and the versions to upgrade them to.
+func (pu *PkgUpgrade) targets() ([]upgradeTarget, error) {
+	if pu.pkgName == "" {
+		if pu.version != "" {
+			return nil, errors.New("a version can only be given when upgrading a single package")
+		}
+
+		outdated, err := pu.outdatedFn()
+		if err != nil {
+			return nil, err
+		}
+
+		var targets []upgradeTarget
+		for _, o := range outdated {
+			if o.EnvName != pu.envName {
+				continue
+			}
+			targets = append(targets, upgradeTarget{lib: o.Library, envName: o.EnvName, version: o.Latest})
+		}
+
+		return targets, nil
+	}
+
+	d, err := pkg.Parse(pu.pkgName)
+	if err != nil {
+		return nil, err
+	}
+
+	version := pu.version
+	if d.Version != "" {
+		if version != "" && version != d.Version {
+			return nil, errors.Errorf("package %s conflicts with version %s", pu.pkgName, version)
+		}
+		version = d.Version
+	}
+
+	lib, err := pu.installedLibrary(d)
+	if err != nil {
+		return nil, err
+	}
+
+	if version == "" {
+		outdated, err := pu.outdatedFn()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, o := range outdated {
+			if o.EnvName == pu.envName && o.Library.Name == lib.Name {
+				version = o.Latest
+			}
+		}
+	}
+
+	if version == "" || version == lib.Version {
+		return nil, nil
+	}
+
+	return []upgradeTarget{{lib: lib, envName: pu.envName, version: version}}, nil
+}
+
+// installedLibrary finds an installed library, in the environment being
+// upgraded or globally.
+func (pu *PkgUpgrade) installedLibrary(d pkg.Descriptor) (*app.LibraryConfig, error) {
+	var libs app.LibraryConfigs
+	if pu.envName != "" {
+		env, err := pu.app.Environment(pu.envName)
+		if err != nil {
+			return nil, err
+		}
+		libs = env.Libraries
+	} else {
+		var err error
+		libs, err = pu.app.Libraries()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	lib, ok := libs[d.Name]
+	if !ok || (d.Registry != "" && lib.Registry != d.Registry) {
+		if pu.envName != "" {
+			return nil, errors.Errorf("package %s is not installed in environment %s", pu.pkgName, pu.envName)
+		}
+		return nil, errors.Errorf("package %s is not installed", pu.pkgName)
+	}
+
+	return lib, nil
+}
+
+// affectedEnvironments returns the environments whose manifests may change
+// with the upgrade.
+func (pu *PkgUpgrade) affectedEnvironments() ([]string, error) {
+	if pu.envName != "" {
+		return []string{pu.envName}, nil
+	}
+
+	envs, err := pu.app.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (pu *PkgUpgrade) showDiff(envName string, r1, r2 io.ReadSeeker) error {
+	r, err := diff.Text(r1, r2)
+	if err != nil {
+		return errors.Wrapf(err, "comparing manifests of environment %q", envName)
+	}
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return err
+	}
+
+	if len(b) == 0 {
+		log.Infof("No changes to the manifests of environment %q", envName)
+		return nil
+	}
+
+	s, err := colorizeDiff(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(pu.out, "Changes to the manifests of environment %q:\n%s", envName, s)
+	return nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPkgUpgrade(t *testing.T) {
+	nginx := &app.LibraryConfig{Registry: "incubator", Name: "nginx", Version: "0.0.1"}
+	redis := &app.LibraryConfig{Registry: "incubator", Name: "redis", Version: "1.0.0"}
+	prodRedis := &app.LibraryConfig{Registry: "incubator", Name: "redis", Version: "0.9.0"}
+
+	outdated := []registry.OutdatedLibrary{
+		{Library: nginx, Current: "0.0.1", Latest: "0.0.2"},
+		{EnvName: "prod", Library: prodRedis, Current: "0.9.0", Latest: "1.1.0"},
+		{Library: redis, Current: "1.0.0", Latest: "1.1.0"},
+	}
+
+	cases := []struct {
+		name      string
+		pkgName   string
+		version   string
+		envName   string
+		installed []string
+		rendered  []string
+		isErr     bool
+	}{
+		{
+			name:      "all global packages",
+			installed: []string{":incubator/nginx@0.0.2", ":incubator/redis@1.1.0"},
+			rendered:  []string{"default", "prod"},
+		},
+		{
+			name:      "all packages in an environment",
+			envName:   "prod",
+			installed: []string{"prod:incubator/redis@1.1.0"},
+			rendered:  []string{"prod"},
+		},
+		{
+			name:      "a package",
+			pkgName:   "nginx",
+			installed: []string{":incubator/nginx@0.0.2"},
+			rendered:  []string{"default", "prod"},
+		},
+		{
+			name:      "a package to a version",
+			pkgName:   "incubator/redis",
+			version:   "1.0.5",
+			installed: []string{":incubator/redis@1.0.5"},
+			rendered:  []string{"default", "prod"},
+		},
+		{
+			name:      "a package with a version",
+			pkgName:   "redis@1.0.5",
+			envName:   "prod",
+			installed: []string{"prod:incubator/redis@1.0.5"},
+			rendered:  []string{"prod"},
+		},
+		{
+			name:    "a package which is up to date",
+			pkgName: "redis",
+			version: "1.0.0",
+		},
+		{
+			name:    "a package which is not installed",
+			pkgName: "mysql",
+			isErr:   true,
+		},
+		{
+			name:    "a package from another registry",
+			pkgName: "stable/nginx",
+			isErr:   true,
+		},
+		{
+			name:    "a version without a package",
+			version: "1.0.0",
+			isErr:   true,
+		},
+		{
+			name:    "conflicting versions",
+			pkgName: "redis@1.0.5",
+			version: "1.0.6",
+			isErr:   true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				appMock.On("Libraries").Return(app.LibraryConfigs{"nginx": nginx, "redis": redis}, nil)
+				envs := app.EnvironmentConfigs{
+					"default": &app.EnvironmentConfig{Name: "default"},
+					"prod":    &app.EnvironmentConfig{Name: "prod", Libraries: app.LibraryConfigs{"redis": prodRedis}},
+				}
+				appMock.On("Environments").Return(envs, nil)
+				appMock.On("Environment", "prod").Return(envs["prod"], nil)
+
+				in := map[string]interface{}{
+					OptionApp:     appMock,
+					OptionPkgName: tc.pkgName,
+					OptionVersion: tc.version,
+					OptionEnvName: tc.envName,
+				}
+
+				a, err := NewPkgUpgrade(in)
+				require.NoError(t, err)
+
+				var installed, rendered []string
+				upgraded := false
+				a.outdatedFn = func() ([]registry.OutdatedLibrary, error) {
+					return outdated, nil
+				}
+				a.installFn = func(d pkg.Descriptor, name, envName string) error {
+					assert.Equal(t, d.Name, name)
+					installed = append(installed, envName+":"+d.String())
+					upgraded = true
+					return nil
+				}
+				a.renderFn = func(envName string) (io.ReadSeeker, error) {
+					if !upgraded {
+						rendered = append(rendered, envName)
+						return bytes.NewReader([]byte("image: nginx:1\n")), nil
+					}
+					return bytes.NewReader([]byte("image: nginx:2\n")), nil
+				}
+
+				var buf bytes.Buffer
+				a.out = &buf
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+					assert.Empty(t, installed)
+					return
+				}
+				require.NoError(t, err)
+
+				assert.Equal(t, tc.installed, installed)
+				assert.Equal(t, tc.rendered, rendered)
+				for _, envName := range tc.rendered {
+					assert.Contains(t, buf.String(), "Changes to the manifests of environment \""+envName+"\":\n")
+				}
+				if len(tc.rendered) > 0 {
+					assert.Contains(t, buf.String(), "+image: nginx:2")
+				}
+			})
+		})
+	}
+}
+
+func TestPkgUpgrade_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewPkgUpgrade(in)
+	require.Error(t, err)
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/pkg/errors"
+)
+
+// RunPkgVerify runs `pkg verify`
+func RunPkgVerify(m map[string]interface{}) error {
+	pv, err := NewPkgVerify(m)
+	if err != nil {
+		return err
+	}
+
+	return pv.Run()
+}
+
+// PkgVerify verifies vendored packages against the lock.
+type PkgVerify struct {
+	app app.App
+
+	verifyFn func() ([]registry.LockMismatch, error)
+	out      io.Writer
+}
+
+// NewPkgVerify creates an instance of PkgVerify.
+func NewPkgVerify(m map[string]interface{}) (*PkgVerify, error) {
+	ol := newOptionLoader(m)
+
+	a := ol.LoadApp()
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	pm := registry.NewPackageManager(a)
+
+	pv := &PkgVerify{
+		app: a,
+
+		verifyFn: func() ([]registry.LockMismatch, error) {
+			return registry.VerifyLock(a, pm)
+		},
+		out: os.Stdout,
+	}
+
+	return pv, nil
+}
+
+// Run verifies vendored packages. It reports each package which differs from
+// the lock, and returns an error if there were any.
+func (pv *PkgVerify) Run() error {
+	mismatches, err := pv.verifyFn()
+	if err != nil {
+		return err
+	}
+
+	if len(mismatches) == 0 {
+		fmt.Fprintf(pv.out, "All vendored packages match %s\n", registry.LockFile)
+		return nil
+	}
+
+	for _, m := range mismatches {
+		fmt.Fprintln(pv.out, m.String())
+	}
+
+	return errors.Errorf("%d vendored package(s) do not match %s", len(mismatches), registry.LockFile)
+}
+
+// checkLock returns an error if the packages vendored in an app do not match its lock.
+func checkLock(a app.App) error {
+	return registry.CheckLock(a, registry.NewPackageManager(a))
+}
@@ -0,0 +1,83 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPkgVerify(t *testing.T) {
+	cases := []struct {
+		name       string
+		mismatches []registry.LockMismatch
+		expected   string
+		isErr      bool
+	}{
+		{
+			name:     "matches",
+			expected: "All vendored packages match ks.lock\n",
+		},
+		{
+			name: "mismatches",
+			mismatches: []registry.LockMismatch{
+				{Package: pkg.Descriptor{Registry: "incubator", Name: "nginx", Version: "1.0.0"}, Reason: "not vendored"},
+			},
+			expected: "incubator/nginx@1.0.0: not vendored\n",
+			isErr:    true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp: appMock,
+				}
+
+				a, err := NewPkgVerify(in)
+				require.NoError(t, err)
+
+				var buf bytes.Buffer
+				a.out = &buf
+				a.verifyFn = func() ([]registry.LockMismatch, error) {
+					return tc.mismatches, nil
+				}
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+
+				assert.Equal(t, tc.expected, buf.String())
+			})
+		})
+	}
+}
+
+func TestPkgVerify_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewPkgVerify(in)
+	require.Error(t, err)
+}
@@ -0,0 +1,238 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/util/table"
+	"github.com/pkg/errors"
+)
+
+// RunPromote runs `promote`.
+func RunPromote(m map[string]interface{}) error {
+	p, err := NewPromote(m)
+	if err != nil {
+		return err
+	}
+
+	return p.Run()
+}
+
+// Promote copies the configuration of one environment to another.
+type Promote struct {
+	app           app.App
+	srcName       string
+	dstName       string
+	componentName string
+	paramOnly     bool
+	dryRun        bool
+
+	guard            *envGuard
+	modulesFromEnvFn func(app.App, string) ([]component.Module, error)
+	setEnvFn         func(ksApp app.App, envName, name, pName, value string) error
+	out              io.Writer
+}
+
+// NewPromote creates an instance of Promote.
+func NewPromote(m map[string]interface{}) (*Promote, error) {
+	ol := newOptionLoader(m)
+
+	p := &Promote{
+		app:           ol.LoadApp(),
+		srcName:       ol.LoadString(OptionEnvName1),
+		dstName:       ol.LoadString(OptionEnvName2),
+		componentName: ol.LoadOptionalString(OptionComponentName),
+		paramOnly:     ol.LoadOptionalBool(OptionParamOnly),
+		dryRun:        ol.LoadOptionalBool(OptionDryRun),
+
+		modulesFromEnvFn: component.ModulesFromEnv,
+		setEnvFn:         setEnv,
+		out:              os.Stdout,
+	}
+
+	p.guard = newEnvGuard(p.app, ol.LoadOptionalString(OptionConfirmEnv))
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	if p.srcName == p.dstName {
+		return nil, errors.New("source and destination environments are the same")
+	}
+
+	return p, nil
+}
+
+// Run shows the changes to the destination environment and then applies them.
+func (p *Promote) Run() error {
+	params, err := p.paramChanges()
+	if err != nil {
+		return err
+	}
+
+	var targets []string
+	var libs app.LibraryConfigs
+	if !p.paramOnly {
+		targets, libs, err = p.configChanges()
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(params) == 0 && targets == nil && len(libs) == 0 {
+		fmt.Fprintf(p.out, "environment %q is up to date with %q\n", p.dstName, p.srcName)
+		return nil
+	}
+
+	if err = p.preview(params, targets, libs); err != nil {
+		return err
+	}
+
+	if p.dryRun {
+		return nil
+	}
+
+	if err = p.guard.check(p.dstName, "promote to", true); err != nil {
+		return err
+	}
+
+	for _, row := range params {
+		if err = p.setEnvFn(p.app, p.dstName, row[0], row[1], row[3]); err != nil {
+			return errors.Wrapf(err, "setting param %s for component %s", row[1], row[0])
+		}
+	}
+
+	if targets != nil {
+		if err = p.app.UpdateTargets(p.dstName, targets, p.app.IsEnvOverride(p.dstName)); err != nil {
+			return err
+		}
+	}
+
+	for _, k := range libNames(libs) {
+		if _, err = p.app.UpdateLib(k, p.dstName, libs[k]); err != nil {
+			return errors.Wrapf(err, "updating library %s", k)
+		}
+	}
+
+	return nil
+}
+
+// paramChanges returns component, param, current and promoted values for
+// the params which differ between the source and destination environments.
+func (p *Promote) paramChanges() ([][]string, error) {
+	srcParams, err := envModuleParams(p.app, p.modulesFromEnvFn, p.srcName)
+	if err != nil {
+		return nil, err
+	}
+
+	dstParams, err := envModuleParams(p.app, p.modulesFromEnvFn, p.dstName)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	for _, mp1 := range srcParams {
+		if p.componentName != "" && p.componentName != mp1.Component {
+			continue
+		}
+
+		current := ""
+		for _, mp2 := range dstParams {
+			if mp1.IsSameType(mp2) {
+				current = mp2.Value
+			}
+		}
+
+		if current != mp1.Value {
+			rows = append(rows, []string{mp1.Component, mp1.Key, current, mp1.Value})
+		}
+	}
+
+	return rows, nil
+}
+
+// configChanges returns the targets and library references to promote. Targets
+// are nil if they are the same in both environments.
+func (p *Promote) configChanges() ([]string, app.LibraryConfigs, error) {
+	src, err := p.app.Environment(p.srcName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	dst, err := p.app.Environment(p.dstName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var targets []string
+	if !reflect.DeepEqual(src.Targets, dst.Targets) && (len(src.Targets) > 0 || len(dst.Targets) > 0) {
+		targets = append([]string{}, src.Targets...)
+	}
+
+	libs := app.LibraryConfigs{}
+	for k, v := range src.Libraries {
+		if !reflect.DeepEqual(v, dst.Libraries[k]) {
+			libs[k] = v
+		}
+	}
+
+	return targets, libs, nil
+}
+
+func (p *Promote) preview(params [][]string, targets []string, libs app.LibraryConfigs) error {
+	fmt.Fprintf(p.out, "Promoting %q to %q:\n\n", p.srcName, p.dstName)
+
+	if len(params) > 0 {
+		t := table.New("promote", p.out)
+		t.SetHeader([]string{"component", "param", p.dstName, p.srcName})
+		t.AppendBulk(params)
+		if err := t.Render(); err != nil {
+			return err
+		}
+		fmt.Fprintln(p.out)
+	}
+
+	if targets != nil {
+		if len(targets) == 0 {
+			fmt.Fprintln(p.out, "targets: (all components)")
+		} else {
+			fmt.Fprintf(p.out, "targets: %s\n", strings.Join(targets, ", "))
+		}
+	}
+
+	for _, k := range libNames(libs) {
+		fmt.Fprintf(p.out, "library %s: %s\n", k, libs[k].Version)
+	}
+
+	return nil
+}
+
+func libNames(libs app.LibraryConfigs) []string {
+	var names []string
+	for k := range libs {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	return names
+}
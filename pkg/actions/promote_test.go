@@ -0,0 +1,246 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/component/mocks"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromote(t *testing.T) {
+	cases := []struct {
+		name          string
+		componentName string
+		paramOnly     bool
+		dryRun        bool
+		outputName    string
+		expectedSet   [][]string
+	}{
+		{
+			name:       "promote params and config",
+			outputName: filepath.Join("promote", "output.txt"),
+			expectedSet: [][]string{
+				{"a", "b", `"b1"`},
+				{"c", "c", "3"},
+			},
+		},
+		{
+			name:          "promote component params",
+			componentName: "c",
+			paramOnly:     true,
+			outputName:    filepath.Join("promote", "component.txt"),
+			expectedSet: [][]string{
+				{"c", "c", "3"},
+			},
+		},
+		{
+			name:       "dry run",
+			paramOnly:  true,
+			dryRun:     true,
+			outputName: filepath.Join("promote", "dry_run.txt"),
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				moduleStaging := &mocks.Module{}
+				moduleStaging.On("Params", "staging").Return([]component.ModuleParameter{
+					{Component: "a", Key: "a", Value: `"a"`},
+					{Component: "a", Key: "b", Value: `"b1"`},
+					{Component: "c", Key: "c", Value: "3"},
+				}, nil)
+
+				moduleProd := &mocks.Module{}
+				moduleProd.On("Params", "prod").Return([]component.ModuleParameter{
+					{Component: "a", Key: "a", Value: `"a"`},
+					{Component: "a", Key: "b", Value: `"b2"`},
+					{Component: "d", Key: "d", Value: `"d"`},
+				}, nil)
+
+				nginx := &app.LibraryConfig{Name: "nginx", Registry: "incubator", Version: "2.0.0"}
+				appMock.On("Environment", "staging").Return(&app.EnvironmentConfig{
+					Name:    "staging",
+					Targets: []string{"a", "c"},
+					Libraries: app.LibraryConfigs{
+						"incubator/nginx": nginx,
+					},
+				}, nil)
+				appMock.On("Environment", "prod").Return(&app.EnvironmentConfig{
+					Name:    "prod",
+					Targets: []string{"a"},
+					Libraries: app.LibraryConfigs{
+						"incubator/nginx": &app.LibraryConfig{Name: "nginx", Registry: "incubator", Version: "1.0.0"},
+					},
+				}, nil)
+				appMock.On("IsEnvOverride", "prod").Return(false)
+				appMock.On("UpdateTargets", "prod", []string{"a", "c"}, false).Return(nil)
+				appMock.On("UpdateLib", "incubator/nginx", "prod", nginx).Return(nil, nil)
+
+				in := map[string]interface{}{
+					OptionApp:           appMock,
+					OptionEnvName1:      "staging",
+					OptionEnvName2:      "prod",
+					OptionComponentName: tc.componentName,
+					OptionParamOnly:     tc.paramOnly,
+					OptionDryRun:        tc.dryRun,
+				}
+
+				p, err := NewPromote(in)
+				require.NoError(t, err)
+
+				p.modulesFromEnvFn = func(_ app.App, envName string) ([]component.Module, error) {
+					switch envName {
+					case "staging":
+						return []component.Module{moduleStaging}, nil
+					case "prod":
+						return []component.Module{moduleProd}, nil
+					default:
+						return nil, errors.Errorf("unknown env %s", envName)
+					}
+				}
+
+				var set [][]string
+				p.setEnvFn = func(_ app.App, envName, name, pName, value string) error {
+					assert.Equal(t, "prod", envName)
+					set = append(set, []string{name, pName, value})
+					return nil
+				}
+
+				var buf bytes.Buffer
+				p.out = &buf
+
+				err = p.Run()
+				require.NoError(t, err)
+
+				assert.Equal(t, tc.expectedSet, set)
+				assertOutput(t, tc.outputName, buf.String())
+
+				if tc.paramOnly {
+					appMock.AssertNotCalled(t, "UpdateTargets", mock.Anything, mock.Anything, mock.Anything)
+				} else {
+					appMock.AssertCalled(t, "UpdateLib", "incubator/nginx", "prod", nginx)
+				}
+			})
+		})
+	}
+}
+
+func TestPromote_protected(t *testing.T) {
+	cases := []struct {
+		name       string
+		confirmEnv string
+		isErr      bool
+	}{
+		{
+			name:  "not confirmed",
+			isErr: true,
+		},
+		{
+			name:       "confirmed",
+			confirmEnv: "prod",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				moduleStaging := &mocks.Module{}
+				moduleStaging.On("Params", "staging").Return([]component.ModuleParameter{
+					{Component: "a", Key: "a", Value: `"a2"`},
+				}, nil)
+
+				moduleProd := &mocks.Module{}
+				moduleProd.On("Params", "prod").Return([]component.ModuleParameter{
+					{Component: "a", Key: "a", Value: `"a1"`},
+				}, nil)
+
+				appMock.On("Environment", "prod").Return(&app.EnvironmentConfig{
+					Name:      "prod",
+					Protected: true,
+				}, nil)
+
+				in := map[string]interface{}{
+					OptionApp:        appMock,
+					OptionEnvName1:   "staging",
+					OptionEnvName2:   "prod",
+					OptionParamOnly:  true,
+					OptionConfirmEnv: tc.confirmEnv,
+				}
+
+				p, err := NewPromote(in)
+				require.NoError(t, err)
+
+				p.guard.isTerminalFn = func() bool { return false }
+				p.modulesFromEnvFn = func(_ app.App, envName string) ([]component.Module, error) {
+					if envName == "staging" {
+						return []component.Module{moduleStaging}, nil
+					}
+					return []component.Module{moduleProd}, nil
+				}
+
+				var set [][]string
+				p.setEnvFn = func(_ app.App, envName, name, pName, value string) error {
+					set = append(set, []string{name, pName, value})
+					return nil
+				}
+
+				var buf bytes.Buffer
+				p.out = &buf
+
+				err = p.Run()
+				if tc.isErr {
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), `environment "prod" is protected`)
+					assert.Empty(t, set)
+					return
+				}
+
+				require.NoError(t, err)
+				assert.Equal(t, [][]string{{"a", "a", `"a2"`}}, set)
+			})
+		})
+	}
+}
+
+func TestPromote_same_environment(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:      appMock,
+			OptionEnvName1: "prod",
+			OptionEnvName2: "prod",
+		}
+
+		_, err := NewPromote(in)
+		require.Error(t, err)
+	})
+}
+
+func TestPromote_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewPromote(in)
+	require.Error(t, err)
+}
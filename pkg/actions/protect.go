@@ -0,0 +1,135 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/util/git"
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// envGuard guards changes to protected environments. A change is allowed if
+// the environment was named with --confirm-env, or if the user types its name
+// when asked on a terminal.
+type envGuard struct {
+	app        app.App
+	confirmEnv string
+
+	in           io.Reader
+	out          io.Writer
+	isTerminalFn func() bool
+	gitStatusFn  func(dir string) (git.Status, error)
+}
+
+func newEnvGuard(a app.App, confirmEnv string) *envGuard {
+	return &envGuard{
+		app:        a,
+		confirmEnv: confirmEnv,
+
+		in:  os.Stdin,
+		out: os.Stdout,
+		isTerminalFn: func() bool {
+			return terminal.IsTerminal(int(os.Stdin.Fd()))
+		},
+		gitStatusFn: git.WorkTreeStatus,
+	}
+}
+
+// check returns an error if changing the environment with the named command
+// has not been confirmed. If checkGit is true, the git working tree
+// requirements of the environment are checked as well.
+func (g *envGuard) check(envName, command string, checkGit bool) error {
+	if envName == "" {
+		return nil
+	}
+
+	e, err := g.app.Environment(envName)
+	if err != nil {
+		return err
+	}
+
+	if !e.Protected {
+		return nil
+	}
+
+	if checkGit {
+		if err := g.checkGit(e); err != nil {
+			return err
+		}
+	}
+
+	if g.confirmEnv != "" {
+		if g.confirmEnv != envName {
+			return errors.Errorf("environment %q is protected and does not match --confirm-env=%s", envName, g.confirmEnv)
+		}
+		return nil
+	}
+
+	if !g.isTerminalFn() {
+		return errors.Errorf("environment %q is protected; use --confirm-env=%s to %s it", envName, envName, command)
+	}
+
+	fmt.Fprintf(g.out, "Environment %q is protected. Type its name to %s it: ", envName, command)
+
+	answer, err := bufio.NewReader(g.in).ReadString('\n')
+	if err != nil && err != io.EOF {
+		return err
+	}
+
+	if strings.TrimSpace(answer) != envName {
+		return errors.Errorf("environment %q was not confirmed", envName)
+	}
+
+	return nil
+}
+
+// checkGit checks the app's git working tree against the requirements of a
+// protected environment.
+func (g *envGuard) checkGit(e *app.EnvironmentConfig) error {
+	if !e.RequireCleanTree && len(e.AllowedBranches) == 0 {
+		return nil
+	}
+
+	status, err := g.gitStatusFn(g.app.Root())
+	if err != nil {
+		return errors.Wrapf(err, "checking git working tree for protected environment %q", e.Name)
+	}
+
+	if e.RequireCleanTree && !status.Clean {
+		return errors.Errorf("environment %q requires a clean git working tree", e.Name)
+	}
+
+	if len(e.AllowedBranches) == 0 {
+		return nil
+	}
+
+	for _, pattern := range e.AllowedBranches {
+		if ok, _ := path.Match(pattern, status.Branch); ok && status.Branch != "" {
+			return nil
+		}
+	}
+
+	return errors.Errorf("environment %q can only be applied from branches %s",
+		e.Name, strings.Join(e.AllowedBranches, ", "))
+}
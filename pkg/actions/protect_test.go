@@ -0,0 +1,160 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/git"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_envGuard_check(t *testing.T) {
+	cases := []struct {
+		name       string
+		env        *app.EnvironmentConfig
+		confirmEnv string
+		isTerminal bool
+		input      string
+		checkGit   bool
+		status     git.Status
+		isErr      bool
+	}{
+		{
+			name: "not protected",
+			env:  &app.EnvironmentConfig{Name: "prod"},
+		},
+		{
+			name:  "protected without confirmation",
+			env:   &app.EnvironmentConfig{Name: "prod", Protected: true},
+			isErr: true,
+		},
+		{
+			name:       "protected with confirmation",
+			env:        &app.EnvironmentConfig{Name: "prod", Protected: true},
+			confirmEnv: "prod",
+		},
+		{
+			name:       "protected with wrong confirmation",
+			env:        &app.EnvironmentConfig{Name: "prod", Protected: true},
+			confirmEnv: "dev",
+			isErr:      true,
+		},
+		{
+			name:       "protected typed on a terminal",
+			env:        &app.EnvironmentConfig{Name: "prod", Protected: true},
+			isTerminal: true,
+			input:      "prod\n",
+		},
+		{
+			name:       "protected mistyped on a terminal",
+			env:        &app.EnvironmentConfig{Name: "prod", Protected: true},
+			isTerminal: true,
+			input:      "prd\n",
+			isErr:      true,
+		},
+		{
+			name: "dirty working tree",
+			env: &app.EnvironmentConfig{
+				Name: "prod", Protected: true, RequireCleanTree: true,
+			},
+			confirmEnv: "prod",
+			checkGit:   true,
+			status:     git.Status{Branch: "master"},
+			isErr:      true,
+		},
+		{
+			name: "dirty working tree without git check",
+			env: &app.EnvironmentConfig{
+				Name: "prod", Protected: true, RequireCleanTree: true,
+			},
+			confirmEnv: "prod",
+			status:     git.Status{Branch: "master"},
+		},
+		{
+			name: "allowed branch",
+			env: &app.EnvironmentConfig{
+				Name: "prod", Protected: true, RequireCleanTree: true,
+				AllowedBranches: []string{"master", "release/*"},
+			},
+			confirmEnv: "prod",
+			checkGit:   true,
+			status:     git.Status{Branch: "release/1.0", Clean: true},
+		},
+		{
+			name: "branch not allowed",
+			env: &app.EnvironmentConfig{
+				Name: "prod", Protected: true,
+				AllowedBranches: []string{"master", "release/*"},
+			},
+			confirmEnv: "prod",
+			checkGit:   true,
+			status:     git.Status{Branch: "feature"},
+			isErr:      true,
+		},
+		{
+			name: "detached head",
+			env: &app.EnvironmentConfig{
+				Name: "prod", Protected: true,
+				AllowedBranches: []string{"*"},
+			},
+			confirmEnv: "prod",
+			checkGit:   true,
+			status:     git.Status{Clean: true},
+			isErr:      true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				appMock.On("Environment", "prod").Return(tc.env, nil)
+
+				var out bytes.Buffer
+				g := newEnvGuard(appMock, tc.confirmEnv)
+				g.in = strings.NewReader(tc.input)
+				g.out = &out
+				g.isTerminalFn = func() bool { return tc.isTerminal }
+				g.gitStatusFn = func(dir string) (git.Status, error) {
+					require.Equal(t, "/", dir)
+					return tc.status, nil
+				}
+
+				err := g.check("prod", "apply", tc.checkGit)
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				if tc.isTerminal {
+					require.Contains(t, out.String(), `Environment "prod" is protected`)
+				}
+			})
+		})
+	}
+}
+
+func Test_envGuard_check_no_env(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		g := newEnvGuard(appMock, "")
+		require.NoError(t, g.check("", "apply", true))
+	})
+}
@@ -40,10 +40,12 @@ type RegistryAdd struct {
 	app        app.App
 	name       string
 	uri        string
+	bundle     string
 	isOverride bool
 	httpClient *http.Client
 
-	registryAddFn func(a app.App, protocol registry.Protocol, name string, uri string, isOverride bool, httpClient *http.Client) (*registry.Spec, error)
+	registryAddFn       func(a app.App, protocol registry.Protocol, name string, uri string, isOverride bool, httpClient *http.Client) (*registry.Spec, error)
+	registryAddBundleFn func(a app.App, name, bundlePath string, isOverride bool, httpClient *http.Client) (*registry.Spec, error)
 }
 
 // NewRegistryAdd creates an instance of RegistryAdd.
@@ -53,11 +55,13 @@ func NewRegistryAdd(m map[string]interface{}) (*RegistryAdd, error) {
 	ra := &RegistryAdd{
 		app:        ol.LoadApp(),
 		name:       ol.LoadString(OptionName),
-		uri:        ol.LoadString(OptionURI),
+		uri:        ol.LoadOptionalString(OptionURI),
+		bundle:     ol.LoadOptionalString(OptionBundle),
 		isOverride: ol.LoadBool(OptionOverride),
 		httpClient: ol.LoadHTTPClient(),
 
-		registryAddFn: registry.Add,
+		registryAddFn:       registry.Add,
+		registryAddBundleFn: registry.AddBundle,
 	}
 
 	if ol.err != nil {
@@ -67,8 +71,22 @@ func NewRegistryAdd(m map[string]interface{}) (*RegistryAdd, error) {
 	return ra, nil
 }
 
-// Run adds a registry.
+// Run adds a registry. Registries added from a bundle are extracted into
+// the app.
 func (ra *RegistryAdd) Run() error {
+	if ra.bundle != "" {
+		if ra.uri != "" {
+			return errors.New("a registry can not be added from both a URI and a bundle")
+		}
+
+		_, err := ra.registryAddBundleFn(ra.app, ra.name, ra.bundle, ra.isOverride, ra.httpClient)
+		return err
+	}
+
+	if ra.uri == "" {
+		return errors.New("registry URI is required")
+	}
+
 	rd, err := ra.protocol()
 	if err != nil {
 		return errors.Wrap(err, "detect registry protocol")
@@ -84,6 +102,15 @@ type registryDetails struct {
 }
 
 func (ra *RegistryAdd) protocol() (registryDetails, error) {
+	if registry.IsGitURI(ra.uri) {
+		rd := registryDetails{
+			URI:      ra.uri,
+			Protocol: registry.ProtocolGit,
+		}
+
+		return rd, nil
+	}
+
 	if ra.isGitHub() {
 		rd := registryDetails{
 			URI:      ra.uri,
@@ -99,21 +126,11 @@ func (ra *RegistryAdd) protocol() (registryDetails, error) {
 			return registryDetails{}, err
 		}
 
-		rd := registryDetails{
-			URI:      u.Path,
-			Protocol: registry.ProtocolFilesystem,
-		}
-
-		return rd, nil
+		return ra.localDetails(u.Path), nil
 	}
 
 	if strings.HasPrefix(ra.uri, "/") || strings.HasPrefix(ra.uri, ".") {
-		rd := registryDetails{
-			URI:      ra.uri,
-			Protocol: registry.ProtocolFilesystem,
-		}
-
-		return rd, nil
+		return ra.localDetails(ra.uri), nil
 	}
 
 	_, err := url.Parse(ra.uri)
@@ -130,6 +147,20 @@ func (ra *RegistryAdd) protocol() (registryDetails, error) {
 	return registryDetails{}, errors.Errorf("could not detect registry type for %s", ra.uri)
 }
 
+// localDetails returns the details of a registry in a local directory.
+// Directories of Helm charts are Helm registries.
+func (ra *RegistryAdd) localDetails(path string) registryDetails {
+	protocol := registry.ProtocolFilesystem
+	if registry.IsLocalChartDir(ra.app, path) {
+		protocol = registry.ProtocolHelm
+	}
+
+	return registryDetails{
+		URI:      path,
+		Protocol: protocol,
+	}
+}
+
 func (ra *RegistryAdd) isGitHub() bool {
 	return strings.HasPrefix(ra.uri, "github") ||
 		strings.HasPrefix(ra.uri, "https://github")
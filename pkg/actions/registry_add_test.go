@@ -22,6 +22,8 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/app"
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -30,6 +32,10 @@ func TestRegistryAdd(t *testing.T) {
 	withApp(t, func(appMock *amocks.App) {
 		name := "new"
 
+		chart := []byte("name: mychart\nversion: 0.1.0\n")
+		err := afero.WriteFile(appMock.Fs(), "/charts/mychart/Chart.yaml", chart, 0644)
+		require.NoError(t, err)
+
 		cases := []struct {
 			name        string
 			uri         string
@@ -75,6 +81,36 @@ func TestRegistryAdd(t *testing.T) {
 				expectedURI: "/path",
 				protocol:    registry.ProtocolFilesystem,
 			},
+			{
+				name:        "local charts",
+				uri:         "/charts",
+				expectedURI: "/charts",
+				protocol:    registry.ProtocolHelm,
+			},
+			{
+				name:        "local charts with URL",
+				uri:         "file:///charts",
+				expectedURI: "/charts",
+				protocol:    registry.ProtocolHelm,
+			},
+			{
+				name:        "git",
+				uri:         "https://git.example.com/org/parts.git//incubator#v1.0",
+				expectedURI: "https://git.example.com/org/parts.git//incubator#v1.0",
+				protocol:    registry.ProtocolGit,
+			},
+			{
+				name:        "git with scp-like address",
+				uri:         "git@git.example.com:org/parts",
+				expectedURI: "git@git.example.com:org/parts",
+				protocol:    registry.ProtocolGit,
+			},
+			{
+				name:        "git with bare repository",
+				uri:         "file:///srv/git/parts.git",
+				expectedURI: "file:///srv/git/parts.git",
+				protocol:    registry.ProtocolGit,
+			},
 			{
 				name:        "URL",
 				uri:         "https://kubernetes-charts.storage.googleapis.com",
@@ -114,6 +150,41 @@ func TestRegistryAdd(t *testing.T) {
 	})
 }
 
+func TestRegistryAdd_bundle(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:           appMock,
+			OptionName:          "offline",
+			OptionBundle:        "/registry.tgz",
+			OptionOverride:      false,
+			OptionTLSSkipVerify: false,
+		}
+
+		a, err := NewRegistryAdd(in)
+		require.NoError(t, err)
+
+		a.registryAddFn = func(a app.App, protocol registry.Protocol, name string, uri string, isOverride bool, httpClient *http.Client) (*registry.Spec, error) {
+			return nil, errors.New("unexpected")
+		}
+
+		var called bool
+		a.registryAddBundleFn = func(a app.App, name, bundlePath string, isOverride bool, httpClient *http.Client) (*registry.Spec, error) {
+			called = true
+			assert.Equal(t, "offline", name)
+			assert.Equal(t, "/registry.tgz", bundlePath)
+			return &registry.Spec{}, nil
+		}
+
+		err = a.Run()
+		require.NoError(t, err)
+		assert.True(t, called)
+
+		a.uri = "github.com/foo/bar"
+		err = a.Run()
+		require.Error(t, err)
+	})
+}
+
 func TestRegistryAdd_requires_app(t *testing.T) {
 	in := make(map[string]interface{})
 	_, err := NewRegistryAdd(in)
@@ -0,0 +1,96 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/pkg/errors"
+)
+
+// RunRegistryBundle runs `registry bundle`
+func RunRegistryBundle(m map[string]interface{}) error {
+	rb, err := NewRegistryBundle(m)
+	if err != nil {
+		return err
+	}
+
+	return rb.Run()
+}
+
+// RegistryBundle snapshots a registry into a bundle.
+type RegistryBundle struct {
+	app        app.App
+	name       string
+	path       string
+	packages   []string
+	httpClient *http.Client
+
+	bundleFn func(a app.App, name string, packages []string, w io.Writer, httpClient *http.Client) error
+	out      io.Writer
+}
+
+// NewRegistryBundle creates an instance of RegistryBundle.
+func NewRegistryBundle(m map[string]interface{}) (*RegistryBundle, error) {
+	ol := newOptionLoader(m)
+
+	rb := &RegistryBundle{
+		app:        ol.LoadApp(),
+		name:       ol.LoadString(OptionName),
+		path:       ol.LoadString(OptionPath),
+		packages:   ol.LoadStringSlice(OptionPkgNames),
+		httpClient: ol.LoadHTTPClient(),
+
+		bundleFn: registry.Bundle,
+		out:      os.Stdout,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return rb, nil
+}
+
+// Run writes the registry bundle.
+func (rb *RegistryBundle) Run() error {
+	if rb.path == "" {
+		return errors.New("bundle path is required")
+	}
+
+	f, err := rb.app.Fs().Create(rb.path)
+	if err != nil {
+		return errors.Wrap(err, "creating bundle")
+	}
+
+	if err = rb.bundleFn(rb.app, rb.name, rb.packages, f, rb.httpClient); err != nil {
+		f.Close()
+		rb.app.Fs().Remove(rb.path)
+		return err
+	}
+
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(rb.out, "Wrote bundle of registry %q to %s\n", rb.name, rb.path)
+	return nil
+}
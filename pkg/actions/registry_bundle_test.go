@@ -0,0 +1,95 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryBundle(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:           appMock,
+			OptionName:          "incubator",
+			OptionPath:          "/registry.tgz",
+			OptionPkgNames:      []string{"nginx@1.0.0"},
+			OptionTLSSkipVerify: false,
+		}
+
+		a, err := NewRegistryBundle(in)
+		require.NoError(t, err)
+
+		var buf bytes.Buffer
+		a.out = &buf
+		a.bundleFn = func(a app.App, name string, packages []string, w io.Writer, httpClient *http.Client) error {
+			assert.Equal(t, "incubator", name)
+			assert.Equal(t, []string{"nginx@1.0.0"}, packages)
+
+			_, err := w.Write([]byte("bundle"))
+			return err
+		}
+
+		err = a.Run()
+		require.NoError(t, err)
+
+		data, err := afero.ReadFile(appMock.Fs(), "/registry.tgz")
+		require.NoError(t, err)
+		assert.Equal(t, "bundle", string(data))
+		assert.Equal(t, "Wrote bundle of registry \"incubator\" to /registry.tgz\n", buf.String())
+	})
+}
+
+func TestRegistryBundle_failure(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:           appMock,
+			OptionName:          "incubator",
+			OptionPath:          "/registry.tgz",
+			OptionPkgNames:      []string{},
+			OptionTLSSkipVerify: false,
+		}
+
+		a, err := NewRegistryBundle(in)
+		require.NoError(t, err)
+
+		a.bundleFn = func(a app.App, name string, packages []string, w io.Writer, httpClient *http.Client) error {
+			return errors.New("failed")
+		}
+
+		err = a.Run()
+		require.Error(t, err)
+
+		exists, err := afero.Exists(appMock.Fs(), "/registry.tgz")
+		require.NoError(t, err)
+		assert.False(t, exists)
+	})
+}
+
+func TestRegistryBundle_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewRegistryBundle(in)
+	require.Error(t, err)
+}
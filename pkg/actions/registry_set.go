@@ -34,12 +34,13 @@ func RunRegistrySet(m map[string]interface{}) error {
 	ol := newOptionLoader(m)
 	name := ol.LoadString(OptionName)
 	uri := ol.LoadString(OptionURI)
+	keys := ol.LoadStringSlice(OptionPublicKeys)
 
 	if ol.err != nil {
 		return ol.err
 	}
 
-	return ru.run(name, uri)
+	return ru.run(name, uri, keys)
 }
 
 type locateFn func(app.App, *app.RegistryConfig) (registry.Setter, error)
@@ -100,7 +101,7 @@ func registryConfig(a app.App, name string) (*app.RegistryConfig, error) {
 }
 
 // run runs the registry set command.
-func (rs *RegistrySet) run(name string, uri string) error {
+func (rs *RegistrySet) run(name string, uri string, keys []string) error {
 	if rs == nil {
 		return errors.Errorf("nil receiver")
 	}
@@ -110,9 +111,40 @@ func (rs *RegistrySet) run(name string, uri string) error {
 		return err
 	}
 
+	if len(keys) > 0 {
+		if err := doSetPublicKeys(rs.app, cfg, keys); err != nil {
+			return err
+		}
+
+		if uri == "" {
+			return nil
+		}
+	}
+
 	return doSetURI(rs.app, rs.locateFn, cfg, uri)
 }
 
+// doSetPublicKeys pins the public keys packages from the specified registry
+// must be signed with.
+func doSetPublicKeys(a app.App, cfg *app.RegistryConfig, keys []string) error {
+	if a == nil {
+		return errors.Errorf("missing application")
+	}
+
+	newCfg := *cfg
+	newCfg.PublicKeys = keys
+	if _, err := registry.PublicKeys(&newCfg); err != nil {
+		return err
+	}
+
+	log.Debugf("setting registry %v public keys", cfg.Name)
+	if err := a.UpdateRegistry(&newCfg); err != nil {
+		return errors.Wrapf(err, "updating registry %v in app", cfg.Name)
+	}
+
+	return nil
+}
+
 // doSetURI sets the URI for the specified registry.
 func doSetURI(a app.App, locateFn locateFn, cfg *app.RegistryConfig, uri string) error {
 	if a == nil {
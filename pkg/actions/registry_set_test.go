@@ -214,3 +214,30 @@ func TestRegistryUpdate_doSetURI(t *testing.T) {
 		}
 	}
 }
+
+func TestRegistrySet_public_keys(t *testing.T) {
+	publicKey, _, err := registry.GenerateKey()
+	require.NoError(t, err)
+
+	cfg := &app.RegistryConfig{
+		Name:     "incubator",
+		Protocol: string(registry.ProtocolGitHub),
+		URI:      "github.com/ksonnet/parts/tree/master/incubator",
+	}
+
+	a := new(amocks.App)
+	a.On("Registries").Return(app.RegistryConfigs{"incubator": cfg}, nil)
+
+	expected := *cfg
+	expected.PublicKeys = []string{publicKey}
+	a.On("UpdateRegistry", &expected).Return(nil)
+
+	rs := &RegistrySet{app: a}
+
+	err = rs.run("incubator", "", []string{publicKey})
+	require.NoError(t, err)
+	a.AssertExpectations(t)
+
+	err = rs.run("incubator", "", []string{"invalid"})
+	require.Error(t, err)
+}
@@ -0,0 +1,146 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/ksonnet/ksonnet/pkg/registry"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// RunRegistrySign runs `registry sign`
+func RunRegistrySign(m map[string]interface{}) error {
+	rs, err := NewRegistrySign(m)
+	if err != nil {
+		return err
+	}
+
+	return rs.Run()
+}
+
+// RegistrySign signs a registry in a local directory.
+type RegistrySign struct {
+	fs         afero.Fs
+	path       string
+	privateKey string
+
+	signFn func(fs afero.Fs, dir, privateKey string) ([]string, error)
+	out    io.Writer
+}
+
+// NewRegistrySign creates an instance of RegistrySign.
+func NewRegistrySign(m map[string]interface{}) (*RegistrySign, error) {
+	ol := newOptionLoader(m)
+
+	rs := &RegistrySign{
+		fs:         ol.LoadFs(),
+		path:       ol.LoadString(OptionPath),
+		privateKey: ol.LoadString(OptionPrivateKey),
+
+		signFn: registry.Sign,
+		out:    os.Stdout,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return rs, nil
+}
+
+// Run signs the registry.
+func (rs *RegistrySign) Run() error {
+	key, err := afero.ReadFile(rs.fs, rs.privateKey)
+	if err != nil {
+		return errors.Wrap(err, "reading private key")
+	}
+
+	written, err := rs.signFn(rs.fs, rs.path, string(key))
+	if err != nil {
+		return err
+	}
+
+	for _, path := range written {
+		fmt.Fprintf(rs.out, "Wrote %s\n", path)
+	}
+
+	return nil
+}
+
+// RunRegistryKeygen runs `registry keygen`
+func RunRegistryKeygen(m map[string]interface{}) error {
+	rk, err := NewRegistryKeygen(m)
+	if err != nil {
+		return err
+	}
+
+	return rk.Run()
+}
+
+// RegistryKeygen generates a key pair for signing registries.
+type RegistryKeygen struct {
+	fs   afero.Fs
+	path string
+
+	generateFn func() (string, string, error)
+	out        io.Writer
+}
+
+// NewRegistryKeygen creates an instance of RegistryKeygen.
+func NewRegistryKeygen(m map[string]interface{}) (*RegistryKeygen, error) {
+	ol := newOptionLoader(m)
+
+	rk := &RegistryKeygen{
+		fs:   ol.LoadFs(),
+		path: ol.LoadString(OptionPath),
+
+		generateFn: registry.GenerateKey,
+		out:        os.Stdout,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return rk, nil
+}
+
+// Run writes the private key to a file and prints the public key.
+func (rk *RegistryKeygen) Run() error {
+	exists, err := afero.Exists(rk.fs, rk.path)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return errors.Errorf("%s already exists", rk.path)
+	}
+
+	publicKey, privateKey, err := rk.generateFn()
+	if err != nil {
+		return err
+	}
+
+	if err = afero.WriteFile(rk.fs, rk.path, []byte(privateKey+"\n"), 0600); err != nil {
+		return errors.Wrap(err, "writing private key")
+	}
+
+	fmt.Fprintf(rk.out, "Wrote private key to %s\nPublic key: %s\n", rk.path, publicKey)
+	return nil
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistrySign(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/keys/registry.key", []byte("private\n"), 0600))
+
+	in := map[string]interface{}{
+		OptionFs:         fs,
+		OptionPath:       "/registry",
+		OptionPrivateKey: "/keys/registry.key",
+	}
+
+	a, err := NewRegistrySign(in)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	a.out = &buf
+	a.signFn = func(_ afero.Fs, dir, privateKey string) ([]string, error) {
+		assert.Equal(t, "/registry", dir)
+		assert.Equal(t, "private\n", privateKey)
+		return []string{"/registry/registry.yaml", "/registry/registry.yaml.sig"}, nil
+	}
+
+	err = a.Run()
+	require.NoError(t, err)
+
+	assert.Equal(t, "Wrote /registry/registry.yaml\nWrote /registry/registry.yaml.sig\n", buf.String())
+}
+
+func TestRegistrySign_missing_key(t *testing.T) {
+	in := map[string]interface{}{
+		OptionFs:         afero.NewMemMapFs(),
+		OptionPath:       "/registry",
+		OptionPrivateKey: "/keys/registry.key",
+	}
+
+	a, err := NewRegistrySign(in)
+	require.NoError(t, err)
+
+	err = a.Run()
+	require.Error(t, err)
+}
+
+func TestRegistryKeygen(t *testing.T) {
+	fs := afero.NewMemMapFs()
+
+	in := map[string]interface{}{
+		OptionFs:   fs,
+		OptionPath: "/keys/registry.key",
+	}
+
+	a, err := NewRegistryKeygen(in)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	a.out = &buf
+	a.generateFn = func() (string, string, error) {
+		return "public", "private", nil
+	}
+
+	err = a.Run()
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(fs, "/keys/registry.key")
+	require.NoError(t, err)
+	assert.Equal(t, "private\n", string(data))
+	assert.Equal(t, "Wrote private key to /keys/registry.key\nPublic key: public\n", buf.String())
+
+	err = a.Run()
+	require.Error(t, err)
+}
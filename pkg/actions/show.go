@@ -45,9 +45,11 @@ type Show struct {
 	componentNames []string
 	envName        string
 	format         string
+	frozen         bool
 
-	out       io.Writer
-	runShowFn runShowFn
+	out         io.Writer
+	lockCheckFn func(app.App) error
+	runShowFn   runShowFn
 }
 
 // RunShow runs `show`
@@ -58,9 +60,11 @@ func newShow(m map[string]interface{}, opts ...showOpt) (*Show, error) {
 		app:            ol.LoadApp(),
 		componentNames: ol.LoadStringSlice(OptionComponentNames),
 		format:         ol.LoadString(OptionFormat),
+		frozen:         ol.LoadOptionalBool(OptionFrozen),
 
-		out:       os.Stdout,
-		runShowFn: cluster.RunShow,
+		out:         os.Stdout,
+		lockCheckFn: checkLock,
+		runShowFn:   cluster.RunShow,
 	}
 
 	if ol.err != nil {
@@ -79,6 +83,12 @@ func newShow(m map[string]interface{}, opts ...showOpt) (*Show, error) {
 }
 
 func (s *Show) run() error {
+	if s.frozen {
+		if err := s.lockCheckFn(s.app); err != nil {
+			return err
+		}
+	}
+
 	config := cluster.ShowConfig{
 		App:            s.app,
 		ComponentNames: s.componentNames,
@@ -19,8 +19,10 @@ import (
 	"os"
 	"testing"
 
+	"github.com/ksonnet/ksonnet/pkg/app"
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -87,6 +89,36 @@ func TestShow(t *testing.T) {
 	}
 }
 
+func TestShow_frozen(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		in := map[string]interface{}{
+			OptionApp:            appMock,
+			OptionComponentNames: []string{},
+			OptionEnvName:        "default",
+			OptionFormat:         "yaml",
+			OptionFrozen:         true,
+		}
+
+		var showCalled bool
+		opt := func(a *Show) {
+			a.lockCheckFn = func(app.App) error {
+				return errors.New("vendored packages do not match ks.lock")
+			}
+			a.runShowFn = func(cluster.ShowConfig, ...cluster.ShowOpts) error {
+				showCalled = true
+				return nil
+			}
+		}
+
+		a, err := newShow(in, opt)
+		require.NoError(t, err)
+
+		err = a.run()
+		require.Error(t, err)
+		assert.False(t, showCalled, "show should not run when the lock does not match")
+	})
+}
+
 func TestShow_invalid_input(t *testing.T) {
 	withApp(t, func(appMock *amocks.App) {
 		in := map[string]interface{}{
@@ -0,0 +1,127 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"os"
+	"sort"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// fileSnapshot holds the contents of files, so changes to them can be reverted.
+type fileSnapshot struct {
+	fs afero.Fs
+	// files maps paths to their contents. Paths which did not exist map to nil.
+	files map[string][]byte
+	// dirs maps directories to the paths which existed in them.
+	dirs map[string]map[string]bool
+}
+
+func newFileSnapshot(fs afero.Fs) *fileSnapshot {
+	return &fileSnapshot{
+		fs:    fs,
+		files: make(map[string][]byte),
+		dirs:  make(map[string]map[string]bool),
+	}
+}
+
+// addFile adds a file to the snapshot. If the file does not exist, it is
+// removed when the snapshot is restored.
+func (s *fileSnapshot) addFile(path string) error {
+	data, err := afero.ReadFile(s.fs, path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return err
+		}
+	} else if data == nil {
+		data = []byte{}
+	}
+
+	s.files[path] = data
+	return nil
+}
+
+// addDir adds the files in a directory tree to the snapshot. Files and directories
+// created in it are removed when the snapshot is restored.
+func (s *fileSnapshot) addDir(dir string) error {
+	existing := make(map[string]bool)
+
+	err := afero.Walk(s.fs, dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		existing[path] = true
+		if fi.IsDir() {
+			return nil
+		}
+
+		return s.addFile(path)
+	})
+
+	if err != nil {
+		return errors.Wrapf(err, "reading %s", dir)
+	}
+
+	s.dirs[dir] = existing
+	return nil
+}
+
+func (s *fileSnapshot) restore() error {
+	for dir, existing := range s.dirs {
+		var created []string
+		err := afero.Walk(s.fs, dir, func(path string, fi os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			if !existing[path] {
+				created = append(created, path)
+			}
+			return nil
+		})
+
+		if err != nil {
+			return err
+		}
+
+		// Parents sort before their children, which are removed with them.
+		sort.Strings(created)
+		for _, path := range created {
+			if err = s.fs.RemoveAll(path); err != nil {
+				return err
+			}
+		}
+	}
+
+	for path, data := range s.files {
+		if data == nil {
+			if err := s.fs.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+
+		if err := afero.WriteFile(s.fs, path, data, app.DefaultFilePermissions); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
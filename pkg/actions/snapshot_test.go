@@ -0,0 +1,60 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_fileSnapshot(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/components/params.libsonnet", []byte("params"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/components/a.jsonnet", []byte("a"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/environments/default/params.libsonnet", []byte("env"), 0644))
+
+	s := newFileSnapshot(fs)
+	require.NoError(t, s.addDir("/components"))
+	require.NoError(t, s.addFile("/environments/default/params.libsonnet"))
+	require.NoError(t, s.addFile("/environments/default/created.libsonnet"))
+
+	require.NoError(t, fs.Remove("/components/a.jsonnet"))
+	require.NoError(t, afero.WriteFile(fs, "/components/params.libsonnet", []byte("changed"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/components/nested/params.libsonnet", []byte("nested"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/environments/default/params.libsonnet", []byte("changed"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/environments/default/created.libsonnet", []byte("created"), 0644))
+
+	require.NoError(t, s.restore())
+
+	for path, expected := range map[string]string{
+		"/components/params.libsonnet":           "params",
+		"/components/a.jsonnet":                  "a",
+		"/environments/default/params.libsonnet": "env",
+	} {
+		data, err := afero.ReadFile(fs, path)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(data), path)
+	}
+
+	for _, path := range []string{"/components/nested", "/environments/default/created.libsonnet"} {
+		exists, err := afero.Exists(fs, path)
+		require.NoError(t, err)
+		assert.False(t, exists, path)
+	}
+}
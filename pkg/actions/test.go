@@ -0,0 +1,162 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/apptest"
+	"github.com/pkg/errors"
+)
+
+// RunTest runs `test`.
+func RunTest(m map[string]interface{}) error {
+	t, err := NewTest(m)
+	if err != nil {
+		return err
+	}
+
+	return t.Run()
+}
+
+// Test runs the tests of an application.
+type Test struct {
+	app       app.App
+	envName   string
+	update    bool
+	junitPath string
+
+	out    io.Writer
+	testFn func(a app.App, envName string, update bool) ([]apptest.Result, error)
+}
+
+// NewTest creates an instance of Test.
+func NewTest(m map[string]interface{}) (*Test, error) {
+	ol := newOptionLoader(m)
+
+	t := &Test{
+		app:       ol.LoadApp(),
+		envName:   ol.LoadOptionalString(OptionEnvName),
+		update:    ol.LoadOptionalBool(OptionUpdate),
+		junitPath: ol.LoadOptionalString(OptionJUnit),
+
+		out:    os.Stdout,
+		testFn: runAppTests,
+	}
+
+	if ol.err != nil {
+		return nil, ol.err
+	}
+
+	return t, nil
+}
+
+// Run runs the tests in each environment, or only in the supplied environment.
+func (t *Test) Run() error {
+	envNames, err := t.envNames()
+	if err != nil {
+		return err
+	}
+
+	var results []apptest.Result
+	for _, envName := range envNames {
+		envResults, err := t.testFn(t.app, envName, t.update)
+		if err != nil {
+			return errors.Wrapf(err, "testing environment %q", envName)
+		}
+
+		results = append(results, envResults...)
+	}
+
+	var failures int
+	for _, r := range results {
+		status := "PASS"
+		if !r.Passed() {
+			status = "FAIL"
+			failures++
+		}
+
+		fmt.Fprintf(t.out, "%s  %s  %s\n", status, r.EnvName, r.Name)
+		if !r.Passed() {
+			fmt.Fprintln(t.out, indent(r.Failure, "      "))
+		}
+	}
+
+	fmt.Fprintf(t.out, "\n%d tests, %d failed\n", len(results), failures)
+
+	if t.junitPath != "" {
+		if err := t.writeJUnit(results); err != nil {
+			return errors.Wrap(err, "writing JUnit report")
+		}
+	}
+
+	if failures > 0 {
+		return errors.Errorf("%d of %d tests failed", failures, len(results))
+	}
+
+	return nil
+}
+
+func (t *Test) envNames() ([]string, error) {
+	if t.envName != "" {
+		if _, err := t.app.Environment(t.envName); err != nil {
+			return nil, err
+		}
+
+		return []string{t.envName}, nil
+	}
+
+	envs, err := t.app.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	for name := range envs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+func (t *Test) writeJUnit(results []apptest.Result) error {
+	f, err := t.app.Fs().Create(t.junitPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return apptest.WriteJUnit(f, results)
+}
+
+func runAppTests(a app.App, envName string, update bool) ([]apptest.Result, error) {
+	return apptest.NewRunner(a, envName, update).Run()
+}
+
+func indent(s, prefix string) string {
+	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
+	for i := range lines {
+		lines[i] = prefix + lines[i]
+	}
+
+	return strings.Join(lines, "\n")
+}
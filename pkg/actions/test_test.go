@@ -0,0 +1,151 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package actions
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/apptest"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTest(t *testing.T) {
+	testResults := map[string][]apptest.Result{
+		"default": {
+			{EnvName: "default", Name: "tests/replicas.jsonnet"},
+			{EnvName: "default", Name: "tests/golden/default/guestbook.yaml"},
+		},
+		"prod": {
+			{EnvName: "prod", Name: "tests/replicas.jsonnet", Failure: "RUNTIME ERROR: Assertion failed. 1 != 3\n\tstd.jsonnet:(664:13)-(666:45)"},
+			{EnvName: "prod", Name: "tests/golden/prod/guestbook.yaml"},
+		},
+	}
+
+	cases := []struct {
+		name         string
+		envName      string
+		expectedFile string
+		isErr        bool
+	}{
+		{
+			name:         "all environments",
+			expectedFile: "test/output.txt",
+			isErr:        true,
+		},
+		{
+			name:         "supplied environment",
+			envName:      "default",
+			expectedFile: "test/env.txt",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				envs := app.EnvironmentConfigs{
+					"default": &app.EnvironmentConfig{Name: "default"},
+					"prod":    &app.EnvironmentConfig{Name: "prod"},
+				}
+				appMock.On("Environments").Return(envs, nil)
+				appMock.On("Environment", "default").Return(envs["default"], nil)
+
+				in := map[string]interface{}{
+					OptionApp:     appMock,
+					OptionEnvName: tc.envName,
+					OptionUpdate:  true,
+				}
+
+				a, err := NewTest(in)
+				require.NoError(t, err)
+
+				a.testFn = func(_ app.App, envName string, update bool) ([]apptest.Result, error) {
+					assert.True(t, update)
+					return testResults[envName], nil
+				}
+
+				var buf bytes.Buffer
+				a.out = &buf
+
+				err = a.Run()
+				if tc.isErr {
+					require.EqualError(t, err, "1 of 4 tests failed")
+				} else {
+					require.NoError(t, err)
+				}
+
+				assertOutput(t, tc.expectedFile, buf.String())
+			})
+		})
+	}
+}
+
+func TestTest_junit(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		appMock.On("Environment", "default").Return(&app.EnvironmentConfig{Name: "default"}, nil)
+
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionEnvName: "default",
+			OptionJUnit:   "/report.xml",
+		}
+
+		a, err := NewTest(in)
+		require.NoError(t, err)
+
+		a.testFn = func(app.App, string, bool) ([]apptest.Result, error) {
+			return []apptest.Result{{EnvName: "default", Name: "tests/replicas.jsonnet"}}, nil
+		}
+		a.out = &bytes.Buffer{}
+
+		require.NoError(t, a.Run())
+
+		b, err := afero.ReadFile(appMock.Fs(), "/report.xml")
+		require.NoError(t, err)
+		assert.Contains(t, string(b), `<testcase name="tests/replicas.jsonnet" classname="default"`)
+	})
+}
+
+func TestTest_test_error(t *testing.T) {
+	withApp(t, func(appMock *amocks.App) {
+		appMock.On("Environment", "default").Return(&app.EnvironmentConfig{Name: "default"}, nil)
+
+		in := map[string]interface{}{
+			OptionApp:     appMock,
+			OptionEnvName: "default",
+		}
+
+		a, err := NewTest(in)
+		require.NoError(t, err)
+
+		a.testFn = func(app.App, string, bool) ([]apptest.Result, error) {
+			return nil, errors.New("failed")
+		}
+
+		require.Error(t, a.Run())
+	})
+}
+
+func TestTest_requires_app(t *testing.T) {
+	in := make(map[string]interface{})
+	_, err := NewTest(in)
+	require.Error(t, err)
+}
@@ -43,10 +43,11 @@ func RunValidate(m map[string]interface{}) error {
 
 type discoveryFn func(a app.App, clientConfig *client.Config, envName string) (discovery.DiscoveryInterface, error)
 
-type validateObjectFn func(
-	a app.App,
-	obj *unstructured.Unstructured,
-	envName string) []error
+type objectValidator interface {
+	Validate(obj *unstructured.Unstructured) []error
+}
+
+type validatorFn func(a app.App, envName string) (objectValidator, error)
 
 type findObjectsFn func(a app.App, envName string,
 	componentNames []string) ([]*unstructured.Unstructured, error)
@@ -58,11 +59,12 @@ type Validate struct {
 	module         string
 	componentNames []string
 	clientConfig   *client.Config
+	offline        bool
 	out            io.Writer
 
-	discoveryFn      discoveryFn
-	validateObjectFn validateObjectFn
-	findObjectsFn    findObjectsFn
+	discoveryFn   discoveryFn
+	validatorFn   validatorFn
+	findObjectsFn findObjectsFn
 }
 
 // NewValidate creates an instance of Validate.
@@ -75,11 +77,12 @@ func NewValidate(m map[string]interface{}) (*Validate, error) {
 		module:         ol.LoadString(OptionModule),
 		componentNames: ol.LoadStringSlice(OptionComponentNames),
 		clientConfig:   ol.LoadClientConfig(),
+		offline:        ol.LoadOptionalBool(OptionOffline),
 
-		out:              os.Stdout,
-		discoveryFn:      loadDiscovery,
-		validateObjectFn: openapi.ValidateAgainstSchema,
-		findObjectsFn:    findObjects,
+		out:           os.Stdout,
+		discoveryFn:   loadDiscovery,
+		validatorFn:   loadValidator,
+		findObjectsFn: findObjects,
 	}
 
 	if ol.err != nil {
@@ -100,18 +103,32 @@ func (v *Validate) Run() error {
 		return err
 	}
 
-	disc, err := v.discoveryFn(v.app, v.clientConfig, v.envName)
+	validator, err := v.validatorFn(v.app, v.envName)
 	if err != nil {
 		return err
 	}
 
+	var disc discovery.DiscoveryInterface
+	if !v.offline {
+		disc, err = v.discoveryFn(v.app, v.clientConfig, v.envName)
+		if err != nil {
+			return err
+		}
+	}
+
 	var hasError bool
 
 	for _, obj := range objects {
-		desc := fmt.Sprintf("%s %s", utils.ResourceNameFor(disc, obj), utils.FqName(obj))
+		// without discovery, objects are described by their kind.
+		resourceName := utils.GroupVersionKindFor(obj)
+		if disc != nil {
+			resourceName = utils.ResourceNameFor(disc, obj)
+		}
+
+		desc := fmt.Sprintf("%s %s", resourceName, utils.FqName(obj))
 		log.Info("Validating ", desc)
 
-		errs := v.validateObjectFn(v.app, obj, v.envName)
+		errs := validator.Validate(obj)
 		for _, err := range errs {
 			log.Errorf("Error in %s: %v", desc, err)
 			hasError = true
@@ -130,6 +147,10 @@ func loadDiscovery(a app.App, clientConfig *client.Config, envName string) (disc
 	return d, err
 }
 
+func loadValidator(a app.App, envName string) (objectValidator, error) {
+	return openapi.NewValidator(a, envName)
+}
+
 func findObjects(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
 	p := pipeline.New(a, envName)
 	return p.Objects(componentNames)
@@ -97,8 +97,8 @@ func TestValidate(t *testing.T) {
 					return objects, nil
 				}
 
-				a.validateObjectFn = func(a app.App, obj *unstructured.Unstructured, envName string) []error {
-					return make([]error, 0)
+				a.validatorFn = func(a app.App, envName string) (objectValidator, error) {
+					return &stubValidator{}, nil
 				}
 
 				err = a.Run()
@@ -108,12 +108,87 @@ func TestValidate(t *testing.T) {
 	}
 }
 
+func TestValidate_offline(t *testing.T) {
+	cases := []struct {
+		name  string
+		errs  []error
+		isErr bool
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name:  "invalid",
+			errs:  []error{errors.New("$.spec.replica: unknown field")},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withApp(t, func(appMock *amocks.App) {
+				in := map[string]interface{}{
+					OptionApp:            appMock,
+					OptionEnvName:        "default",
+					OptionModule:         "",
+					OptionComponentNames: []string{},
+					OptionClientConfig:   &client.Config{},
+					OptionOffline:        true,
+				}
+
+				a, err := NewValidate(in)
+				require.NoError(t, err)
+
+				a.discoveryFn = func(app.App, *client.Config, string) (discovery.DiscoveryInterface, error) {
+					return nil, errors.New("offline validation should not use discovery")
+				}
+
+				obj := &unstructured.Unstructured{
+					Object: map[string]interface{}{
+						"apiVersion": "apps/v1",
+						"kind":       "Deployment",
+						"metadata":   map[string]interface{}{"name": "web"},
+					},
+				}
+				a.findObjectsFn = func(app.App, string, []string) ([]*unstructured.Unstructured, error) {
+					return []*unstructured.Unstructured{obj}, nil
+				}
+
+				validator := &stubValidator{errs: tc.errs}
+				a.validatorFn = func(_ app.App, envName string) (objectValidator, error) {
+					assert.Equal(t, "default", envName)
+					return validator, nil
+				}
+
+				err = a.Run()
+				if tc.isErr {
+					require.Error(t, err)
+				} else {
+					require.NoError(t, err)
+				}
+
+				assert.Equal(t, []*unstructured.Unstructured{obj}, validator.validated)
+			})
+		})
+	}
+}
+
 func TestValidate_requires_app(t *testing.T) {
 	in := make(map[string]interface{})
 	_, err := NewValidate(in)
 	require.Error(t, err)
 }
 
+type stubValidator struct {
+	errs      []error
+	validated []*unstructured.Unstructured
+}
+
+func (v *stubValidator) Validate(obj *unstructured.Unstructured) []error {
+	v.validated = append(v.validated, obj)
+	return v.errs
+}
+
 type stubDiscovery struct{}
 
 func (d *stubDiscovery) RESTClient() restclient.Interface {
@@ -381,7 +381,10 @@ func (ba *baseApp) Environment(name string) (*EnvironmentConfig, error) {
 		}
 	}
 
-	e := ba.mergedEnvironment(name)
+	e, err := ba.resolvedEnvironment(name)
+	if err != nil {
+		return nil, err
+	}
 	if e == nil {
 		return nil, errors.Errorf("environment %q was not found", name)
 	}
@@ -401,6 +404,18 @@ func deepCopyLibraries(src LibraryConfigs) LibraryConfigs {
 	return lc
 }
 
+func deepCopyDestinations(src []*EnvironmentDestinationSpec) []*EnvironmentDestinationSpec {
+	dests := make([]*EnvironmentDestinationSpec, 0, len(src))
+	for _, v := range src {
+		if v == nil {
+			continue
+		}
+		d := *v
+		dests = append(dests, &d)
+	}
+	return dests
+}
+
 func deepCopyEnvironmentConfig(src EnvironmentConfig) *EnvironmentConfig {
 	e := src
 
@@ -408,18 +423,46 @@ func deepCopyEnvironmentConfig(src EnvironmentConfig) *EnvironmentConfig {
 		d := *src.Destination
 		e.Destination = &d
 	}
+	if src.Destinations != nil {
+		e.Destinations = deepCopyDestinations(src.Destinations)
+	}
 	if src.Targets != nil {
 		t := make([]string, len(src.Targets))
 		copy(t, src.Targets)
 		e.Targets = t
 	}
+	if src.AllowedBranches != nil {
+		b := make([]string, len(src.AllowedBranches))
+		copy(b, src.AllowedBranches)
+		e.AllowedBranches = b
+	}
 	if src.Libraries != nil {
 		e.Libraries = deepCopyLibraries(src.Libraries)
 	}
+	if src.Lint != nil {
+		e.Lint = deepCopyLintConfig(src.Lint)
+	}
 
 	return &e
 }
 
+func deepCopyLintConfig(src *LintConfig) *LintConfig {
+	l := &LintConfig{}
+
+	if src.Rules != nil {
+		l.Rules = make(map[string]string)
+		for k, v := range src.Rules {
+			l.Rules[k] = v
+		}
+	}
+	if src.RequiredLabels != nil {
+		l.RequiredLabels = make([]string, len(src.RequiredLabels))
+		copy(l.RequiredLabels, src.RequiredLabels)
+	}
+
+	return l
+}
+
 // mergedEnvrionment returns a fresh copy of the named environment, merged with
 // optional overrides if present. Note overrides cannot override environment-scoped library
 // references.
@@ -451,10 +494,16 @@ func (ba *baseApp) mergedEnvironment(name string) *EnvironmentConfig {
 		combined.Name = override.Name
 		combined.KubernetesVersion = override.KubernetesVersion
 		combined.Path = override.Path
+		if override.Parent != "" {
+			combined.Parent = override.Parent
+		}
 		if override.Destination != nil {
 			d := *override.Destination
 			combined.Destination = &d
 		}
+		if override.Destinations != nil {
+			combined.Destinations = deepCopyDestinations(override.Destinations)
+		}
 		if override.Targets != nil {
 			t := make([]string, len(override.Targets))
 			copy(t, override.Targets)
@@ -469,7 +518,8 @@ func (ba *baseApp) mergedEnvironment(name string) *EnvironmentConfig {
 	}
 }
 
-// Environments returns all environment specs, merged with any corresponding overrides.
+// Environments returns all environment specs, merged with any corresponding overrides
+// and values inherited from parent environments.
 // Note overrides cannot override environment libraries.
 func (ba *baseApp) Environments() (EnvironmentConfigs, error) {
 	if !ba.loaded {
@@ -492,7 +542,10 @@ func (ba *baseApp) Environments() (EnvironmentConfigs, error) {
 	}
 
 	for k := range environments {
-		e := ba.mergedEnvironment(k)
+		e, err := ba.resolvedEnvironment(k)
+		if err != nil {
+			return nil, err
+		}
 		if e == nil {
 			delete(environments, k)
 			continue
@@ -505,7 +558,8 @@ func (ba *baseApp) Environments() (EnvironmentConfigs, error) {
 }
 
 // AddEnvironment adds an environment spec to the app spec. If the spec already exists,
-// it is overwritten.
+// it is overwritten. If the environment has a parent, values equal to the ones it
+// would inherit are not stored.
 func (ba *baseApp) AddEnvironment(newEnv *EnvironmentConfig, k8sSpecFlag string, isOverride bool) error {
 	log.WithFields(log.Fields{
 		"k8s-spec-flag": k8sSpecFlag,
@@ -520,10 +574,6 @@ func (ba *baseApp) AddEnvironment(newEnv *EnvironmentConfig, k8sSpecFlag string,
 		return errors.Errorf("invalid environment name")
 	}
 
-	if isOverride && len(newEnv.Libraries) > 0 {
-		return errors.Errorf("library references not allowed in overrides")
-	}
-
 	if err := ba.load(); err != nil {
 		return errors.Wrap(err, "load configuration")
 	}
@@ -537,6 +587,33 @@ func (ba *baseApp) AddEnvironment(newEnv *EnvironmentConfig, k8sSpecFlag string,
 		newEnv.KubernetesVersion = ver
 	}
 
+	if newEnv.Parent != "" {
+		parent, err := ba.resolvedEnvironment(newEnv.Parent)
+		if err != nil {
+			return err
+		}
+		if parent == nil {
+			return errors.Errorf("parent environment %q was not found", newEnv.Parent)
+		}
+		if err := ba.checkParentCycle(newEnv.Name, newEnv.Parent); err != nil {
+			return err
+		}
+
+		newEnv = detachInherited(parent, newEnv)
+	}
+
+	if len(newEnv.Destinations) > 0 && newEnv.Destination != nil &&
+		*newEnv.Destination == *newEnv.Destinations[0] {
+		// Destination was populated from Destinations when the environment was read.
+		e := *newEnv
+		e.Destination = nil
+		newEnv = &e
+	}
+
+	if isOverride && len(newEnv.Libraries) > 0 {
+		return errors.Errorf("library references not allowed in overrides")
+	}
+
 	var envMap = ba.config.Environments
 	if isOverride {
 		if ba.overrides == nil {
@@ -560,6 +637,22 @@ func (ba *baseApp) Libraries() (LibraryConfigs, error) {
 	return ba.config.Libraries, nil
 }
 
+// LintConfig returns the lint configuration of the application. Environments
+// can override it.
+func (ba *baseApp) LintConfig() (*LintConfig, error) {
+	if !ba.loaded {
+		if err := ba.load(); err != nil {
+			return nil, errors.Wrap(err, "load configuration")
+		}
+	}
+
+	if ba.config.Lint == nil {
+		return &LintConfig{}, nil
+	}
+
+	return deepCopyLintConfig(ba.config.Lint), nil
+}
+
 // Registries returns application registries.
 func (ba *baseApp) Registries() (RegistryConfigs, error) {
 	if !ba.loaded {
@@ -596,6 +689,17 @@ func (ba *baseApp) RemoveEnvironment(envName string, override bool) error {
 		return errors.Errorf("environment %q does not exist", envName)
 	}
 
+	remaining := ba.overrides.Environments
+	if override {
+		remaining = ba.config.Environments
+	}
+
+	if _, ok := remaining[envName]; !ok {
+		if children := ba.children(envName); len(children) > 0 {
+			return errors.Errorf("environment %q is the parent of %s", envName, strings.Join(children, ", "))
+		}
+	}
+
 	delete(envMap, envName)
 
 	return ba.save()
@@ -619,6 +723,14 @@ func (ba *baseApp) RenameEnvironment(from, to string, override bool) error {
 	envMap[to].Path = to
 	delete(envMap, from)
 
+	for _, envs := range []EnvironmentConfigs{ba.config.Environments, ba.overrides.Environments} {
+		for _, e := range envs {
+			if e != nil && e.Parent == from {
+				e.Parent = to
+			}
+		}
+	}
+
 	if err := moveEnvironment(ba.fs, ba.root, from, to); err != nil {
 		return err
 	}
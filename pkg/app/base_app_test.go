@@ -417,6 +417,26 @@ func Test_baseApp_load_override(t *testing.T) {
 	require.True(t, ok)
 }
 
+func Test_baseApp_LintConfig(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	stageFile(t, fs, "app030_lint.yaml", "/app.yaml")
+
+	ba := NewBaseApp(fs, "/", nil)
+
+	lint, err := ba.LintConfig()
+	require.NoError(t, err)
+
+	expected := &LintConfig{
+		Rules:          map[string]string{"image-latest": "error", "resource-requests": "off"},
+		RequiredLabels: []string{"app"},
+	}
+	require.Equal(t, expected, lint)
+
+	e, err := ba.Environment("default")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"resource-requests": "error"}, e.Lint.Rules)
+}
+
 func Test_baseApp_load_override_invalid(t *testing.T) {
 	fs := afero.NewMemMapFs()
 
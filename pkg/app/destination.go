@@ -0,0 +1,78 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+// destinationApp is an App where one environment is pinned to a single
+// destination.
+type destinationApp struct {
+	App
+
+	envName     string
+	destination EnvironmentDestinationSpec
+}
+
+var _ App = (*destinationApp)(nil)
+
+// WithDestination returns an App which reports dest as the destination of
+// environment envName. It is used to run an action against each destination
+// of an environment with multiple destinations.
+func WithDestination(a App, envName string, dest *EnvironmentDestinationSpec) App {
+	return &destinationApp{
+		App:         a,
+		envName:     envName,
+		destination: *dest,
+	}
+}
+
+// Environment returns the spec for an environment.
+func (da *destinationApp) Environment(name string) (*EnvironmentConfig, error) {
+	e, err := da.App.Environment(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if name == da.envName {
+		e = da.pin(e)
+	}
+
+	return e, nil
+}
+
+// Environments returns all environment specs.
+func (da *destinationApp) Environments() (EnvironmentConfigs, error) {
+	envs, err := da.App.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	result := EnvironmentConfigs{}
+	for k, v := range envs {
+		if k == da.envName {
+			v = da.pin(v)
+		}
+		result[k] = v
+	}
+
+	return result, nil
+}
+
+func (da *destinationApp) pin(e *EnvironmentConfig) *EnvironmentConfig {
+	pinned := deepCopyEnvironmentConfig(*e)
+	d := da.destination
+	pinned.Destination = &d
+	pinned.Destinations = []*EnvironmentDestinationSpec{&d}
+	return pinned
+}
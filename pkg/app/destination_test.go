@@ -0,0 +1,82 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func multiDestinationApp() *baseApp {
+	ba := inheritingApp()
+	ba.config.Environments["staging"].Destinations = []*EnvironmentDestinationSpec{
+		{Name: "us", Server: "http://us.staging.com", Namespace: "staging"},
+		{Name: "eu", Server: "http://eu.staging.com", Namespace: "staging", Params: "eu.libsonnet"},
+	}
+	ba.config.Environments["staging-eu"].Destination = nil
+
+	return ba
+}
+
+func Test_baseApp_Environment_destinations(t *testing.T) {
+	ba := multiDestinationApp()
+
+	e, err := ba.Environment("staging")
+	require.NoError(t, err)
+	require.Len(t, e.Destinations, 2)
+	assert.Equal(t, e.Destinations[0], e.Destination)
+
+	// Children without a destination of their own inherit every destination.
+	e, err = ba.Environment("staging-eu-canary")
+	require.NoError(t, err)
+	require.Len(t, e.Destinations, 2)
+	assert.Equal(t, "eu", e.Destinations[1].Name)
+}
+
+func TestWithDestination(t *testing.T) {
+	ba := multiDestinationApp()
+
+	staging, err := ba.Environment("staging")
+	require.NoError(t, err)
+
+	a := WithDestination(ba, "staging", staging.Destinations[1])
+
+	e, err := a.Environment("staging")
+	require.NoError(t, err)
+	assert.Equal(t, "http://eu.staging.com", e.Destination.Server)
+	assert.Equal(t, "eu.libsonnet", e.Destination.Params)
+	assert.Equal(t, []*EnvironmentDestinationSpec{e.Destination}, e.Destinations)
+
+	envs, err := a.Environments()
+	require.NoError(t, err)
+	assert.Equal(t, e, envs["staging"])
+	assert.Len(t, envs["staging-eu-canary"].Destinations, 2)
+
+	// The wrapped app is left untouched.
+	staging, err = ba.Environment("staging")
+	require.NoError(t, err)
+	assert.Len(t, staging.Destinations, 2)
+}
+
+func TestEnvironmentDestinationSpec_String(t *testing.T) {
+	d := &EnvironmentDestinationSpec{Server: "http://example.com", Namespace: "default"}
+	assert.Equal(t, "http://example.com (default)", d.String())
+
+	d.Name = "us"
+	assert.Equal(t, "us", d.String())
+}
@@ -0,0 +1,280 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"reflect"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// EnvironmentChain returns the environment and its ancestors, starting with
+// the root of the hierarchy and ending with the named environment.
+func EnvironmentChain(a App, name string) ([]*EnvironmentConfig, error) {
+	if a == nil {
+		return nil, errors.New("nil app")
+	}
+
+	var chain []*EnvironmentConfig
+	seen := make(map[string]bool)
+
+	for cur := name; cur != ""; {
+		if seen[cur] {
+			return nil, errors.Errorf("environment %q has a cyclic parent chain", name)
+		}
+		seen[cur] = true
+
+		e, err := a.Environment(cur)
+		if err != nil {
+			return nil, err
+		}
+
+		if e.Name == "" {
+			e.Name = cur
+		}
+
+		chain = append([]*EnvironmentConfig{e}, chain...)
+		cur = e.Parent
+	}
+
+	return chain, nil
+}
+
+// inheritEnvironment returns a copy of child with unset values filled in from
+// parent. Libraries are merged, with the child's references taking precedence.
+// Children of protected environments are protected as well.
+func inheritEnvironment(parent, child *EnvironmentConfig) *EnvironmentConfig {
+	e := deepCopyEnvironmentConfig(*child)
+	if parent == nil {
+		return e
+	}
+
+	if e.KubernetesVersion == "" {
+		e.KubernetesVersion = parent.KubernetesVersion
+	}
+
+	if e.Destination == nil && len(e.Destinations) == 0 && len(parent.Destinations) > 0 {
+		e.Destinations = deepCopyDestinations(parent.Destinations)
+	}
+
+	if parent.Destination != nil && len(e.Destinations) == 0 {
+		if e.Destination == nil {
+			e.Destination = &EnvironmentDestinationSpec{}
+		}
+		if e.Destination.Server == "" && e.Destination.Context == "" && !e.Destination.InCluster {
+			e.Destination.Server = parent.Destination.Server
+			e.Destination.Context = parent.Destination.Context
+			e.Destination.Kubeconfig = parent.Destination.Kubeconfig
+			e.Destination.InCluster = parent.Destination.InCluster
+		}
+		if e.Destination.Namespace == "" {
+			e.Destination.Namespace = parent.Destination.Namespace
+		}
+	}
+
+	if len(e.Targets) == 0 && len(parent.Targets) > 0 {
+		e.Targets = make([]string, len(parent.Targets))
+		copy(e.Targets, parent.Targets)
+	}
+
+	e.Protected = e.Protected || parent.Protected
+	e.RequireCleanTree = e.RequireCleanTree || parent.RequireCleanTree
+	if len(e.AllowedBranches) == 0 && len(parent.AllowedBranches) > 0 {
+		e.AllowedBranches = make([]string, len(parent.AllowedBranches))
+		copy(e.AllowedBranches, parent.AllowedBranches)
+	}
+
+	if parent.Lint != nil {
+		lint := deepCopyLintConfig(parent.Lint)
+		if e.Lint != nil {
+			for k, v := range e.Lint.Rules {
+				if lint.Rules == nil {
+					lint.Rules = make(map[string]string)
+				}
+				lint.Rules[k] = v
+			}
+			if len(e.Lint.RequiredLabels) > 0 {
+				lint.RequiredLabels = e.Lint.RequiredLabels
+			}
+		}
+		e.Lint = lint
+	}
+
+	if parent.Libraries != nil {
+		libs := deepCopyLibraries(parent.Libraries)
+		for k, v := range e.Libraries {
+			libs[k] = v
+		}
+		e.Libraries = libs
+	}
+
+	return e
+}
+
+// detachInherited returns a copy of child without the values it would
+// inherit from parent anyway. This keeps resolved configurations from being
+// flattened into the child when they are written back to app.yaml.
+func detachInherited(parent, child *EnvironmentConfig) *EnvironmentConfig {
+	e := deepCopyEnvironmentConfig(*child)
+	if parent == nil {
+		return e
+	}
+
+	if e.KubernetesVersion == parent.KubernetesVersion {
+		e.KubernetesVersion = ""
+	}
+
+	if e.Destination != nil && parent.Destination != nil {
+		if e.Destination.Server == parent.Destination.Server &&
+			e.Destination.Context == parent.Destination.Context &&
+			e.Destination.Kubeconfig == parent.Destination.Kubeconfig &&
+			e.Destination.InCluster == parent.Destination.InCluster {
+			e.Destination.Server = ""
+			e.Destination.Context = ""
+			e.Destination.Kubeconfig = ""
+			e.Destination.InCluster = false
+		}
+		if e.Destination.Namespace == parent.Destination.Namespace {
+			e.Destination.Namespace = ""
+		}
+		if *e.Destination == (EnvironmentDestinationSpec{}) {
+			e.Destination = nil
+		}
+	}
+
+	if reflect.DeepEqual(e.Destinations, parent.Destinations) {
+		e.Destinations = nil
+	}
+
+	if reflect.DeepEqual(e.Targets, parent.Targets) {
+		e.Targets = nil
+	}
+
+	if parent.Protected {
+		e.Protected = false
+	}
+	if parent.RequireCleanTree {
+		e.RequireCleanTree = false
+	}
+	if reflect.DeepEqual(e.AllowedBranches, parent.AllowedBranches) {
+		e.AllowedBranches = nil
+	}
+
+	if e.Lint != nil && parent.Lint != nil {
+		for k, v := range e.Lint.Rules {
+			if pv, ok := parent.Lint.Rules[k]; ok && pv == v {
+				delete(e.Lint.Rules, k)
+			}
+		}
+		if len(e.Lint.Rules) == 0 {
+			e.Lint.Rules = nil
+		}
+		if reflect.DeepEqual(e.Lint.RequiredLabels, parent.Lint.RequiredLabels) {
+			e.Lint.RequiredLabels = nil
+		}
+		if e.Lint.Rules == nil && e.Lint.RequiredLabels == nil {
+			e.Lint = nil
+		}
+	}
+
+	for k, v := range e.Libraries {
+		if pv, ok := parent.Libraries[k]; ok && reflect.DeepEqual(pv, v) {
+			delete(e.Libraries, k)
+		}
+	}
+	if len(e.Libraries) == 0 {
+		e.Libraries = nil
+	}
+
+	return e
+}
+
+// resolvedEnvironment returns the named environment merged with overrides
+// and with values inherited from its parents.
+// Returns nil if the environment does not exist.
+func (ba *baseApp) resolvedEnvironment(name string) (*EnvironmentConfig, error) {
+	var chain []*EnvironmentConfig
+	seen := make(map[string]bool)
+
+	for cur := name; cur != ""; {
+		if seen[cur] {
+			return nil, errors.Errorf("environment %q has a cyclic parent chain", name)
+		}
+		seen[cur] = true
+
+		e := ba.mergedEnvironment(cur)
+		if e == nil {
+			if cur == name {
+				return nil, nil
+			}
+			return nil, errors.Errorf("parent environment %q of %q was not found", cur, name)
+		}
+
+		chain = append(chain, e)
+		cur = e.Parent
+	}
+
+	var resolved *EnvironmentConfig
+	for i := len(chain) - 1; i >= 0; i-- {
+		resolved = inheritEnvironment(resolved, chain[i])
+	}
+
+	if len(resolved.Destinations) > 0 {
+		d := *resolved.Destinations[0]
+		resolved.Destination = &d
+	}
+
+	return resolved, nil
+}
+
+// checkParentCycle returns an error if making parent the parent of name would
+// create a cycle.
+func (ba *baseApp) checkParentCycle(name, parent string) error {
+	seen := make(map[string]bool)
+	for cur := parent; cur != ""; {
+		if cur == name || seen[cur] {
+			return errors.Errorf("environment %q can't inherit from %q: parent chain is cyclic", name, parent)
+		}
+		seen[cur] = true
+
+		e := ba.mergedEnvironment(cur)
+		if e == nil {
+			return nil
+		}
+		cur = e.Parent
+	}
+
+	return nil
+}
+
+// children returns the names of environments which name the environment as their parent.
+func (ba *baseApp) children(name string) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, envs := range []EnvironmentConfigs{ba.config.Environments, ba.overrides.Environments} {
+		for k, v := range envs {
+			if v != nil && v.Parent == name && !seen[k] {
+				seen[k] = true
+				names = append(names, k)
+			}
+		}
+	}
+
+	sort.Strings(names)
+	return names
+}
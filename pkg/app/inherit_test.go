@@ -0,0 +1,187 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package app
+
+import (
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func inheritingApp() *baseApp {
+	fs := afero.NewMemMapFs()
+	ba := NewBaseApp(fs, "/", nil, optNoopLoader())
+	ba.config.Environments = EnvironmentConfigs{
+		"staging": &EnvironmentConfig{
+			Name:              "staging",
+			KubernetesVersion: "v1.8.0",
+			Path:              "staging",
+			Destination: &EnvironmentDestinationSpec{
+				Server:    "http://staging.com",
+				Namespace: "staging",
+			},
+			Targets: []string{"app"},
+			Libraries: LibraryConfigs{
+				"incubator/nginx": &LibraryConfig{Name: "nginx", Registry: "incubator", Version: "1.0.0"},
+				"incubator/redis": &LibraryConfig{Name: "redis", Registry: "incubator", Version: "1.0.0"},
+			},
+		},
+		"staging-eu": &EnvironmentConfig{
+			Name:   "staging-eu",
+			Path:   "staging-eu",
+			Parent: "staging",
+			Destination: &EnvironmentDestinationSpec{
+				Server: "http://eu.staging.com",
+			},
+			Libraries: LibraryConfigs{
+				"incubator/redis": &LibraryConfig{Name: "redis", Registry: "incubator", Version: "2.0.0"},
+			},
+		},
+		"staging-eu-canary": &EnvironmentConfig{
+			Name:    "staging-eu-canary",
+			Path:    "staging-eu-canary",
+			Parent:  "staging-eu",
+			Targets: []string{"canary"},
+		},
+	}
+
+	return ba
+}
+
+func Test_baseApp_Environment_inherits(t *testing.T) {
+	ba := inheritingApp()
+
+	expected := &EnvironmentConfig{
+		Name:              "staging-eu-canary",
+		KubernetesVersion: "v1.8.0",
+		Path:              "staging-eu-canary",
+		Parent:            "staging-eu",
+		Destination: &EnvironmentDestinationSpec{
+			Server:    "http://eu.staging.com",
+			Namespace: "staging",
+		},
+		Targets: []string{"canary"},
+		Libraries: LibraryConfigs{
+			"incubator/nginx": &LibraryConfig{Name: "nginx", Registry: "incubator", Version: "1.0.0"},
+			"incubator/redis": &LibraryConfig{Name: "redis", Registry: "incubator", Version: "2.0.0"},
+		},
+	}
+
+	e, err := ba.Environment("staging-eu-canary")
+	require.NoError(t, err)
+	assert.Equal(t, expected, e)
+
+	envs, err := ba.Environments()
+	require.NoError(t, err)
+	assert.Equal(t, expected, envs["staging-eu-canary"])
+}
+
+func Test_baseApp_Environment_inherit_errors(t *testing.T) {
+	cases := []struct {
+		name   string
+		parent map[string]string
+	}{
+		{
+			name:   "missing parent",
+			parent: map[string]string{"staging": "missing"},
+		},
+		{
+			name:   "cycle",
+			parent: map[string]string{"staging": "staging-eu-canary"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ba := inheritingApp()
+			for k, v := range tc.parent {
+				ba.config.Environments[k].Parent = v
+			}
+
+			_, err := ba.Environment("staging-eu-canary")
+			require.Error(t, err)
+		})
+	}
+}
+
+func Test_baseApp_AddEnvironment_detaches_inherited(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	stageFile(t, fs, "app030_app.yaml", "/app.yaml")
+	ba := NewBaseApp(fs, "/", nil)
+
+	e, err := ba.Environment("default")
+	require.NoError(t, err)
+
+	e.Name = "child"
+	e.Path = "child"
+	e.Parent = "default"
+	e.Destination.Namespace = "child"
+
+	err = ba.AddEnvironment(e, "", false)
+	require.NoError(t, err)
+
+	stored := ba.config.Environments["child"]
+	expected := &EnvironmentConfig{
+		Name:   "child",
+		Path:   "child",
+		Parent: "default",
+		Destination: &EnvironmentDestinationSpec{
+			Namespace: "child",
+		},
+	}
+	assert.Equal(t, expected, stored)
+
+	resolved, err := ba.Environment("child")
+	require.NoError(t, err)
+	assert.Equal(t, "v1.7.0", resolved.KubernetesVersion)
+	assert.Equal(t, "http://example.com", resolved.Destination.Server)
+
+	err = ba.RemoveEnvironment("default", false)
+	require.Error(t, err)
+
+	e.Name = "default"
+	e.Parent = "child"
+	err = ba.AddEnvironment(e, "", false)
+	require.Error(t, err)
+}
+
+func Test_baseApp_Environment_inherits_lint(t *testing.T) {
+	ba := inheritingApp()
+	ba.config.Environments["staging"].Lint = &LintConfig{
+		Rules:          map[string]string{"image-latest": "error", "host-path": "warning"},
+		RequiredLabels: []string{"app"},
+	}
+	ba.config.Environments["staging-eu"].Lint = &LintConfig{
+		Rules: map[string]string{"host-path": "off"},
+	}
+
+	e, err := ba.Environment("staging-eu-canary")
+	require.NoError(t, err)
+
+	expected := &LintConfig{
+		Rules:          map[string]string{"image-latest": "error", "host-path": "off"},
+		RequiredLabels: []string{"app"},
+	}
+	assert.Equal(t, expected, e.Lint)
+
+	parent, err := ba.Environment("staging-eu")
+	require.NoError(t, err)
+
+	detached := detachInherited(parent, e)
+	assert.Nil(t, detached.Lint)
+}
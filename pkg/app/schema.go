@@ -89,6 +89,9 @@ type EnvironmentConfig = EnvironmentConfig030
 // address that the environment points to.
 type EnvironmentDestinationSpec = EnvironmentDestinationSpec030
 
+// LintConfig configures the lint rules of an application or environment.
+type LintConfig = LintConfig030
+
 // LibraryConfig is the specification for a library part.
 type LibraryConfig = LibraryConfig030
 
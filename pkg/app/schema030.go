@@ -43,6 +43,7 @@ type Spec030 struct {
 	Environments EnvironmentConfigs030 `json:"environments,omitempty"`
 	Libraries    LibraryConfigs030     `json:"libraries,omitempty"`
 	License      string                `json:"license,omitempty"`
+	Lint         *LintConfig030        `json:"lint,omitempty"`
 }
 
 // RepositorySpec030 defines the spec for the upstream repository of this project.
@@ -61,6 +62,9 @@ type RegistryConfig030 struct {
 	Protocol string `json:"protocol"`
 	// URI is the location of the registry.
 	URI string `json:"uri"`
+	// PublicKeys are base64 encoded ed25519 public keys. If any are set,
+	// packages from this registry must be signed by one of them.
+	PublicKeys []string `json:"publicKeys,omitempty"`
 }
 
 // RegistryConfigs030 is a map of the registry name to a RegistryConfig.
@@ -127,11 +131,31 @@ type EnvironmentConfig030 struct {
 	Path string `json:"path"`
 	// Destination stores the cluster address that this environment points to.
 	Destination *EnvironmentDestinationSpec030 `json:"destination"`
+	// Destinations stores the cluster addresses of environments that are
+	// deployed to more than one cluster. When set, the first entry is used
+	// wherever a single destination is required.
+	Destinations []*EnvironmentDestinationSpec030 `json:"destinations,omitempty" yaml:"destinations,omitempty"`
 	// Targets contain the relative component paths that this environment
 	// wishes to deploy on it's destination.
 	Targets []string `json:"targets,omitempty"`
 	// Libraries specifies versioned libraries specifically used by this environment.
 	Libraries LibraryConfigs030 `json:"libraries,omitempty"`
+	// Parent is the name of the environment this environment inherits from.
+	// Params, main.jsonnet overrides, targets and libraries of the parent are
+	// applied before this environment's own.
+	Parent string `json:"parent,omitempty" yaml:"parent,omitempty"`
+	// Protected environments require confirmation before commands change
+	// them or the clusters they point to.
+	Protected bool `json:"protected,omitempty" yaml:"protected,omitempty"`
+	// RequireCleanTree requires the app's git working tree to be clean
+	// before a protected environment is applied.
+	RequireCleanTree bool `json:"requireCleanTree,omitempty" yaml:"requireCleanTree,omitempty"`
+	// AllowedBranches are the git branches, or branch patterns, a protected
+	// environment can be applied from.
+	AllowedBranches []string `json:"allowedBranches,omitempty" yaml:"allowedBranches,omitempty"`
+	// Lint configures the lint rules for this environment. Its rules take
+	// precedence over the rules of the application and of parent environments.
+	Lint *LintConfig030 `json:"lint,omitempty" yaml:"lint,omitempty"`
 }
 
 // MakePath return the absolute path to the environment directory.
@@ -145,11 +169,50 @@ func (e *EnvironmentConfig030) MakePath(rootPath string) string {
 // EnvironmentDestinationSpec030 contains the specification for the cluster
 // address that the environment points to.
 type EnvironmentDestinationSpec030 struct {
+	// Name optionally identifies the destination in environments with
+	// multiple destinations.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
 	// Server is the Kubernetes server that the cluster is running on.
 	Server string `json:"server"`
 	// Namespace is the namespace of the Kubernetes server that targets should
 	// be deployed to. This is "default", if not specified.
 	Namespace string `json:"namespace"`
+	// Params is an optional params file, relative to the environment
+	// directory, which is evaluated after the environment's params when
+	// targeting this destination.
+	Params string `json:"params,omitempty" yaml:"params,omitempty"`
+	// Context is an optional kubeconfig context. When it is set, the
+	// cluster and credentials of the context are used instead of looking up
+	// a context by server.
+	Context string `json:"context,omitempty" yaml:"context,omitempty"`
+	// Kubeconfig is an optional path to the kubeconfig file containing
+	// Context.
+	Kubeconfig string `json:"kubeconfig,omitempty" yaml:"kubeconfig,omitempty"`
+	// InCluster uses the service account of the pod ks is running in.
+	InCluster bool `json:"inCluster,omitempty" yaml:"inCluster,omitempty"`
+}
+
+// String returns the name of the destination, or its server and namespace
+// if it is unnamed.
+func (d *EnvironmentDestinationSpec030) String() string {
+	if d.Name != "" {
+		return d.Name
+	}
+
+	if d.Context != "" {
+		return fmt.Sprintf("%s (%s)", d.Context, d.Namespace)
+	}
+
+	return fmt.Sprintf("%s (%s)", d.Server, d.Namespace)
+}
+
+// LintConfig030 configures the lint rules of an application or environment.
+type LintConfig030 struct {
+	// Rules maps rule names to their severity. Supported severities are
+	// `error`, `warning`, `info` and `off`.
+	Rules map[string]string `json:"rules,omitempty" yaml:"rules,omitempty"`
+	// RequiredLabels are the labels objects are required to have.
+	RequiredLabels []string `json:"requiredLabels,omitempty" yaml:"requiredLabels,omitempty"`
 }
 
 // LibraryConfig030 is the specification for a library part.
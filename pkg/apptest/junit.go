@@ -0,0 +1,112 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package apptest
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+type junitTestSuites struct {
+	XMLName xml.Name         `xml:"testsuites"`
+	Suites  []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit writes results as a JUnit XML report. Each environment is a test
+// suite.
+func WriteJUnit(w io.Writer, results []Result) error {
+	var suites junitTestSuites
+	index := make(map[string]int)
+	durations := make(map[string]time.Duration)
+
+	for _, r := range results {
+		i, ok := index[r.EnvName]
+		if !ok {
+			i = len(suites.Suites)
+			index[r.EnvName] = i
+			suites.Suites = append(suites.Suites, junitTestSuite{Name: r.EnvName})
+		}
+
+		tc := junitTestCase{
+			Name:      r.Name,
+			ClassName: r.EnvName,
+			Time:      junitTime(r.Duration),
+		}
+
+		suite := &suites.Suites[i]
+		suite.Tests++
+		if !r.Passed() {
+			suite.Failures++
+			tc.Failure = &junitFailure{Message: firstLine(r.Failure), Text: r.Failure}
+		}
+
+		durations[r.EnvName] += r.Duration
+		suite.TestCases = append(suite.TestCases, tc)
+	}
+
+	for i := range suites.Suites {
+		suites.Suites[i].Time = junitTime(durations[suites.Suites[i].Name])
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(&suites); err != nil {
+		return err
+	}
+
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+func junitTime(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+func firstLine(s string) string {
+	for i, c := range s {
+		if c == '\n' {
+			return s[:i]
+		}
+	}
+
+	return s
+}
@@ -0,0 +1,398 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+// Package apptest runs the tests of a ksonnet application.
+package apptest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/cluster"
+	"github.com/ksonnet/ksonnet/pkg/diff"
+	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/ksonnet/ksonnet/pkg/ksonnet"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/pipeline"
+	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// TestsDirName is the name of the directory which houses the tests of an
+	// application.
+	TestsDirName = "tests"
+	// GoldenDirName is the name of the directory in the tests directory which
+	// houses the golden files of the components, by environment.
+	GoldenDirName = "golden"
+)
+
+// Result is the result of a test.
+type Result struct {
+	// EnvName is the environment the test ran in.
+	EnvName string
+	// Name is the name of the test, the path of its file relative to the
+	// application root.
+	Name string
+	// Failure describes why the test failed. It is blank if the test passed.
+	Failure string
+	// Duration is how long the test took.
+	Duration time.Duration
+}
+
+// Passed returns true if the test passed.
+func (r *Result) Passed() bool {
+	return r.Failure == ""
+}
+
+// Runner runs the tests of an application in an environment. Jsonnet tests are
+// files in the tests directory which evaluate without errors, usually using
+// assertions on the objects of the environment. Golden tests compare the
+// objects of each component to a golden file.
+type Runner struct {
+	app     app.App
+	envName string
+	update  bool
+
+	objectsFn func(a app.App, envName string) ([]*unstructured.Unstructured, error)
+	paramsFn  func(a app.App, envName string) (string, error)
+	vmFn      func(a app.App, envName string) (*jsonnet.VM, func() error, error)
+}
+
+// NewRunner creates an instance of Runner. If update is true, golden files
+// are updated instead of being compared.
+func NewRunner(a app.App, envName string, update bool) *Runner {
+	return &Runner{
+		app:     a,
+		envName: envName,
+		update:  update,
+
+		objectsFn: renderObjects,
+		paramsFn:  evaluateParams,
+		vmFn: func(a app.App, envName string) (*jsonnet.VM, func() error, error) {
+			return env.VM(a, envName)
+		},
+	}
+}
+
+func renderObjects(a app.App, envName string) ([]*unstructured.Unstructured, error) {
+	return pipeline.New(a, envName).Objects(nil)
+}
+
+// evaluateParams evaluates the params of the components in the root module,
+// which are imported by components as __ksonnet/params.
+func evaluateParams(a app.App, envName string) (string, error) {
+	return pipeline.New(a, envName).EnvParameters("/", true)
+}
+
+// Run runs the tests.
+func (r *Runner) Run() ([]Result, error) {
+	start := time.Now()
+	objects, err := r.objectsFn(r.app, r.envName)
+	if err != nil {
+		// nothing can be tested if the environment can't be rendered.
+		return []Result{r.result("render", start, err.Error())}, nil
+	}
+
+	var results []Result
+
+	jsonnetResults, err := r.runJsonnetTests(objects)
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, jsonnetResults...)
+
+	goldenResults, err := r.runGoldenTests(objects)
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, goldenResults...)
+
+	return results, nil
+}
+
+func (r *Runner) result(name string, start time.Time, failure string) Result {
+	return Result{
+		EnvName:  r.envName,
+		Name:     name,
+		Failure:  failure,
+		Duration: time.Since(start),
+	}
+}
+
+// runJsonnetTests evaluates the Jsonnet files in the tests directory.
+func (r *Runner) runJsonnetTests(objects []*unstructured.Unstructured) ([]Result, error) {
+	paths, err := r.jsonnetTests()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(paths) == 0 {
+		return nil, nil
+	}
+
+	objectsData, err := json.Marshal(objects)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding objects")
+	}
+
+	params, err := r.paramsFn(r.app, r.envName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "evaluating params for environment %s", r.envName)
+	}
+
+	var results []Result
+	for _, path := range paths {
+		start := time.Now()
+		name := r.relPath(path)
+		log.WithField("env-name", r.envName).Debugf("running %s", name)
+
+		var failure string
+		if err := r.evaluate(path, string(objectsData), params); err != nil {
+			failure = err.Error()
+		}
+
+		results = append(results, r.result(name, start, failure))
+	}
+
+	return results, nil
+}
+
+func (r *Runner) evaluate(path, objects, params string) error {
+	source, err := afero.ReadFile(r.app.Fs(), path)
+	if err != nil {
+		return err
+	}
+
+	vm, cleanup, err := r.vmFn(r.app, r.envName)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	vm.ExtCode(env.ObjectsExtCodeKey, objects)
+	vm.ExtCode(ksonnet.ParamsExtCodeKey, params)
+
+	_, err = vm.EvaluateSnippet(path, string(source))
+	return err
+}
+
+// jsonnetTests returns the paths of the Jsonnet tests. Golden files are not
+// searched.
+func (r *Runner) jsonnetTests() ([]string, error) {
+	root := filepath.Join(r.app.Root(), TestsDirName)
+	goldenRoot := filepath.Join(root, GoldenDirName)
+
+	exists, err := afero.DirExists(r.app.Fs(), root)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	var paths []string
+	err = afero.Walk(r.app.Fs(), root, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if fi.IsDir() {
+			if path == goldenRoot {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		if filepath.Ext(path) == ".jsonnet" {
+			paths = append(paths, path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "finding tests")
+	}
+
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// runGoldenTests compares the objects of each component to its golden file,
+// or updates the golden files.
+func (r *Runner) runGoldenTests(objects []*unstructured.Unstructured) ([]Result, error) {
+	dir := filepath.Join(r.app.Root(), TestsDirName, GoldenDirName, r.envName)
+
+	rendered, err := renderComponents(objects)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.update {
+		return r.updateGoldenFiles(dir, rendered)
+	}
+
+	fis, err := afero.ReadDir(r.app.Fs(), dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var results []Result
+	for _, fi := range fis {
+		if fi.IsDir() || filepath.Ext(fi.Name()) != ".yaml" {
+			continue
+		}
+
+		start := time.Now()
+		path := filepath.Join(dir, fi.Name())
+		componentName := strings.TrimSuffix(fi.Name(), ".yaml")
+
+		expected, err := afero.ReadFile(r.app.Fs(), path)
+		if err != nil {
+			return nil, err
+		}
+
+		failure, err := compareGolden(componentName, expected, rendered)
+		if err != nil {
+			return nil, err
+		}
+
+		results = append(results, r.result(r.relPath(path), start, failure))
+	}
+
+	return results, nil
+}
+
+// updateGoldenFiles writes the golden files of the rendered components, and
+// removes the golden files of components which are no longer rendered.
+func (r *Runner) updateGoldenFiles(dir string, rendered map[string][]byte) ([]Result, error) {
+	fs := r.app.Fs()
+
+	if err := fs.MkdirAll(dir, app.DefaultFolderPermissions); err != nil {
+		return nil, err
+	}
+
+	fis, err := afero.ReadDir(fs, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, fi := range fis {
+		componentName := strings.TrimSuffix(fi.Name(), ".yaml")
+		if _, ok := rendered[componentName]; ok || fi.IsDir() || filepath.Ext(fi.Name()) != ".yaml" {
+			continue
+		}
+
+		path := filepath.Join(dir, fi.Name())
+		log.Infof("Removing golden file %s", r.relPath(path))
+		if err := fs.Remove(path); err != nil {
+			return nil, err
+		}
+	}
+
+	var componentNames []string
+	for componentName := range rendered {
+		componentNames = append(componentNames, componentName)
+	}
+	sort.Strings(componentNames)
+
+	var results []Result
+	for _, componentName := range componentNames {
+		start := time.Now()
+		path := filepath.Join(dir, componentName+".yaml")
+
+		if err := afero.WriteFile(fs, path, rendered[componentName], app.DefaultFilePermissions); err != nil {
+			return nil, err
+		}
+
+		log.Debugf("updated golden file %s", r.relPath(path))
+		results = append(results, r.result(r.relPath(path), start, ""))
+	}
+
+	return results, nil
+}
+
+// compareGolden compares the golden file of a component to its rendered
+// objects. It returns a description of the differences.
+func compareGolden(componentName string, expected []byte, rendered map[string][]byte) (string, error) {
+	actual, ok := rendered[componentName]
+	if !ok {
+		return fmt.Sprintf("component %q is not rendered; run with --update to remove its golden file", componentName), nil
+	}
+
+	if bytes.Equal(expected, actual) {
+		return "", nil
+	}
+
+	r, err := diff.Text(bytes.NewReader(expected), bytes.NewReader(actual))
+	if err != nil {
+		return "", err
+	}
+
+	changes, err := ioutil.ReadAll(r)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("objects of component %q differ from the golden file; run with --update to update it\n%s",
+		componentName, changes), nil
+}
+
+// renderComponents renders the objects of each component as YAML.
+func renderComponents(objects []*unstructured.Unstructured) (map[string][]byte, error) {
+	byComponent := make(map[string][]*unstructured.Unstructured)
+	for _, obj := range objects {
+		componentName := obj.GetLabels()[metadata.LabelComponent]
+		if componentName == "" {
+			continue
+		}
+
+		byComponent[componentName] = append(byComponent[componentName], obj)
+	}
+
+	rendered := make(map[string][]byte)
+	for componentName, componentObjects := range byComponent {
+		cluster.UnstructuredSlice(componentObjects).Sort()
+
+		var buf bytes.Buffer
+		if err := pipeline.Fprint(&buf, componentObjects, "yaml"); err != nil {
+			return nil, err
+		}
+
+		rendered[componentName] = buf.Bytes()
+	}
+
+	return rendered, nil
+}
+
+func (r *Runner) relPath(path string) string {
+	rel, err := filepath.Rel(r.app.Root(), path)
+	if err != nil {
+		return path
+	}
+
+	return rel
+}
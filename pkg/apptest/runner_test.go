@@ -0,0 +1,230 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package apptest
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	appmocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func testObject(componentName, kind, name string) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name": name,
+				"labels": map[string]interface{}{
+					metadata.LabelComponent: componentName,
+				},
+			},
+		},
+	}
+}
+
+func withRunner(t *testing.T, update bool, fn func(*Runner, afero.Fs)) {
+	fs := afero.NewMemMapFs()
+	a := &appmocks.App{}
+	a.On("Fs").Return(fs)
+	a.On("Root").Return("/app")
+
+	r := NewRunner(a, "default", update)
+	r.objectsFn = func(app.App, string) ([]*unstructured.Unstructured, error) {
+		return []*unstructured.Unstructured{
+			testObject("guestbook", "Service", "guestbook"),
+			testObject("redis", "Service", "redis"),
+		}, nil
+	}
+	r.paramsFn = func(app.App, string) (string, error) {
+		return `{"components": {"guestbook": {"name": "guestbook"}}}`, nil
+	}
+	r.vmFn = func(app.App, string) (*jsonnet.VM, func() error, error) {
+		return jsonnet.NewVM(), func() error { return nil }, nil
+	}
+
+	fn(r, fs)
+}
+
+func stageFile(t *testing.T, fs afero.Fs, path, content string) {
+	require.NoError(t, fs.MkdirAll(filepath.Dir(path), app.DefaultFolderPermissions))
+	require.NoError(t, afero.WriteFile(fs, path, []byte(content), app.DefaultFilePermissions))
+}
+
+func stripDurations(results []Result) []Result {
+	for i := range results {
+		results[i].Duration = 0
+	}
+
+	return results
+}
+
+func TestRunner_Run_jsonnet(t *testing.T) {
+	withRunner(t, false, func(r *Runner, fs afero.Fs) {
+		stageFile(t, fs, "/app/tests/count.jsonnet",
+			`local objects = std.extVar("__ksonnet/objects"); std.assertEqual(2, std.length(objects))`)
+		stageFile(t, fs, "/app/tests/services/name.jsonnet",
+			`local objects = std.extVar("__ksonnet/objects"); std.assertEqual("web", objects[0].metadata.name)`)
+		stageFile(t, fs, "/app/tests/README.md", "not a test")
+		stageFile(t, fs, "/app/tests/golden/default/ignored.jsonnet", `error "not a test"`)
+
+		results, err := r.Run()
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+
+		assert.Equal(t, "tests/count.jsonnet", results[0].Name)
+		assert.True(t, results[0].Passed())
+
+		assert.Equal(t, "tests/services/name.jsonnet", results[1].Name)
+		assert.Equal(t, "default", results[1].EnvName)
+		assert.False(t, results[1].Passed())
+		assert.Contains(t, results[1].Failure, "Assertion failed")
+	})
+}
+
+func TestRunner_Run_jsonnet_component(t *testing.T) {
+	// Imports are read from the OS file system.
+	dir, err := ioutil.TempDir("", "apptest")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	component := `local params = std.extVar("__ksonnet/params").components.guestbook;
+{apiVersion: "v1", kind: "Service", metadata: {name: params.name}}`
+	err = ioutil.WriteFile(filepath.Join(dir, "guestbook.jsonnet"), []byte(component), 0644)
+	require.NoError(t, err)
+
+	withRunner(t, false, func(r *Runner, fs afero.Fs) {
+		r.vmFn = func(app.App, string) (*jsonnet.VM, func() error, error) {
+			vm := jsonnet.NewVM()
+			vm.AddJPath(dir)
+			return vm, func() error { return nil }, nil
+		}
+
+		stageFile(t, fs, "/app/tests/component.jsonnet",
+			`local guestbook = import "guestbook.jsonnet"; std.assertEqual("guestbook", guestbook.metadata.name)`)
+
+		results, err := r.Run()
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		assert.True(t, results[0].Passed(), results[0].Failure)
+	})
+}
+
+func TestRunner_Run_golden(t *testing.T) {
+	withRunner(t, false, func(r *Runner, fs afero.Fs) {
+		rendered, err := renderComponents([]*unstructured.Unstructured{
+			testObject("guestbook", "Service", "guestbook"),
+		})
+		require.NoError(t, err)
+
+		stageFile(t, fs, "/app/tests/golden/default/guestbook.yaml", string(rendered["guestbook"]))
+		stageFile(t, fs, "/app/tests/golden/default/redis.yaml", "---\nkind: Service\n")
+		stageFile(t, fs, "/app/tests/golden/default/removed.yaml", "---\n")
+		stageFile(t, fs, "/app/tests/golden/staging/redis.yaml", "---\n")
+
+		results, err := r.Run()
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+
+		assert.Equal(t, "tests/golden/default/guestbook.yaml", results[0].Name)
+		assert.True(t, results[0].Passed())
+
+		assert.Equal(t, "tests/golden/default/redis.yaml", results[1].Name)
+		assert.Contains(t, results[1].Failure, `objects of component "redis" differ from the golden file`)
+
+		assert.Equal(t, "tests/golden/default/removed.yaml", results[2].Name)
+		assert.Contains(t, results[2].Failure, `component "removed" is not rendered`)
+	})
+}
+
+func TestRunner_Run_update(t *testing.T) {
+	withRunner(t, true, func(r *Runner, fs afero.Fs) {
+		stageFile(t, fs, "/app/tests/golden/default/redis.yaml", "---\n")
+		stageFile(t, fs, "/app/tests/golden/default/removed.yaml", "---\n")
+
+		results, err := r.Run()
+		require.NoError(t, err)
+
+		expected := []Result{
+			{EnvName: "default", Name: "tests/golden/default/guestbook.yaml"},
+			{EnvName: "default", Name: "tests/golden/default/redis.yaml"},
+		}
+		require.Equal(t, expected, stripDurations(results))
+
+		exists, err := afero.Exists(fs, "/app/tests/golden/default/removed.yaml")
+		require.NoError(t, err)
+		assert.False(t, exists)
+
+		b, err := afero.ReadFile(fs, "/app/tests/golden/default/redis.yaml")
+		require.NoError(t, err)
+		assert.Contains(t, string(b), "name: redis")
+
+		r.update = false
+		results, err = r.Run()
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		for _, result := range results {
+			assert.True(t, result.Passed(), result.Failure)
+		}
+	})
+}
+
+func TestRunner_Run_render_failure(t *testing.T) {
+	withRunner(t, false, func(r *Runner, fs afero.Fs) {
+		stageFile(t, fs, "/app/tests/count.jsonnet", `true`)
+
+		r.objectsFn = func(app.App, string) ([]*unstructured.Unstructured, error) {
+			return nil, errors.New("failed")
+		}
+
+		results, err := r.Run()
+		require.NoError(t, err)
+
+		expected := []Result{
+			{EnvName: "default", Name: "render", Failure: "failed"},
+		}
+		require.Equal(t, expected, stripDurations(results))
+	})
+}
+
+func TestWriteJUnit(t *testing.T) {
+	results := []Result{
+		{EnvName: "default", Name: "tests/count.jsonnet", Duration: 1500 * time.Millisecond},
+		{EnvName: "default", Name: "tests/golden/default/redis.yaml", Failure: "objects differ\n-a\n+b"},
+		{EnvName: "staging", Name: "tests/count.jsonnet", Duration: 250 * time.Millisecond},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteJUnit(&buf, results))
+
+	expected, err := ioutil.ReadFile(filepath.Join("testdata", "junit.xml"))
+	require.NoError(t, err)
+
+	require.Equal(t, string(expected), buf.String())
+}
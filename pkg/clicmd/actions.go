@@ -25,20 +25,26 @@ type initName int
 
 const (
 	actionApply initName = iota
+	actionComponentConvert
+	actionComponentDescribe
 	actionComponentList
+	actionComponentMv
 	actionComponentRm
 	actionDelete
 	actionDiff
 	actionEnvAdd
+	actionEnvClone
 	actionEnvCurrent
 	actionEnvDescribe
 	actionEnvList
+	actionEnvPatchAdd
 	actionEnvRm
 	actionEnvSet
 	actionEnvTargets
 	actionEnvUpdate
 	actionImport
 	actionInit
+	actionLint
 	actionModuleCreate
 	actionModuleList
 	actionParamDelete
@@ -49,17 +55,26 @@ const (
 	actionPkgDescribe
 	actionPkgInstall
 	actionPkgList
+	actionPkgOutdated
 	actionPkgRemove
+	actionPkgSearch
+	actionPkgUpgrade
+	actionPkgVerify
 	actionPrototypeDescribe
 	actionPrototypeList
 	actionPrototypePreview
 	actionPrototypeSearch
 	actionPrototypeUse
+	actionPromote
 	actionRegistryAdd
+	actionRegistryBundle
 	actionRegistryDescribe
+	actionRegistryKeygen
 	actionRegistryList
 	actionRegistrySet
+	actionRegistrySign
 	actionShow
+	actionTest
 	actionUpgrade
 	actionValidate
 )
@@ -69,20 +84,26 @@ type actionFn func(map[string]interface{}) error
 var (
 	actionFns = map[initName]actionFn{
 		actionApply:             actions.RunApply,
+		actionComponentConvert:  actions.RunComponentConvert,
+		actionComponentDescribe: actions.RunComponentDescribe,
 		actionComponentList:     actions.RunComponentList,
+		actionComponentMv:       actions.RunComponentMv,
 		actionComponentRm:       actions.RunComponentRm,
 		actionDelete:            actions.RunDelete,
 		actionDiff:              actions.RunDiff,
 		actionEnvAdd:            actions.RunEnvAdd,
+		actionEnvClone:          actions.RunEnvClone,
 		actionEnvCurrent:        actions.RunEnvCurrent,
 		actionEnvDescribe:       actions.RunEnvDescribe,
 		actionEnvList:           actions.RunEnvList,
+		actionEnvPatchAdd:       actions.RunEnvPatchAdd,
 		actionEnvRm:             actions.RunEnvRm,
 		actionEnvSet:            actions.RunEnvSet,
 		actionEnvTargets:        actions.RunEnvTargets,
 		actionEnvUpdate:         actions.RunEnvUpdate,
 		actionImport:            actions.RunImport,
 		actionInit:              actions.RunInit,
+		actionLint:              actions.RunLint,
 		actionModuleCreate:      actions.RunModuleCreate,
 		actionModuleList:        actions.RunModuleList,
 		actionParamDiff:         actions.RunParamDiff,
@@ -93,17 +114,26 @@ var (
 		actionPkgDescribe:       actions.RunPkgDescribe,
 		actionPkgInstall:        actions.RunPkgInstall,
 		actionPkgList:           actions.RunPkgList,
+		actionPkgOutdated:       actions.RunPkgOutdated,
 		actionPkgRemove:         actions.RunPkgRemove,
+		actionPkgSearch:         actions.RunPkgSearch,
+		actionPkgUpgrade:        actions.RunPkgUpgrade,
+		actionPkgVerify:         actions.RunPkgVerify,
 		actionPrototypeDescribe: actions.RunPrototypeDescribe,
 		actionPrototypeList:     actions.RunPrototypeList,
 		actionPrototypePreview:  actions.RunPrototypePreview,
 		actionPrototypeSearch:   actions.RunPrototypeSearch,
 		actionPrototypeUse:      actions.RunPrototypeUse,
+		actionPromote:           actions.RunPromote,
 		actionRegistryAdd:       actions.RunRegistryAdd,
+		actionRegistryBundle:    actions.RunRegistryBundle,
 		actionRegistryDescribe:  actions.RunRegistryDescribe,
+		actionRegistryKeygen:    actions.RunRegistryKeygen,
 		actionRegistryList:      actions.RunRegistryList,
 		actionRegistrySet:       actions.RunRegistrySet,
+		actionRegistrySign:      actions.RunRegistrySign,
 		actionShow:              actions.RunShow,
+		actionTest:              actions.RunTest,
 		actionUpgrade:           actions.RunUpgrade,
 		actionValidate:          actions.RunValidate,
 	}
@@ -25,11 +25,14 @@ import (
 )
 
 const (
-	vApplyComponent = "apply-components"
-	vApplyCreate    = "apply-create"
-	vApplyGcTag     = "apply-gc-tag"
-	vApplyDryRun    = "apply-dry-run"
-	vApplySkipGc    = "apply-skip-gc"
+	vApplyComponent  = "apply-components"
+	vApplyConfirmEnv = "apply-confirm-env"
+	vApplyCreate     = "apply-create"
+	vApplyGcTag      = "apply-gc-tag"
+	vApplyDryRun     = "apply-dry-run"
+	vApplyFrozen     = "apply-frozen"
+	vApplyParallel   = "apply-parallel"
+	vApplySkipGc     = "apply-skip-gc"
 
 	applyShortDesc = "Apply local Kubernetes manifests (components) to remote clusters"
 	applyLong      = `
@@ -44,6 +47,14 @@ expanded using the parameters of the specified environment.
 By default, all component manifests are applied. To apply a subset of components,
 use the ` + "`--component` " + `flag, as seen in the examples below.
 
+Components that set ` + "`dependsOn`" + ` in their parameters are applied after the
+components they depend on, once the objects of those components are ready. For
+example, a Job has to complete and a Deployment has to be fully rolled out.
+
+If the environment lists multiple ` + "`destinations`" + `, the manifests are applied to
+each of them in turn, or concurrently with ` + "`--parallel`" + `. A summary of which
+destinations succeeded and failed is printed at the end.
+
 Note that this command needs to be run *within* a ksonnet app directory.
 
 ### Related Commands
@@ -97,10 +108,13 @@ func newApplyCmd(fs afero.Fs) *cobra.Command {
 			m := map[string]interface{}{
 				actions.OptionClientConfig:   applyClientConfig,
 				actions.OptionComponentNames: viper.GetStringSlice(vApplyComponent),
+				actions.OptionConfirmEnv:     viper.GetString(vApplyConfirmEnv),
 				actions.OptionCreate:         viper.GetBool(vApplyCreate),
 				actions.OptionDryRun:         viper.GetBool(vApplyDryRun),
 				actions.OptionEnvName:        envName,
+				actions.OptionFrozen:         viper.GetBool(vApplyFrozen),
 				actions.OptionGcTag:          viper.GetString(vApplyGcTag),
+				actions.OptionParallel:       viper.GetBool(vApplyParallel),
 				actions.OptionSkipGc:         viper.GetBool(vApplySkipGc),
 			}
 			addGlobalOptions(m)
@@ -133,5 +147,11 @@ func newApplyCmd(fs afero.Fs) *cobra.Command {
 	applyCmd.Flags().Bool(flagDryRun, false, "Option to preview the list of operations without changing the cluster state")
 	viper.BindPFlag(vApplyDryRun, applyCmd.Flags().Lookup(flagDryRun))
 
+	applyCmd.Flags().Bool(flagParallel, false, "Option to apply to all destinations of a multi-destination environment concurrently")
+	viper.BindPFlag(vApplyParallel, applyCmd.Flags().Lookup(flagParallel))
+
+	addCmdConfirmEnv(applyCmd, vApplyConfirmEnv)
+	addCmdFrozen(applyCmd, vApplyFrozen)
+
 	return applyCmd
 }
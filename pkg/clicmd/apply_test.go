@@ -32,11 +32,50 @@ func Test_applyCmd(t *testing.T) {
 			expected: map[string]interface{}{
 				actions.OptionApp:            mock.AnythingOfType("*app.App"),
 				actions.OptionEnvName:        "default",
+				actions.OptionConfirmEnv:     "",
 				actions.OptionGcTag:          "",
 				actions.OptionSkipGc:         false,
 				actions.OptionComponentNames: make([]string, 0),
 				actions.OptionCreate:         true,
 				actions.OptionDryRun:         false,
+				actions.OptionFrozen:         false,
+				actions.OptionParallel:       false,
+				actions.OptionClientConfig:   mock.AnythingOfType("*client.Config"),
+			},
+		},
+		{
+			name:   "in parallel",
+			args:   []string{"apply", "default", "--parallel"},
+			action: actionApply,
+			expected: map[string]interface{}{
+				actions.OptionApp:            mock.AnythingOfType("*app.App"),
+				actions.OptionEnvName:        "default",
+				actions.OptionConfirmEnv:     "",
+				actions.OptionGcTag:          "",
+				actions.OptionSkipGc:         false,
+				actions.OptionComponentNames: make([]string, 0),
+				actions.OptionCreate:         true,
+				actions.OptionDryRun:         false,
+				actions.OptionFrozen:         false,
+				actions.OptionParallel:       true,
+				actions.OptionClientConfig:   mock.AnythingOfType("*client.Config"),
+			},
+		},
+		{
+			name:   "with confirm env",
+			args:   []string{"apply", "default", "--confirm-env", "default"},
+			action: actionApply,
+			expected: map[string]interface{}{
+				actions.OptionApp:            mock.AnythingOfType("*app.App"),
+				actions.OptionEnvName:        "default",
+				actions.OptionConfirmEnv:     "default",
+				actions.OptionGcTag:          "",
+				actions.OptionSkipGc:         false,
+				actions.OptionComponentNames: make([]string, 0),
+				actions.OptionCreate:         true,
+				actions.OptionDryRun:         false,
+				actions.OptionFrozen:         false,
+				actions.OptionParallel:       false,
 				actions.OptionClientConfig:   mock.AnythingOfType("*client.Config"),
 			},
 		},
@@ -34,7 +34,10 @@ func newComponentCmd() *cobra.Command {
 		},
 	}
 
+	componentCmd.AddCommand(newComponentConvertCmd())
+	componentCmd.AddCommand(newComponentDescribeCmd())
 	componentCmd.AddCommand(newComponentListCmd())
+	componentCmd.AddCommand(newComponentMvCmd())
 	componentCmd.AddCommand(newComponentRmCmd())
 
 	return componentCmd
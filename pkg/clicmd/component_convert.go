@@ -0,0 +1,69 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vComponentConvertTo = "component-convert-to"
+)
+
+var (
+	componentConvertLong = `Convert a YAML or JSON component to Jsonnet.
+
+Every field set by the component's parameters, in its module or in any
+environment, is replaced by a reference to ` + "`params`" + `. Parameters which are only
+set in environments are given the component's value as their default.
+
+The component is rendered in every environment before and after the conversion.
+If the output of any environment changes, the conversion is reverted.`
+	componentConvertExample = `# Convert the YAML component 'guestbook' to Jsonnet. This replaces
+# guestbook.yaml in the components directory with guestbook.jsonnet.
+ks component convert guestbook --to jsonnet`
+)
+
+func newComponentConvertCmd() *cobra.Command {
+	componentConvertCmd := &cobra.Command{
+		Use:     "convert <component-name> --to jsonnet",
+		Short:   "Convert a YAML or JSON component to Jsonnet",
+		Long:    componentConvertLong,
+		Example: componentConvertExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("'component convert' takes a single argument, that is the name of the component")
+			}
+
+			m := map[string]interface{}{
+				actions.OptionComponentName: args[0],
+				actions.OptionFormat:        viper.GetString(vComponentConvertTo),
+			}
+			addGlobalOptions(m)
+
+			return runAction(actionComponentConvert, m)
+		},
+	}
+
+	componentConvertCmd.Flags().String(flagTo, "jsonnet", "Format to convert the component to")
+	viper.BindPFlag(vComponentConvertTo, componentConvertCmd.Flags().Lookup(flagTo))
+
+	return componentConvertCmd
+}
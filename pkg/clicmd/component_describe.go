@@ -0,0 +1,85 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vComponentDescribeEnv    = "component-describe-env"
+	vComponentDescribeOutput = "component-describe-output"
+)
+
+var (
+	componentDescribeLong = `
+The ` + "`describe`" + ` command shows where a component is defined, the environments
+that target its module, and its parameters as evaluated in each of them. It also
+lists the packages the component imports.
+
+The objects the component renders, and whether they are deployed and in sync
+with the cluster, are shown for the environment selected with ` + "`--env`" + `, or
+for the current environment if it targets the component. Packages installed in
+that environment are listed along with the global ones.
+
+### Syntax
+`
+	componentDescribeExample = `
+# Describe the component 'guestbook-ui'
+ks component describe guestbook-ui
+
+# Describe the component 'ui' of the module 'frontend' in the 'prod' environment
+ks component describe frontend.ui --env prod`
+)
+
+func newComponentDescribeCmd() *cobra.Command {
+	componentDescribeClientConfig := client.NewDefaultClientConfig()
+
+	componentDescribeCmd := &cobra.Command{
+		Use:     "describe <component-name>",
+		Short:   "Describe a component",
+		Long:    componentDescribeLong,
+		Example: componentDescribeExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("'component describe' takes a single argument, the name of the component")
+			}
+
+			m := map[string]interface{}{
+				actions.OptionComponentName: args[0],
+				actions.OptionEnvName:       viper.GetString(vComponentDescribeEnv),
+				actions.OptionOutput:        viper.GetString(vComponentDescribeOutput),
+				actions.OptionClientConfig:  componentDescribeClientConfig,
+			}
+			addGlobalOptions(m)
+
+			return runAction(actionComponentDescribe, m)
+		},
+	}
+
+	addCmdOutput(componentDescribeCmd, vComponentDescribeOutput)
+	componentDescribeCmd.Flags().String(flagEnv, "", "Environment to describe the objects of the component in")
+	viper.BindPFlag(vComponentDescribeEnv, componentDescribeCmd.Flags().Lookup(flagEnv))
+
+	componentDescribeClientConfig.BindClientGoFlags(componentDescribeCmd)
+
+	return componentDescribeCmd
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vComponentMvConfirmEnv = "component-mv-confirm-env"
+)
+
+var (
+	componentMvLong = `Move a component to another module, rename it, or both. Components are
+named ` + "`<module>.<name>`" + `; components in the root module are named ` + "`<name>`" + `.
+
+The component file is moved, and its parameters are moved in the params.libsonnet
+of its module and of every environment, and in the params files of environment
+destinations. References to the component's parameters in Jsonnet components are
+renamed. Environments targeting the old module also target the new one. The new
+module is created if it does not exist.
+
+The component is rendered in every environment, and for each destination of
+environments with several destinations, before and after the move. If any output
+changes, apart from the component label, the move is reverted. As the params of
+every environment are rewritten, protected environments have to be confirmed.`
+	componentMvExample = `# Move the component 'guestbook' to the module 'frontend'.
+ks component mv guestbook frontend.guestbook
+
+# Rename the component 'ui' in the module 'frontend' to 'guestbook-ui'.
+ks component mv frontend.ui frontend.guestbook-ui`
+)
+
+func newComponentMvCmd() *cobra.Command {
+	componentMvCmd := &cobra.Command{
+		Use:     "mv <module.name> <new-module.new-name>",
+		Short:   "Move or rename a component",
+		Long:    componentMvLong,
+		Example: componentMvExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("'component mv' takes two arguments, the name of the component and its new name")
+			}
+
+			m := map[string]interface{}{
+				actions.OptionComponentName:    args[0],
+				actions.OptionNewComponentName: args[1],
+				actions.OptionConfirmEnv:       viper.GetString(vComponentMvConfirmEnv),
+			}
+			addGlobalOptions(m)
+
+			return runAction(actionComponentMv, m)
+		},
+	}
+
+	addCmdConfirmEnv(componentMvCmd, vComponentMvConfirmEnv)
+
+	return componentMvCmd
+}
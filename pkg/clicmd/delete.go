@@ -26,7 +26,9 @@ import (
 
 const (
 	vDeleteComponent   = "delete-components"
+	vDeleteConfirmEnv  = "delete-confirm-env"
 	vDeleteGracePeriod = "delete-grace-period"
+	vDeleteParallel    = "delete-parallel"
 
 	deleteShortDesc = "Remove component-specified Kubernetes resources from remote clusters"
 	deleteLong      = `
@@ -35,7 +37,9 @@ The ` + "`delete`" + ` command removes Kubernetes resources (described in local
 ` + "`<env-name>`" + `argument.
 
 An entire ksonnet application can be removed from a cluster, or just its specific
-components.
+components. Components are deleted in the reverse order they are applied in:
+a component is deleted, and its objects are gone, before the components it
+depends on are deleted.
 
 **This command can be considered the inverse of the ` + "`ks apply`" + ` command.**
 
@@ -74,8 +78,10 @@ func newDeleteCmd(fs afero.Fs) *cobra.Command {
 			m := map[string]interface{}{
 				actions.OptionClientConfig:   deleteClientConfig,
 				actions.OptionComponentNames: viper.GetStringSlice(vDeleteComponent),
+				actions.OptionConfirmEnv:     viper.GetString(vDeleteConfirmEnv),
 				actions.OptionEnvName:        envName,
 				actions.OptionGracePeriod:    viper.GetInt64(vDeleteGracePeriod),
+				actions.OptionParallel:       viper.GetBool(vDeleteParallel),
 			}
 			addGlobalOptions(m)
 
@@ -96,5 +102,10 @@ func newDeleteCmd(fs afero.Fs) *cobra.Command {
 	deleteCmd.Flags().Int64(flagGracePeriod, -1, "Number of seconds given to resources to terminate gracefully. A negative value is ignored")
 	viper.BindPFlag(vDeleteGracePeriod, deleteCmd.Flags().Lookup(flagGracePeriod))
 
+	deleteCmd.Flags().Bool(flagParallel, false, "Option to delete from all destinations of a multi-destination environment concurrently")
+	viper.BindPFlag(vDeleteParallel, deleteCmd.Flags().Lookup(flagParallel))
+
+	addCmdConfirmEnv(deleteCmd, vDeleteConfirmEnv)
+
 	return deleteCmd
 }
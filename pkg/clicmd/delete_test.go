@@ -30,9 +30,11 @@ func Test_deleteCmd(t *testing.T) {
 			expected: map[string]interface{}{
 				actions.OptionApp:            nil,
 				actions.OptionEnvName:        "default",
+				actions.OptionConfirmEnv:     "",
 				actions.OptionComponentNames: make([]string, 0),
 				actions.OptionClientConfig:   nil,
 				actions.OptionGracePeriod:    int64(-1),
+				actions.OptionParallel:       false,
 			},
 		},
 		{
@@ -29,6 +29,7 @@ import (
 
 const (
 	vDiffComponentNames = "diff-component-names"
+	vDiffParallel       = "diff-parallel"
 
 	diffShortDesc = "Compare manifests, based on environment or location (local or remote)"
 )
@@ -105,6 +106,7 @@ func newDiffCmd(fs afero.Fs) *cobra.Command {
 				actions.OptionClientConfig:   diffClientConfig,
 				actions.OptionSrc1:           args[0],
 				actions.OptionComponentNames: viper.GetStringSlice(vDiffComponentNames),
+				actions.OptionParallel:       viper.GetBool(vDiffParallel),
 			}
 			addGlobalOptions(m)
 
@@ -126,5 +128,8 @@ func newDiffCmd(fs afero.Fs) *cobra.Command {
 	diffCmd.Flags().StringSliceP(flagComponent, shortComponent, nil, "Name of a specific component")
 	viper.BindPFlag(vDiffComponentNames, diffCmd.Flags().Lookup(flagComponent))
 
+	diffCmd.Flags().Bool(flagParallel, false, "Option to compare all destinations of a multi-destination environment concurrently")
+	viper.BindPFlag(vDiffParallel, diffCmd.Flags().Lookup(flagParallel))
+
 	return diffCmd
 }
@@ -33,6 +33,7 @@ func Test_diffCmd(t *testing.T) {
 				actions.OptionSrc1:           "env1",
 				actions.OptionSrc2:           "env2",
 				actions.OptionComponentNames: []string{},
+				actions.OptionParallel:       false,
 			},
 		},
 		{
@@ -34,8 +34,10 @@ const (
 var (
 	envShortDesc = map[string]string{
 		"add":     "Add a new environment to a ksonnet application",
+		"clone":   "Create a new environment from an existing one",
 		"current": "Sets the current environment",
 		"list":    "List all environments in a ksonnet application",
+		"patch":   "Manage patches applied to the objects of an environment",
 		"rm":      "Delete an environment from a ksonnet application",
 		"set":     "Set environment-specific fields (name, namespace, server)",
 		"targets": "Set target modules for an environment",
@@ -86,9 +88,11 @@ func newEnvCmd() *cobra.Command {
 	}
 
 	envCmd.AddCommand(newEnvAddCmd())
+	envCmd.AddCommand(newEnvCloneCmd())
 	envCmd.AddCommand(newEnvCurrentCmd())
 	envCmd.AddCommand(newEnvDescribeCmd())
 	envCmd.AddCommand(newEnvListCmd())
+	envCmd.AddCommand(newEnvPatchCmd())
 	envCmd.AddCommand(newEnvRmCmd())
 	envCmd.AddCommand(newEnvSetCmd())
 	envCmd.AddCommand(newEnvTargetsCmd())
@@ -26,7 +26,8 @@ import (
 )
 
 const (
-	vEnvAddOverride = "env-add-override"
+	vEnvAddInCluster = "env-add-in-cluster"
+	vEnvAddOverride  = "env-add-override"
 )
 
 var (
@@ -45,6 +46,12 @@ info:
 specified by individual flags. Unless otherwise specified, (4) defaults to the
 latest Kubernetes version that ksonnet supports.
 
+When ` + "`--context`" + ` is given, the environment remembers the context (and the
+` + "`--kubeconfig`" + ` file, if one was given). Commands that talk to the cluster then
+use the exact cluster and credentials of that context, even if other contexts
+point at the same server. Environments added with ` + "`--in-cluster`" + ` use the
+service account of the pod that ks runs in.
+
 Note that an environment *DOES NOT* contain user-specific data such as private keys.
 
 ### Related Commands
@@ -96,9 +103,30 @@ func newEnvAddCmd() *cobra.Command {
 
 			name := args[0]
 
-			server, namespace, err := resolveEnvFlags(flags, envClientConfig)
-			if err != nil {
-				return err
+			inCluster := viper.GetBool(vEnvAddInCluster)
+
+			var server, namespace string
+			var err error
+			if inCluster {
+				// The in-cluster destination can't be resolved from a kubeconfig.
+				if _, namespace, _, err = commonEnvFlags(flags); err != nil {
+					return err
+				}
+				if namespace == "" {
+					namespace = "default"
+				}
+			} else {
+				if server, namespace, err = resolveEnvFlags(flags, envClientConfig); err != nil {
+					return err
+				}
+			}
+
+			var context, kubeconfig string
+			if flags.Changed(flagEnvContext) {
+				if context, err = flags.GetString(flagEnvContext); err != nil {
+					return err
+				}
+				kubeconfig = envClientConfig.LoadingRules.ExplicitPath
 			}
 
 			// TODO: pass envClientConfig to the action so it can pull out the
@@ -114,11 +142,14 @@ func newEnvAddCmd() *cobra.Command {
 			isOverride := viper.GetBool(vEnvAddOverride)
 
 			m := map[string]interface{}{
-				actions.OptionEnvName:  name,
-				actions.OptionServer:   server,
-				actions.OptionModule:   namespace,
-				actions.OptionSpecFlag: specFlag,
-				actions.OptionOverride: isOverride,
+				actions.OptionEnvName:    name,
+				actions.OptionServer:     server,
+				actions.OptionModule:     namespace,
+				actions.OptionSpecFlag:   specFlag,
+				actions.OptionOverride:   isOverride,
+				actions.OptionContext:    context,
+				actions.OptionKubeconfig: kubeconfig,
+				actions.OptionInCluster:  inCluster,
 			}
 			addGlobalOptions(m)
 
@@ -135,5 +166,8 @@ func newEnvAddCmd() *cobra.Command {
 	envAddCmd.Flags().BoolP(flagOverride, shortOverride, false, "Add environment as override")
 	viper.BindPFlag(vEnvAddOverride, envAddCmd.Flags().Lookup(flagOverride))
 
+	envAddCmd.Flags().Bool(flagInCluster, false, "Deploy using the service account of the pod ks runs in")
+	viper.BindPFlag(vEnvAddInCluster, envAddCmd.Flags().Lookup(flagInCluster))
+
 	return envAddCmd
 }
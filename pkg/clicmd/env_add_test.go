@@ -28,12 +28,15 @@ func Test_envAddCmd(t *testing.T) {
 			args:   []string{"env", "add", "prod", "--server", "http://example.com", "--api-spec", "version:v1.9.5"},
 			action: actionEnvAdd,
 			expected: map[string]interface{}{
-				actions.OptionApp:      nil,
-				actions.OptionEnvName:  "prod",
-				actions.OptionModule:   "default",
-				actions.OptionOverride: false,
-				actions.OptionServer:   "http://example.com",
-				actions.OptionSpecFlag: "version:v1.9.5",
+				actions.OptionApp:        nil,
+				actions.OptionEnvName:    "prod",
+				actions.OptionModule:     "default",
+				actions.OptionOverride:   false,
+				actions.OptionServer:     "http://example.com",
+				actions.OptionSpecFlag:   "version:v1.9.5",
+				actions.OptionContext:    "",
+				actions.OptionKubeconfig: "",
+				actions.OptionInCluster:  false,
 			},
 		},
 		{
@@ -41,12 +44,15 @@ func Test_envAddCmd(t *testing.T) {
 			args:   []string{"env", "add", "prod", "--server", "http://example.com", "--api-spec", "version:v1.9.5", "-o"},
 			action: actionEnvAdd,
 			expected: map[string]interface{}{
-				actions.OptionApp:      nil,
-				actions.OptionEnvName:  "prod",
-				actions.OptionModule:   "default",
-				actions.OptionOverride: true,
-				actions.OptionServer:   "http://example.com",
-				actions.OptionSpecFlag: "version:v1.9.5",
+				actions.OptionApp:        nil,
+				actions.OptionEnvName:    "prod",
+				actions.OptionModule:     "default",
+				actions.OptionOverride:   true,
+				actions.OptionServer:     "http://example.com",
+				actions.OptionSpecFlag:   "version:v1.9.5",
+				actions.OptionContext:    "",
+				actions.OptionKubeconfig: "",
+				actions.OptionInCluster:  false,
 			},
 		},
 		{
@@ -54,12 +60,31 @@ func Test_envAddCmd(t *testing.T) {
 			args:   []string{"env", "add", "prod", "--server", "http://example.com", "--api-spec", "version:v1.9.5", "--override"},
 			action: actionEnvAdd,
 			expected: map[string]interface{}{
-				actions.OptionApp:      nil,
-				actions.OptionEnvName:  "prod",
-				actions.OptionModule:   "default",
-				actions.OptionOverride: true,
-				actions.OptionServer:   "http://example.com",
-				actions.OptionSpecFlag: "version:v1.9.5",
+				actions.OptionApp:        nil,
+				actions.OptionEnvName:    "prod",
+				actions.OptionModule:     "default",
+				actions.OptionOverride:   true,
+				actions.OptionServer:     "http://example.com",
+				actions.OptionSpecFlag:   "version:v1.9.5",
+				actions.OptionContext:    "",
+				actions.OptionKubeconfig: "",
+				actions.OptionInCluster:  false,
+			},
+		},
+		{
+			name:   "in cluster",
+			args:   []string{"env", "add", "prod", "--in-cluster", "--namespace", "prod", "--api-spec", "version:v1.9.5"},
+			action: actionEnvAdd,
+			expected: map[string]interface{}{
+				actions.OptionApp:        nil,
+				actions.OptionEnvName:    "prod",
+				actions.OptionModule:     "prod",
+				actions.OptionOverride:   false,
+				actions.OptionServer:     "",
+				actions.OptionSpecFlag:   "version:v1.9.5",
+				actions.OptionContext:    "",
+				actions.OptionKubeconfig: "",
+				actions.OptionInCluster:  true,
 			},
 		},
 		{
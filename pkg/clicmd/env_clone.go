@@ -0,0 +1,84 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vEnvCloneNamespace = "env-clone-namespace"
+	vEnvCloneServer    = "env-clone-server"
+)
+
+var (
+	envCloneLong = `
+The ` + "`clone`" + ` command creates a new environment from an existing one. The
+new environment gets a copy of the source environment's directory in
+` + "`environments/`" + ` (params, ` + "`main.jsonnet`" + ` overrides and globals), as well as its
+targets and library references.
+
+The destination is copied as well, unless ` + "`--server`" + ` or ` + "`--namespace`" + ` are given.
+
+### Related Commands
+
+* ` + "`ks env add` " + `— ` + envShortDesc["add"] + `
+* ` + "`ks promote` " + `— ` + promoteShortDesc + `
+
+### Syntax
+`
+	envCloneExample = `# Create the 'prod' environment from 'staging', pointing it to another
+# cluster and namespace.
+ks env clone staging prod --server=https://prod.example.com --namespace=prod`
+)
+
+func newEnvCloneCmd() *cobra.Command {
+	envCloneCmd := &cobra.Command{
+		Use:     "clone <src-env> <dst-env>",
+		Short:   envShortDesc["clone"],
+		Long:    envCloneLong,
+		Example: envCloneExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("'env clone' takes two arguments, the names of the source and new environments")
+			}
+
+			m := map[string]interface{}{
+				actions.OptionEnvName:    args[0],
+				actions.OptionNewEnvName: args[1],
+				actions.OptionNamespace:  viper.GetString(vEnvCloneNamespace),
+				actions.OptionServer:     viper.GetString(vEnvCloneServer),
+			}
+			addGlobalOptions(m)
+
+			return runAction(actionEnvClone, m)
+		},
+	}
+
+	envCloneCmd.Flags().String(flagNamespace, "",
+		"Namespace for the new environment")
+	viper.BindPFlag(vEnvCloneNamespace, envCloneCmd.Flags().Lookup(flagNamespace))
+
+	envCloneCmd.Flags().String(flagServer, "",
+		"Cluster server for the new environment")
+	viper.BindPFlag(vEnvCloneServer, envCloneCmd.Flags().Lookup(flagServer))
+
+	return envCloneCmd
+}
@@ -0,0 +1,126 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vEnvPatchAddAPIVersion = "env-patch-add-api-version"
+	vEnvPatchAddType       = "env-patch-add-type"
+	vEnvPatchAddConfirmEnv = "env-patch-add-confirm-env"
+)
+
+var (
+	envPatchLong = `
+Patches change the objects of an environment after they are rendered, without
+editing the components that generate them. Patches are kept in the ` + "`patches/`" + `
+directory of an environment, one patch per YAML or JSON file:
+
+` + "```" + `
+target:
+  apiVersion: apps/v1beta1
+  kind: Deployment
+  name: guestbook-ui
+type: strategic
+patch:
+  spec:
+    replicas: 3
+` + "```" + `
+
+The ` + "`type`" + ` of a patch is either ` + "`strategic`" + ` (a strategic merge patch,
+the default) or ` + "`json`" + ` (a JSON patch, RFC 6902). Patches of parent
+environments are applied before the patches of their children.
+
+If the object targeted by a patch is no longer rendered in the environment, the
+environment fails to render until the patch is updated or removed.
+`
+
+	envPatchAddLong = `
+The ` + "`add`" + ` command scaffolds a patch for an object rendered in an
+environment. The patch is written to the ` + "`patches/`" + ` directory of the
+environment, and is applied whenever the environment is rendered.
+
+The object is looked up by kind and name in the rendered environment. If more
+than one object matches, use ` + "`--api-version`" + ` to select one.
+`
+	envPatchAddExample = `
+# Scaffold a strategic merge patch for the deployment 'guestbook-ui' in 'prod'.
+ks env patch add prod deployment guestbook-ui
+
+# Scaffold a JSON patch for the service 'guestbook-ui' in 'prod'.
+ks env patch add prod service guestbook-ui --type json`
+)
+
+func newEnvPatchCmd() *cobra.Command {
+	envPatchCmd := &cobra.Command{
+		Use:   "patch",
+		Short: envShortDesc["patch"],
+		Long:  envPatchLong,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("%s is not a valid subcommand\n\n%s", strings.Join(args, " "), cmd.UsageString())
+			}
+			return fmt.Errorf("Command 'env patch' requires a subcommand\n\n%s", cmd.UsageString())
+		},
+	}
+
+	envPatchCmd.AddCommand(newEnvPatchAddCmd())
+
+	return envPatchCmd
+}
+
+func newEnvPatchAddCmd() *cobra.Command {
+	envPatchAddCmd := &cobra.Command{
+		Use:     "add <env-name> <kind> <name>",
+		Short:   "Scaffold a patch for an object of an environment",
+		Long:    envPatchAddLong,
+		Example: envPatchAddExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 3 {
+				return fmt.Errorf("'env patch add' takes three arguments, the environment name, and the kind and name of the object to patch")
+			}
+
+			m := map[string]interface{}{
+				actions.OptionEnvName:    args[0],
+				actions.OptionKind:       args[1],
+				actions.OptionName:       args[2],
+				actions.OptionAPIVersion: viper.GetString(vEnvPatchAddAPIVersion),
+				actions.OptionPatchType:  viper.GetString(vEnvPatchAddType),
+				actions.OptionConfirmEnv: viper.GetString(vEnvPatchAddConfirmEnv),
+			}
+			addGlobalOptions(m)
+
+			return runAction(actionEnvPatchAdd, m)
+		},
+	}
+
+	envPatchAddCmd.Flags().String(flagAPIVersion, "", "API version of the object to patch")
+	viper.BindPFlag(vEnvPatchAddAPIVersion, envPatchAddCmd.Flags().Lookup(flagAPIVersion))
+
+	envPatchAddCmd.Flags().String(flagType, "strategic", "Type of the patch, strategic or json")
+	viper.BindPFlag(vEnvPatchAddType, envPatchAddCmd.Flags().Lookup(flagType))
+
+	addCmdConfirmEnv(envPatchAddCmd, vEnvPatchAddConfirmEnv)
+
+	return envPatchAddCmd
+}
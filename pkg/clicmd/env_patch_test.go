@@ -0,0 +1,76 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+)
+
+func Test_envPatchAddCmd(t *testing.T) {
+	cases := []cmdTestCase{
+		{
+			name:   "in general",
+			args:   []string{"env", "patch", "add", "prod", "deployment", "guestbook-ui"},
+			action: actionEnvPatchAdd,
+			expected: map[string]interface{}{
+				actions.OptionApp:        nil,
+				actions.OptionEnvName:    "prod",
+				actions.OptionKind:       "deployment",
+				actions.OptionName:       "guestbook-ui",
+				actions.OptionAPIVersion: "",
+				actions.OptionPatchType:  "strategic",
+				actions.OptionConfirmEnv: "",
+			},
+		},
+		{
+			name:   "with api version and type",
+			args:   []string{"env", "patch", "add", "prod", "deployment", "guestbook-ui", "--api-version", "apps/v1beta1", "--type", "json"},
+			action: actionEnvPatchAdd,
+			expected: map[string]interface{}{
+				actions.OptionApp:        nil,
+				actions.OptionEnvName:    "prod",
+				actions.OptionKind:       "deployment",
+				actions.OptionName:       "guestbook-ui",
+				actions.OptionAPIVersion: "apps/v1beta1",
+				actions.OptionPatchType:  "json",
+				actions.OptionConfirmEnv: "",
+			},
+		},
+		{
+			name:   "confirm protected environment",
+			args:   []string{"env", "patch", "add", "prod", "deployment", "guestbook-ui", "--confirm-env", "prod"},
+			action: actionEnvPatchAdd,
+			expected: map[string]interface{}{
+				actions.OptionApp:        nil,
+				actions.OptionEnvName:    "prod",
+				actions.OptionKind:       "deployment",
+				actions.OptionName:       "guestbook-ui",
+				actions.OptionAPIVersion: "",
+				actions.OptionPatchType:  "strategic",
+				actions.OptionConfirmEnv: "prod",
+			},
+		},
+		{
+			name:  "missing name",
+			args:  []string{"env", "patch", "add", "prod", "deployment"},
+			isErr: true,
+		},
+	}
+
+	runTestCmd(t, cases)
+}
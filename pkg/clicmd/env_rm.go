@@ -24,7 +24,8 @@ import (
 )
 
 const (
-	vEnvRmOverride = "env-rm-override"
+	vEnvRmConfirmEnv = "env-rm-confirm-env"
+	vEnvRmOverride   = "env-rm-override"
 )
 
 var (
@@ -63,8 +64,9 @@ func newEnvRmCmd() *cobra.Command {
 			}
 
 			m := map[string]interface{}{
-				actions.OptionEnvName:  args[0],
-				actions.OptionOverride: viper.GetBool(vEnvRmOverride),
+				actions.OptionConfirmEnv: viper.GetString(vEnvRmConfirmEnv),
+				actions.OptionEnvName:    args[0],
+				actions.OptionOverride:   viper.GetBool(vEnvRmOverride),
 			}
 			addGlobalOptions(m)
 
@@ -75,6 +77,8 @@ func newEnvRmCmd() *cobra.Command {
 	envRmCmd.Flags().BoolP(flagOverride, shortOverride, false, "Remove the overridden environment")
 	viper.BindPFlag(vEnvRmOverride, envRmCmd.Flags().Lookup(flagOverride))
 
+	addCmdConfirmEnv(envRmCmd, vEnvRmConfirmEnv)
+
 	return envRmCmd
 
 }
@@ -28,9 +28,10 @@ func Test_envRmCmd(t *testing.T) {
 			args:   []string{"env", "rm", "prod"},
 			action: actionEnvRm,
 			expected: map[string]interface{}{
-				actions.OptionApp:      nil,
-				actions.OptionEnvName:  "prod",
-				actions.OptionOverride: false,
+				actions.OptionApp:        nil,
+				actions.OptionEnvName:    "prod",
+				actions.OptionConfirmEnv: "",
+				actions.OptionOverride:   false,
 			},
 		},
 		{
@@ -24,11 +24,12 @@ import (
 )
 
 const (
-	vEnvSetName      = "env-set-name"
-	vEnvSetNamespace = "env-set-namespace"
-	vEnvSetServer    = "env-set-server"
-	vEnvSetAPISpec   = "env-set-spec-flag"
-	vEnvSetOverride  = "env-set-override-flag"
+	vEnvSetName       = "env-set-name"
+	vEnvSetNamespace  = "env-set-namespace"
+	vEnvSetServer     = "env-set-server"
+	vEnvSetAPISpec    = "env-set-spec-flag"
+	vEnvSetOverride   = "env-set-override-flag"
+	vEnvSetConfirmEnv = "env-set-confirm-env"
 )
 
 var (
@@ -75,6 +76,7 @@ func newEnvSetCmd() *cobra.Command {
 				actions.OptionServer:     viper.GetString(vEnvSetServer),
 				actions.OptionSpecFlag:   viper.GetString(vEnvSetAPISpec),
 				actions.OptionOverride:   viper.GetBool(vEnvSetOverride),
+				actions.OptionConfirmEnv: viper.GetString(vEnvSetConfirmEnv),
 			}
 			addGlobalOptions(m)
 
@@ -101,5 +103,7 @@ func newEnvSetCmd() *cobra.Command {
 	envSetCmd.Flags().BoolP(flagOverride, shortOverride, false, "Set fields in environment as override")
 	viper.BindPFlag(vEnvSetOverride, envSetCmd.Flags().Lookup(flagOverride))
 
+	addCmdConfirmEnv(envSetCmd, vEnvSetConfirmEnv)
+
 	return envSetCmd
 }
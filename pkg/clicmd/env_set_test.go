@@ -35,6 +35,7 @@ func Test_envSetCmd(t *testing.T) {
 				actions.OptionServer:     "new-server",
 				actions.OptionSpecFlag:   "new-api-spec",
 				actions.OptionOverride:   false,
+				actions.OptionConfirmEnv: "",
 			},
 		},
 		{
@@ -49,6 +50,7 @@ func Test_envSetCmd(t *testing.T) {
 				actions.OptionServer:     "new-server",
 				actions.OptionSpecFlag:   "new-api-spec",
 				actions.OptionOverride:   true,
+				actions.OptionConfirmEnv: "",
 			},
 		},
 		{
@@ -63,6 +65,22 @@ func Test_envSetCmd(t *testing.T) {
 				actions.OptionServer:     "new-server",
 				actions.OptionSpecFlag:   "new-api-spec",
 				actions.OptionOverride:   true,
+				actions.OptionConfirmEnv: "",
+			},
+		},
+		{
+			name:   "confirm protected environment",
+			args:   []string{"env", "set", "prod", "--name", "production", "--confirm-env", "prod"},
+			action: actionEnvSet,
+			expected: map[string]interface{}{
+				actions.OptionApp:        nil,
+				actions.OptionEnvName:    "prod",
+				actions.OptionNewEnvName: "production",
+				actions.OptionNamespace:  "",
+				actions.OptionServer:     "",
+				actions.OptionSpecFlag:   "",
+				actions.OptionOverride:   false,
+				actions.OptionConfirmEnv: "prod",
 			},
 		},
 		{
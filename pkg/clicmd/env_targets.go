@@ -23,8 +23,9 @@ import (
 )
 
 const (
-	vEnvTargetModules  = "env-target-modules"
-	vEnvTargetOverride = "env-target-override-flag"
+	vEnvTargetModules    = "env-target-modules"
+	vEnvTargetOverride   = "env-target-override-flag"
+	vEnvTargetConfirmEnv = "env-target-confirm-env"
 )
 
 var (
@@ -58,9 +59,10 @@ func newEnvTargetsCmd() *cobra.Command {
 			}
 
 			m := map[string]interface{}{
-				actions.OptionEnvName:  args[0],
-				actions.OptionModule:   viper.GetStringSlice(vEnvTargetModules),
-				actions.OptionOverride: viper.GetBool(vEnvTargetOverride),
+				actions.OptionEnvName:    args[0],
+				actions.OptionModule:     viper.GetStringSlice(vEnvTargetModules),
+				actions.OptionOverride:   viper.GetBool(vEnvTargetOverride),
+				actions.OptionConfirmEnv: viper.GetString(vEnvTargetConfirmEnv),
 			}
 			addGlobalOptions(m)
 
@@ -74,5 +76,7 @@ func newEnvTargetsCmd() *cobra.Command {
 	envTargetsCmd.Flags().BoolP(flagOverride, shortOverride, false, "Set targets in environment as override")
 	viper.BindPFlag(vEnvTargetOverride, envTargetsCmd.Flags().Lookup(flagOverride))
 
+	addCmdConfirmEnv(envTargetsCmd, vEnvTargetConfirmEnv)
+
 	return envTargetsCmd
 }
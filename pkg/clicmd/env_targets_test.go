@@ -28,10 +28,23 @@ func Test_envTargetsCmd(t *testing.T) {
 			args:   []string{"env", "targets", "prod", "--module", "app1"},
 			action: actionEnvTargets,
 			expected: map[string]interface{}{
-				actions.OptionApp:      nil,
-				actions.OptionEnvName:  "prod",
-				actions.OptionModule:   []string{"app1"},
-				actions.OptionOverride: false,
+				actions.OptionApp:        nil,
+				actions.OptionEnvName:    "prod",
+				actions.OptionModule:     []string{"app1"},
+				actions.OptionOverride:   false,
+				actions.OptionConfirmEnv: "",
+			},
+		},
+		{
+			name:   "confirm protected environment",
+			args:   []string{"env", "targets", "prod", "--module", "app1", "--confirm-env", "prod"},
+			action: actionEnvTargets,
+			expected: map[string]interface{}{
+				actions.OptionApp:        nil,
+				actions.OptionEnvName:    "prod",
+				actions.OptionModule:     []string{"app1"},
+				actions.OptionOverride:   false,
+				actions.OptionConfirmEnv: "prod",
 			},
 		},
 		{
@@ -20,6 +20,11 @@ import (
 
 	"github.com/ksonnet/ksonnet/pkg/actions"
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vEnvUpdateConfirmEnv = "env-update-confirm-env"
 )
 
 var (
@@ -52,7 +57,8 @@ func newEnvUpdateCmd() *cobra.Command {
 			}
 
 			m := map[string]interface{}{
-				actions.OptionEnvName: args[0],
+				actions.OptionConfirmEnv: viper.GetString(vEnvUpdateConfirmEnv),
+				actions.OptionEnvName:    args[0],
 			}
 			addGlobalOptions(m)
 
@@ -60,6 +66,8 @@ func newEnvUpdateCmd() *cobra.Command {
 		},
 	}
 
+	addCmdConfirmEnv(envUpdateCmd, vEnvUpdateConfirmEnv)
+
 	return envUpdateCmd
 
 }
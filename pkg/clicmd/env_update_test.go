@@ -30,6 +30,7 @@ func Test_envUpdateCmd(t *testing.T) {
 			expected: map[string]interface{}{
 				actions.OptionApp:           nil,
 				actions.OptionEnvName:       "prod",
+				actions.OptionConfirmEnv:    "",
 				actions.OptionTLSSkipVerify: false,
 			},
 		},
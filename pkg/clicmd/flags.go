@@ -23,10 +23,16 @@ import (
 const (
 	// For use in the commands (e.g., diff, apply, delete) that require either an
 	// environment or the -f flag.
+	flagAdopt                 = "adopt"
 	flagAPISpec               = "api-spec"
+	flagAPIVersion            = "api-version"
 	flagAsString              = "as-string"
+	flagBundle                = "bundle"
+	flagCascade               = "cascade"
 	flagComponent             = "component"
+	flagConfirmEnv            = "confirm-env"
 	flagCreate                = "create"
+	flagDetails               = "details"
 	flagDir                   = "dir"
 	flagDryRun                = "dry-run"
 	flagEnv                   = "env"
@@ -35,23 +41,42 @@ const (
 	flagFilename              = "filename"
 	flagForce                 = "force"
 	flagFormat                = "format"
+	flagFrozen                = "frozen"
+	flagFromCluster           = "from-cluster"
 	flagGcTag                 = "gc-tag"
 	flagGracePeriod           = "grace-period"
+	flagGroupBy               = "group-by"
+	flagInCluster             = "in-cluster"
 	flagInstalled             = "installed"
 	flagJpath                 = "jpath"
+	flagJUnit                 = "junit"
+	flagKind                  = "kind"
 	flagModule                = "module"
+	flagNameTemplate          = "name-template"
 	flagNamespace             = "namespace"
+	flagRefresh               = "refresh"
 	flagResolveImage          = "resolve-image"
+	flagSelector              = "selector"
 	flagServer                = "server"
 	flagSet                   = "set"
 	flagSkipDefaultRegistries = "skip-default-registries"
 	flagSkipGc                = "skip-gc"
 	flagTlaVar                = "tla-str"
+	flagTo                    = "to"
+	flagType                  = "type"
 	flagTlaVarFile            = "tla-str-file"
 	flagTLSSkipVerify         = "tls-skip-verify"
+	flagOffline               = "offline"
+	flagOnCollision           = "on-collision"
 	flagOutput                = "output"
 	flagOverride              = "override"
+	flagPackage               = "package"
+	flagParallel              = "parallel"
+	flagParamOnly             = "param-only"
+	flagPrivateKey            = "private-key"
+	flagPublicKey             = "public-key"
 	flagUnset                 = "unset"
+	flagUpdate                = "update"
 	flagVerbose               = "verbose"
 	flagVersion               = "version"
 	flagWithoutModules        = "without-modules"
@@ -61,8 +86,23 @@ const (
 	shortFormat    = "o"
 	shortOutput    = "o"
 	shortOverride  = "o"
+	shortSelector  = "l"
 )
 
+// addCmdConfirmEnv adds a confirm-env flag to a command which changes an
+// environment. `name` is the name of the viper assignment.
+func addCmdConfirmEnv(cmd *cobra.Command, name string) {
+	cmd.Flags().String(flagConfirmEnv, "", "Name of the environment to confirm changes to, if it is protected")
+	viper.BindPFlag(name, cmd.Flags().Lookup(flagConfirmEnv))
+}
+
+// addCmdFrozen adds a frozen flag to a command which renders components.
+// `name` is the name of the viper assignment.
+func addCmdFrozen(cmd *cobra.Command, name string) {
+	cmd.Flags().Bool(flagFrozen, false, "Fail if vendored packages do not match ks.lock")
+	viper.BindPFlag(name, cmd.Flags().Lookup(flagFrozen))
+}
+
 // addCmdOutput adds an output flag to a command. `name` is the name
 // of the viper assignment.
 func addCmdOutput(cmd *cobra.Command, name string) {
@@ -17,21 +17,75 @@ package clicmd
 
 import (
 	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/ksonnet/ksonnet/pkg/client"
 	"github.com/spf13/viper"
 
 	"github.com/spf13/cobra"
 )
 
 const (
-	vImportFilename = "import-filename"
-	vImportModule   = "import-module"
+	vImportAdopt        = "import-adopt"
+	vImportFilename     = "import-filename"
+	vImportFromCluster  = "import-from-cluster"
+	vImportGcTag        = "import-gc-tag"
+	vImportGroupBy      = "import-group-by"
+	vImportKind         = "import-kind"
+	vImportModule       = "import-module"
+	vImportNameTemplate = "import-name-template"
+	vImportOnCollision  = "import-on-collision"
+	vImportSelector     = "import-selector"
+
+	importLong = `
+Import manifests into components.
+
+With ` + "`--filename`" + `, a YAML, JSON or Jsonnet file, a directory of them, or
+a URL is imported. Each object of a YAML file becomes a component named
+` + "`<kind>-<name>`" + `. With ` + "`--group-by`" + `, the objects of each file, application
+label (` + "`app.kubernetes.io/name`" + ` or ` + "`app`" + `) or kind are imported into a single
+multi-document YAML component.
+
+Component names are generated with ` + "`--name-template`" + `, a Go template with the
+fields ` + "`.Group`" + `, ` + "`.File`" + `, ` + "`.Kind`" + `, ` + "`.Namespace`" + `, ` + "`.Name`" + ` and ` + "`.Labels`" + `, and the
+functions ` + "`lower`" + ` and ` + "`upper`" + `. ` + "`.Group`" + ` is the file, label or kind objects are
+grouped by, or ` + "`<kind>-<name>`" + ` for ungrouped objects. If a name is already
+taken, the import fails, or with ` + "`--on-collision=suffix`" + `, a numeric suffix is
+added to the name.
+
+With ` + "`--from-cluster`" + `, the objects in the namespace of an environment are
+imported. Fields populated by the cluster (status, uids, resource versions,
+defaulted values, etc.) are removed. Objects belonging to the same application
+are grouped into a single component: objects sharing an ` + "`app.kubernetes.io/name`" + `,
+` + "`app`" + ` or ` + "`k8s-app`" + ` label, Services selecting their pods, and the ConfigMaps,
+Secrets, PersistentVolumeClaims and ServiceAccounts their pods use. Objects
+created by controllers are skipped.
+
+With ` + "`--adopt`" + `, the imported objects are labelled as managed by ksonnet, so
+` + "`ks apply --gc-tag`" + ` garbage collects them once they are removed from the app.`
+
+	importExample = `
+# Import the objects in manifest.yaml into components
+ks import -f manifest.yaml
+
+# Import the objects in the manifests directory into one component per
+# application, named after the application and its namespace
+ks import -f manifests --group-by app-label --name-template '{{.Group}}-{{.Namespace}}'
+
+# Import the objects in the namespace of the 'default' environment
+ks import --from-cluster default
+
+# Import the Deployments and Services labelled 'tier=frontend', and label
+# them as managed by ksonnet
+ks import --from-cluster default -l tier=frontend --kind deployment --kind service --adopt`
 )
 
 func newImportCmd() *cobra.Command {
+	importClientConfig := client.NewDefaultClientConfig()
+
 	importCmd := &cobra.Command{
-		Use:   "import",
-		Short: "Import manifest",
-		Long:  `Import manifest`,
+		Use:     "import",
+		Short:   "Import manifest",
+		Long:    importLong,
+		Example: importExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			m := map[string]interface{}{
 				actions.OptionPath: viper.GetString(vImportFilename),
@@ -42,6 +96,25 @@ func newImportCmd() *cobra.Command {
 				m[actions.OptionModule] = mod
 			}
 
+			if tmpl := viper.GetString(vImportNameTemplate); tmpl != "" {
+				m[actions.OptionNameTemplate] = tmpl
+			}
+
+			if groupBy := viper.GetString(vImportGroupBy); groupBy != "" {
+				m[actions.OptionGroupBy] = groupBy
+			}
+
+			m[actions.OptionOnCollision] = viper.GetString(vImportOnCollision)
+
+			if envName := viper.GetString(vImportFromCluster); envName != "" {
+				m[actions.OptionFromCluster] = envName
+				m[actions.OptionClientConfig] = importClientConfig
+				m[actions.OptionSelector] = viper.GetString(vImportSelector)
+				m[actions.OptionKinds] = viper.GetStringSlice(vImportKind)
+				m[actions.OptionAdopt] = viper.GetBool(vImportAdopt)
+				m[actions.OptionGcTag] = viper.GetString(vImportGcTag)
+			}
+
 			return runAction(actionImport, m)
 		},
 	}
@@ -51,5 +124,25 @@ func newImportCmd() *cobra.Command {
 	importCmd.Flags().String(flagModule, "/", "Component module")
 	viper.BindPFlag(vImportModule, importCmd.Flags().Lookup(flagModule))
 
+	importCmd.Flags().String(flagNameTemplate, "", "Go template for the names of imported components")
+	viper.BindPFlag(vImportNameTemplate, importCmd.Flags().Lookup(flagNameTemplate))
+	importCmd.Flags().String(flagGroupBy, "", "Group imported YAML objects into components by file, app-label or kind")
+	viper.BindPFlag(vImportGroupBy, importCmd.Flags().Lookup(flagGroupBy))
+	importCmd.Flags().String(flagOnCollision, "fail", "Handling of component names which are taken: fail or suffix")
+	viper.BindPFlag(vImportOnCollision, importCmd.Flags().Lookup(flagOnCollision))
+
+	importClientConfig.BindClientGoFlags(importCmd)
+
+	importCmd.Flags().String(flagFromCluster, "", "Environment whose namespace is imported")
+	viper.BindPFlag(vImportFromCluster, importCmd.Flags().Lookup(flagFromCluster))
+	importCmd.Flags().StringP(flagSelector, shortSelector, "", "Label selector for the imported cluster objects")
+	viper.BindPFlag(vImportSelector, importCmd.Flags().Lookup(flagSelector))
+	importCmd.Flags().StringSlice(flagKind, nil, "Kind of the imported cluster objects (multiple --kind flags accepted)")
+	viper.BindPFlag(vImportKind, importCmd.Flags().Lookup(flagKind))
+	importCmd.Flags().Bool(flagAdopt, false, "Label the imported cluster objects as managed by ksonnet")
+	viper.BindPFlag(vImportAdopt, importCmd.Flags().Lookup(flagAdopt))
+	importCmd.Flags().String(flagGcTag, "", "A tag added to the adopted cluster objects, used to garbage collect them with 'ks apply --gc-tag'")
+	viper.BindPFlag(vImportGcTag, importCmd.Flags().Lookup(flagGcTag))
+
 	return importCmd
 }
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/stretchr/testify/mock"
 )
 
 func Test_importCmd(t *testing.T) {
@@ -28,9 +29,10 @@ func Test_importCmd(t *testing.T) {
 			args:   []string{"import", "-f", "location"},
 			action: actionImport,
 			expected: map[string]interface{}{
-				actions.OptionApp:    nil,
-				actions.OptionPath:   "location",
-				actions.OptionModule: "/",
+				actions.OptionApp:         nil,
+				actions.OptionPath:        "location",
+				actions.OptionModule:      "/",
+				actions.OptionOnCollision: "fail",
 			},
 		},
 		{
@@ -38,9 +40,40 @@ func Test_importCmd(t *testing.T) {
 			args:   []string{"import", "-f", "location", "--module", "module"},
 			action: actionImport,
 			expected: map[string]interface{}{
-				actions.OptionApp:    nil,
-				actions.OptionPath:   "location",
-				actions.OptionModule: "module",
+				actions.OptionApp:         nil,
+				actions.OptionPath:        "location",
+				actions.OptionModule:      "module",
+				actions.OptionOnCollision: "fail",
+			},
+		},
+		{
+			name:   "import location with naming",
+			args:   []string{"import", "-f", "location", "--group-by", "app-label", "--name-template", "{{.Group}}-{{.Namespace}}", "--on-collision", "suffix"},
+			action: actionImport,
+			expected: map[string]interface{}{
+				actions.OptionApp:          nil,
+				actions.OptionPath:         "location",
+				actions.OptionModule:       "/",
+				actions.OptionGroupBy:      "app-label",
+				actions.OptionNameTemplate: "{{.Group}}-{{.Namespace}}",
+				actions.OptionOnCollision:  "suffix",
+			},
+		},
+		{
+			name:   "import from cluster",
+			args:   []string{"import", "--from-cluster", "default", "-l", "app=guestbook", "--kind", "deployment", "--kind", "service", "--adopt", "--gc-tag", "tag"},
+			action: actionImport,
+			expected: map[string]interface{}{
+				actions.OptionApp:          nil,
+				actions.OptionPath:         "",
+				actions.OptionModule:       "/",
+				actions.OptionOnCollision:  "fail",
+				actions.OptionFromCluster:  "default",
+				actions.OptionClientConfig: mock.AnythingOfType("*client.Config"),
+				actions.OptionSelector:     "app=guestbook",
+				actions.OptionKinds:        []string{"deployment", "service"},
+				actions.OptionAdopt:        true,
+				actions.OptionGcTag:        "tag",
 			},
 		},
 	}
@@ -0,0 +1,123 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	lintShortDesc = "Check generated component manifests against policy rules"
+	vLintOutput   = "lint-output"
+)
+
+var (
+	lintLong = `
+The ` + "`lint`" + ` command checks the objects of an environment against policy rules.
+Unlike ` + "`ks validate`" + `, it does not communicate with the cluster of the
+environment.
+
+The built-in rules are:
+
+* ` + "`host-path`" + ` (error) — Pods must not mount hostPath volumes
+* ` + "`latest-image`" + ` (warning) — Container images must be pinned to a tag other
+  than latest, or a digest
+* ` + "`probes`" + ` (warning) — Containers of workloads must have readiness and
+  liveness probes
+* ` + "`required-labels`" + ` (warning) — Objects must have the labels configured
+  with ` + "`requiredLabels`" + `
+* ` + "`resource-requests`" + ` (warning) — Containers must request cpu and memory
+
+Applications can add rules in the ` + "`lint/`" + ` directory. Each ` + "`lint/<rule-name>.jsonnet`" + `
+file is a function which takes an object, and returns an array of violation
+messages. Rules are evaluated with the jpaths and ext vars of the environment.
+
+The severity of each rule (` + "`error`" + `, ` + "`warning`" + `, ` + "`info`" + ` or ` + "`off`" + `) can be set in the
+` + "`lint`" + ` section of app.yaml, and overridden by the ` + "`lint`" + ` section of an
+environment:
+
+    lint:
+      rules:
+        latest-image: error
+      requiredLabels:
+      - app
+    environments:
+      dev:
+        lint:
+          rules:
+            latest-image: "off"
+
+Objects can suppress rules with the ` + "`ksonnet.io/lint-ignore`" + ` annotation, which lists
+rule names separated by commas, or ` + "`*`" + ` for every rule.
+
+The command fails if any violation has the ` + "`error`" + ` severity.
+
+### Related Commands
+
+* ` + "`ks validate` " + `— ` + valShortDesc + `
+* ` + "`ks test` " + `— ` + testShortDesc + `
+
+### Syntax
+`
+	lintExample = `
+# Lint the objects of the 'dev' environment
+ks lint dev
+
+# Lint the objects of the current environment, and write a SARIF log for code
+# scanning tools
+ks lint -o sarif > lint.sarif
+`
+)
+
+func newLintCmd(fs afero.Fs) *cobra.Command {
+	lintCmd := &cobra.Command{
+		Use:     "lint [<env-name>]",
+		Short:   lintShortDesc,
+		Long:    lintLong,
+		Example: lintExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return errors.New("'lint' takes at most one argument, the name of an environment")
+			}
+
+			var envName string
+			if len(args) == 1 {
+				envName = args[0]
+			}
+
+			m := map[string]interface{}{
+				actions.OptionEnvName: envName,
+				actions.OptionOutput:  viper.GetString(vLintOutput),
+			}
+
+			if err := extractJsonnetFlags(fs, "lint"); err != nil {
+				return errors.Wrap(err, "handle jsonnet flags")
+			}
+
+			return runAction(actionLint, m)
+		},
+	}
+	bindJsonnetFlags(lintCmd, "lint")
+
+	lintCmd.Flags().StringP(flagOutput, shortOutput, "", "Output format. Valid options: table|json|sarif")
+	viper.BindPFlag(vLintOutput, lintCmd.Flags().Lookup(flagOutput))
+
+	return lintCmd
+}
@@ -23,8 +23,9 @@ import (
 )
 
 var (
-	vParamDeleteEnv = "param-delete-env"
-	paramDeleteLong = `
+	vParamDeleteConfirmEnv = "param-delete-confirm-env"
+	vParamDeleteEnv        = "param-delete-env"
+	paramDeleteLong        = `
 The ` + "`delete`" + ` command deletes component or environment parameters.
 
 ### Related Commands
@@ -64,9 +65,10 @@ func newParamDeleteCmd() *cobra.Command {
 			}
 
 			m := map[string]interface{}{
-				actions.OptionName:    name,
-				actions.OptionPath:    path,
-				actions.OptionEnvName: viper.GetString(vParamDeleteEnv),
+				actions.OptionName:       name,
+				actions.OptionPath:       path,
+				actions.OptionEnvName:    viper.GetString(vParamDeleteEnv),
+				actions.OptionConfirmEnv: viper.GetString(vParamDeleteConfirmEnv),
 			}
 			addGlobalOptions(m)
 
@@ -77,5 +79,7 @@ func newParamDeleteCmd() *cobra.Command {
 	paramDeleteCmd.Flags().String(flagEnv, "", "Specify environment to delete parameter from")
 	viper.BindPFlag(vParamDeleteEnv, paramDeleteCmd.Flags().Lookup(flagEnv))
 
+	addCmdConfirmEnv(paramDeleteCmd, vParamDeleteConfirmEnv)
+
 	return paramDeleteCmd
 }
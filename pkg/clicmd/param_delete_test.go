@@ -28,10 +28,11 @@ func Test_paramDeleteCmd(t *testing.T) {
 			args:   []string{"param", "delete", "component-name", "param-name"},
 			action: actionParamDelete,
 			expected: map[string]interface{}{
-				actions.OptionApp:     nil,
-				actions.OptionName:    "component-name",
-				actions.OptionPath:    "param-name",
-				actions.OptionEnvName: "",
+				actions.OptionApp:        nil,
+				actions.OptionName:       "component-name",
+				actions.OptionPath:       "param-name",
+				actions.OptionEnvName:    "",
+				actions.OptionConfirmEnv: "",
 			},
 		},
 		{
@@ -39,10 +40,11 @@ func Test_paramDeleteCmd(t *testing.T) {
 			args:   []string{"param", "delete", "param-name", "--env", "default"},
 			action: actionParamDelete,
 			expected: map[string]interface{}{
-				actions.OptionApp:     nil,
-				actions.OptionName:    "",
-				actions.OptionPath:    "param-name",
-				actions.OptionEnvName: "default",
+				actions.OptionApp:        nil,
+				actions.OptionName:       "",
+				actions.OptionPath:       "param-name",
+				actions.OptionEnvName:    "default",
+				actions.OptionConfirmEnv: "",
 			},
 		},
 		{
@@ -23,6 +23,7 @@ import (
 )
 
 var (
+	vParamSetConfirmEnv   = "param-set-confirm-env"
 	vParamSetEnv          = "param-set-env"
 	vParamSetAsString     = "param-set-as-string"
 	vParamSetResolveImage = "param-set-resolve-image"
@@ -85,6 +86,7 @@ func newParamSetCmd() *cobra.Command {
 				actions.OptionEnvName:      viper.GetString(vParamSetEnv),
 				actions.OptionAsString:     viper.GetBool(vParamSetAsString),
 				actions.OptionResolveImage: viper.GetBool(vParamSetResolveImage),
+				actions.OptionConfirmEnv:   viper.GetString(vParamSetConfirmEnv),
 			}
 
 			return runAction(actionParamSet, m)
@@ -100,5 +102,7 @@ func newParamSetCmd() *cobra.Command {
 	paramSetCmd.Flags().Bool(flagResolveImage, false, "Resolve Docker image tag to reference")
 	viper.BindPFlag(vParamSetResolveImage, paramSetCmd.Flags().Lookup(flagResolveImage))
 
+	addCmdConfirmEnv(paramSetCmd, vParamSetConfirmEnv)
+
 	return paramSetCmd
 }
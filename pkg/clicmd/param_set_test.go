@@ -33,6 +33,7 @@ func Test_paramSetCmd(t *testing.T) {
 				actions.OptionPath:         "param-name",
 				actions.OptionValue:        "param-value",
 				actions.OptionEnvName:      "",
+				actions.OptionConfirmEnv:   "",
 				actions.OptionAsString:     false,
 				actions.OptionResolveImage: false,
 			},
@@ -47,6 +48,7 @@ func Test_paramSetCmd(t *testing.T) {
 				actions.OptionPath:         "param-name",
 				actions.OptionValue:        "param-value",
 				actions.OptionEnvName:      "",
+				actions.OptionConfirmEnv:   "",
 				actions.OptionAsString:     false,
 				actions.OptionResolveImage: true,
 			},
@@ -62,6 +64,7 @@ func Test_paramSetCmd(t *testing.T) {
 				actions.OptionPath:         "param-name",
 				actions.OptionValue:        "param-value",
 				actions.OptionEnvName:      "default",
+				actions.OptionConfirmEnv:   "",
 				actions.OptionAsString:     false,
 				actions.OptionResolveImage: false,
 			},
@@ -82,6 +85,7 @@ func Test_paramSetCmd(t *testing.T) {
 				actions.OptionPath:         "param-name",
 				actions.OptionValue:        "param-value",
 				actions.OptionEnvName:      "",
+				actions.OptionConfirmEnv:   "",
 				actions.OptionAsString:     true,
 				actions.OptionResolveImage: false,
 			},
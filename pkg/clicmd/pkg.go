@@ -29,6 +29,10 @@ var (
 		"remove":   "Remove a package from the app or environment scope",
 		"describe": "Describe a ksonnet package and its contents",
 		"list":     "List all packages known (downloaded or not) for the current ksonnet app",
+		"outdated": "List installed packages for which a newer version is available",
+		"search":   "Search the packages of all registries",
+		"upgrade":  "Upgrade installed packages to a newer version",
+		"verify":   "Verify vendored packages against ks.lock",
 	}
 	pkgLong = `
 A ksonnet package contains:
@@ -69,6 +73,10 @@ func newPkgCmd() *cobra.Command {
 	pkgCmd.AddCommand(newPkgInstallCmd())
 	pkgCmd.AddCommand(newPkgDescribeCmd())
 	pkgCmd.AddCommand(newPkgRemoveCmd())
+	pkgCmd.AddCommand(newPkgOutdatedCmd())
+	pkgCmd.AddCommand(newPkgSearchCmd())
+	pkgCmd.AddCommand(newPkgUpgradeCmd())
+	pkgCmd.AddCommand(newPkgVerifyCmd())
 
 	return pkgCmd
 }
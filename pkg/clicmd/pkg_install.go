@@ -38,6 +38,12 @@ The package itself needs to be located in a registry (e.g. Github repo). By defa
 ksonnet knows about two registries: *incubator* and *stable*, which are the release
 channels for official ksonnet packages.
 
+Packages can depend on other packages by listing them in the ` + "`dependencies`" + ` of
+their ` + "`parts.yaml`" + `, with an optional semver range (e.g. ` + "`^1.2.0`" + `) for their version.
+Dependencies are installed along with the package, unless a version that satisfies
+the range is installed already, either globally or in the environment given with
+--env. Conflicting version requirements are reported before anything is installed.
+
 ### Related Commands
 
 * ` + "`ks pkg list` " + `— ` + pkgShortDesc["list"] + `
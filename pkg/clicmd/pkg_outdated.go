@@ -0,0 +1,74 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+)
+
+const (
+	vPkgOutdatedOutput = "pkg-outdated-output"
+)
+
+var (
+	pkgOutdatedLong = `
+The ` + "`outdated`" + ` command compares every installed package, both in the app and
+in environments, with the latest version offered by its registry. It outputs a
+table of the packages for which a different version is available:
+
+1. Registry name
+2. Package name
+3. Environment the package is installed in (empty for the app)
+4. Installed version
+5. Latest version
+
+### Related Commands
+
+* ` + "`ks pkg upgrade` " + `— ` + pkgShortDesc["upgrade"] + `
+* ` + "`ks pkg list` " + `— ` + pkgShortDesc["list"] + `
+
+### Syntax
+`
+)
+
+func newPkgOutdatedCmd() *cobra.Command {
+	pkgOutdatedCmd := &cobra.Command{
+		Use:   "outdated",
+		Short: pkgShortDesc["outdated"],
+		Long:  pkgOutdatedLong,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("Command 'pkg outdated' does not take arguments")
+			}
+
+			m := map[string]interface{}{
+				actions.OptionOutput: viper.GetString(vPkgOutdatedOutput),
+			}
+			addGlobalOptions(m)
+
+			return runAction(actionPkgOutdated, m)
+		},
+	}
+
+	addCmdOutput(pkgOutdatedCmd, vPkgOutdatedOutput)
+
+	return pkgOutdatedCmd
+}
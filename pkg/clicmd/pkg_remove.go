@@ -25,13 +25,19 @@ import (
 )
 
 var (
-	vPkgRemoveEnv = "pkg-remove-env"
+	vPkgRemoveCascade = "pkg-remove-cascade"
+	vPkgRemoveEnv     = "pkg-remove-env"
 
 	pkgRemoveLong = `
 The ` + "`remove`" + ` command removes a reference to a ksonnet library.  The reference can either be
 global or scoped to an environment. If the last reference to a library version is removed, the cached
 files will be removed as well.
 
+A package can't be removed while other installed packages depend on it. Use
+` + "`--cascade`" + ` to remove those packages as well. Only the global packages and the packages
+of the environment a package is removed from are checked, and ` + "`--cascade`" + ` never
+removes packages outside of that environment.
+
 ### Syntax
 `
 	pkgRemoveExample = `
@@ -40,6 +46,9 @@ ks pkg remove incubator/nginx
 
 # Remove an nginx dependency from the stage environment
 ks pkg remove incubator/nginx --env stage
+
+# Remove a k8s-util dependency, and all packages which depend on it
+ks pkg remove incubator/k8s-util --cascade
 `
 )
 
@@ -59,6 +68,7 @@ func newPkgRemoveCmd() *cobra.Command {
 			m := map[string]interface{}{
 				actions.OptionPkgName: args[0],
 				actions.OptionEnvName: viper.GetString(vPkgRemoveEnv),
+				actions.OptionCascade: viper.GetBool(vPkgRemoveCascade),
 			}
 			addGlobalOptions(m)
 
@@ -69,5 +79,8 @@ func newPkgRemoveCmd() *cobra.Command {
 	pkgRemoveCmd.Flags().String(flagEnv, "", "Environment to remove package from (optional)")
 	viper.BindPFlag(vPkgRemoveEnv, pkgRemoveCmd.Flags().Lookup(flagEnv))
 
+	pkgRemoveCmd.Flags().Bool(flagCascade, false, "Remove packages which depend on the package as well")
+	viper.BindPFlag(vPkgRemoveCascade, pkgRemoveCmd.Flags().Lookup(flagCascade))
+
 	return pkgRemoveCmd
 }
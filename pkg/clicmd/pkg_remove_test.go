@@ -31,6 +31,7 @@ func Test_pkgRemoveCmd(t *testing.T) {
 				actions.OptionApp:           nil,
 				actions.OptionPkgName:       "package-name",
 				actions.OptionEnvName:       "",
+				actions.OptionCascade:       false,
 				actions.OptionTLSSkipVerify: false,
 			},
 		},
@@ -42,6 +43,19 @@ func Test_pkgRemoveCmd(t *testing.T) {
 				actions.OptionApp:           nil,
 				actions.OptionPkgName:       "package-name",
 				actions.OptionEnvName:       "production",
+				actions.OptionCascade:       false,
+				actions.OptionTLSSkipVerify: false,
+			},
+		},
+		{
+			name:   "with cascade flag",
+			args:   []string{"pkg", "remove", "--cascade", "package-name"},
+			action: actionPkgRemove,
+			expected: map[string]interface{}{
+				actions.OptionApp:           nil,
+				actions.OptionPkgName:       "package-name",
+				actions.OptionEnvName:       "",
+				actions.OptionCascade:       true,
 				actions.OptionTLSSkipVerify: false,
 			},
 		},
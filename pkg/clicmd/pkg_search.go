@@ -0,0 +1,103 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+)
+
+const (
+	vPkgSearchDetails = "pkg-search-details"
+	vPkgSearchOutput  = "pkg-search-output"
+	vPkgSearchRefresh = "pkg-search-refresh"
+)
+
+var (
+	pkgSearchLong = `
+The ` + "`search`" + ` command searches the packages of every registry known to the current
+ksonnet app. A package matches if every word of the query matches its name, keywords,
+prototypes or description. Results are ranked by relevance, with matches on the
+package name first.
+
+The package index is built from each package's ` + "`parts.yaml`" + ` and from Helm chart
+metadata, and is cached in ` + "`.ksonnet/registries`" + `. The cache is rebuilt when registries
+are added or removed, or when ` + "`--refresh`" + ` is passed. ` + "`ks pkg search --refresh`" + `
+without a query only rebuilds the index.
+
+Pass ` + "`--details`" + ` to print the versions, keywords, prototypes and quick start
+instructions of each package.
+
+### Related Commands
+
+* ` + "`ks pkg list` " + `— ` + pkgShortDesc["list"] + `
+* ` + "`ks pkg describe` " + `— ` + pkgShortDesc["describe"] + `
+* ` + "`ks pkg install` " + `— ` + pkgShortDesc["install"] + `
+
+### Syntax
+`
+	pkgSearchExample = `
+# Search for packages related to redis
+ks pkg search redis
+
+# Print the details and quick start instructions of matching packages
+ks pkg search redis --details
+
+# Rebuild the package index
+ks pkg search --refresh`
+)
+
+func newPkgSearchCmd() *cobra.Command {
+	pkgSearchCmd := &cobra.Command{
+		Use:     "search [<query>]",
+		Short:   pkgShortDesc["search"],
+		Long:    pkgSearchLong,
+		Example: pkgSearchExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			refresh := viper.GetBool(vPkgSearchRefresh)
+			if len(args) > 1 || (len(args) == 0 && !refresh) {
+				return fmt.Errorf("Command 'pkg search' requires a query, unless --refresh is set")
+			}
+
+			var query string
+			if len(args) == 1 {
+				query = args[0]
+			}
+
+			m := map[string]interface{}{
+				actions.OptionQuery:   query,
+				actions.OptionRefresh: refresh,
+				actions.OptionDetails: viper.GetBool(vPkgSearchDetails),
+				actions.OptionOutput:  viper.GetString(vPkgSearchOutput),
+			}
+			addGlobalOptions(m)
+
+			return runAction(actionPkgSearch, m)
+		},
+	}
+
+	addCmdOutput(pkgSearchCmd, vPkgSearchOutput)
+	pkgSearchCmd.Flags().Bool(flagDetails, false, "Print the details of each package")
+	viper.BindPFlag(vPkgSearchDetails, pkgSearchCmd.Flags().Lookup(flagDetails))
+	pkgSearchCmd.Flags().Bool(flagRefresh, false, "Rebuild the package index")
+	viper.BindPFlag(vPkgSearchRefresh, pkgSearchCmd.Flags().Lookup(flagRefresh))
+
+	return pkgSearchCmd
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+)
+
+func Test_pkgSearchCmd(t *testing.T) {
+	cases := []cmdTestCase{
+		{
+			name:   "in general",
+			args:   []string{"pkg", "search", "redis"},
+			action: actionPkgSearch,
+			expected: map[string]interface{}{
+				actions.OptionApp:           nil,
+				actions.OptionQuery:         "redis",
+				actions.OptionRefresh:       false,
+				actions.OptionDetails:       false,
+				actions.OptionOutput:        "",
+				actions.OptionTLSSkipVerify: false,
+			},
+		},
+		{
+			name:   "details",
+			args:   []string{"pkg", "search", "redis", "--details", "-o", "json"},
+			action: actionPkgSearch,
+			expected: map[string]interface{}{
+				actions.OptionApp:           nil,
+				actions.OptionQuery:         "redis",
+				actions.OptionRefresh:       false,
+				actions.OptionDetails:       true,
+				actions.OptionOutput:        "json",
+				actions.OptionTLSSkipVerify: false,
+			},
+		},
+		{
+			name:   "refresh without query",
+			args:   []string{"pkg", "search", "--refresh"},
+			action: actionPkgSearch,
+			expected: map[string]interface{}{
+				actions.OptionApp:           nil,
+				actions.OptionQuery:         "",
+				actions.OptionRefresh:       true,
+				actions.OptionDetails:       false,
+				actions.OptionOutput:        "",
+				actions.OptionTLSSkipVerify: false,
+			},
+		},
+		{
+			name:  "no query",
+			args:  []string{"pkg", "search"},
+			isErr: true,
+		},
+		{
+			name:  "too many args",
+			args:  []string{"pkg", "search", "redis", "mysql"},
+			isErr: true,
+		},
+	}
+
+	runTestCmd(t, cases)
+}
@@ -0,0 +1,96 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/viper"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+)
+
+var (
+	vPkgUpgradeEnv = "pkg-upgrade-env"
+	vPkgUpgradeTo  = "pkg-upgrade-to"
+
+	pkgUpgradeLong = `
+The ` + "`upgrade`" + ` command re-vendors an installed package at a newer version and
+updates its reference in ` + "`app.yaml`" + `. The previously vendored version is removed
+if it is no longer referenced. Without ` + "`--to`" + `, the package is upgraded to the
+latest version offered by its registry.
+
+Without a package, all outdated packages are upgraded. Packages are upgraded in the
+app, or in an environment with ` + "`--env`" + `.
+
+The manifests of the affected environments are rendered before and after the
+upgrade, and the changes are shown.
+
+### Related Commands
+
+* ` + "`ks pkg outdated` " + `— ` + pkgShortDesc["outdated"] + `
+* ` + "`ks pkg install` " + `— ` + pkgShortDesc["install"] + `
+
+### Syntax
+`
+	pkgUpgradeExample = `
+# Upgrade all outdated packages in the app
+ks pkg upgrade
+
+# Upgrade nginx to the latest version offered by its registry
+ks pkg upgrade incubator/nginx
+
+# Upgrade redis in the stage environment to version 3.2.1
+ks pkg upgrade incubator/redis --to 3.2.1 --env stage
+`
+)
+
+func newPkgUpgradeCmd() *cobra.Command {
+	pkgUpgradeCmd := &cobra.Command{
+		Use:     "upgrade [<registry>/<package>]",
+		Short:   pkgShortDesc["upgrade"],
+		Long:    pkgUpgradeLong,
+		Example: pkgUpgradeExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 1 {
+				return fmt.Errorf("Command accepts at most a single argument of the form <registry>/<package>\n\n%s", cmd.UsageString())
+			}
+
+			var pkgName string
+			if len(args) == 1 {
+				pkgName = args[0]
+			}
+
+			m := map[string]interface{}{
+				actions.OptionPkgName: pkgName,
+				actions.OptionVersion: viper.GetString(vPkgUpgradeTo),
+				actions.OptionEnvName: viper.GetString(vPkgUpgradeEnv),
+			}
+			addGlobalOptions(m)
+
+			return runAction(actionPkgUpgrade, m)
+		},
+	}
+
+	pkgUpgradeCmd.Flags().String(flagEnv, "", "Environment to upgrade packages in (optional)")
+	viper.BindPFlag(vPkgUpgradeEnv, pkgUpgradeCmd.Flags().Lookup(flagEnv))
+
+	pkgUpgradeCmd.Flags().String(flagTo, "", "Version to upgrade the package to (optional)")
+	viper.BindPFlag(vPkgUpgradeTo, pkgUpgradeCmd.Flags().Lookup(flagTo))
+
+	return pkgUpgradeCmd
+}
@@ -0,0 +1,58 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+)
+
+func Test_pkgUpgradeCmd(t *testing.T) {
+	cases := []cmdTestCase{
+		{
+			name:   "all packages",
+			args:   []string{"pkg", "upgrade"},
+			action: actionPkgUpgrade,
+			expected: map[string]interface{}{
+				actions.OptionApp:           nil,
+				actions.OptionPkgName:       "",
+				actions.OptionVersion:       "",
+				actions.OptionEnvName:       "",
+				actions.OptionTLSSkipVerify: false,
+			},
+		},
+		{
+			name:   "a package to a version in an environment",
+			args:   []string{"pkg", "upgrade", "incubator/redis", "--to", "3.2.1", "--env", "stage"},
+			action: actionPkgUpgrade,
+			expected: map[string]interface{}{
+				actions.OptionApp:           nil,
+				actions.OptionPkgName:       "incubator/redis",
+				actions.OptionVersion:       "3.2.1",
+				actions.OptionEnvName:       "stage",
+				actions.OptionTLSSkipVerify: false,
+			},
+		},
+		{
+			name:  "invalid args",
+			args:  []string{"pkg", "upgrade", "incubator/redis", "incubator/nginx"},
+			isErr: true,
+		},
+	}
+
+	runTestCmd(t, cases)
+}
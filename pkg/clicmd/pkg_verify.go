@@ -0,0 +1,68 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	pkgVerifyLong = `
+The ` + "`verify`" + ` command checks that the packages vendored in ` + "`vendor/`" + ` still match
+the versions and content digests recorded in ` + "`ks.lock`" + `. ` + "`ks pkg install`" + ` and
+` + "`ks pkg remove`" + ` keep ` + "`ks.lock`" + ` up to date.
+
+Each package which was modified, is missing, or is not recorded in ` + "`ks.lock`" + ` is
+reported, and the command fails.
+
+To refuse to render or apply components when the vendored packages do not match
+` + "`ks.lock`" + `, use the ` + "`--frozen`" + ` flag of ` + "`ks show`" + ` and ` + "`ks apply`" + `.
+
+### Related Commands
+
+* ` + "`ks pkg install` " + `— ` + pkgShortDesc["install"] + `
+* ` + "`ks pkg remove` " + `— ` + pkgShortDesc["remove"] + `
+
+### Syntax
+`
+	pkgVerifyExample = `
+# Verify the vendored packages of the current ksonnet app
+ks pkg verify
+`
+)
+
+func newPkgVerifyCmd() *cobra.Command {
+	pkgVerifyCmd := &cobra.Command{
+		Use:     "verify",
+		Short:   pkgShortDesc["verify"],
+		Long:    pkgVerifyLong,
+		Example: pkgVerifyExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return fmt.Errorf("Command 'pkg verify' does not take arguments")
+			}
+
+			m := map[string]interface{}{}
+			addGlobalOptions(m)
+
+			return runAction(actionPkgVerify, m)
+		},
+	}
+
+	return pkgVerifyCmd
+}
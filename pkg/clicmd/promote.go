@@ -0,0 +1,98 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vPromoteComponent  = "promote-component"
+	vPromoteConfirmEnv = "promote-confirm-env"
+	vPromoteDryRun     = "promote-dry-run"
+	vPromoteParamOnly  = "promote-param-only"
+
+	promoteShortDesc = "Promote the configuration of one environment to another"
+)
+
+var (
+	promoteLong = `
+The ` + "`promote`" + ` command copies the component parameters which differ between
+two environments from the source to the destination environment. Unless
+` + "`--param-only`" + ` is given, the source's targets and library references are
+promoted as well.
+
+A preview of the changes is printed before they are made. Use ` + "`--dry-run`" + ` to
+only show the preview. If the destination environment is protected, the changes
+have to be confirmed, with ` + "`--confirm-env`" + ` or by typing its name.
+
+### Related Commands
+
+* ` + "`ks param diff` " + `— ` + paramShortDesc["diff"] + `
+* ` + "`ks env clone` " + `— ` + envShortDesc["clone"] + `
+
+### Syntax
+`
+	promoteExample = `
+# Promote everything that was tested in 'staging' to 'prod'
+ks promote staging prod
+
+# Preview the parameter changes for the 'guestbook' component
+ks promote staging prod --component=guestbook --param-only --dry-run`
+)
+
+func newPromoteCmd() *cobra.Command {
+	promoteCmd := &cobra.Command{
+		Use:     "promote <src-env> <dst-env> [--component <component-name>] [--param-only]",
+		Short:   promoteShortDesc,
+		Long:    promoteLong,
+		Example: promoteExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 2 {
+				return fmt.Errorf("'promote' takes exactly two arguments: the names of the source and destination environments")
+			}
+
+			m := map[string]interface{}{
+				actions.OptionEnvName1:      args[0],
+				actions.OptionEnvName2:      args[1],
+				actions.OptionComponentName: viper.GetString(vPromoteComponent),
+				actions.OptionConfirmEnv:    viper.GetString(vPromoteConfirmEnv),
+				actions.OptionDryRun:        viper.GetBool(vPromoteDryRun),
+				actions.OptionParamOnly:     viper.GetBool(vPromoteParamOnly),
+			}
+			addGlobalOptions(m)
+
+			return runAction(actionPromote, m)
+		},
+	}
+
+	promoteCmd.Flags().String(flagComponent, "", "Only promote the parameters of this component")
+	viper.BindPFlag(vPromoteComponent, promoteCmd.Flags().Lookup(flagComponent))
+
+	addCmdConfirmEnv(promoteCmd, vPromoteConfirmEnv)
+
+	promoteCmd.Flags().Bool(flagDryRun, false, "Only show the changes which would be made")
+	viper.BindPFlag(vPromoteDryRun, promoteCmd.Flags().Lookup(flagDryRun))
+
+	promoteCmd.Flags().Bool(flagParamOnly, false, "Only promote component parameters")
+	viper.BindPFlag(vPromoteParamOnly, promoteCmd.Flags().Lookup(flagParamOnly))
+
+	return promoteCmd
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+)
+
+func Test_promoteCmd(t *testing.T) {
+	cases := []cmdTestCase{
+		{
+			name:   "in general",
+			args:   []string{"promote", "staging", "prod"},
+			action: actionPromote,
+			expected: map[string]interface{}{
+				actions.OptionApp:           nil,
+				actions.OptionEnvName1:      "staging",
+				actions.OptionEnvName2:      "prod",
+				actions.OptionComponentName: "",
+				actions.OptionConfirmEnv:    "",
+				actions.OptionDryRun:        false,
+				actions.OptionParamOnly:     false,
+			},
+		},
+		{
+			name:   "component params",
+			args:   []string{"promote", "staging", "prod", "--component", "guestbook", "--param-only", "--dry-run"},
+			action: actionPromote,
+			expected: map[string]interface{}{
+				actions.OptionApp:           nil,
+				actions.OptionEnvName1:      "staging",
+				actions.OptionEnvName2:      "prod",
+				actions.OptionComponentName: "guestbook",
+				actions.OptionConfirmEnv:    "",
+				actions.OptionDryRun:        true,
+				actions.OptionParamOnly:     true,
+			},
+		},
+		{
+			name:   "confirm protected environment",
+			args:   []string{"promote", "staging", "prod", "--confirm-env", "prod"},
+			action: actionPromote,
+			expected: map[string]interface{}{
+				actions.OptionApp:           nil,
+				actions.OptionEnvName1:      "staging",
+				actions.OptionEnvName2:      "prod",
+				actions.OptionComponentName: "",
+				actions.OptionConfirmEnv:    "prod",
+				actions.OptionDryRun:        false,
+				actions.OptionParamOnly:     false,
+			},
+		},
+		{
+			name:  "missing destination",
+			args:  []string{"promote", "staging"},
+			isErr: true,
+		},
+	}
+
+	runTestCmd(t, cases)
+}
@@ -24,7 +24,10 @@ var (
 		"list":     "List all registries known to the current ksonnet app",
 		"describe": "Describe a ksonnet registry and the packages it contains",
 		"add":      "Add a registry to the current ksonnet app",
+		"bundle":   "Snapshot a registry into a bundle for offline use",
 		"set":      "Set configuration options for registry",
+		"sign":     "Sign a registry in a local directory",
+		"keygen":   "Generate a key pair for signing registries",
 	}
 	registryLong = `
 A ksonnet registry is basically a repository for *packages*. (Registry here is
@@ -53,9 +56,12 @@ func newRegistryCmd() *cobra.Command {
 	}
 
 	registryCmd.AddCommand(newRegistryAddCmd())
+	registryCmd.AddCommand(newRegistryBundleCmd())
 	registryCmd.AddCommand(newRegistryDescribeCmd())
+	registryCmd.AddCommand(newRegistryKeygenCmd())
 	registryCmd.AddCommand(newRegistryListCmd())
 	registryCmd.AddCommand(newRegistrySetCmd())
+	registryCmd.AddCommand(newRegistrySignCmd())
 
 	return registryCmd
 }
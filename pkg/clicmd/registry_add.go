@@ -25,6 +25,7 @@ import (
 )
 
 const (
+	vRegistryAddBundle   = "registry-add-bundle"
 	vRegistryAddOverride = "registry-add-override"
 )
 
@@ -37,13 +38,28 @@ as an example.*
 
 A registry is given a string identifier, which must be unique within a ksonnet application.
 
-There are three supported registry protocols: **github**, **fs**, and **Helm**.
+There are four supported registry protocols: **github**, **git**, **fs**, and **Helm**.
 
 GitHub registries expect a path in a GitHub repository, and filesystem based
-registries expect a path on the local filesystem.
+registries expect a path on the local filesystem. Git registries can be hosted in
+any git repository, and expect a URI of the form
+` + "`<repository>[//<path>][#<ref>]`" + `. URIs whose repository ends in
+` + "`.git`" + `, or that start with ` + "`git://`" + `, ` + "`ssh://`" + ` or ` + "`git@`" + `, use the git protocol.
 
 During creation, all registries must specify a unique name and URI where the
-registry lives. GitHub registries can specify a commit, tag, or branch to follow as part of the URI.
+registry lives. GitHub and git registries can specify a commit, tag, or branch to follow as part of the URI.
+
+Registries can also be added from a bundle created by ` + "`ks registry bundle`" + `,
+with ` + "`--bundle`" + `. The bundle is extracted into ` + "`.ksonnet/bundles/<registry-name>`" + `,
+so its packages can be installed without network access.
+
+A local directory without a ` + "`registry.yaml`" + ` which contains chart directories
+or chart archives is added as a Helm registry. Its index is generated from the
+` + "`Chart.yaml`" + ` of each chart, so charts can be installed without publishing them.
+
+Credentials and TLS options for private Helm and GitHub registries are read from
+` + "`credentials`" + ` in the user configuration (` + "`$HOME/.config/ksonnet/config.yaml`" + `),
+keyed by registry URI. They are never stored in ` + "`app.yaml`" + `.
 
 Registries can be overridden with ` + "`--override`" + `.  Overridden registries
 are stored in ` + "`app.override.yaml`" + ` and can be safely ignored using your
@@ -52,6 +68,7 @@ SCM configuration.
 ### Related Commands
 
 * ` + "`ks registry list` " + `— ` + regShortDesc["list"] + `
+* ` + "`ks registry bundle` " + `— ` + regShortDesc["bundle"] + `
 
 ### Syntax
 `
@@ -62,17 +79,44 @@ ks registry add databases github.com/example
 # 'github.com/org/example/tree/0.0.1/registry' (0.0.1 is the branch name)
 ks registry add databases github.com/org/example/tree/0.0.1/registry
 
+# Add a registry with the name 'internal' from the 'incubator' directory of a
+# git repository, following the tag v1.0
+ks registry add internal https://git.example.com/org/parts.git//incubator#v1.0
+
 # Add a registry with a Helm Charts Repository uri
-ks registry add helm-stable https://kubernetes-charts.storage.googleapis.com`
+ks registry add helm-stable https://kubernetes-charts.storage.googleapis.com
+
+# Add a Helm registry with the name 'localcharts' from a directory of chart
+# directories and chart archives
+ks registry add localcharts ./charts
+
+# Add a registry with the name 'offline' from a registry bundle
+ks registry add offline --bundle registry.tgz`
 )
 
 func newRegistryAddCmd() *cobra.Command {
 	registryAddCmd := &cobra.Command{
-		Use:     "add <registry-name> <registry-uri>",
+		Use:     "add <registry-name> [<registry-uri>]",
 		Short:   regShortDesc["add"],
 		Long:    registryAddLong,
 		Example: registryAddExample,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			bundle := viper.GetString(vRegistryAddBundle)
+			if bundle != "" {
+				if len(args) != 1 {
+					return fmt.Errorf("Command 'registry add --bundle' takes a single argument, which is the name of the registry to add")
+				}
+
+				m := map[string]interface{}{
+					actions.OptionName:     args[0],
+					actions.OptionBundle:   bundle,
+					actions.OptionOverride: viper.GetBool(vRegistryAddOverride),
+				}
+				addGlobalOptions(m)
+
+				return runAction(actionRegistryAdd, m)
+			}
+
 			if len(args) != 2 {
 				return fmt.Errorf("Command 'registry add' takes two arguments, which is the name and the repository address of the registry to add")
 			}
@@ -88,6 +132,9 @@ func newRegistryAddCmd() *cobra.Command {
 		},
 	}
 
+	registryAddCmd.Flags().String(flagBundle, "", "Add the registry from a bundle created by 'ks registry bundle'")
+	viper.BindPFlag(vRegistryAddBundle, registryAddCmd.Flags().Lookup(flagBundle))
+
 	registryAddCmd.Flags().BoolP(flagOverride, shortOverride, false, "Store in override configuration")
 	viper.BindPFlag(vRegistryAddOverride, registryAddCmd.Flags().Lookup(flagOverride))
 
@@ -36,6 +36,18 @@ func Test_registryAddCmd(t *testing.T) {
 				actions.OptionTLSSkipVerify: false,
 			},
 		},
+		{
+			name:   "bundle",
+			args:   []string{"registry", "add", "name", "--bundle", "registry.tgz"},
+			action: actionRegistryAdd,
+			expected: map[string]interface{}{
+				actions.OptionApp:           nil,
+				actions.OptionName:          "name",
+				actions.OptionBundle:        "registry.tgz",
+				actions.OptionOverride:      false,
+				actions.OptionTLSSkipVerify: false,
+			},
+		},
 		{
 			name:  "invalid arguments",
 			args:  []string{"registry", "add"},
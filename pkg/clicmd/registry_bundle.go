@@ -0,0 +1,93 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vRegistryBundleOutput  = "registry-bundle-output"
+	vRegistryBundlePackage = "registry-bundle-package"
+)
+
+var (
+	registryBundleLong = `
+The ` + "`bundle`" + ` command snapshots a registry into a single archive, so its packages
+can be installed on machines without network access. The bundle contains the
+registry spec and the selected packages, at their current or requested versions.
+Without ` + "`--package`" + `, every package in the registry is bundled.
+
+Bundles are added to an app with ` + "`ks registry add <name> --bundle <file>`" + `.
+
+Alternatively, a registry URI can be redirected to a local directory for every
+app by mapping it in ` + "`$HOME/.config/ksonnet/config.yaml`" + `:
+
+    mirrors:
+      github.com/ksonnet/parts/tree/master/incubator: /srv/mirrors/incubator
+
+### Related Commands
+
+* ` + "`ks registry add` " + `— ` + regShortDesc["add"] + `
+
+### Syntax
+`
+	registryBundleExample = `
+# Snapshot the 'incubator' registry
+ks registry bundle incubator -o incubator.tgz
+
+# Snapshot version 1.0.0 of the 'redis' package of the 'stable' registry
+ks registry bundle stable -o stable.tgz --package redis@1.0.0`
+)
+
+func newRegistryBundleCmd() *cobra.Command {
+	registryBundleCmd := &cobra.Command{
+		Use:     "bundle <registry-name>",
+		Short:   regShortDesc["bundle"],
+		Long:    registryBundleLong,
+		Example: registryBundleExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("Command 'registry bundle' requires a single argument, which is the name of the registry to bundle")
+			}
+
+			output := viper.GetString(vRegistryBundleOutput)
+			if output == "" {
+				return fmt.Errorf("Command 'registry bundle' requires an output file")
+			}
+
+			m := map[string]interface{}{
+				actions.OptionName:     args[0],
+				actions.OptionPath:     output,
+				actions.OptionPkgNames: viper.GetStringSlice(vRegistryBundlePackage),
+			}
+			addGlobalOptions(m)
+
+			return runAction(actionRegistryBundle, m)
+		},
+	}
+
+	registryBundleCmd.Flags().StringP(flagOutput, shortOutput, "", "Path of the bundle to write")
+	viper.BindPFlag(vRegistryBundleOutput, registryBundleCmd.Flags().Lookup(flagOutput))
+	registryBundleCmd.Flags().StringSlice(flagPackage, nil, "Package to bundle, as <name>[@<version>] (multiple --package flags accepted)")
+	viper.BindPFlag(vRegistryBundlePackage, registryBundleCmd.Flags().Lookup(flagPackage))
+
+	return registryBundleCmd
+}
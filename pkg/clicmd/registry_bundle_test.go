@@ -0,0 +1,51 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+)
+
+func Test_registryBundleCmd(t *testing.T) {
+	cases := []cmdTestCase{
+		{
+			name:   "in general",
+			args:   []string{"registry", "bundle", "incubator", "-o", "incubator.tgz", "--package", "nginx", "--package", "redis@1.0.0"},
+			action: actionRegistryBundle,
+			expected: map[string]interface{}{
+				actions.OptionApp:           nil,
+				actions.OptionName:          "incubator",
+				actions.OptionPath:          "incubator.tgz",
+				actions.OptionPkgNames:      []string{"nginx", "redis@1.0.0"},
+				actions.OptionTLSSkipVerify: false,
+			},
+		},
+		{
+			name:  "missing output",
+			args:  []string{"registry", "bundle", "incubator"},
+			isErr: true,
+		},
+		{
+			name:  "invalid arguments",
+			args:  []string{"registry", "bundle"},
+			isErr: true,
+		},
+	}
+
+	runTestCmd(t, cases)
+}
@@ -0,0 +1,62 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+)
+
+var (
+	registryKeygenLong = `
+The ` + "`keygen`" + ` command generates an ed25519 key pair for signing registries. The
+private key is written to the given file, and the public key is printed. Keep the
+private key secret; distribute the public key to the apps which use the registry.
+
+### Related Commands
+
+* ` + "`ks registry sign` " + `— ` + regShortDesc["sign"] + `
+
+### Syntax
+`
+	registryKeygenExample = `
+# Generate a key pair
+ks registry keygen ~/.ksonnet/registry.key`
+)
+
+func newRegistryKeygenCmd() *cobra.Command {
+	registryKeygenCmd := &cobra.Command{
+		Use:     "keygen <private-key-file>",
+		Short:   regShortDesc["keygen"],
+		Long:    registryKeygenLong,
+		Example: registryKeygenExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("Command 'registry keygen' requires a single argument, which is the file to write the private key to")
+			}
+
+			m := map[string]interface{}{
+				actions.OptionPath: args[0],
+			}
+
+			return runAction(actionRegistryKeygen, m)
+		},
+	}
+
+	return registryKeygenCmd
+}
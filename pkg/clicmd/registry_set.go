@@ -22,7 +22,8 @@ import (
 )
 
 const (
-	vRegistrySetURI = "registry-set-uri"
+	vRegistrySetPublicKey = "registry-set-public-key"
+	vRegistrySetURI       = "registry-set-uri"
 )
 
 var (
@@ -31,10 +32,15 @@ The ` + "`set`" + ` command sets configuration parameters on a configured regist
 The following parameters can be set:
 
 * --uri: The uri a registry points to. For GitHub-based registries, this can be used to select a specific branch.
+* --public-key: A trusted ed25519 public key, as printed by ` + "`ks registry keygen`" + `. Once keys
+  are set, packages from the registry must be signed with one of them to be installed.
 `
 	registrySetExample = `
 	# Set the incubator registry to the experimental branch:
 	ks registry set incubator --uri https://github.com/ksonnet/parts/tree/experimental/incubator
+
+	# Only install packages from the internal registry which are signed with a trusted key:
+	ks registry set internal --public-key 2Kq3TmlqJ0lZc1k4N5PSl1rqk1vMu5cKqkZnZT9lUxA=
 `
 )
 
@@ -49,8 +55,9 @@ func newRegistrySetCmd() *cobra.Command {
 			registryName := args[0] // len(args) was verified
 
 			m := map[string]interface{}{
-				actions.OptionName: registryName,
-				actions.OptionURI:  viper.GetString(vRegistrySetURI),
+				actions.OptionName:       registryName,
+				actions.OptionURI:        viper.GetString(vRegistrySetURI),
+				actions.OptionPublicKeys: viper.GetStringSlice(vRegistrySetPublicKey),
 			}
 
 			return runAction(actionRegistrySet, m)
@@ -60,6 +67,8 @@ func newRegistrySetCmd() *cobra.Command {
 	flagURI := "uri"
 	registrySetCmd.Flags().String(flagURI, "", "URI to configure the registry")
 	viper.BindPFlag(vRegistrySetURI, registrySetCmd.Flags().Lookup(flagURI))
+	registrySetCmd.Flags().StringSlice(flagPublicKey, nil, "Trusted public key for package signatures (multiple --public-key flags accepted)")
+	viper.BindPFlag(vRegistrySetPublicKey, registrySetCmd.Flags().Lookup(flagPublicKey))
 
 	return registrySetCmd
 }
@@ -0,0 +1,81 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"fmt"
+
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	vRegistrySignPrivateKey = "registry-sign-private-key"
+)
+
+var (
+	registrySignLong = `
+The ` + "`sign`" + ` command signs a registry in a local directory, usually a checkout of the
+repository which hosts it. It records the digest of each package's file tree in
+` + "`registry.yaml`" + `, and writes a signature of ` + "`registry.yaml`" + ` to
+` + "`registry.yaml.sig`" + `. Commit both files along with the registry.
+
+Apps which pin the matching public key with ` + "`ks registry set --public-key`" + ` verify
+these signatures before packages are vendored.
+
+### Related Commands
+
+* ` + "`ks registry keygen` " + `— ` + regShortDesc["keygen"] + `
+* ` + "`ks registry set` " + `— ` + regShortDesc["set"] + `
+
+### Syntax
+`
+	registrySignExample = `
+# Sign the registry in the current directory
+ks registry sign . --private-key ~/.ksonnet/registry.key`
+)
+
+func newRegistrySignCmd() *cobra.Command {
+	registrySignCmd := &cobra.Command{
+		Use:     "sign <registry-dir>",
+		Short:   regShortDesc["sign"],
+		Long:    registrySignLong,
+		Example: registrySignExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 1 {
+				return fmt.Errorf("Command 'registry sign' requires a single argument, which is the directory of the registry to sign")
+			}
+
+			privateKey := viper.GetString(vRegistrySignPrivateKey)
+			if privateKey == "" {
+				return fmt.Errorf("Command 'registry sign' requires a private key")
+			}
+
+			m := map[string]interface{}{
+				actions.OptionPath:       args[0],
+				actions.OptionPrivateKey: privateKey,
+			}
+
+			return runAction(actionRegistrySign, m)
+		},
+	}
+
+	registrySignCmd.Flags().String(flagPrivateKey, "", "Path of the private key to sign with")
+	viper.BindPFlag(vRegistrySignPrivateKey, registrySignCmd.Flags().Lookup(flagPrivateKey))
+
+	return registrySignCmd
+}
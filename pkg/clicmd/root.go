@@ -142,12 +142,15 @@ func NewRoot(appFs afero.Fs, wd string, args []string) (*cobra.Command, error) {
 	rootCmd.AddCommand(newGenerateCmd(appFs))
 	rootCmd.AddCommand(newImportCmd())
 	rootCmd.AddCommand(newInitCmd(appFs, wd))
+	rootCmd.AddCommand(newLintCmd(appFs))
 	rootCmd.AddCommand(newModuleCmd())
 	rootCmd.AddCommand(newParamCmd())
 	rootCmd.AddCommand(newPkgCmd())
+	rootCmd.AddCommand(newPromoteCmd())
 	rootCmd.AddCommand(newPrototypeCmd(appFs))
 	rootCmd.AddCommand(newRegistryCmd())
 	rootCmd.AddCommand(newShowCmd(appFs))
+	rootCmd.AddCommand(newTestCmd(appFs))
 	rootCmd.AddCommand(newValidateCmd(appFs))
 	rootCmd.AddCommand(newUpgradeCmd())
 	rootCmd.AddCommand(newVersionCmd())
@@ -27,6 +27,7 @@ const (
 	showShortDesc  = "Show expanded manifests for a specific environment."
 	vShowComponent = "show-components"
 	vShowFormat    = "show-format"
+	vShowFrozen    = "show-frozen"
 )
 
 var (
@@ -80,6 +81,7 @@ func newShowCmd(fs afero.Fs) *cobra.Command {
 				actions.OptionComponentNames: viper.GetStringSlice(vShowComponent),
 				actions.OptionEnvName:        envName,
 				actions.OptionFormat:         viper.GetString(vShowFormat),
+				actions.OptionFrozen:         viper.GetBool(vShowFrozen),
 			}
 
 			if err := extractJsonnetFlags(fs, "show"); err != nil {
@@ -97,5 +99,7 @@ func newShowCmd(fs afero.Fs) *cobra.Command {
 	showCmd.Flags().StringP(flagFormat, shortFormat, "yaml", "Output format.  Supported values are: json, yaml")
 	viper.BindPFlag(vShowFormat, showCmd.Flags().Lookup(flagFormat))
 
+	addCmdFrozen(showCmd, vShowFrozen)
+
 	return showCmd
 }
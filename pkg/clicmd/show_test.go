@@ -32,6 +32,19 @@ func Test_showCmd(t *testing.T) {
 				actions.OptionEnvName:        "default",
 				actions.OptionComponentNames: make([]string, 0),
 				actions.OptionFormat:         "yaml",
+				actions.OptionFrozen:         false,
+			},
+		},
+		{
+			name:   "frozen",
+			args:   []string{"show", "default", "--frozen"},
+			action: actionShow,
+			expected: map[string]interface{}{
+				actions.OptionApp:            nil,
+				actions.OptionEnvName:        "default",
+				actions.OptionComponentNames: make([]string, 0),
+				actions.OptionFormat:         "yaml",
+				actions.OptionFrozen:         true,
 			},
 		},
 		{
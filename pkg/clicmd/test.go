@@ -0,0 +1,108 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package clicmd
+
+import (
+	"github.com/ksonnet/ksonnet/pkg/actions"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	testShortDesc = "Run the tests of the application."
+	vTestEnv      = "test-env"
+	vTestJUnit    = "test-junit"
+	vTestUpdate   = "test-update"
+)
+
+var (
+	testLong = `
+The ` + "`test`" + ` command runs the tests in the ` + "`tests/`" + ` directory of the application,
+in each environment, or only in the environment given with ` + "`--env`" + `.
+
+There are two kinds of tests:
+
+* **Jsonnet tests** are the ` + "`.jsonnet`" + ` files in ` + "`tests/`" + `. A test passes if it
+  evaluates without errors, so tests are usually written with ` + "`assert`" + ` or
+  ` + "`std.assertEqual`" + `. Tests are evaluated with the jpaths and ext vars of the
+  environment, and the objects rendered for the environment are available as
+  ` + "`std.extVar(\"__ksonnet/objects\")`" + `.
+* **Golden tests** compare the objects rendered for each component to the
+  snapshot in ` + "`tests/golden/<env-name>/<component-name>.yaml`" + `. Components
+  without a snapshot are not compared. Use ` + "`--update`" + ` to write the snapshots
+  of every component, and to remove the snapshots of components which are no
+  longer rendered.
+
+Use ` + "`--junit`" + ` to write the results as a JUnit XML report for CI systems.
+
+### Related Commands
+
+* ` + "`ks show` " + `— ` + showShortDesc + `
+* ` + "`ks validate` " + `— ` + valShortDesc + `
+
+### Syntax
+`
+	testExample = `
+# Run the tests in every environment
+ks test
+
+# Run the tests in the 'dev' environment, and write a JUnit report
+ks test --env dev --junit report.xml
+
+# Update the golden files of the 'dev' environment
+ks test --env dev --update
+`
+)
+
+func newTestCmd(fs afero.Fs) *cobra.Command {
+	testCmd := &cobra.Command{
+		Use:     "test [--env <env-name>]",
+		Short:   testShortDesc,
+		Long:    testLong,
+		Example: testExample,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) != 0 {
+				return errors.New("'test' takes no arguments")
+			}
+
+			m := map[string]interface{}{
+				actions.OptionEnvName: viper.GetString(vTestEnv),
+				actions.OptionJUnit:   viper.GetString(vTestJUnit),
+				actions.OptionUpdate:  viper.GetBool(vTestUpdate),
+			}
+
+			if err := extractJsonnetFlags(fs, "test"); err != nil {
+				return errors.Wrap(err, "handle jsonnet flags")
+			}
+
+			return runAction(actionTest, m)
+		},
+	}
+	bindJsonnetFlags(testCmd, "test")
+
+	testCmd.Flags().String(flagEnv, "", "Environment to run the tests in (defaults to every environment)")
+	viper.BindPFlag(vTestEnv, testCmd.Flags().Lookup(flagEnv))
+
+	testCmd.Flags().String(flagJUnit, "", "Path to write a JUnit XML report to")
+	viper.BindPFlag(vTestJUnit, testCmd.Flags().Lookup(flagJUnit))
+
+	testCmd.Flags().Bool(flagUpdate, false, "Update the golden files instead of comparing them")
+	viper.BindPFlag(vTestUpdate, testCmd.Flags().Lookup(flagUpdate))
+
+	return testCmd
+}
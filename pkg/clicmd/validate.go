@@ -28,15 +28,16 @@ import (
 
 const (
 	vValidateComponent = "validate-component"
+	vValidateOffline   = "validate-offline"
 	valShortDesc       = "Check generated component manifests against the server's API"
 )
 
 var (
 	validateLong = `
 The ` + "`validate`" + ` command checks that an application or file is compliant with the
-server APIs Kubernetes specification. Note that this command actually communicates
-*with* the server for the specified ` + "`<env-name>`" + `, so it only works if your
-$KUBECONFIG specifies a valid kubeconfig file.
+server APIs Kubernetes specification. Note that unless ` + "`--offline`" + ` is given, this
+command actually communicates *with* the server for the specified ` + "`<env-name>`" + `, so
+it only works if your $KUBECONFIG specifies a valid kubeconfig file.
 
 When NO component is specified (no ` + "`-c`" + ` flag), this command checks all of
 the files in the ` + "`components/`" + ` directory. This is the same as what would
@@ -45,6 +46,14 @@ get deployed to your cluster with ` + "`ks apply <env-name>`" + `.
 When a component IS specified via the ` + "`-c`" + ` flag, this command only checks
 the manifest for that particular component.
 
+Objects are validated against the OpenAPI definitions of the Kubernetes version
+of the environment, which are stored with its ksonnet-lib in ` + "`lib/`" + `. Types,
+enums, required fields and unknown fields are checked, and errors point to the
+invalid values with JSONPath expressions, like
+` + "`$.spec.template.spec.containers[0].image`" + `. With ` + "`--offline`" + `, the command does not
+communicate with the cluster at all, so it can run in CI systems without
+cluster access.
+
 ### Related Commands
 
 * ` + "`ks show` " + `— ` + showShortDesc + `
@@ -62,6 +71,10 @@ ksonnet validate dev
 # by the 'prod' environment
 # NOTE: Make sure your current $KUBECONFIG matches the 'prod' cluster info
 ksonnet validate prod -c redis
+
+# Validate all resources of the 'prod' environment without communicating with
+# its cluster
+ksonnet validate prod --offline
 `
 )
 
@@ -84,6 +97,7 @@ func newValidateCmd(fs afero.Fs) *cobra.Command {
 				actions.OptionModule:         "",
 				actions.OptionComponentNames: viper.GetStringSlice(vValidateComponent),
 				actions.OptionClientConfig:   validateClientConfig,
+				actions.OptionOffline:        viper.GetBool(vValidateOffline),
 			}
 
 			if err := extractJsonnetFlags(fs, "validate"); err != nil {
@@ -100,5 +114,8 @@ func newValidateCmd(fs afero.Fs) *cobra.Command {
 
 	viper.BindPFlag(vValidateComponent, validateCmd.Flag(flagComponent))
 
+	validateCmd.Flags().Bool(flagOffline, false, "Validate without communicating with the cluster")
+	viper.BindPFlag(vValidateOffline, validateCmd.Flags().Lookup(flagOffline))
+
 	return validateCmd
 }
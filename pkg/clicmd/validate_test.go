@@ -33,6 +33,20 @@ func Test_validateCmd(t *testing.T) {
 				actions.OptionModule:         "",
 				actions.OptionComponentNames: make([]string, 0),
 				actions.OptionClientConfig:   nil,
+				actions.OptionOffline:        false,
+			},
+		},
+		{
+			name:   "offline",
+			args:   []string{"validate", "env-name", "--offline"},
+			action: actionValidate,
+			expected: map[string]interface{}{
+				actions.OptionApp:            nil,
+				actions.OptionEnvName:        "env-name",
+				actions.OptionModule:         "",
+				actions.OptionComponentNames: make([]string, 0),
+				actions.OptionClientConfig:   nil,
+				actions.OptionOffline:        true,
 			},
 		},
 	}
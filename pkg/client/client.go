@@ -17,9 +17,11 @@ package client
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"reflect"
 	"regexp"
+	"strings"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	str "github.com/ksonnet/ksonnet/pkg/util/strings"
@@ -29,7 +31,9 @@ import (
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
 )
 
 const (
@@ -82,6 +86,22 @@ func NewDefaultClientConfig() *Config {
 	return NewClientConfig(overrides, loadingRules)
 }
 
+// Copy returns a copy of the client configuration with its own overrides, so
+// it can be resolved against a different environment destination.
+func (c *Config) Copy() *Config {
+	var overrides clientcmd.ConfigOverrides
+	if c.Overrides != nil {
+		overrides = *c.Overrides
+	}
+
+	loadingRules := *clientcmd.NewDefaultClientConfigLoadingRules()
+	if c.LoadingRules != nil {
+		loadingRules = *c.LoadingRules
+	}
+
+	return NewClientConfig(overrides, loadingRules)
+}
+
 // InitClient initializes a new ClientConfig given the specified environment
 // spec and returns the ClientPool, DiscoveryInterface, and namespace.
 // TODO DELETEME?
@@ -196,7 +216,27 @@ func (c *Config) ResolveContext(context string) (server, namespace string, err e
 // If the environment server the user is attempting to deploy to is not the current
 // kubeconfig context, we must manually override the client-go --cluster flag
 // to ensure we are deploying to the correct cluster.
+//
+// Destinations which name a kubeconfig context, or which are in-cluster, are
+// used as is instead.
 func (c *Config) overrideCluster(a app.App, envName string) error {
+	env, err := a.Environment(envName)
+	if err != nil {
+		return err
+	}
+
+	destination := env.Destination
+	if destination == nil {
+		return errors.Errorf("environment %q has no destination", envName)
+	}
+
+	switch {
+	case destination.InCluster:
+		return c.useInCluster(destination)
+	case destination.Context != "":
+		return c.useContext(envName, destination)
+	}
+
 	rawConfig, err := c.Config.RawConfig()
 	if err != nil {
 		return err
@@ -218,12 +258,6 @@ func (c *Config) overrideCluster(a app.App, envName string) error {
 	//
 
 	log.Debugf("Validating deployment at '%s' with server '%v'", envName, reflect.ValueOf(servers).MapKeys())
-	env, err := a.Environment(envName)
-	if err != nil {
-		return err
-	}
-
-	destination := env.Destination
 
 	server, err := str.NormalizeURL(destination.Server)
 	if err != nil {
@@ -254,3 +288,96 @@ func (c *Config) overrideCluster(a app.App, envName string) error {
 	c.Overrides.ClusterInfo.InsecureSkipTLSVerify = true
 	return nil
 }
+
+// useContext points the client configuration at the kubeconfig context of an
+// environment destination. Client-go flags given on the command line take
+// precedence over the destination.
+func (c *Config) useContext(envName string, destination *app.EnvironmentDestinationSpec) error {
+	if c.Overrides == nil || c.LoadingRules == nil {
+		return errors.New("client configuration is not initialized")
+	}
+
+	if destination.Kubeconfig != "" && c.LoadingRules.ExplicitPath == "" {
+		c.LoadingRules.ExplicitPath = destination.Kubeconfig
+	}
+	if c.Overrides.CurrentContext == "" {
+		c.Overrides.CurrentContext = destination.Context
+	}
+	if c.Overrides.Context.Namespace == "" {
+		c.Overrides.Context.Namespace = destination.Namespace
+	}
+
+	// The deferred client configuration caches the kubeconfig it loaded, so it
+	// has to be recreated to pick up the new loading rules and context.
+	c.reload()
+
+	rawConfig, err := c.Config.RawConfig()
+	if err != nil {
+		return err
+	}
+
+	if _, ok := rawConfig.Contexts[c.Overrides.CurrentContext]; !ok {
+		return errors.Errorf("context %q of environment %q does not exist in the kubeconfig file",
+			c.Overrides.CurrentContext, envName)
+	}
+
+	log.Debugf("Using context %q for environment %q", c.Overrides.CurrentContext, envName)
+	return nil
+}
+
+// useInCluster points the client configuration at the cluster ks is running in.
+func (c *Config) useInCluster(destination *app.EnvironmentDestinationSpec) error {
+	log.Debug("Using in-cluster configuration")
+
+	c.Config = &inClusterConfig{
+		namespace:    destination.Namespace,
+		loadingRules: c.LoadingRules,
+	}
+	c.discoveryClient = defaultDiscoveryClient(c.Config)
+	return nil
+}
+
+func (c *Config) reload() {
+	c.Config = clientcmd.NewInteractiveDeferredLoadingClientConfig(c.LoadingRules, c.Overrides, os.Stdin)
+	c.discoveryClient = defaultDiscoveryClient(c.Config)
+}
+
+const inClusterNamespacePath = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// inClusterConfig is a clientcmd.ClientConfig which uses the service account
+// of the pod it is running in.
+type inClusterConfig struct {
+	namespace    string
+	loadingRules *clientcmd.ClientConfigLoadingRules
+}
+
+var _ clientcmd.ClientConfig = (*inClusterConfig)(nil)
+
+func (ic *inClusterConfig) RawConfig() (clientcmdapi.Config, error) {
+	return clientcmdapi.Config{}, nil
+}
+
+func (ic *inClusterConfig) ClientConfig() (*rest.Config, error) {
+	return rest.InClusterConfig()
+}
+
+func (ic *inClusterConfig) Namespace() (string, bool, error) {
+	if ic.namespace != "" {
+		return ic.namespace, true, nil
+	}
+
+	data, err := ioutil.ReadFile(inClusterNamespacePath)
+	if err != nil {
+		return "default", false, nil
+	}
+
+	if ns := strings.TrimSpace(string(data)); ns != "" {
+		return ns, false, nil
+	}
+
+	return "default", false, nil
+}
+
+func (ic *inClusterConfig) ConfigAccess() clientcmd.ConfigAccess {
+	return ic.loadingRules
+}
@@ -16,10 +16,13 @@
 package client
 
 import (
+	"path/filepath"
 	"testing"
 
 	swagger "github.com/emicklei/go-restful-swagger12"
 	"github.com/googleapis/gnostic/OpenAPIv2"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/require"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -80,6 +83,88 @@ func TestConfig_GetAPISpec(t *testing.T) {
 
 }
 
+func TestConfig_overrideCluster_context(t *testing.T) {
+	cases := []struct {
+		name         string
+		destination  *app.EnvironmentDestinationSpec
+		explicitPath string
+		token        string
+		namespace    string
+		isErr        bool
+	}{
+		{
+			name: "context from the destination's kubeconfig",
+			destination: &app.EnvironmentDestinationSpec{
+				Server:     "https://prod.example.com",
+				Context:    "prod-admin",
+				Kubeconfig: filepath.Join("testdata", "kubeconfig"),
+			},
+			token:     "admin-token",
+			namespace: "kube-system",
+		},
+		{
+			name: "destination namespace",
+			destination: &app.EnvironmentDestinationSpec{
+				Namespace: "prod",
+				Context:   "prod-admin",
+			},
+			explicitPath: filepath.Join("testdata", "kubeconfig"),
+			token:        "admin-token",
+			namespace:    "prod",
+		},
+		{
+			name: "missing context",
+			destination: &app.EnvironmentDestinationSpec{
+				Context:    "missing",
+				Kubeconfig: filepath.Join("testdata", "kubeconfig"),
+			},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			a := &amocks.App{}
+			a.On("Environment", "prod").Return(&app.EnvironmentConfig{Destination: tc.destination}, nil)
+
+			loadingRules := clientcmd.ClientConfigLoadingRules{ExplicitPath: tc.explicitPath}
+			c := NewClientConfig(clientcmd.ConfigOverrides{}, loadingRules)
+
+			err := c.overrideCluster(a, "prod")
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			rc, err := c.Config.ClientConfig()
+			require.NoError(t, err)
+			require.Equal(t, "https://prod.example.com", rc.Host)
+			require.Equal(t, tc.token, rc.BearerToken)
+
+			ns, err := c.Namespace()
+			require.NoError(t, err)
+			require.Equal(t, tc.namespace, ns)
+		})
+	}
+}
+
+func TestConfig_overrideCluster_inCluster(t *testing.T) {
+	a := &amocks.App{}
+	destination := &app.EnvironmentDestinationSpec{Namespace: "prod", InCluster: true}
+	a.On("Environment", "prod").Return(&app.EnvironmentConfig{Destination: destination}, nil)
+
+	c := NewDefaultClientConfig()
+	err := c.overrideCluster(a, "prod")
+	require.NoError(t, err)
+
+	require.IsType(t, &inClusterConfig{}, c.Config)
+
+	ns, err := c.Namespace()
+	require.NoError(t, err)
+	require.Equal(t, "prod", ns)
+}
+
 type clientConfig struct {
 }
 
@@ -17,7 +17,6 @@ package cluster
 
 import (
 	"fmt"
-	"sort"
 	"time"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
@@ -77,6 +76,8 @@ type Apply struct {
 	ksonnetObjectFactory  func() ksonnetObject
 	upserterFactory       func() Upserter
 	conflictTimeout       time.Duration
+	dependencyTimeout     time.Duration
+	dependencyInterval    time.Duration
 }
 
 // RunApply runs apply against a cluster given a configuration.
@@ -94,7 +95,9 @@ func RunApply(config ApplyConfig, opts ...ApplyOpts) error {
 			factory := cmdutil.NewFactory(config.ClientConfig.Config)
 			return newDefaultKsonnetObject(factory)
 		},
-		conflictTimeout: 1 * time.Second,
+		conflictTimeout:    1 * time.Second,
+		dependencyTimeout:  defaultDependencyTimeout,
+		dependencyInterval: dependencyPollInterval,
 	}
 
 	for _, opt := range opts {
@@ -130,23 +133,32 @@ func (a *Apply) Apply() error {
 		return errors.Wrap(err, "find objects")
 	}
 
-	sort.Sort(utils.DependencyOrder(apiObjects))
+	stages, err := dependencyStages(apiObjects)
+	if err != nil {
+		return err
+	}
 
 	seenUids := sets.NewString()
 
-	for _, obj := range apiObjects {
-		var uid string
-		uid, err = a.handleObject(obj)
-		if err != nil {
-			return errors.Wrap(err, "handle object")
+	for _, stage := range stages {
+		for _, obj := range stage.objects {
+			var uid string
+			uid, err = a.handleObject(obj)
+			if err != nil {
+				return errors.Wrap(err, "handle object")
+			}
+
+			// Some objects appear under multiple kinds
+			// (eg: Deployment is both extensions/v1beta1
+			// and apps/v1beta1).  UID is the only stable
+			// identifier that links these two views of
+			// the same object.
+			seenUids.Insert(uid)
 		}
 
-		// Some objects appear under multiple kinds
-		// (eg: Deployment is both extensions/v1beta1
-		// and apps/v1beta1).  UID is the only stable
-		// identifier that links these two views of
-		// the same object.
-		seenUids.Insert(uid)
+		if err = a.waitForDependencies(stage.dependedOn); err != nil {
+			return err
+		}
 	}
 
 	if a.GcTag != "" && !a.SkipGc {
@@ -158,6 +170,23 @@ func (a *Apply) Apply() error {
 	return nil
 }
 
+// waitForDependencies waits for objects that other components depend on to
+// become ready.
+func (a *Apply) waitForDependencies(objects []*unstructured.Unstructured) error {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	if a.DryRun {
+		log.Info("waiting for dependencies to become ready", a.dryRunText())
+		return nil
+	}
+
+	log.Info("Waiting for dependencies to become ready")
+	return waitForObjects(*a.clientOpts, a.resourceClientFactory, objects,
+		a.dependencyInterval, a.dependencyTimeout, objectReady)
+}
+
 func (a *Apply) handleObject(obj *unstructured.Unstructured) (string, error) {
 	if err := a.preprocessObject(obj); err != nil {
 		return "", errors.Wrap(err, "preprocessing object before apply")
@@ -0,0 +1,263 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"reflect"
+
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// anyValue matches any value of a field.
+var anyValue = &struct{}{}
+
+// anyValueExcept matches any value of a field but one.
+type anyValueExcept struct {
+	value interface{}
+}
+
+type fieldDefault struct {
+	path  []string
+	value interface{}
+}
+
+var (
+	// serverFields are populated by the API server.
+	serverFields = [][]string{
+		{"status"},
+		{"metadata", "creationTimestamp"},
+		{"metadata", "deletionGracePeriodSeconds"},
+		{"metadata", "deletionTimestamp"},
+		{"metadata", "generation"},
+		{"metadata", "managedFields"},
+		{"metadata", "namespace"},
+		{"metadata", "ownerReferences"},
+		{"metadata", "resourceVersion"},
+		{"metadata", "selfLink"},
+		{"metadata", "uid"},
+	}
+
+	// serverAnnotations are annotations added by Kubernetes, kubectl and ksonnet.
+	serverAnnotations = []string{
+		"kubectl.kubernetes.io/last-applied-configuration",
+		"deployment.kubernetes.io/revision",
+		metadata.AnnotationGcTag,
+		metadata.AnnotationManaged,
+	}
+
+	// serverLabels are labels added by ksonnet.
+	serverLabels = []string{
+		metadata.LabelComponent,
+		metadata.LabelDeployManager,
+	}
+
+	podSpecDefaults = []fieldDefault{
+		{path: []string{"dnsPolicy"}, value: "ClusterFirst"},
+		{path: []string{"restartPolicy"}, value: "Always"},
+		{path: []string{"schedulerName"}, value: "default-scheduler"},
+		{path: []string{"securityContext"}, value: map[string]interface{}{}},
+		{path: []string{"terminationGracePeriodSeconds"}, value: 30},
+		{path: []string{"serviceAccount"}, value: anyValue},
+	}
+
+	containerDefaults = []fieldDefault{
+		{path: []string{"imagePullPolicy"}, value: "IfNotPresent"},
+		{path: []string{"ports", "*", "protocol"}, value: "TCP"},
+		{path: []string{"resources"}, value: map[string]interface{}{}},
+		{path: []string{"terminationMessagePath"}, value: "/dev/termination-log"},
+		{path: []string{"terminationMessagePolicy"}, value: "File"},
+	}
+
+	// kindDefaults are fields defaulted by the API server, by kind.
+	kindDefaults = map[string][]fieldDefault{
+		"Service": {
+			// Headless services set their cluster IP to None.
+			{path: []string{"spec", "clusterIP"}, value: anyValueExcept{"None"}},
+			{path: []string{"spec", "clusterIPs"}, value: anyValueExcept{[]interface{}{"None"}}},
+			{path: []string{"spec", "internalTrafficPolicy"}, value: "Cluster"},
+			{path: []string{"spec", "ipFamilies"}, value: anyValue},
+			{path: []string{"spec", "ipFamilyPolicy"}, value: "SingleStack"},
+			{path: []string{"spec", "ports", "*", "protocol"}, value: "TCP"},
+			{path: []string{"spec", "sessionAffinity"}, value: "None"},
+			{path: []string{"spec", "type"}, value: "ClusterIP"},
+		},
+		"Deployment": {
+			{path: []string{"spec", "progressDeadlineSeconds"}, value: 600},
+			{path: []string{"spec", "revisionHistoryLimit"}, value: 10},
+			{path: []string{"spec", "strategy"}, value: map[string]interface{}{
+				"type": "RollingUpdate",
+				"rollingUpdate": map[string]interface{}{
+					"maxSurge":       "25%",
+					"maxUnavailable": "25%",
+				},
+			}},
+		},
+		"StatefulSet": {
+			{path: []string{"spec", "podManagementPolicy"}, value: "OrderedReady"},
+			{path: []string{"spec", "revisionHistoryLimit"}, value: 10},
+			{path: []string{"spec", "updateStrategy"}, value: map[string]interface{}{
+				"type": "RollingUpdate",
+				"rollingUpdate": map[string]interface{}{
+					"partition": 0,
+				},
+			}},
+		},
+		"DaemonSet": {
+			{path: []string{"spec", "revisionHistoryLimit"}, value: 10},
+		},
+	}
+
+	// podSpecPaths are the locations of pod specs, by kind.
+	podSpecPaths = map[string][]string{
+		"Pod":         {"spec"},
+		"Deployment":  {"spec", "template", "spec"},
+		"StatefulSet": {"spec", "template", "spec"},
+		"DaemonSet":   {"spec", "template", "spec"},
+		"ReplicaSet":  {"spec", "template", "spec"},
+		"Job":         {"spec", "template", "spec"},
+		"CronJob":     {"spec", "jobTemplate", "spec", "template", "spec"},
+	}
+)
+
+// CleanObject returns a copy of a live object without the fields populated
+// by the API server: its status, server managed metadata, annotations added
+// by tools and values which were defaulted.
+func CleanObject(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	clean := obj.DeepCopy()
+	m := clean.Object
+
+	for _, path := range serverFields {
+		unstructured.RemoveNestedField(m, path...)
+	}
+
+	for _, a := range serverAnnotations {
+		unstructured.RemoveNestedField(m, "metadata", "annotations", a)
+	}
+	for _, l := range serverLabels {
+		unstructured.RemoveNestedField(m, "metadata", "labels", l)
+	}
+
+	kind := clean.GetKind()
+	for _, d := range kindDefaults[kind] {
+		removeDefault(m, d.path, d.value)
+	}
+
+	if podSpec, ok := podSpecPaths[kind]; ok {
+		if len(podSpec) > 1 {
+			// Pod templates have a null creationTimestamp.
+			template := podSpec[:len(podSpec)-1]
+			unstructured.RemoveNestedField(m, append(append([]string{}, template...), "metadata", "creationTimestamp")...)
+		}
+
+		for _, d := range podSpecDefaults {
+			removeDefault(m, append(append([]string{}, podSpec...), d.path...), d.value)
+		}
+
+		for _, containers := range []string{"containers", "initContainers"} {
+			for _, d := range containerDefaults {
+				path := append(append([]string{}, podSpec...), containers, "*")
+				removeDefault(m, append(path, d.path...), d.value)
+			}
+		}
+	}
+
+	for _, field := range []string{"annotations", "labels"} {
+		if v, ok, err := unstructured.NestedMap(m, "metadata", field); err == nil && ok && len(v) == 0 {
+			unstructured.RemoveNestedField(m, "metadata", field)
+		}
+	}
+
+	return clean
+}
+
+// removeDefault removes a field if it has its default value. A "*" in the
+// path matches every item of a list.
+func removeDefault(obj interface{}, path []string, value interface{}) {
+	m, ok := obj.(map[string]interface{})
+	if !ok || len(path) == 0 {
+		return
+	}
+
+	key := path[0]
+	if len(path) == 1 {
+		if v, ok := m[key]; ok && isDefault(v, value) {
+			delete(m, key)
+		}
+		return
+	}
+
+	if path[1] == "*" {
+		list, ok := m[key].([]interface{})
+		if !ok {
+			return
+		}
+		for _, item := range list {
+			removeDefault(item, path[2:], value)
+		}
+		return
+	}
+
+	removeDefault(m[key], path[1:], value)
+}
+
+// isDefault returns true if v matches the default value of a field.
+func isDefault(v, value interface{}) bool {
+	if except, ok := value.(anyValueExcept); ok {
+		return !equalValues(v, except.value)
+	}
+
+	return value == anyValue || equalValues(v, value)
+}
+
+// equalValues compares values decoded from JSON, ignoring the type of numbers.
+func equalValues(a, b interface{}) bool {
+	if fa, ok := toFloat(a); ok {
+		fb, ok := toFloat(b)
+		return ok && fa == fb
+	}
+
+	am, aok := a.(map[string]interface{})
+	bm, bok := b.(map[string]interface{})
+	if aok && bok {
+		if len(am) != len(bm) {
+			return false
+		}
+		for k, v := range am {
+			if !equalValues(v, bm[k]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func toFloat(v interface{}) (float64, bool) {
+	switch t := v.(type) {
+	case int:
+		return float64(t), true
+	case int32:
+		return float64(t), true
+	case int64:
+		return float64(t), true
+	case float64:
+		return t, true
+	}
+
+	return 0, false
+}
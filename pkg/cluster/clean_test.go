@@ -0,0 +1,105 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCleanObject(t *testing.T) {
+	cases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name:     "deployment",
+			src:      "deployment.json",
+			expected: "deployment-clean.json",
+		},
+		{
+			name:     "service",
+			src:      "service.json",
+			expected: "service-clean.json",
+		},
+		{
+			name:     "headless service",
+			src:      "headless-service.json",
+			expected: "headless-service-clean.json",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			obj := readCleanObject(t, tc.src)
+			expected := readCleanObject(t, tc.expected)
+
+			got := CleanObject(obj)
+			require.Equal(t, expected.Object, got.Object)
+
+			// the source object is not modified
+			assert.Equal(t, readCleanObject(t, tc.src).Object, obj.Object)
+		})
+	}
+}
+
+func TestCleanObject_no_metadata(t *testing.T) {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+	}}
+
+	got := CleanObject(obj)
+	assert.Equal(t, obj.Object, got.Object)
+}
+
+func readCleanObject(t *testing.T, name string) *unstructured.Unstructured {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "clean", name))
+	require.NoError(t, err)
+
+	obj := &unstructured.Unstructured{}
+	require.NoError(t, obj.UnmarshalJSON(data))
+
+	return obj
+}
+
+func Test_removeDefault(t *testing.T) {
+	obj := map[string]interface{}{
+		"replicas": int64(10),
+		"items": []interface{}{
+			map[string]interface{}{"protocol": "TCP"},
+			map[string]interface{}{"protocol": "UDP"},
+		},
+	}
+
+	removeDefault(obj, []string{"replicas"}, 10)
+	removeDefault(obj, []string{"items", "*", "protocol"}, "TCP")
+	removeDefault(obj, []string{"missing", "field"}, "TCP")
+
+	expected := map[string]interface{}{
+		"items": []interface{}{
+			map[string]interface{}{},
+			map[string]interface{}{"protocol": "UDP"},
+		},
+	}
+	require.Equal(t, expected, obj)
+}
@@ -18,6 +18,7 @@ package cluster
 import (
 	"fmt"
 	"sort"
+	"time"
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/client"
@@ -49,6 +50,8 @@ type Delete struct {
 	genClientOptsFn       genClientOptsFn
 	objectInfo            ObjectInfo
 	resourceClientFactory resourceClientFactoryFn
+	dependencyTimeout     time.Duration
+	dependencyInterval    time.Duration
 }
 
 // RunDelete runs delete against a cluster for a given configuration.
@@ -59,6 +62,8 @@ func RunDelete(config DeleteConfig, opts ...DeleteOpts) error {
 		genClientOptsFn:       GenClients,
 		resourceClientFactory: resourceClientFactory,
 		objectInfo:            &objectInfo{},
+		dependencyTimeout:     defaultDependencyTimeout,
+		dependencyInterval:    dependencyPollInterval,
 	}
 
 	for _, opt := range opts {
@@ -84,7 +89,11 @@ func (d *Delete) Delete() error {
 	if err != nil {
 		return err
 	}
-	sort.Sort(sort.Reverse(utils.DependencyOrder(apiObjects)))
+
+	stages, err := dependencyStages(apiObjects)
+	if err != nil {
+		return err
+	}
 
 	deleteOpts := metav1.DeleteOptions{}
 	if version.Compare(1, 6) < 0 {
@@ -100,21 +109,38 @@ func (d *Delete) Delete() error {
 		deleteOpts.GracePeriodSeconds = &d.GracePeriod
 	}
 
-	for _, obj := range apiObjects {
-		desc := fmt.Sprintf("%s %s", d.objectInfo.ResourceName(co.discovery, obj), utils.FqName(obj))
-		log.Info("Deleting ", desc)
+	// Components are deleted in the reverse order they are applied in. Objects
+	// are deleted before the objects of the components they depend on.
+	for i := len(stages) - 1; i >= 0; i-- {
+		objects := stages[i].objects
+		sort.Sort(sort.Reverse(utils.DependencyOrder(objects)))
 
-		client, err := d.resourceClientFactory(co, obj)
-		if err != nil {
-			return err
+		for _, obj := range objects {
+			desc := fmt.Sprintf("%s %s", d.objectInfo.ResourceName(co.discovery, obj), utils.FqName(obj))
+			log.Info("Deleting ", desc)
+
+			client, err := d.resourceClientFactory(co, obj)
+			if err != nil {
+				return err
+			}
+
+			err = client.Delete(&deleteOpts)
+			if err != nil && !kerrors.IsNotFound(err) {
+				return fmt.Errorf("Error deleting %s: %s", desc, err)
+			}
+
+			log.Debugf("Deleted object: ", obj)
 		}
 
-		err = client.Delete(&deleteOpts)
-		if err != nil && !kerrors.IsNotFound(err) {
-			return fmt.Errorf("Error deleting %s: %s", desc, err)
+		if i == 0 {
+			continue
 		}
 
-		log.Debugf("Deleted object: ", obj)
+		log.Info("Waiting for dependent objects to be deleted")
+		err = waitForObjects(co, d.resourceClientFactory, objects, d.dependencyInterval, d.dependencyTimeout, objectDeleted)
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -0,0 +1,239 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/utils"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	// defaultDependencyTimeout sets how long to wait for the objects of a
+	// component to become ready, or to be deleted.
+	defaultDependencyTimeout = 5 * time.Minute
+
+	// dependencyPollInterval sets how often objects are checked while waiting.
+	dependencyPollInterval = 2 * time.Second
+)
+
+// dependencyStage is a set of objects whose components only depend on the
+// components of earlier stages.
+type dependencyStage struct {
+	// objects are the objects of the stage, sorted by kind.
+	objects []*unstructured.Unstructured
+	// dependedOn are the objects of the stage which later stages depend on.
+	dependedOn []*unstructured.Unstructured
+}
+
+// dependencyStages splits objects into stages in the order of the dependencies
+// between their components. Without dependencies there is a single stage.
+func dependencyStages(objects []*unstructured.Unstructured) ([]dependencyStage, error) {
+	groups, err := component.GroupByDependencies(objects, false)
+	if err != nil {
+		return nil, err
+	}
+
+	dependedOn := make(map[string]bool)
+	for _, g := range groups {
+		for _, dep := range g.DependsOn {
+			dependedOn[dep] = true
+		}
+	}
+
+	// groups are ordered so dependencies come first.
+	levels := make(map[string]int)
+	var stages []dependencyStage
+	for _, g := range groups {
+		level := 0
+		for _, dep := range g.DependsOn {
+			if l, ok := levels[dep]; ok && l+1 > level {
+				level = l + 1
+			}
+		}
+		levels[g.Component] = level
+
+		for len(stages) <= level {
+			stages = append(stages, dependencyStage{})
+		}
+
+		stages[level].objects = append(stages[level].objects, g.Objects...)
+		if dependedOn[g.Component] {
+			stages[level].dependedOn = append(stages[level].dependedOn, g.Objects...)
+		}
+	}
+
+	for _, stage := range stages {
+		sort.Stable(utils.DependencyOrder(stage.objects))
+	}
+
+	return stages, nil
+}
+
+// waitForObjects polls objects in the cluster until done reports true for all
+// of them, or the timeout expires.
+func waitForObjects(co Clients, rcFactory resourceClientFactoryFn, objects []*unstructured.Unstructured,
+	interval, timeout time.Duration, done func(*unstructured.Unstructured, error) (bool, error)) error {
+	for _, obj := range objects {
+		rc, err := rcFactory(co, obj)
+		if err != nil {
+			return err
+		}
+
+		err = wait.PollImmediate(interval, timeout, func() (bool, error) {
+			return done(rc.Get(metav1.GetOptions{}))
+		})
+		if err == wait.ErrWaitTimeout {
+			return errors.Errorf("timed out waiting for %s", describeObject(obj))
+		}
+		if err != nil {
+			return errors.Wrapf(err, "waiting for %s", describeObject(obj))
+		}
+	}
+
+	return nil
+}
+
+// objectReady is a done function for waitForObjects which reports whether
+// objects are ready.
+func objectReady(obj *unstructured.Unstructured, err error) (bool, error) {
+	if kerrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	ready, err := isReady(obj)
+	if err != nil {
+		return false, err
+	}
+
+	if !ready {
+		log.Debugf("%s is not ready", describeObject(obj))
+	}
+	return ready, nil
+}
+
+// objectDeleted is a done function for waitForObjects which reports whether
+// objects have been deleted.
+func objectDeleted(_ *unstructured.Unstructured, err error) (bool, error) {
+	if kerrors.IsNotFound(err) {
+		return true, nil
+	}
+
+	return false, err
+}
+
+// isReady reports whether an object can be used by the objects depending on
+// it. Jobs have to complete, and workloads have to have all their replicas
+// updated and available. Other objects are ready once they exist.
+func isReady(obj *unstructured.Unstructured) (bool, error) {
+	switch obj.GetKind() {
+	case "Job":
+		if hasCondition(obj, "Failed") {
+			return false, errors.Errorf("job %s failed", obj.GetName())
+		}
+		return hasCondition(obj, "Complete"), nil
+	case "Deployment", "ReplicaSet", "ReplicationController":
+		if !observedGeneration(obj) {
+			return false, nil
+		}
+		replicas := specReplicas(obj)
+		return nestedInt(obj, "status", "updatedReplicas") >= replicas &&
+			nestedInt(obj, "status", "availableReplicas") >= replicas, nil
+	case "StatefulSet":
+		if !observedGeneration(obj) {
+			return false, nil
+		}
+		return nestedInt(obj, "status", "readyReplicas") >= specReplicas(obj), nil
+	case "DaemonSet":
+		if !observedGeneration(obj) {
+			return false, nil
+		}
+		desired := nestedInt(obj, "status", "desiredNumberScheduled")
+		return nestedInt(obj, "status", "updatedNumberScheduled") >= desired &&
+			nestedInt(obj, "status", "numberAvailable") >= desired, nil
+	case "Pod":
+		phase, _, _ := unstructured.NestedString(obj.Object, "status", "phase")
+		if phase == "Failed" {
+			return false, errors.Errorf("pod %s failed", obj.GetName())
+		}
+		return phase == "Succeeded" || hasCondition(obj, "Ready"), nil
+	default:
+		return true, nil
+	}
+}
+
+// observedGeneration reports whether the status of an object describes its
+// current spec.
+func observedGeneration(obj *unstructured.Unstructured) bool {
+	return nestedInt(obj, "status", "observedGeneration") >= obj.GetGeneration()
+}
+
+// specReplicas returns the desired number of replicas of an object. It
+// defaults to 1.
+func specReplicas(obj *unstructured.Unstructured) int64 {
+	if _, ok, _ := unstructured.NestedFieldCopy(obj.Object, "spec", "replicas"); !ok {
+		return 1
+	}
+
+	return nestedInt(obj, "spec", "replicas")
+}
+
+// hasCondition reports whether an object has a true status condition.
+func hasCondition(obj *unstructured.Unstructured, conditionType string) bool {
+	conditions, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	for _, item := range conditions {
+		condition, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		if condition["type"] == conditionType && condition["status"] == "True" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// nestedInt returns an integer field of an object, which may have been decoded
+// as an int64 or a float64.
+func nestedInt(obj *unstructured.Unstructured, fields ...string) int64 {
+	v, _, _ := unstructured.NestedFieldCopy(obj.Object, fields...)
+	switch t := v.(type) {
+	case int64:
+		return t
+	case float64:
+		return int64(t)
+	default:
+		return 0
+	}
+}
+
+func describeObject(obj *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s %s", obj.GetKind(), utils.FqName(obj))
+}
@@ -0,0 +1,345 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster/mocks"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func componentObject(componentName, kind, name string, dependsOn ...string) *unstructured.Unstructured {
+	obj := map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       kind,
+		"metadata": map[string]interface{}{
+			"name": name,
+			"labels": map[string]interface{}{
+				metadata.LabelComponent: componentName,
+			},
+		},
+	}
+	component.SetDependsOn(obj, dependsOn)
+
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func objectNames(objects []*unstructured.Unstructured) []string {
+	var names []string
+	for _, obj := range objects {
+		names = append(names, obj.GetName())
+	}
+
+	return names
+}
+
+func Test_dependencyStages(t *testing.T) {
+	cases := []struct {
+		name               string
+		objects            []*unstructured.Unstructured
+		expected           [][]string
+		expectedDependedOn [][]string
+		isErr              bool
+	}{
+		{
+			name: "without dependencies",
+			objects: []*unstructured.Unstructured{
+				componentObject("app", "Deployment", "app"),
+				componentObject("ns", "Namespace", "ns"),
+			},
+			expected:           [][]string{{"ns", "app"}},
+			expectedDependedOn: [][]string{nil},
+		},
+		{
+			name: "with dependencies",
+			objects: []*unstructured.Unstructured{
+				componentObject("app", "Deployment", "app", "migrate"),
+				componentObject("app", "Service", "app-svc", "migrate"),
+				componentObject("migrate", "Job", "migrate", "db"),
+				componentObject("db", "StatefulSet", "db"),
+				componentObject("ns", "Namespace", "ns"),
+			},
+			expected:           [][]string{{"ns", "db"}, {"migrate"}, {"app", "app-svc"}},
+			expectedDependedOn: [][]string{{"db"}, {"migrate"}, nil},
+		},
+		{
+			name: "cycle",
+			objects: []*unstructured.Unstructured{
+				componentObject("a", "Deployment", "a", "b"),
+				componentObject("b", "Deployment", "b", "a"),
+			},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stages, err := dependencyStages(tc.objects)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			var got, gotDependedOn [][]string
+			for _, stage := range stages {
+				got = append(got, objectNames(stage.objects))
+				gotDependedOn = append(gotDependedOn, objectNames(stage.dependedOn))
+			}
+
+			assert.Equal(t, tc.expected, got)
+			assert.Equal(t, tc.expectedDependedOn, gotDependedOn)
+		})
+	}
+}
+
+func Test_isReady(t *testing.T) {
+	cases := []struct {
+		name     string
+		object   map[string]interface{}
+		expected bool
+		isErr    bool
+	}{
+		{
+			name:     "config map",
+			object:   map[string]interface{}{"kind": "ConfigMap"},
+			expected: true,
+		},
+		{
+			name: "complete job",
+			object: map[string]interface{}{
+				"kind": "Job",
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Complete", "status": "True"},
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name:   "running job",
+			object: map[string]interface{}{"kind": "Job", "status": map[string]interface{}{"active": int64(1)}},
+		},
+		{
+			name: "failed job",
+			object: map[string]interface{}{
+				"kind": "Job",
+				"status": map[string]interface{}{
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Failed", "status": "True"},
+					},
+				},
+			},
+			isErr: true,
+		},
+		{
+			name: "available deployment",
+			object: map[string]interface{}{
+				"kind":     "Deployment",
+				"metadata": map[string]interface{}{"generation": int64(2)},
+				"spec":     map[string]interface{}{"replicas": int64(2)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(2),
+					"updatedReplicas":    int64(2),
+					"availableReplicas":  int64(2),
+				},
+			},
+			expected: true,
+		},
+		{
+			name: "deployment rolling out",
+			object: map[string]interface{}{
+				"kind":     "Deployment",
+				"metadata": map[string]interface{}{"generation": int64(2)},
+				"spec":     map[string]interface{}{"replicas": int64(2)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(2),
+					"updatedReplicas":    int64(1),
+					"availableReplicas":  int64(2),
+				},
+			},
+		},
+		{
+			name: "deployment not observed",
+			object: map[string]interface{}{
+				"kind":     "Deployment",
+				"metadata": map[string]interface{}{"generation": int64(3)},
+				"status": map[string]interface{}{
+					"observedGeneration": int64(2),
+					"updatedReplicas":    int64(1),
+					"availableReplicas":  int64(1),
+				},
+			},
+		},
+		{
+			name: "ready stateful set",
+			object: map[string]interface{}{
+				"kind":   "StatefulSet",
+				"spec":   map[string]interface{}{"replicas": float64(3)},
+				"status": map[string]interface{}{"readyReplicas": float64(3)},
+			},
+			expected: true,
+		},
+		{
+			name: "daemon set",
+			object: map[string]interface{}{
+				"kind": "DaemonSet",
+				"status": map[string]interface{}{
+					"desiredNumberScheduled": int64(3),
+					"updatedNumberScheduled": int64(3),
+					"numberAvailable":        int64(2),
+				},
+			},
+		},
+		{
+			name: "ready pod",
+			object: map[string]interface{}{
+				"kind": "Pod",
+				"status": map[string]interface{}{
+					"phase": "Running",
+					"conditions": []interface{}{
+						map[string]interface{}{"type": "Ready", "status": "True"},
+					},
+				},
+			},
+			expected: true,
+		},
+		{
+			name:   "failed pod",
+			object: map[string]interface{}{"kind": "Pod", "status": map[string]interface{}{"phase": "Failed"}},
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ready, err := isReady(&unstructured.Unstructured{Object: tc.object})
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, ready)
+		})
+	}
+}
+
+type recordingUpserter struct {
+	upserted []string
+}
+
+func (u *recordingUpserter) Upsert(obj *unstructured.Unstructured) (string, error) {
+	u.upserted = append(u.upserted, obj.GetName())
+	return obj.GetName(), nil
+}
+
+type passthroughKsonnetObject struct{}
+
+func (ko *passthroughKsonnetObject) MergeFromCluster(co Clients, obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	return obj, nil
+}
+
+func Test_Apply_waits_for_dependencies(t *testing.T) {
+	test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+		applyConfig := ApplyConfig{
+			App:          a,
+			ClientConfig: &client.Config{},
+		}
+
+		u := &recordingUpserter{}
+		var polls int
+
+		setupApp := func(apply *Apply) {
+			apply.clientOpts = &Clients{}
+
+			apply.findObjectsFn = func(a app.App, envName string, componentNames []string) ([]*unstructured.Unstructured, error) {
+				return []*unstructured.Unstructured{
+					componentObject("app", "Deployment", "app", "migrate"),
+					componentObject("migrate", "Job", "migrate"),
+				}, nil
+			}
+
+			apply.resourceClientFactory = func(opts Clients, object runtime.Object) (ResourceClient, error) {
+				obj := object.(*unstructured.Unstructured)
+				require.Equal(t, "migrate", obj.GetName())
+
+				// the dependent deployment isn't applied before the job completes.
+				assert.Equal(t, []string{"migrate"}, u.upserted)
+
+				live := obj.DeepCopy()
+				rc := &mocks.ResourceClient{}
+				rc.On("Get", mock.Anything).Return(func(metav1.GetOptions) *unstructured.Unstructured {
+					// the job completes on the second poll.
+					polls++
+					if polls > 1 {
+						unstructured.SetNestedSlice(live.Object, []interface{}{
+							map[string]interface{}{"type": "Complete", "status": "True"},
+						}, "status", "conditions")
+					}
+					return live
+				}, nil)
+				return rc, nil
+			}
+
+			apply.ksonnetObjectFactory = func() ksonnetObject {
+				return &passthroughKsonnetObject{}
+			}
+
+			apply.upserterFactory = func() Upserter {
+				return u
+			}
+
+			apply.dependencyInterval = time.Millisecond
+			apply.dependencyTimeout = time.Second
+		}
+
+		err := RunApply(applyConfig, setupApp)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"migrate", "app"}, u.upserted)
+		assert.Equal(t, 2, polls)
+	})
+}
+
+func Test_waitForObjects_timeout(t *testing.T) {
+	obj := componentObject("migrate", "Job", "migrate")
+
+	rcFactory := func(opts Clients, object runtime.Object) (ResourceClient, error) {
+		rc := &mocks.ResourceClient{}
+		rc.On("Get", mock.Anything).Return(obj, nil)
+		return rc, nil
+	}
+
+	err := waitForObjects(Clients{}, rcFactory, []*unstructured.Unstructured{obj},
+		time.Millisecond, 10*time.Millisecond, objectReady)
+	require.EqualError(t, err, "timed out waiting for Job migrate")
+}
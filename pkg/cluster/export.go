@@ -0,0 +1,313 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var (
+	// exportSkippedKinds are kinds which are maintained by Kubernetes. They
+	// are only exported if they are requested explicitly.
+	exportSkippedKinds = map[string]bool{
+		"ControllerRevision": true,
+		"EndpointSlice":      true,
+		"Endpoints":          true,
+		"Event":              true,
+		"Lease":              true,
+		"PodMetrics":         true,
+	}
+)
+
+type listObjectsFn func(co Clients, listOpts metav1.ListOptions, kinds []string) ([]*unstructured.Unstructured, error)
+
+// ExportConfig is configuration for Export.
+type ExportConfig struct {
+	App          app.App
+	ClientConfig *client.Config
+	EnvName      string
+	// Selector is a label selector for the objects to export.
+	Selector string
+	// Kinds limits the export to objects of these kinds. Kinds are matched
+	// case insensitively against the kind, resource and short names.
+	Kinds []string
+}
+
+// ExportOpts is an option for configuring Export.
+type ExportOpts func(*Export)
+
+// Export exports objects from a cluster.
+type Export struct {
+	ExportConfig
+
+	genClientOptsFn genClientOptsFn
+	listObjectsFn   listObjectsFn
+}
+
+// RunExport lists the objects in the namespace of an environment. Objects
+// which are created by controllers or by Kubernetes itself are skipped.
+func RunExport(config ExportConfig, opts ...ExportOpts) ([]*unstructured.Unstructured, error) {
+	e := &Export{
+		ExportConfig:    config,
+		genClientOptsFn: GenClients,
+		listObjectsFn:   listNamespacedObjects,
+	}
+
+	for _, opt := range opts {
+		opt(e)
+	}
+
+	return e.Export()
+}
+
+// Export lists the objects to export.
+func (e *Export) Export() ([]*unstructured.Unstructured, error) {
+	co, err := e.genClientOptsFn(e.App, e.ClientConfig, e.EnvName)
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := e.listObjectsFn(co, metav1.ListOptions{LabelSelector: e.Selector}, e.Kinds)
+	if err != nil {
+		return nil, errors.Wrap(err, "listing objects")
+	}
+
+	var out []*unstructured.Unstructured
+	for _, obj := range objects {
+		if hasController(obj) || isSystemObject(obj) {
+			log.Debugf("Skipping %s %s", obj.GetKind(), obj.GetName())
+			continue
+		}
+
+		out = append(out, obj)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		if out[i].GetKind() != out[j].GetKind() {
+			return out[i].GetKind() < out[j].GetKind()
+		}
+		return out[i].GetName() < out[j].GetName()
+	})
+
+	return out, nil
+}
+
+// listNamespacedObjects lists the objects of every namespaced resource in
+// the namespace of the clients, using the preferred version of each resource.
+func listNamespacedObjects(co Clients, listOpts metav1.ListOptions, kinds []string) ([]*unstructured.Unstructured, error) {
+	rsrclists, err := co.discovery.ServerPreferredNamespacedResources()
+	if err != nil {
+		return nil, err
+	}
+
+	var out []*unstructured.Unstructured
+	for _, rsrclist := range rsrclists {
+		gv, err := schema.ParseGroupVersion(rsrclist.GroupVersion)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, rsrc := range rsrclist.APIResources {
+			// Skip subresources.
+			if strings.Contains(rsrc.Name, "/") || !stringListContains(rsrc.Verbs, "list") {
+				continue
+			}
+
+			if len(kinds) > 0 {
+				if !matchesKind(rsrc, kinds) {
+					continue
+				}
+			} else if exportSkippedKinds[rsrc.Kind] {
+				continue
+			}
+
+			gvk := gv.WithKind(rsrc.Kind)
+			client, err := co.clientPool.ClientForGroupVersionKind(gvk)
+			if err != nil {
+				return nil, err
+			}
+
+			rsrc := rsrc
+			log.Debugf("Listing %s", gvk)
+			list, err := client.Resource(&rsrc, co.namespace).List(listOpts)
+			if err != nil {
+				return nil, err
+			}
+
+			err = meta.EachListItem(list, func(o runtime.Object) error {
+				u, ok := o.(*unstructured.Unstructured)
+				if !ok {
+					return errors.Errorf("unexpected object type %T", o)
+				}
+
+				if u.GetKind() == "" {
+					u.SetAPIVersion(gv.String())
+					u.SetKind(rsrc.Kind)
+				}
+
+				out = append(out, u)
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return out, nil
+}
+
+func matchesKind(rsrc metav1.APIResource, kinds []string) bool {
+	names := append([]string{rsrc.Kind, rsrc.Name, rsrc.SingularName}, rsrc.ShortNames...)
+	for _, kind := range kinds {
+		for _, name := range names {
+			if name != "" && strings.EqualFold(kind, name) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func hasController(obj metav1.Object) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.Controller != nil && *ref.Controller {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isSystemObject returns true for objects Kubernetes creates in every
+// namespace.
+func isSystemObject(obj *unstructured.Unstructured) bool {
+	switch obj.GetKind() {
+	case "ServiceAccount":
+		return obj.GetName() == "default"
+	case "ConfigMap":
+		return obj.GetName() == "kube-root-ca.crt"
+	case "Secret":
+		t, _, _ := unstructured.NestedString(obj.Object, "type")
+		return t == "kubernetes.io/service-account-token"
+	}
+
+	return false
+}
+
+// AdoptedObject is a live object which was imported into a component.
+type AdoptedObject struct {
+	Object    *unstructured.Unstructured
+	Component string
+}
+
+// AdoptConfig is configuration for Adopt.
+type AdoptConfig struct {
+	App          app.App
+	ClientConfig *client.Config
+	EnvName      string
+	GcTag        string
+	Objects      []AdoptedObject
+}
+
+// AdoptOpts is an option for configuring Adopt.
+type AdoptOpts func(*Adopt)
+
+// Adopt labels live objects as managed by ksonnet.
+type Adopt struct {
+	AdoptConfig
+
+	genClientOptsFn       genClientOptsFn
+	resourceClientFactory resourceClientFactoryFn
+}
+
+// RunAdopt labels imported live objects as managed by ksonnet, with the
+// component they were imported into. If a garbage collection tag is set,
+// the objects are annotated with it, so `ks apply --gc-tag` collects them
+// once they are removed from the app.
+func RunAdopt(config AdoptConfig, opts ...AdoptOpts) error {
+	a := &Adopt{
+		AdoptConfig:           config,
+		genClientOptsFn:       GenClients,
+		resourceClientFactory: resourceClientFactory,
+	}
+
+	for _, opt := range opts {
+		opt(a)
+	}
+
+	return a.Adopt()
+}
+
+// Adopt labels the objects.
+func (a *Adopt) Adopt() error {
+	co, err := a.genClientOptsFn(a.App, a.ClientConfig, a.EnvName)
+	if err != nil {
+		return err
+	}
+
+	for _, ao := range a.Objects {
+		desc := fmt.Sprintf("%s %s", ao.Object.GetKind(), ao.Object.GetName())
+		log.Info("Labelling ", desc, " as managed by ksonnet")
+
+		patch := map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels": map[string]interface{}{
+					metadata.LabelDeployManager: appKsonnet,
+					metadata.LabelComponent:     ao.Component,
+				},
+			},
+		}
+
+		if a.GcTag != "" {
+			patch["metadata"].(map[string]interface{})["annotations"] = map[string]interface{}{
+				metadata.AnnotationGcTag: a.GcTag,
+			}
+		}
+
+		data, err := json.Marshal(patch)
+		if err != nil {
+			return err
+		}
+
+		rc, err := a.resourceClientFactory(co, ao.Object)
+		if err != nil {
+			return err
+		}
+
+		if _, err = rc.Patch(types.MergePatchType, data); err != nil {
+			return errors.Wrapf(err, "labelling %s", desc)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,147 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/client"
+	"github.com/ksonnet/ksonnet/pkg/cluster/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func exportObject(kind, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion("v1")
+	obj.SetKind(kind)
+	obj.SetName(name)
+	return obj
+}
+
+func TestExport(t *testing.T) {
+	test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+		config := ExportConfig{
+			App:          a,
+			ClientConfig: &client.Config{},
+			EnvName:      "default",
+			Selector:     "app=guestbook",
+			Kinds:        []string{"svc"},
+		}
+
+		controlled := exportObject("Pod", "guestbook-1234")
+		isController := true
+		controlled.SetOwnerReferences([]metav1.OwnerReference{
+			{Kind: "ReplicaSet", Name: "guestbook", Controller: &isController},
+		})
+
+		token := exportObject("Secret", "default-token-abcde")
+		token.Object["type"] = "kubernetes.io/service-account-token"
+
+		objects := []*unstructured.Unstructured{
+			exportObject("Service", "guestbook"),
+			exportObject("Deployment", "guestbook"),
+			exportObject("ConfigMap", "guestbook"),
+			exportObject("ConfigMap", "kube-root-ca.crt"),
+			exportObject("ServiceAccount", "default"),
+			controlled,
+			token,
+		}
+
+		setup := func(e *Export) {
+			e.genClientOptsFn = func(a app.App, c *client.Config, envName string) (Clients, error) {
+				require.Equal(t, "default", envName)
+				return Clients{}, nil
+			}
+
+			e.listObjectsFn = func(co Clients, listOpts metav1.ListOptions, kinds []string) ([]*unstructured.Unstructured, error) {
+				require.Equal(t, "app=guestbook", listOpts.LabelSelector)
+				require.Equal(t, []string{"svc"}, kinds)
+				return objects, nil
+			}
+		}
+
+		got, err := RunExport(config, setup)
+		require.NoError(t, err)
+
+		expected := []*unstructured.Unstructured{
+			exportObject("ConfigMap", "guestbook"),
+			exportObject("Deployment", "guestbook"),
+			exportObject("Service", "guestbook"),
+		}
+		require.Equal(t, expected, got)
+	})
+}
+
+func Test_matchesKind(t *testing.T) {
+	rsrc := metav1.APIResource{
+		Name:         "services",
+		SingularName: "service",
+		Kind:         "Service",
+		ShortNames:   []string{"svc"},
+	}
+
+	for _, kind := range []string{"Service", "service", "services", "svc", "SVC"} {
+		require.True(t, matchesKind(rsrc, []string{"deployment", kind}), kind)
+	}
+
+	require.False(t, matchesKind(rsrc, []string{"deployment"}))
+}
+
+func TestAdopt(t *testing.T) {
+	test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+		obj := exportObject("Service", "guestbook")
+
+		config := AdoptConfig{
+			App:          a,
+			ClientConfig: &client.Config{},
+			EnvName:      "default",
+			GcTag:        "tag",
+			Objects: []AdoptedObject{
+				{Object: obj, Component: "guestbook"},
+			},
+		}
+
+		patch := `{"metadata":{"annotations":{"kubecfg.ksonnet.io/garbage-collect-tag":"tag"},` +
+			`"labels":{"app.kubernetes.io/deploy-manager":"ksonnet","ksonnet.io/component":"guestbook"}}}`
+
+		rc := &mocks.ResourceClient{}
+		rc.On("Patch", types.MergePatchType, []byte(patch)).Return(obj, nil)
+
+		setup := func(adopt *Adopt) {
+			adopt.genClientOptsFn = func(a app.App, c *client.Config, envName string) (Clients, error) {
+				return Clients{}, nil
+			}
+
+			adopt.resourceClientFactory = func(opts Clients, object runtime.Object) (ResourceClient, error) {
+				require.Equal(t, obj, object)
+				return rc, nil
+			}
+		}
+
+		err := RunAdopt(config, setup)
+		require.NoError(t, err)
+
+		rc.AssertExpectations(t)
+	})
+}
@@ -0,0 +1,324 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package component
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/params"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+var (
+	reJsonnetID = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+	jsonnetKeywords = map[string]bool{
+		"assert": true, "else": true, "error": true, "false": true, "for": true,
+		"function": true, "if": true, "import": true, "importstr": true, "in": true,
+		"local": true, "null": true, "self": true, "super": true, "tailstrict": true,
+		"then": true, "true": true,
+	}
+)
+
+// paramRef is a reference to a component parameter in generated Jsonnet.
+type paramRef []string
+
+// ConvertToJsonnet converts a YAML component to a Jsonnet component. Fields set by
+// the component's parameters, in its module or in any environment, are replaced by
+// references to `params`. Parameters which are only set in environments are added to
+// the module parameters with the value from the YAML source, so the component renders
+// the same in every environment.
+func ConvertToJsonnet(a app.App, name string) (Component, error) {
+	c, err := ExtractComponent(a, name)
+	if err != nil {
+		return nil, err
+	}
+
+	y, ok := c.(*YAML)
+	if !ok {
+		return nil, errors.Errorf("component %q is not a YAML or JSON component", name)
+	}
+
+	envs, err := a.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	var envNames []string
+	for envName := range envs {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+
+	return y.toJsonnet(envNames)
+}
+
+func (y *YAML) toJsonnet(envNames []string) (*Jsonnet, error) {
+	name := y.Name(false)
+	dest := filepath.Join(filepath.Dir(y.source), name+".jsonnet")
+	if exists, err := afero.Exists(y.app.Fs(), dest); err != nil {
+		return nil, err
+	} else if exists {
+		return nil, errors.Errorf("%s already exists", dest)
+	}
+
+	data, err := y.readJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var doc map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	if err = dec.Decode(&doc); err != nil {
+		return nil, errors.Wrapf(err, "component %q is not an object", name)
+	}
+
+	moduleParamsData, err := y.readModuleParams()
+	if err != nil {
+		return nil, err
+	}
+
+	moduleParams := componentParamsMap(name, moduleParamsData)
+	paramTree := copyParamTree(moduleParams)
+
+	for _, envName := range envNames {
+		envParamsData, err := envParams(y.app, y.module, envName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "evaluating params for environment %q", envName)
+		}
+
+		mergeParamTrees(paramTree, componentParamsMap(name, envParamsData))
+	}
+
+	var refs []paramRef
+	tree := referenceParams(doc, paramTree, nil, &refs).(map[string]interface{})
+
+	for _, ref := range refs {
+		if _, ok := lookupPath(moduleParams, ref); ok {
+			continue
+		}
+
+		v, ok := lookupPath(doc, ref)
+		if !ok {
+			return nil, errors.Errorf("parameter %q of component %q is only set in environments; set a default value with `ks param set`",
+				strings.Join(ref, "."), name)
+		}
+
+		if v, err = plainValue(v); err != nil {
+			return nil, err
+		}
+
+		moduleParamsData, err = params.SetInObject(ref, moduleParamsData, name, v, paramsComponentRoot)
+		if err != nil {
+			return nil, errors.Wrapf(err, "setting default for parameter %q", strings.Join(ref, "."))
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("local env = std.extVar(\"__ksonnet/environments\");\n")
+	fmt.Fprintf(&buf, "local params = std.extVar(\"__ksonnet/params\").components[%s];\n\n", jsonnetString(name))
+	writeJsonnet(&buf, tree, 0)
+	buf.WriteString("\n")
+
+	if err = afero.WriteFile(y.app.Fs(), dest, buf.Bytes(), app.DefaultFilePermissions); err != nil {
+		return nil, err
+	}
+
+	if len(refs) > 0 {
+		if err = y.writeParams(moduleParamsData); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = y.app.Fs().Remove(y.source); err != nil {
+		return nil, errors.Wrapf(err, "removing %q", y.source)
+	}
+
+	return NewJsonnet(y.app, y.module, dest, y.paramsPath), nil
+}
+
+// componentParamsMap returns the params of a component, or an empty map if it has none.
+func componentParamsMap(name, paramsData string) map[string]interface{} {
+	m, err := params.ToMap(name, paramsData, paramsComponentRoot)
+	if err != nil {
+		return make(map[string]interface{})
+	}
+
+	return m
+}
+
+func copyParamTree(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{})
+	mergeParamTrees(out, m)
+	return out
+}
+
+// mergeParamTrees merges the paths of src into dest.
+func mergeParamTrees(dest, src map[string]interface{}) {
+	for k, v := range src {
+		sm, ok := v.(map[string]interface{})
+		if !ok {
+			if _, exists := dest[k]; !exists {
+				dest[k] = v
+			}
+			continue
+		}
+
+		dm, ok := dest[k].(map[string]interface{})
+		if !ok {
+			dm = make(map[string]interface{})
+			dest[k] = dm
+		}
+		mergeParamTrees(dm, sm)
+	}
+}
+
+// referenceParams replaces the values of v which are set by parameters with
+// references to the parameters. Objects in parameters are merged into objects
+// in v, the same way parameters are patched into YAML components.
+func referenceParams(v interface{}, paramTree interface{}, path []string, refs *[]paramRef) interface{} {
+	pm, ok := paramTree.(map[string]interface{})
+	if !ok {
+		ref := paramRef(append([]string{}, path...))
+		*refs = append(*refs, ref)
+		return ref
+	}
+
+	out := make(map[string]interface{})
+	if m, ok := v.(map[string]interface{}); ok {
+		for k, child := range m {
+			out[k] = child
+		}
+	}
+
+	for k, child := range pm {
+		out[k] = referenceParams(out[k], child, append(path, k), refs)
+	}
+
+	return out
+}
+
+// plainValue converts a value decoded with json.Number to a value decoded with float64.
+func plainValue(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	var out interface{}
+	if err = json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func lookupPath(m map[string]interface{}, path []string) (interface{}, bool) {
+	var cur interface{} = m
+	for _, k := range path {
+		cm, ok := cur.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if cur, ok = cm[k]; !ok {
+			return nil, false
+		}
+	}
+
+	return cur, true
+}
+
+// writeJsonnet writes a value as Jsonnet.
+func writeJsonnet(buf *bytes.Buffer, v interface{}, indent int) {
+	pad := strings.Repeat("  ", indent+1)
+
+	switch t := v.(type) {
+	case paramRef:
+		buf.WriteString("params")
+		for _, k := range t {
+			if isJsonnetID(k) {
+				buf.WriteString("." + k)
+			} else {
+				buf.WriteString("[" + jsonnetString(k) + "]")
+			}
+		}
+	case map[string]interface{}:
+		if len(t) == 0 {
+			buf.WriteString("{}")
+			return
+		}
+
+		var keys []string
+		for k := range t {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		buf.WriteString("{\n")
+		for _, k := range keys {
+			buf.WriteString(pad)
+			if isJsonnetID(k) {
+				buf.WriteString(k)
+			} else {
+				buf.WriteString(jsonnetString(k))
+			}
+			buf.WriteString(": ")
+			writeJsonnet(buf, t[k], indent+1)
+			buf.WriteString(",\n")
+		}
+		buf.WriteString(strings.Repeat("  ", indent) + "}")
+	case []interface{}:
+		if len(t) == 0 {
+			buf.WriteString("[]")
+			return
+		}
+
+		buf.WriteString("[\n")
+		for _, item := range t {
+			buf.WriteString(pad)
+			writeJsonnet(buf, item, indent+1)
+			buf.WriteString(",\n")
+		}
+		buf.WriteString(strings.Repeat("  ", indent) + "]")
+	case string:
+		buf.WriteString(jsonnetString(t))
+	default:
+		b, _ := json.Marshal(t)
+		buf.Write(b)
+	}
+}
+
+func isJsonnetID(s string) bool {
+	return reJsonnetID.MatchString(s) && !jsonnetKeywords[s]
+}
+
+func jsonnetString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
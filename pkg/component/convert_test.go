@@ -0,0 +1,65 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package component
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertToJsonnet(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		test.StageFile(t, fs, "convert/params.libsonnet", "/app/components/params.libsonnet")
+		test.StageFile(t, fs, "convert/deployment.yaml", "/app/components/deployment.yaml")
+
+		envs := app.EnvironmentConfigs{
+			"prod": &app.EnvironmentConfig{
+				Name: "prod",
+				Path: "prod",
+				Destination: &app.EnvironmentDestinationSpec{
+					Server:    "http://example.com",
+					Namespace: "default",
+				},
+			},
+		}
+		a.On("Environments").Return(envs, nil)
+		a.On("Environment", "prod").Return(envs["prod"], nil)
+		a.On("EnvironmentParams", "prod").Return(test.ReadTestData(t, "convert/prod-params.libsonnet"), nil)
+
+		c, err := ConvertToJsonnet(a, "deployment")
+		require.NoError(t, err)
+		require.Equal(t, TypeJsonnet, c.Type())
+
+		test.AssertNotExists(t, fs, "/app/components/deployment.yaml")
+		test.AssertContents(t, fs, "convert/deployment.jsonnet", "/app/components/deployment.jsonnet")
+		test.AssertContents(t, fs, "convert/params-converted.libsonnet", "/app/components/params.libsonnet")
+	})
+}
+
+func TestConvertToJsonnet_not_yaml(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		test.StageFile(t, fs, "params-mixed.libsonnet", "/app/components/params.libsonnet")
+		test.StageFile(t, fs, "guestbook/guestbook-ui.jsonnet", "/app/components/guestbook-ui.jsonnet")
+
+		_, err := ConvertToJsonnet(a, "guestbook-ui")
+		require.Error(t, err)
+	})
+}
@@ -126,12 +126,12 @@ func collectEnvParams(a app.App, env *app.EnvironmentConfig, componentName, envN
 	return ecr.Remove(componentName, string(envParamsFile))
 }
 
-// updateEnvParam removes the component references in each environment's
+// updateEnvParam writes the updated component references in each environment's
 // params.libsonnet.
 func updateEnvParam(a app.App, envs app.EnvironmentConfigs, envParams map[string]string) error {
 	for envName := range envs {
 		path := filepath.Join(a.Root(), "environments", envName, "params.libsonnet")
-		log.Debugf("... deleting references in %s", path)
+		log.Debugf("... updating references in %s", path)
 		if err := afero.WriteFile(a.Fs(), path, []byte(envParams[envName]), app.DefaultFilePermissions); err != nil {
 			return errors.Wrapf(err, "writing params for environment %q", envName)
 		}
@@ -0,0 +1,160 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package component
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	ksstrings "github.com/ksonnet/ksonnet/pkg/util/strings"
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// ParamDependsOn is the component parameter which lists the components
+	// a component depends on.
+	ParamDependsOn = "dependsOn"
+)
+
+// ObjectGroup is the objects rendered by a component.
+type ObjectGroup struct {
+	// Component is the name of the component. It is blank for objects which
+	// aren't labeled with a component.
+	Component string
+	// DependsOn is the components the component depends on.
+	DependsOn []string
+	// Objects are the objects rendered by the component.
+	Objects []*unstructured.Unstructured
+}
+
+// GroupByDependencies groups objects by the component they were rendered by,
+// and orders the groups so a component comes after the components it depends
+// on. Dependencies are read from the metadata.AnnotationDependsOn annotation of
+// the objects. If strict is set, a component depending on a component without
+// objects is an error. A dependency cycle is always an error.
+func GroupByDependencies(objects []*unstructured.Unstructured, strict bool) ([]ObjectGroup, error) {
+	groups := make(map[string]*ObjectGroup)
+	for _, obj := range objects {
+		name := obj.GetLabels()[metadata.LabelComponent]
+
+		g, ok := groups[name]
+		if !ok {
+			g = &ObjectGroup{Component: name}
+			groups[name] = g
+		}
+
+		g.Objects = append(g.Objects, obj)
+		for _, dep := range dependsOn(obj) {
+			if !ksstrings.InSlice(dep, g.DependsOn) {
+				g.DependsOn = append(g.DependsOn, dep)
+			}
+		}
+	}
+
+	var names []string
+	for name, g := range groups {
+		names = append(names, name)
+		sort.Strings(g.DependsOn)
+
+		for _, dep := range g.DependsOn {
+			if dep == name {
+				return nil, errors.Errorf("component %q depends on itself", name)
+			}
+
+			if _, ok := groups[dep]; !ok && strict {
+				return nil, errors.Errorf("component %q depends on %q, which is not rendered in the environment", name, dep)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	var ordered []ObjectGroup
+	state := make(map[string]int)
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	var visit func(name string, path []string) error
+	visit = func(name string, path []string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.Errorf("components have a dependency cycle: %s", strings.Join(append(path, name), " -> "))
+		}
+
+		g, ok := groups[name]
+		if !ok {
+			return nil
+		}
+
+		state[name] = visiting
+		for _, dep := range g.DependsOn {
+			if err := visit(dep, append(path, name)); err != nil {
+				return err
+			}
+		}
+		state[name] = visited
+
+		ordered = append(ordered, *g)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+// SetDependsOn records the components a component depends on in an object.
+func SetDependsOn(obj map[string]interface{}, dependencies []string) {
+	if len(dependencies) == 0 {
+		return
+	}
+
+	metadataObject, ok := obj["metadata"].(map[string]interface{})
+	if !ok {
+		metadataObject = make(map[string]interface{})
+		obj["metadata"] = metadataObject
+	}
+
+	annotations, ok := metadataObject["annotations"].(map[string]interface{})
+	if !ok {
+		annotations = make(map[string]interface{})
+		metadataObject["annotations"] = annotations
+	}
+
+	annotations[metadata.AnnotationDependsOn] = strings.Join(dependencies, ",")
+}
+
+// dependsOn returns the components the component of an object depends on.
+func dependsOn(obj *unstructured.Unstructured) []string {
+	var deps []string
+	for _, dep := range strings.Split(obj.GetAnnotations()[metadata.AnnotationDependsOn], ",") {
+		if dep = strings.TrimSpace(dep); dep != "" {
+			deps = append(deps, dep)
+		}
+	}
+
+	return deps
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package component
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestGroupByDependencies(t *testing.T) {
+	object := func(componentName, name, dependsOn string) *unstructured.Unstructured {
+		obj := map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata": map[string]interface{}{
+				"name": name,
+				"labels": map[string]interface{}{
+					metadata.LabelComponent: componentName,
+				},
+			},
+		}
+		if dependsOn != "" {
+			SetDependsOn(obj, []string{dependsOn})
+		}
+
+		return &unstructured.Unstructured{Object: obj}
+	}
+
+	cases := []struct {
+		name     string
+		objects  []*unstructured.Unstructured
+		strict   bool
+		expected []string
+		isErr    bool
+	}{
+		{
+			name: "without dependencies",
+			objects: []*unstructured.Unstructured{
+				object("b", "b", ""),
+				object("a", "a", ""),
+			},
+			expected: []string{"a", "b"},
+		},
+		{
+			name: "with dependencies",
+			objects: []*unstructured.Unstructured{
+				object("app", "app", "db.migrate"),
+				object("db.migrate", "migrate", "db.postgres"),
+				object("db.postgres", "postgres", ""),
+				object("cache", "cache", ""),
+			},
+			strict:   true,
+			expected: []string{"db.postgres", "db.migrate", "app", "cache"},
+		},
+		{
+			name: "dependency not rendered",
+			objects: []*unstructured.Unstructured{
+				object("app", "app", "db"),
+			},
+			expected: []string{"app"},
+		},
+		{
+			name: "dependency not rendered in strict mode",
+			objects: []*unstructured.Unstructured{
+				object("app", "app", "db"),
+			},
+			strict: true,
+			isErr:  true,
+		},
+		{
+			name: "cycle",
+			objects: []*unstructured.Unstructured{
+				object("a", "a", "b"),
+				object("b", "b", "c"),
+				object("c", "c", "a"),
+			},
+			isErr: true,
+		},
+		{
+			name: "depends on itself",
+			objects: []*unstructured.Unstructured{
+				object("a", "a", "a"),
+			},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			groups, err := GroupByDependencies(tc.objects, tc.strict)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+
+			var components []string
+			for _, g := range groups {
+				components = append(components, g.Component)
+			}
+			assert.Equal(t, tc.expected, components)
+		})
+	}
+}
+
+func TestGroupByDependencies_cycle_message(t *testing.T) {
+	objects := []*unstructured.Unstructured{
+		{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels":      map[string]interface{}{metadata.LabelComponent: "a"},
+				"annotations": map[string]interface{}{metadata.AnnotationDependsOn: "b"},
+			},
+		}},
+		{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{
+				"labels":      map[string]interface{}{metadata.LabelComponent: "b"},
+				"annotations": map[string]interface{}{metadata.AnnotationDependsOn: " a, "},
+			},
+		}},
+	}
+
+	_, err := GroupByDependencies(objects, false)
+	require.EqualError(t, err, "components have a dependency cycle: a -> b -> a")
+}
@@ -0,0 +1,290 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package component
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/params"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// Move moves a component to another module, another name, or both. `from` and `to`
+// are namespaced component names, e.g. `module.component`. The component's entries in
+// its module's params.libsonnet, in every environment's params.libsonnet and in the
+// params files of environment destinations are moved with it, references to its params
+// in Jsonnet source are renamed, and environments targeting the old module also target
+// the new one. The new module is created if it does not exist.
+func Move(a app.App, from, to string) (Component, error) {
+	log.Debugf("moving component %s to %s", from, to)
+
+	moduleName, componentName, err := extractPathParts(a, from)
+	if err != nil {
+		return nil, err
+	}
+
+	c, err := LocateComponent(a, moduleName, componentName)
+	if err != nil {
+		return nil, err
+	}
+
+	newModuleName, newComponentName, err := splitMoveTarget(to)
+	if err != nil {
+		return nil, err
+	}
+
+	if moduleName == newModuleName && componentName == newComponentName {
+		return nil, errors.Errorf("component %q is already named %q", from, to)
+	}
+
+	m := NewModule(a, moduleName)
+	newModule := NewModule(a, newModuleName)
+
+	newModuleExists, err := afero.Exists(a.Fs(), newModule.ParamsPath())
+	if err != nil {
+		return nil, err
+	}
+
+	if newModuleExists {
+		var components []Component
+		if components, err = newModule.Components(); err != nil {
+			return nil, err
+		}
+
+		for _, existing := range components {
+			if existing.Name(false) == newComponentName {
+				return nil, errors.Errorf("component %q already exists", to)
+			}
+		}
+	}
+
+	// Build the new component source.
+	source := filepath.Join(m.Dir(), componentName+"."+c.Type())
+	data, err := afero.ReadFile(a.Fs(), source)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.Type() == TypeJsonnet {
+		data = renameParamsReferences(data, componentName, newComponentName)
+	}
+
+	dest := filepath.Join(newModule.Dir(), newComponentName+filepath.Ext(source))
+
+	// Build the new component params.libsonnet files.
+	cm := params.NewComponentMover()
+
+	moduleParams, err := afero.ReadFile(a.Fs(), m.ParamsPath())
+	if err != nil {
+		return nil, err
+	}
+
+	var updatedParams, updatedNewParams string
+	if moduleName == newModuleName {
+		updatedParams, err = cm.Rename(componentName, newComponentName, string(moduleParams))
+		if err != nil {
+			return nil, errors.Wrapf(err, "renaming params in %s", m.ParamsPath())
+		}
+	} else {
+		newModuleParams := GenParamsContent()
+		if newModuleExists {
+			if newModuleParams, err = afero.ReadFile(a.Fs(), newModule.ParamsPath()); err != nil {
+				return nil, err
+			}
+		}
+
+		updatedParams, updatedNewParams, err = cm.Move(componentName, newComponentName,
+			string(moduleParams), string(newModuleParams))
+		if err != nil {
+			return nil, errors.Wrapf(err, "moving params from %s", m.ParamsPath())
+		}
+	}
+
+	// Build the new environment/<env>/params.libsonnet files.
+	// environment name -> jsonnet
+	envs, err := a.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	newName := namespacedName(newModuleName, newComponentName)
+
+	envParams := make(map[string]string)
+	for envName := range envs {
+		path := filepath.Join(a.Root(), "environments", envName, "params.libsonnet")
+		var envParamsFile []byte
+		if envParamsFile, err = afero.ReadFile(a.Fs(), path); err != nil {
+			return nil, err
+		}
+
+		if envParams[envName], err = cm.Rename(c.Name(true), newName, string(envParamsFile)); err != nil {
+			return nil, errors.Wrapf(err, "renaming params for environment %q", envName)
+		}
+	}
+
+	// Build the params files of environment destinations.
+	// path -> jsonnet
+	destParams := make(map[string]string)
+	for _, env := range envs {
+		for _, path := range params.DestinationParamsPaths(a, env) {
+			var destParamsFile []byte
+			if destParamsFile, err = afero.ReadFile(a.Fs(), path); err != nil {
+				return nil, err
+			}
+
+			if destParams[path], err = cm.Rename(c.Name(true), newName, string(destParamsFile)); err != nil {
+				return nil, errors.Wrapf(err, "renaming params in %s", path)
+			}
+		}
+	}
+
+	//
+	// Move the component and its references.
+	//
+	log.Infof("Moving component %q to %q", from, to)
+
+	if !newModuleExists {
+		if err = DefaultManager.CreateModule(a, newModuleName); err != nil {
+			return nil, errors.Wrapf(err, "creating module %q", newModuleName)
+		}
+	}
+
+	if err = afero.WriteFile(a.Fs(), dest, data, defaultFilePermissions); err != nil {
+		return nil, err
+	}
+
+	if err = c.Remove(); err != nil {
+		return nil, err
+	}
+
+	log.Debugf("... moving references in %s", m.ParamsPath())
+	if err = afero.WriteFile(a.Fs(), m.ParamsPath(), []byte(updatedParams), defaultFilePermissions); err != nil {
+		return nil, err
+	}
+
+	if moduleName != newModuleName {
+		log.Debugf("... moving references to %s", newModule.ParamsPath())
+		if err = afero.WriteFile(a.Fs(), newModule.ParamsPath(), []byte(updatedNewParams), defaultFilePermissions); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = updateEnvParam(a, envs, envParams); err != nil {
+		return nil, errors.Wrap(err, "writing environment params")
+	}
+
+	for path, data := range destParams {
+		log.Debugf("... moving references in %s", path)
+		if err = afero.WriteFile(a.Fs(), path, []byte(data), defaultFilePermissions); err != nil {
+			return nil, errors.Wrapf(err, "writing destination params %s", path)
+		}
+	}
+
+	if moduleName != newModuleName {
+		if err = moveTargets(a, envs, m.Name(), newModule.Name()); err != nil {
+			return nil, err
+		}
+	}
+
+	moved, err := LocateComponent(a, newModuleName, newComponentName)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("Successfully moved component %q to %q", from, to)
+	return moved, nil
+}
+
+// splitMoveTarget splits a namespaced component name into a module and
+// a component name.
+func splitMoveTarget(name string) (string, string, error) {
+	if strings.Contains(name, "/") {
+		return "", "", errors.New("component can't contain a /")
+	}
+
+	moduleName, componentName := "/", name
+	if i := strings.LastIndex(name, "."); i >= 0 {
+		moduleName, componentName = name[:i], name[i+1:]
+	}
+
+	if !isValidName(componentName) || !isValidModuleName(strings.TrimPrefix(moduleName, "/")) {
+		return "", "", errors.Errorf("%q is not a valid component name", name)
+	}
+
+	return moduleName, componentName, nil
+}
+
+// namespacedName returns the name of a component in a module.
+func namespacedName(moduleName, componentName string) string {
+	if moduleName == "/" || moduleName == "" {
+		return componentName
+	}
+
+	return moduleName + "." + componentName
+}
+
+// renameParamsReferences renames references to a component's params, e.g.
+// `std.extVar("__ksonnet/params").components.name`, in Jsonnet source.
+func renameParamsReferences(data []byte, from, to string) []byte {
+	re := regexp.MustCompile(`(std\.extVar\(\s*["']__ksonnet/params["']\s*\)\s*\.components)` +
+		`(\.` + regexp.QuoteMeta(from) + `\b|\[\s*"` + regexp.QuoteMeta(from) + `"\s*\]|\[\s*'` + regexp.QuoteMeta(from) + `'\s*\])`)
+
+	ref := fmt.Sprintf("[%q]", to)
+	if reJsonnetID.MatchString(to) && !jsonnetKeywords[to] {
+		ref = "." + to
+	}
+
+	return re.ReplaceAll(data, []byte("${1}"+ref))
+}
+
+// moveTargets adds the new module to the targets of environments targeting the
+// old module.
+func moveTargets(a app.App, envs app.EnvironmentConfigs, from, to string) error {
+	var envNames []string
+	for envName := range envs {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+
+	for _, envName := range envNames {
+		targets := envs[envName].Targets
+
+		var hasOld, hasNew bool
+		for _, target := range targets {
+			hasOld = hasOld || target == from
+			hasNew = hasNew || target == to
+		}
+
+		if !hasOld || hasNew {
+			continue
+		}
+
+		log.Infof("Adding target %q to environment %q", to, envName)
+		updated := append(append([]string{}, targets...), to)
+		if err := a.UpdateTargets(envName, updated, a.IsEnvOverride(envName)); err != nil {
+			return errors.Wrapf(err, "updating targets for environment %q", envName)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,214 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package component
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMove(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		test.StageDir(t, fs, "delete", "/app")
+
+		envs := app.EnvironmentConfigs{
+			"default": &app.EnvironmentConfig{Targets: []string{"/"}},
+		}
+		a.On("Environments").Return(envs, nil)
+		a.On("IsEnvOverride", "default").Return(false)
+		a.On("UpdateTargets", "default", []string{"/", "frontend"}, false).Return(nil)
+
+		c, err := Move(a, "guestbook-ui", "frontend.guestbook")
+		require.NoError(t, err)
+		require.Equal(t, "frontend.guestbook", c.Name(true))
+
+		test.AssertNotExists(t, fs, filepath.Join("/app", "components", "guestbook-ui.jsonnet"))
+		test.AssertContents(
+			t,
+			fs,
+			filepath.Join("move", "guestbook.jsonnet"),
+			filepath.Join("/app", "components", "frontend", "guestbook.jsonnet"),
+		)
+		test.AssertContents(
+			t,
+			fs,
+			filepath.Join("move", "params.libsonnet"),
+			filepath.Join("/app", "components", "params.libsonnet"),
+		)
+		test.AssertContents(
+			t,
+			fs,
+			filepath.Join("move", "frontend-params.libsonnet"),
+			filepath.Join("/app", "components", "frontend", "params.libsonnet"),
+		)
+		test.AssertContents(
+			t,
+			fs,
+			filepath.Join("move", "env-params.libsonnet"),
+			filepath.Join("/app", "environments", "default", "params.libsonnet"),
+		)
+
+		a.AssertCalled(t, "UpdateTargets", "default", []string{"/", "frontend"}, false)
+	})
+}
+
+func TestMove_destination_params(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		test.StageDir(t, fs, "delete", "/app")
+		test.StageFile(t, fs, filepath.Join("delete", "environments", "default", "params.libsonnet"),
+			filepath.Join("/app", "environments", "default", "eu-west.libsonnet"))
+
+		envs := app.EnvironmentConfigs{
+			"default": &app.EnvironmentConfig{
+				Path:    "default",
+				Targets: []string{"/"},
+				Destinations: []*app.EnvironmentDestinationSpec{
+					{Name: "us-east"},
+					{Name: "eu-west", Params: "eu-west.libsonnet"},
+				},
+			},
+		}
+		a.On("Environments").Return(envs, nil)
+		a.On("IsEnvOverride", "default").Return(false)
+		a.On("UpdateTargets", "default", []string{"/", "frontend"}, false).Return(nil)
+
+		_, err := Move(a, "guestbook-ui", "frontend.guestbook")
+		require.NoError(t, err)
+
+		test.AssertContents(
+			t,
+			fs,
+			filepath.Join("move", "env-params.libsonnet"),
+			filepath.Join("/app", "environments", "default", "eu-west.libsonnet"),
+		)
+	})
+}
+
+func TestMoveWithinModule(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		test.StageDir(t, fs, "delete", "/app")
+
+		envs := app.EnvironmentConfigs{
+			"default": &app.EnvironmentConfig{Targets: []string{"nested"}},
+		}
+		a.On("Environments").Return(envs, nil)
+
+		c, err := Move(a, "nested.guestbook-ui", "nested.ui")
+		require.NoError(t, err)
+		require.Equal(t, "nested.ui", c.Name(true))
+
+		base := filepath.Join("/app", "components", "nested")
+
+		test.AssertNotExists(t, fs, filepath.Join(base, "guestbook-ui.jsonnet"))
+		test.AssertContents(
+			t,
+			fs,
+			filepath.Join("move", "ui.jsonnet"),
+			filepath.Join(base, "ui.jsonnet"),
+		)
+		test.AssertContents(
+			t,
+			fs,
+			filepath.Join("move", "nested-params.libsonnet"),
+			filepath.Join(base, "params.libsonnet"),
+		)
+		test.AssertContents(
+			t,
+			fs,
+			filepath.Join("move", "env-params-nested.libsonnet"),
+			filepath.Join("/app", "environments", "default", "params.libsonnet"),
+		)
+
+		a.AssertNotCalled(t, "UpdateTargets")
+	})
+}
+
+func TestMove_invalid(t *testing.T) {
+	cases := []struct {
+		name string
+		from string
+		to   string
+	}{
+		{name: "unknown component", from: "missing", to: "other"},
+		{name: "same name", from: "nested.guestbook-ui", to: "nested.guestbook-ui"},
+		{name: "component exists", from: "guestbook-ui", to: "nested.guestbook-ui"},
+		{name: "invalid name", from: "guestbook-ui", to: "nested."},
+		{name: "path", from: "guestbook-ui", to: "nested/guestbook-ui"},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+				test.StageDir(t, fs, "delete", "/app")
+
+				envs := app.EnvironmentConfigs{
+					"default": &app.EnvironmentConfig{},
+				}
+				a.On("Environments").Return(envs, nil)
+
+				_, err := Move(a, tc.from, tc.to)
+				require.Error(t, err)
+			})
+		})
+	}
+}
+
+func Test_renameParamsReferences(t *testing.T) {
+	cases := []struct {
+		name     string
+		source   string
+		to       string
+		expected string
+	}{
+		{
+			name:     "field",
+			source:   `local params = std.extVar("__ksonnet/params").components.guestbook;`,
+			to:       "frontend",
+			expected: `local params = std.extVar("__ksonnet/params").components.frontend;`,
+		},
+		{
+			name:     "field to index",
+			source:   `local params = std.extVar('__ksonnet/params').components.guestbook;`,
+			to:       "guestbook-ui",
+			expected: `local params = std.extVar('__ksonnet/params').components["guestbook-ui"];`,
+		},
+		{
+			name:     "index",
+			source:   `local params = std.extVar("__ksonnet/params").components["guestbook"];`,
+			to:       "frontend",
+			expected: `local params = std.extVar("__ksonnet/params").components.frontend;`,
+		},
+		{
+			name:     "other component",
+			source:   `local params = std.extVar("__ksonnet/params").components.guestbook2;`,
+			to:       "frontend",
+			expected: `local params = std.extVar("__ksonnet/params").components.guestbook2;`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := renameParamsReferences([]byte(tc.source), "guestbook", tc.to)
+			require.Equal(t, tc.expected, string(got))
+		})
+	}
+}
@@ -0,0 +1,20 @@
+{
+  global: {},
+  components: {
+    deployment: {
+      metadata: {
+        labels: {
+          "app.kubernetes.io/name": 'nginx',
+        },
+      },
+      spec: {
+        replicas: 1,
+        selector: {
+          matchLabels: {
+            app: 'nginx',
+          },
+        },
+      },
+    },
+  },
+}
\ No newline at end of file
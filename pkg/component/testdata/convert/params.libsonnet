@@ -0,0 +1,16 @@
+{
+  global: {
+  },
+  components: {
+    "deployment": {
+      metadata: {
+        labels: {
+          "app.kubernetes.io/name": "nginx",
+        },
+      },
+      spec: {
+        replicas: 1,
+      },
+    },
+  },
+}
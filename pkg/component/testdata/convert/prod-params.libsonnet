@@ -0,0 +1,16 @@
+local params = std.extVar("__ksonnet/params");
+
+params + {
+  components+: {
+    deployment+: {
+      spec+: {
+        replicas: 5,
+        selector+: {
+          matchLabels+: {
+            app: "nginx-prod",
+          },
+        },
+      },
+    },
+  },
+}
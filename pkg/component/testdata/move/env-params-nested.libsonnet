@@ -0,0 +1,12 @@
+local params = import '../../components/params.libsonnet';
+
+params {
+  components+: {
+    "guestbook-ui"+: {
+      name: 'guestbook-dev',
+    },
+    "nested.ui"+: {
+      name: 'guestbook-dev',
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,12 @@
+local params = import '../../components/params.libsonnet';
+
+params {
+  components+: {
+    "frontend.guestbook"+: {
+      name: 'guestbook-dev',
+    },
+    "nested.guestbook-ui"+: {
+      name: 'guestbook-dev',
+    },
+  },
+}
\ No newline at end of file
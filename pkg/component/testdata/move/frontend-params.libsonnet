@@ -0,0 +1,14 @@
+{
+  global: {},
+  components: {
+    guestbook: {
+      containerPort: 80,
+      image: 'gcr.io/heptio-images/ks-guestbook-demo:0.1',
+      name: 'guiroot',
+      replicas: 1,
+      servicePort: 80,
+      type: 'ClusterIP',
+      obj: { a: 'b' },
+    },
+  },
+}
\ No newline at end of file
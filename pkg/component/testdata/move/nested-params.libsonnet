@@ -0,0 +1,16 @@
+{
+  global: {},
+  components: {
+    // Component-level parameters, defined initially from 'ks prototype use ...'
+    // Each object below should correspond to a component in the components/ directory
+    ui: {
+      containerPort: 80,
+      image: 'gcr.io/heptio-images/ks-guestbook-demo:0.1',
+      name: 'guiroot',
+      replicas: 1,
+      servicePort: 80,
+      type: 'ClusterIP',
+      obj: { a: 'b' },
+    },
+  },
+}
\ No newline at end of file
@@ -16,8 +16,10 @@
 package component
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	"path/filepath"
 	"sort"
 	"strings"
@@ -28,6 +30,7 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/params"
 	"github.com/ksonnet/ksonnet/pkg/schema"
 	jsonnetutil "github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	utilyaml "github.com/ksonnet/ksonnet/pkg/util/yaml"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
@@ -308,31 +311,69 @@ func (y *YAML) Summarize() (Summary, error) {
 // ToNode converts a YAML component to a Jsonnet node.
 func (y *YAML) ToNode(envName string) (string, ast.Node, error) {
 	key := y.Name(false)
-	data, err := afero.ReadFile(y.app.Fs(), y.source)
+	data, err := y.readJSON()
 	if err != nil {
 		return "", nil, err
 	}
 
-	if len(data) == 0 {
-		return "", nil, errors.New("object was empty")
+	patchedData, err := y.applyParams(key, string(data))
+	if err != nil {
+		return "", nil, err
 	}
 
-	data, err = yaml.YAMLToJSON(data)
+	o, err := jsonnetutil.Parse(y.source, patchedData)
 	if err != nil {
 		return "", nil, err
 	}
 
-	patchedData, err := y.applyParams(key, string(data))
+	return y.Name(true), o, nil
+}
+
+// readJSON reads the component as JSON. A component with multiple YAML
+// documents is read as a v1 List of its documents.
+func (y *YAML) readJSON() ([]byte, error) {
+	f, err := y.app.Fs().Open(y.source)
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
+	defer f.Close()
 
-	o, err := jsonnetutil.Parse(y.source, patchedData)
+	readers, err := utilyaml.Decode(f)
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
-	return y.Name(true), o, nil
+	var docs []json.RawMessage
+	for _, r := range readers {
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return nil, err
+		}
+
+		data, err = yaml.YAMLToJSON(data)
+		if err != nil {
+			return nil, err
+		}
+
+		if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || bytes.Equal(trimmed, []byte("null")) {
+			continue
+		}
+
+		docs = append(docs, data)
+	}
+
+	switch len(docs) {
+	case 0:
+		return nil, errors.New("object was empty")
+	case 1:
+		return docs[0], nil
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "List",
+		"items":      docs,
+	})
 }
 
 func (y *YAML) applyParams(componentName, data string) (string, error) {
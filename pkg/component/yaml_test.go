@@ -301,3 +301,36 @@ func TestYAML_ToNode(t *testing.T) {
 		})
 	}
 }
+
+func TestYAML_readJSON(t *testing.T) {
+	cases := []struct {
+		name     string
+		src      string
+		expected string
+	}{
+		{
+			name:     "single document",
+			src:      "trailing-dash.yaml",
+			expected: `{"apiVersion":"apiextensions.k8s.io/v1beta1","kind":"CustomResourceDefinition","metadata":{"labels":{"app":"cert-manager","chart":"cert-manager-0.2.2","heritage":"Tiller","release":"cert-manager"},"name":"certificates.certmanager.k8s.io"},"spec":{"group":"certmanager.k8s.io","names":{"kind":"Certificate","plural":"certificates"},"scope":"Namespaced","version":"v1alpha1"}}`,
+		},
+		{
+			name:     "multiple documents",
+			src:      "multiple-documents.yaml",
+			expected: `{"apiVersion":"v1","items":[{"apiVersion":"v1","kind":"Service","metadata":{"name":"nginx"},"spec":{"ports":[{"port":80}]}},{"apiVersion":"v1","data":{"key":"value"},"kind":"ConfigMap","metadata":{"name":"nginx"}}],"kind":"List"}`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+				test.StageFile(t, fs, tc.src, "/app/components/component.yaml")
+
+				y := NewYAML(a, "", "/app/components/component.yaml", "/app/components/params.libsonnet")
+
+				data, err := y.readJSON()
+				require.NoError(t, err)
+				require.Equal(t, tc.expected, string(data))
+			})
+		})
+	}
+}
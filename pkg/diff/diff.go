@@ -78,6 +78,11 @@ func (d *Differ) Diff(location1, location2 *Location) (io.Reader, error) {
 		return nil, err
 	}
 
+	return Text(r1, r2)
+}
+
+// Text generates the differences between two YAML renderings.
+func Text(r1, r2 io.ReadSeeker) (io.Reader, error) {
 	var buf bytes.Buffer
 	if err := godiff.DefaultDiffer().Diff(&buf, r1, r2); err != nil {
 		return nil, err
@@ -86,6 +91,18 @@ func (d *Differ) Diff(location1, location2 *Location) (io.Reader, error) {
 	return &buf, nil
 }
 
+// Local renders the objects of an environment as YAML, the same way the
+// local side of a diff is rendered.
+func Local(a app.App, envName string, components []string) (io.ReadSeeker, error) {
+	return newYamlLocal(a).Generate(NewLocation("local:"+envName), components)
+}
+
+// Remote renders the objects of an environment found in its cluster as YAML,
+// the same way the remote side of a diff is rendered.
+func Remote(a app.App, config *client.Config, envName string, components []string) (io.ReadSeeker, error) {
+	return newYamlRemote(a, config).Generate(NewLocation("remote:"+envName), components)
+}
+
 func (d *Differ) toYAML(location *Location) (io.ReadSeeker, error) {
 	if err := location.Err(); err != nil {
 		return nil, err
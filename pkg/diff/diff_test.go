@@ -75,6 +75,24 @@ func TestDiffer(t *testing.T) {
 	})
 }
 
+func TestText(t *testing.T) {
+	r, err := Text(bytes.NewReader([]byte("a: 1\nb: 2\n")), bytes.NewReader([]byte("a: 1\nb: 3\n")))
+	require.NoError(t, err)
+
+	b, err := ioutil.ReadAll(r)
+	require.NoError(t, err)
+
+	require.Contains(t, string(b), "-b: 2\n")
+	require.Contains(t, string(b), "+b: 3\n")
+
+	r, err = Text(bytes.NewReader([]byte("a: 1\n")), bytes.NewReader([]byte("a: 1\n")))
+	require.NoError(t, err)
+
+	b, err = ioutil.ReadAll(r)
+	require.NoError(t, err)
+	require.Empty(t, string(b))
+}
+
 func Test_yamlLocal(t *testing.T) {
 	cases := []struct {
 		name             string
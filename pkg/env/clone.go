@@ -0,0 +1,184 @@
+// Copyright 2018 The kubecfg authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package env
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+// CloneConfig is configuration for cloning an environment.
+type CloneConfig struct {
+	App app.App
+	// From is the name of the environment to clone.
+	From string
+	// To is the name of the new environment.
+	To string
+	// Server replaces the cloned destination's server if it is set.
+	Server string
+	// Namespace replaces the cloned destination's namespace if it is set.
+	Namespace string
+}
+
+// Clone creates a new environment from an existing one. The new environment
+// gets a copy of the source's params, main.jsonnet overrides, targets and
+// library references.
+func Clone(config CloneConfig) error {
+	c := &cloner{CloneConfig: config}
+	return c.Clone()
+}
+
+type cloner struct {
+	CloneConfig
+}
+
+func (c *cloner) Clone() error {
+	if !isValidName(c.To) {
+		return fmt.Errorf("environment name %q is not valid; must not contain punctuation, spaces, or begin or end with a slash", c.To)
+	}
+
+	src, err := c.App.Environment(c.From)
+	if err != nil {
+		return errors.Wrapf(err, "environment %q does not exist", c.From)
+	}
+
+	if _, err := c.App.Environment(c.To); err == nil {
+		return errors.Errorf("environment %q already exists", c.To)
+	}
+
+	isOverride := c.App.IsEnvOverride(c.From)
+	dst := c.cloneConfig(src, isOverride)
+
+	srcPath := filepath.Join(c.App.Root(), envRootName, src.Path)
+	dstPath := filepath.Join(c.App.Root(), envRootName, dst.Path)
+
+	dstExists, err := afero.Exists(c.App.Fs(), dstPath)
+	if err != nil {
+		return err
+	}
+
+	log.Infof("Cloning environment %q to %q", c.From, c.To)
+
+	if err = copyEnvDir(c.App.Fs(), dstPath, srcPath); err == nil {
+		err = c.App.AddEnvironment(dst, "", isOverride)
+	}
+
+	if err != nil {
+		// Remove the copy, so the clone can be retried.
+		if !dstExists {
+			if rerr := c.App.Fs().RemoveAll(dstPath); rerr != nil {
+				log.Warnf("unable to remove %s: %v", dstPath, rerr)
+			}
+		}
+		return errors.Wrapf(err, "cloning environment %q", c.From)
+	}
+
+	return nil
+}
+
+// cloneConfig returns the configuration of the new environment.
+func (c *cloner) cloneConfig(src *app.EnvironmentConfig, isOverride bool) *app.EnvironmentConfig {
+	dst := &app.EnvironmentConfig{
+		Name:              c.To,
+		Path:              c.To,
+		Parent:            src.Parent,
+		KubernetesVersion: src.KubernetesVersion,
+	}
+
+	if len(src.Targets) > 0 {
+		dst.Targets = make([]string, len(src.Targets))
+		copy(dst.Targets, src.Targets)
+	}
+
+	// Library references can't be stored in overrides.
+	if !isOverride && len(src.Libraries) > 0 {
+		dst.Libraries = app.LibraryConfigs{}
+		for k, v := range src.Libraries {
+			lib := *v
+			dst.Libraries[k] = &lib
+		}
+	}
+
+	if c.Server == "" && c.Namespace == "" && len(src.Destinations) > 0 {
+		for _, d := range src.Destinations {
+			dest := *d
+			dst.Destinations = append(dst.Destinations, &dest)
+		}
+		return dst
+	}
+
+	// Setting a server or namespace collapses the clone to a single destination.
+	dest := app.EnvironmentDestinationSpec{}
+	if src.Destination != nil {
+		dest = *src.Destination
+		dest.Name = ""
+		dest.Params = ""
+	}
+	if c.Server != "" {
+		dest.Server = c.Server
+		dest.Context = ""
+		dest.Kubeconfig = ""
+		dest.InCluster = false
+	}
+	if c.Namespace != "" {
+		dest.Namespace = c.Namespace
+	}
+	dst.Destination = &dest
+
+	return dst
+}
+
+// copyEnvDir copies the files of the environment in src to dst. Nested
+// environments are skipped.
+func copyEnvDir(fs afero.Fs, dst, src string) error {
+	return afero.Walk(fs, src, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		dstPath := filepath.Join(dst, rel)
+
+		if !fi.IsDir() {
+			data, err := afero.ReadFile(fs, path)
+			if err != nil {
+				return err
+			}
+			return afero.WriteFile(fs, dstPath, data, app.DefaultFilePermissions)
+		}
+
+		if path != src {
+			isEnv, err := afero.Exists(fs, filepath.Join(path, envFileName))
+			if err != nil {
+				return err
+			}
+			if isEnv {
+				return filepath.SkipDir
+			}
+		}
+
+		return fs.MkdirAll(dstPath, app.DefaultFolderPermissions)
+	})
+}
@@ -0,0 +1,133 @@
+// Copyright 2018 The kubecfg authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package env
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClone(t *testing.T) {
+	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+		stageFile(t, fs, "params.libsonnet", "/environments/env1/patches/params.libsonnet")
+		stageFile(t, fs, "main.jsonnet", "/environments/env1/nested/main.jsonnet")
+
+		expected := &app.EnvironmentConfig{
+			Name: "env4",
+			Path: "env4",
+			Destination: &app.EnvironmentDestinationSpec{
+				Server:    "http://example.com",
+				Namespace: "env4",
+			},
+		}
+
+		appMock.On("Environment", "env4").Return(nil, errors.New("it does not exist"))
+		appMock.On("IsEnvOverride", "env1").Return(false)
+		appMock.On("AddEnvironment", expected, "", false).Return(nil)
+
+		config := CloneConfig{
+			App:       appMock,
+			From:      "env1",
+			To:        "env4",
+			Server:    "http://example.com",
+			Namespace: "env4",
+		}
+
+		err := Clone(config)
+		require.NoError(t, err)
+
+		for _, name := range []string{"main.jsonnet", "params.libsonnet", "globals.libsonnet", "patches/params.libsonnet"} {
+			compareOutput(t, fs, filepath.Base(name), filepath.Join("/environments/env4", name))
+		}
+		checkNotExists(t, fs, "/environments/env4/nested")
+	})
+}
+
+func TestClone_add_failed(t *testing.T) {
+	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+		appMock.On("Environment", "env4").Return(nil, errors.New("it does not exist"))
+		appMock.On("IsEnvOverride", "env1").Return(false)
+		appMock.On("AddEnvironment", mock.Anything, "", false).Return(errors.New("failed"))
+
+		config := CloneConfig{
+			App:  appMock,
+			From: "env1",
+			To:   "env4",
+		}
+
+		err := Clone(config)
+		require.Error(t, err)
+
+		checkNotExists(t, fs, "/environments/env4")
+	})
+}
+
+func TestClone_existing(t *testing.T) {
+	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+		config := CloneConfig{
+			App:  appMock,
+			From: "env1",
+			To:   "env1",
+		}
+
+		err := Clone(config)
+		require.Error(t, err)
+	})
+}
+
+func Test_cloner_cloneConfig(t *testing.T) {
+	src := &app.EnvironmentConfig{
+		Name:              "prod",
+		Path:              "prod",
+		Parent:            "base",
+		KubernetesVersion: "v1.8.0",
+		Destination: &app.EnvironmentDestinationSpec{
+			Name:      "us",
+			Server:    "http://us.example.com",
+			Namespace: "prod",
+		},
+		Destinations: []*app.EnvironmentDestinationSpec{
+			{Name: "us", Server: "http://us.example.com", Namespace: "prod"},
+			{Name: "eu", Server: "http://eu.example.com", Namespace: "prod"},
+		},
+		Targets: []string{"app"},
+		Libraries: app.LibraryConfigs{
+			"incubator/nginx": &app.LibraryConfig{Name: "nginx", Registry: "incubator", Version: "1.0.0"},
+		},
+	}
+
+	c := &cloner{CloneConfig{To: "prod-2"}}
+	got := c.cloneConfig(src, false)
+	require.Equal(t, "prod-2", got.Path)
+	require.Equal(t, "base", got.Parent)
+	require.Nil(t, got.Destination)
+	require.Equal(t, src.Destinations, got.Destinations)
+	require.Equal(t, src.Targets, got.Targets)
+	require.Equal(t, src.Libraries, got.Libraries)
+
+	c = &cloner{CloneConfig{To: "prod-2", Namespace: "other"}}
+	got = c.cloneConfig(src, true)
+	require.Nil(t, got.Destinations)
+	require.Nil(t, got.Libraries)
+	require.Equal(t, &app.EnvironmentDestinationSpec{Server: "http://us.example.com", Namespace: "other"}, got.Destination)
+}
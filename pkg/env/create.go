@@ -118,8 +118,11 @@ func (c *creator) Create() error {
 		Name: c.name,
 		Path: c.name,
 		Destination: &app.EnvironmentDestinationSpec{
-			Server:    c.d.Server(),
-			Namespace: c.d.Namespace(),
+			Server:     c.d.Server(),
+			Namespace:  c.d.Namespace(),
+			Context:    c.d.Context(),
+			Kubeconfig: c.d.Kubeconfig(),
+			InCluster:  c.d.InCluster(),
 		},
 	}, c.k8sSpecFlag, c.isOverride)
 
@@ -18,6 +18,9 @@ package env
 const (
 	// ComponentsExtCodeKey is the ExtCode key for component imports
 	ComponentsExtCodeKey = "__ksonnet/components"
+	// ObjectsExtCodeKey is the ExtCode key for the objects rendered for an
+	// environment. It is set when testing and linting objects.
+	ObjectsExtCodeKey = "__ksonnet/objects"
 
 	relComponentParamsPath = "../../components/params.libsonnet"
 )
@@ -24,26 +24,61 @@ const (
 
 // Destination contains destination information for a cluster.
 type Destination struct {
-	server    string
-	namespace string
+	server     string
+	namespace  string
+	context    string
+	kubeconfig string
+	inCluster  bool
+}
+
+// DestinationOpt is an option for configuring a Destination.
+type DestinationOpt func(*Destination)
+
+// DestinationContext sets the kubeconfig context of a Destination, and
+// optionally the kubeconfig file it is read from.
+func DestinationContext(context, kubeconfig string) DestinationOpt {
+	return func(d *Destination) {
+		d.context = context
+		d.kubeconfig = kubeconfig
+	}
+}
+
+// DestinationInCluster configures a Destination to use the service account
+// of the pod ks is running in.
+func DestinationInCluster() DestinationOpt {
+	return func(d *Destination) {
+		d.inCluster = true
+	}
 }
 
 // NewDestination creates an instance of Destination.
-func NewDestination(server, namespace string) Destination {
-	return Destination{
+func NewDestination(server, namespace string, opts ...DestinationOpt) Destination {
+	d := Destination{
 		server:    server,
 		namespace: namespace,
 	}
+
+	for _, opt := range opts {
+		opt(&d)
+	}
+
+	return d
 }
 
 // MarshalJSON marshals a Destination to JSON.
 func (d *Destination) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&struct {
-		Server    string `json:"server"`
-		Namespace string `json:"namespace"`
+		Server     string `json:"server"`
+		Namespace  string `json:"namespace"`
+		Context    string `json:"context,omitempty"`
+		Kubeconfig string `json:"kubeconfig,omitempty"`
+		InCluster  bool   `json:"inCluster,omitempty"`
 	}{
-		Server:    d.Server(),
-		Namespace: d.Namespace(),
+		Server:     d.Server(),
+		Namespace:  d.Namespace(),
+		Context:    d.Context(),
+		Kubeconfig: d.Kubeconfig(),
+		InCluster:  d.InCluster(),
 	})
 }
 
@@ -61,3 +96,18 @@ func (d *Destination) Namespace() string {
 
 	return d.namespace
 }
+
+// Context is the kubeconfig context used to connect to the cluster.
+func (d *Destination) Context() string {
+	return d.context
+}
+
+// Kubeconfig is the path of the kubeconfig file containing the context.
+func (d *Destination) Kubeconfig() string {
+	return d.kubeconfig
+}
+
+// InCluster is true if the destination is the cluster ks is running in.
+func (d *Destination) InCluster() bool {
+	return d.inCluster
+}
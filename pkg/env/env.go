@@ -100,31 +100,72 @@ func MainFile(a app.App, envName string) (string, error) {
 	return string(snippet), nil
 }
 
-// Evaluate evaluates an environment.
+// Evaluate evaluates an environment. If the environment has parents, their
+// main sources are evaluated first, starting with the root of the hierarchy.
 func Evaluate(a app.App, envName, components, paramsStr string, opts ...jsonnet.VMOpt) (string, error) {
-
-	snippet, err := MainFile(a, envName)
+	chain, err := app.EnvironmentChain(a, envName)
 	if err != nil {
 		return "", err
 	}
 
-	evaluated, err := evaluateMain(a, envName, snippet, components, paramsStr, opts...)
+	evaluated := components
+	for _, e := range chain {
+		snippet, err := MainFile(a, e.Name)
+		if err != nil {
+			return "", err
+		}
+
+		evaluated, err = evaluateMain(a, envName, e.Path, snippet, evaluated, paramsStr, opts...)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return upgradeArray(evaluated)
+}
+
+// evaluateMain evaluates a main source located in envPath on behalf of envName.
+func evaluateMain(a app.App, envName, envPath, snippet, components, paramsStr string, opts ...jsonnet.VMOpt) (string, error) {
+	vm, cleanup, err := newVM(a, envName, envPath, opts...)
 	if err != nil {
 		return "", err
 	}
+	defer cleanup()
 
-	return upgradeArray(evaluated)
+	for k, v := range componentTlaVars {
+		vm.TLAVar(k, v)
+	}
+
+	vm.ExtCode(ComponentsExtCodeKey, components)
+	vm.ExtCode("__ksonnet/params", paramsStr)
+
+	return vm.EvaluateSnippet(envFileName, snippet)
+}
+
+// VM creates a Jsonnet VM which evaluates code the way components are
+// evaluated in an environment: with the same jpaths, ext vars and native
+// functions. The returned cleanup function has to be called once the VM is no
+// longer used.
+func VM(a app.App, envName string, opts ...jsonnet.VMOpt) (*jsonnet.VM, func() error, error) {
+	appEnv, err := a.Environment(envName)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return newVM(a, envName, appEnv.Path, opts...)
 }
 
-func evaluateMain(a app.App, envName, snippet, components, paramsStr string, opts ...jsonnet.VMOpt) (string, error) {
+// newVM creates a Jsonnet VM for envName, with the directory of the environment
+// located in envPath in its jpath.
+func newVM(a app.App, envName, envPath string, opts ...jsonnet.VMOpt) (*jsonnet.VM, func() error, error) {
 	libPath, err := a.LibPath(envName)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 
 	appEnv, err := a.Environment(envName)
 	if err != nil {
-		return "", err
+		return nil, nil, err
 	}
 
 	vm := jsonnet.NewVM(opts...)
@@ -132,7 +173,7 @@ func evaluateMain(a app.App, envName, snippet, components, paramsStr string, opt
 	vm.AddJPath(componentJPaths...)
 	vm.AddJPath(
 		filepath.Join(a.Root(), envRootName),
-		filepath.Join(a.Root(), envRootName, appEnv.Path),
+		filepath.Join(a.Root(), envRootName, envPath),
 		filepath.Join(a.Root(), "vendor"),
 		filepath.Join(a.Root(), "lib"),
 		libPath,
@@ -146,9 +187,8 @@ func evaluateMain(a app.App, envName, snippet, components, paramsStr string, opt
 	pm := registry.NewPackageManager(a)
 	revendoredPath, cleanup, err := revendorPackages(a, pm, appEnv)
 	if err != nil {
-		return "", errors.Wrapf(err, "revendoring packages for environment: %v", envName)
+		return nil, nil, errors.Wrapf(err, "revendoring packages for environment: %v", envName)
 	}
-	defer cleanup()
 	vm.AddJPath(revendoredPath) // TODO does precedence matter?
 	// end re-vendor
 
@@ -163,22 +203,17 @@ func evaluateMain(a app.App, envName, snippet, components, paramsStr string, opt
 
 	envCode, err := params.JsonnetEnvObject(a, envName)
 	if err != nil {
-		return "", err
+		cleanup()
+		return nil, nil, err
 	}
 
 	for k, v := range componentExtVars {
 		vm.ExtVar(k, v)
 	}
 
-	for k, v := range componentTlaVars {
-		vm.TLAVar(k, v)
-	}
-
 	vm.ExtCode("__ksonnet/environments", envCode)
-	vm.ExtCode(ComponentsExtCodeKey, components)
-	vm.ExtCode("__ksonnet/params", paramsStr)
 
-	return vm.EvaluateSnippet(envFileName, snippet)
+	return vm, cleanup, nil
 }
 
 // upgradeArray wraps component lists in Kubernetes lists.
@@ -0,0 +1,306 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	jsonpatch "github.com/evanphx/json-patch"
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	yamlv2 "gopkg.in/yaml.v2"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/strategicpatch"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+const (
+	// patchesDirName is the directory in an environment containing patches.
+	patchesDirName = "patches"
+
+	// PatchTypeStrategic is a strategic merge patch. Kinds which are not built
+	// into Kubernetes are patched with a JSON merge patch.
+	PatchTypeStrategic = "strategic"
+	// PatchTypeJSON is a JSON patch (RFC 6902).
+	PatchTypeJSON = "json"
+)
+
+// PatchTarget identifies the object a patch applies to.
+type PatchTarget struct {
+	APIVersion string `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string `json:"kind" yaml:"kind"`
+	Name       string `json:"name" yaml:"name"`
+	// Namespace is optional. If it is set, only objects in the namespace are patched.
+	Namespace string `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+}
+
+func (t PatchTarget) String() string {
+	s := fmt.Sprintf("%s %q (%s)", t.Kind, t.Name, t.APIVersion)
+	if t.Namespace != "" {
+		s += fmt.Sprintf(" in namespace %q", t.Namespace)
+	}
+	return s
+}
+
+// Patch is a patch applied to an object after the components of an environment
+// are rendered. Patches are read from the `patches` directory of an environment.
+type Patch struct {
+	// Path is the file the patch was read from.
+	Path string `json:"-"`
+	// Target is the object the patch applies to.
+	Target PatchTarget `json:"target"`
+	// Type is the type of the patch. It defaults to a strategic merge patch.
+	Type string `json:"type,omitempty"`
+	// Patch is the patch document.
+	Patch json.RawMessage `json:"patch"`
+}
+
+// Patches returns the patches for an environment. Patches of parent environments
+// are returned first. Each environment's patches are ordered by file name.
+func Patches(a app.App, envName string) ([]*Patch, error) {
+	chain, err := app.EnvironmentChain(a, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	var patches []*Patch
+	for _, e := range chain {
+		dir, err := Path(a, e.Name, patchesDirName)
+		if err != nil {
+			return nil, err
+		}
+
+		exists, err := afero.DirExists(a.Fs(), dir)
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			continue
+		}
+
+		fis, err := afero.ReadDir(a.Fs(), dir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, fi := range fis {
+			switch filepath.Ext(fi.Name()) {
+			case ".yaml", ".yml", ".json":
+			default:
+				continue
+			}
+
+			p, err := readPatch(a.Fs(), filepath.Join(dir, fi.Name()))
+			if err != nil {
+				return nil, err
+			}
+
+			patches = append(patches, p)
+		}
+	}
+
+	return patches, nil
+}
+
+func readPatch(fs afero.Fs, path string) (*Patch, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		return nil, err
+	}
+
+	var p Patch
+	if err = yaml.Unmarshal(data, &p); err != nil {
+		return nil, errors.Wrapf(err, "reading patch %s", path)
+	}
+	p.Path = path
+
+	if p.Type == "" {
+		p.Type = PatchTypeStrategic
+	}
+
+	if err = p.validate(); err != nil {
+		return nil, errors.Wrapf(err, "invalid patch %s", path)
+	}
+
+	return &p, nil
+}
+
+func (p *Patch) validate() error {
+	t := p.Target
+	if t.APIVersion == "" || t.Kind == "" || t.Name == "" {
+		return errors.New("target requires an apiVersion, kind and name")
+	}
+
+	if p.Type != PatchTypeStrategic && p.Type != PatchTypeJSON {
+		return errors.Errorf("type %q is not one of %q or %q", p.Type, PatchTypeStrategic, PatchTypeJSON)
+	}
+
+	if len(p.Patch) == 0 || bytes.Equal(p.Patch, []byte("null")) {
+		return errors.New("patch is empty")
+	}
+
+	return nil
+}
+
+// Matches reports whether the patch applies to an object.
+func (p *Patch) Matches(obj *unstructured.Unstructured) bool {
+	t := p.Target
+	return obj.GetAPIVersion() == t.APIVersion &&
+		obj.GetKind() == t.Kind &&
+		obj.GetName() == t.Name &&
+		(t.Namespace == "" || obj.GetNamespace() == t.Namespace)
+}
+
+// Apply applies the patch to an object, and returns the patched object.
+func (p *Patch) Apply(obj *unstructured.Unstructured) (*unstructured.Unstructured, error) {
+	original, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	var patched []byte
+	switch p.Type {
+	case PatchTypeJSON:
+		var jp jsonpatch.Patch
+		if jp, err = jsonpatch.DecodePatch(p.Patch); err != nil {
+			return nil, errors.Wrapf(err, "decoding patch %s", p.Path)
+		}
+		patched, err = jp.Apply(original)
+	default:
+		gvk := schema.FromAPIVersionAndKind(obj.GetAPIVersion(), obj.GetKind())
+		var versioned runtime.Object
+		versioned, err = scheme.Scheme.New(gvk)
+		switch {
+		case runtime.IsNotRegisteredError(err):
+			patched, err = jsonpatch.MergePatch(original, p.Patch)
+		case err == nil:
+			patched, err = strategicpatch.StrategicMergePatch(original, p.Patch, versioned)
+		}
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "applying patch %s to %s", p.Path, p.Target)
+	}
+
+	u := &unstructured.Unstructured{}
+	if err = u.UnmarshalJSON(patched); err != nil {
+		return nil, errors.Wrapf(err, "decoding object patched by %s", p.Path)
+	}
+
+	if !p.Matches(u) {
+		return nil, errors.Errorf("patch %s changes the apiVersion, kind, name or namespace of %s", p.Path, p.Target)
+	}
+
+	return u, nil
+}
+
+// ApplyPatches applies patches to objects in order. If strict is true, it is an error
+// for a patch to target an object which does not exist.
+func ApplyPatches(objects []*unstructured.Unstructured, patches []*Patch, strict bool) ([]*unstructured.Unstructured, error) {
+	for _, p := range patches {
+		matched := false
+		for i := range objects {
+			if !p.Matches(objects[i]) {
+				continue
+			}
+
+			log.Debugf("applying patch %s to %s", p.Path, p.Target)
+			patched, err := p.Apply(objects[i])
+			if err != nil {
+				return nil, err
+			}
+
+			objects[i] = patched
+			matched = true
+		}
+
+		if !matched && strict {
+			return nil, errors.Errorf("patch %s targets %s, which is not rendered in the environment; update or remove the patch", p.Path, p.Target)
+		}
+	}
+
+	return objects, nil
+}
+
+// CreatePatch creates a patch file for an object in an environment. The patch
+// document is empty. The path of the file is returned.
+func CreatePatch(a app.App, envName string, target PatchTarget, patchType string) (string, error) {
+	if err := ensureEnvExists(a, envName); err != nil {
+		return "", err
+	}
+
+	scaffold := struct {
+		Target PatchTarget `yaml:"target"`
+		Type   string      `yaml:"type"`
+		Patch  interface{} `yaml:"patch"`
+	}{
+		Target: target,
+		Type:   patchType,
+		Patch:  map[string]interface{}{},
+	}
+
+	if patchType == PatchTypeJSON {
+		scaffold.Patch = []interface{}{}
+	}
+
+	p := &Patch{Target: target, Type: patchType, Patch: json.RawMessage("{}")}
+	if err := p.validate(); err != nil {
+		return "", err
+	}
+
+	dir, err := Path(a, envName, patchesDirName)
+	if err != nil {
+		return "", err
+	}
+
+	name := strings.ToLower(target.Kind) + "-" + strings.Replace(target.Name, ":", "-", -1) + ".yaml"
+	path := filepath.Join(dir, name)
+
+	exists, err := afero.Exists(a.Fs(), path)
+	if err != nil {
+		return "", err
+	}
+
+	if exists {
+		return "", errors.Errorf("patch %s already exists", path)
+	}
+
+	data, err := yamlv2.Marshal(&scaffold)
+	if err != nil {
+		return "", err
+	}
+	data = append([]byte(fmt.Sprintf("# Patch for %s, applied when environment %q is rendered.\n", target, envName)), data...)
+
+	if err = a.Fs().MkdirAll(dir, app.DefaultFolderPermissions); err != nil {
+		return "", err
+	}
+
+	if err = afero.WriteFile(a.Fs(), path, data, app.DefaultFilePermissions); err != nil {
+		return "", err
+	}
+
+	return path, nil
+}
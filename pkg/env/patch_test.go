@@ -0,0 +1,225 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package env
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func readObject(t *testing.T, name string) *unstructured.Unstructured {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "patches", name))
+	require.NoError(t, err)
+
+	u := &unstructured.Unstructured{}
+	require.NoError(t, u.UnmarshalJSON(data))
+	return u
+}
+
+func TestPatches(t *testing.T) {
+	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+		envSpec := &app.EnvironmentConfig{Name: "env2", Path: "env2", Parent: "env1"}
+		appMock.On("Environment", "env2").Return(envSpec, nil)
+
+		stageFile(t, fs, "patches/sidecar.yaml", "/environments/env1/patches/sidecar.yaml")
+		stageFile(t, fs, "patches/replicas.json", "/environments/env2/patches/replicas.json")
+		stageFile(t, fs, "patches/deployment.json", "/environments/env2/patches/README.md")
+
+		patches, err := Patches(appMock, "env2")
+		require.NoError(t, err)
+
+		require.Len(t, patches, 2)
+		assert.Equal(t, "/environments/env1/patches/sidecar.yaml", patches[0].Path)
+		assert.Equal(t, PatchTypeStrategic, patches[0].Type)
+		assert.Equal(t, "/environments/env2/patches/replicas.json", patches[1].Path)
+		assert.Equal(t, PatchTypeJSON, patches[1].Type)
+
+		objects, err := ApplyPatches([]*unstructured.Unstructured{readObject(t, "deployment.json")}, patches, true)
+		require.NoError(t, err)
+		require.Len(t, objects, 1)
+
+		assert.Equal(t, readObject(t, "deployment-patched.json"), objects[0])
+	})
+}
+
+func TestPatches_invalid(t *testing.T) {
+	cases := []struct {
+		name  string
+		patch string
+	}{
+		{
+			name:  "missing target",
+			patch: "patch: {}",
+		},
+		{
+			name:  "unknown type",
+			patch: "target: {apiVersion: v1, kind: Service, name: guestbook}\ntype: merge\npatch: {}",
+		},
+		{
+			name:  "empty patch",
+			patch: "target: {apiVersion: v1, kind: Service, name: guestbook}",
+		},
+		{
+			name:  "invalid document",
+			patch: "target: [",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+				path := "/environments/env1/patches/patch.yaml"
+				require.NoError(t, fs.MkdirAll(filepath.Dir(path), 0755))
+				require.NoError(t, afero.WriteFile(fs, path, []byte(tc.patch), 0644))
+
+				_, err := Patches(appMock, "env1")
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), path)
+			})
+		})
+	}
+}
+
+func TestPatch_Apply(t *testing.T) {
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "certmanager.k8s.io/v1alpha1",
+		"kind":       "Certificate",
+		"metadata": map[string]interface{}{
+			"name": "cert",
+		},
+		"spec": map[string]interface{}{
+			"dnsNames": []interface{}{"example.com"},
+		},
+	}}
+
+	cases := []struct {
+		name     string
+		object   *unstructured.Unstructured
+		patch    *Patch
+		expected map[string]interface{}
+		isErr    bool
+	}{
+		{
+			name:   "merge patch for unregistered kind",
+			object: crd,
+			patch: &Patch{
+				Target: PatchTarget{APIVersion: "certmanager.k8s.io/v1alpha1", Kind: "Certificate", Name: "cert"},
+				Type:   PatchTypeStrategic,
+				Patch:  []byte(`{"spec": {"dnsNames": ["example.org"]}}`),
+			},
+			expected: map[string]interface{}{
+				"dnsNames": []interface{}{"example.org"},
+			},
+		},
+		{
+			name:   "json patch",
+			object: crd,
+			patch: &Patch{
+				Target: PatchTarget{APIVersion: "certmanager.k8s.io/v1alpha1", Kind: "Certificate", Name: "cert"},
+				Type:   PatchTypeJSON,
+				Patch:  []byte(`[{"op": "add", "path": "/spec/dnsNames/-", "value": "example.org"}]`),
+			},
+			expected: map[string]interface{}{
+				"dnsNames": []interface{}{"example.com", "example.org"},
+			},
+		},
+		{
+			name:   "json patch fails",
+			object: crd,
+			patch: &Patch{
+				Target: PatchTarget{APIVersion: "certmanager.k8s.io/v1alpha1", Kind: "Certificate", Name: "cert"},
+				Type:   PatchTypeJSON,
+				Patch:  []byte(`[{"op": "replace", "path": "/spec/missing", "value": "x"}]`),
+			},
+			isErr: true,
+		},
+		{
+			name:   "patch renames object",
+			object: crd,
+			patch: &Patch{
+				Target: PatchTarget{APIVersion: "certmanager.k8s.io/v1alpha1", Kind: "Certificate", Name: "cert"},
+				Type:   PatchTypeStrategic,
+				Patch:  []byte(`{"metadata": {"name": "other"}}`),
+			},
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.patch.Apply(tc.object)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, got.Object["spec"])
+		})
+	}
+}
+
+func TestApplyPatches_missing_target(t *testing.T) {
+	patches := []*Patch{
+		{
+			Path:   "/environments/default/patches/service-guestbook.yaml",
+			Target: PatchTarget{APIVersion: "v1", Kind: "Service", Name: "guestbook"},
+			Type:   PatchTypeStrategic,
+			Patch:  []byte(`{"spec": {"type": "NodePort"}}`),
+		},
+	}
+
+	objects := []*unstructured.Unstructured{readObject(t, "deployment.json")}
+
+	_, err := ApplyPatches(objects, patches, true)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "service-guestbook.yaml")
+
+	got, err := ApplyPatches(objects, patches, false)
+	require.NoError(t, err)
+	assert.Equal(t, objects, got)
+}
+
+func TestCreatePatch(t *testing.T) {
+	withEnv(t, func(appMock *mocks.App, fs afero.Fs) {
+		target := PatchTarget{APIVersion: "apps/v1beta2", Kind: "Deployment", Name: "guestbook"}
+
+		path, err := CreatePatch(appMock, "env1", target, PatchTypeStrategic)
+		require.NoError(t, err)
+		require.Equal(t, "/environments/env1/patches/deployment-guestbook.yaml", path)
+
+		compareOutput(t, fs, "patches/scaffold.yaml", path)
+
+		_, err = CreatePatch(appMock, "env1", target, PatchTypeStrategic)
+		require.Error(t, err)
+
+		path, err = CreatePatch(appMock, "env1", PatchTarget{APIVersion: "v1", Kind: "Service", Name: "guestbook"}, PatchTypeJSON)
+		require.NoError(t, err)
+
+		p, err := readPatch(fs, path)
+		require.NoError(t, err)
+		assert.Equal(t, PatchTypeJSON, p.Type)
+		assert.Equal(t, "[]", string(p.Patch))
+	})
+}
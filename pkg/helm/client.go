@@ -71,6 +71,7 @@ func (hr *Repository) Latest() []RepositoryChart {
 // RepositoryChart is metadata describing a Helm Chart in a repository.
 type RepositoryChart struct {
 	Description string   `json:"description,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
 	Name        string   `json:"name,omitempty"`
 	URLs        []string `json:"urls,omitempty"`
 	Version     string   `json:"version,omitempty"`
@@ -187,6 +188,12 @@ func (hrc *HTTPClient) Chart(name, version string) (*RepositoryChart, error) {
 		return nil, errors.Wrap(err, "retrieving repository")
 	}
 
+	return repo.Chart(name, version)
+}
+
+// Chart returns a chart from the repository. If version is blank, it returns the latest.
+func (hr *Repository) Chart(name, version string) (*RepositoryChart, error) {
+	repo := hr
 	if version == "" {
 		for _, chart := range repo.Latest() {
 			if name == chart.Name {
@@ -0,0 +1,286 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package helm
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/util/archive"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// DirClient is a client for a Helm repository in a local directory. The
+// directory contains the repository's index.yaml and chart archives. A
+// directory without an index.yaml is indexed from the chart directories and
+// chart archives it contains, so charts can be used without publishing them.
+type DirClient struct {
+	fs  afero.Fs
+	dir string
+}
+
+var _ RepositoryClient = (*DirClient)(nil)
+
+// NewDirClient creates an instance of DirClient.
+func NewDirClient(fs afero.Fs, dir string) *DirClient {
+	return &DirClient{
+		fs:  fs,
+		dir: dir,
+	}
+}
+
+// Repository returns the Helm repository's content.
+func (dc *DirClient) Repository() (*Repository, error) {
+	b, err := afero.ReadFile(dc.fs, filepath.Join(dc.dir, "index.yaml"))
+	if os.IsNotExist(err) {
+		repo, _, err := dc.index()
+		return repo, err
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "reading repository index.yaml")
+	}
+
+	var hre Repository
+
+	if err := yaml.Unmarshal(b, &hre); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling repository index.yaml")
+	}
+
+	return &hre, nil
+}
+
+// Chart returns a chart from the repository. If version is blank, it returns the latest.
+func (dc *DirClient) Chart(name, version string) (*RepositoryChart, error) {
+	repo, err := dc.Repository()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving repository")
+	}
+
+	return repo.Chart(name, version)
+}
+
+// Fetch fetches a chart archive from the directory. Only the file name of
+// uri is used, so charts which reference their original location are found
+// as well.
+func (dc *DirClient) Fetch(uri string) (io.ReadCloser, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		return nil, errors.Errorf("%q does not reference a file", uri)
+	}
+
+	b, err := afero.ReadFile(dc.fs, filepath.Join(dc.dir, name))
+	if os.IsNotExist(err) {
+		b, err = dc.fetchLocalChart(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return ioutil.NopCloser(bytes.NewReader(b)), nil
+}
+
+// chartMetadata is the subset of Chart.yaml used to index local charts.
+type chartMetadata struct {
+	Name        string   `json:"name"`
+	Version     string   `json:"version"`
+	Description string   `json:"description,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+}
+
+// localChart is a chart in the directory. path is a chart directory or a
+// chart archive.
+type localChart struct {
+	path  string
+	isDir bool
+}
+
+// index generates a repository index from the chart directories and chart
+// archives in the directory. Charts are referenced by the name of their
+// archive, <name>-<version>.tgz.
+func (dc *DirClient) index() (*Repository, map[string]localChart, error) {
+	fis, err := afero.ReadDir(dc.fs, dc.dir)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "reading repository directory")
+	}
+
+	repo := &Repository{Charts: make(map[string][]RepositoryChart)}
+	charts := make(map[string]localChart)
+
+	for _, fi := range fis {
+		p := filepath.Join(dc.dir, fi.Name())
+
+		var md *chartMetadata
+		switch {
+		case fi.IsDir():
+			b, err := afero.ReadFile(dc.fs, filepath.Join(p, "Chart.yaml"))
+			if os.IsNotExist(err) {
+				continue
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			if md, err = parseChartMetadata(b); err != nil {
+				return nil, nil, errors.Wrapf(err, "reading chart %s", p)
+			}
+		case strings.HasSuffix(fi.Name(), ".tgz"):
+			if md, err = dc.archiveMetadata(p); err != nil {
+				return nil, nil, errors.Wrapf(err, "reading chart archive %s", p)
+			}
+		default:
+			continue
+		}
+
+		fileName := md.Name + "-" + md.Version + ".tgz"
+		charts[fileName] = localChart{path: p, isDir: fi.IsDir()}
+		repo.Charts[md.Name] = append(repo.Charts[md.Name], RepositoryChart{
+			Name:        md.Name,
+			Version:     md.Version,
+			Description: md.Description,
+			Keywords:    md.Keywords,
+			URLs:        []string{fileName},
+		})
+	}
+
+	return repo, charts, nil
+}
+
+func parseChartMetadata(b []byte) (*chartMetadata, error) {
+	var md chartMetadata
+	if err := yaml.Unmarshal(b, &md); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling Chart.yaml")
+	}
+
+	if md.Name == "" || md.Version == "" {
+		return nil, errors.New("Chart.yaml requires a name and a version")
+	}
+
+	return &md, nil
+}
+
+// archiveMetadata reads the Chart.yaml of a chart archive.
+func (dc *DirClient) archiveMetadata(p string) (*chartMetadata, error) {
+	f, err := dc.fs.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var md *chartMetadata
+	var tgz archive.Tgz
+	err = tgz.Unarchive(f, func(af *archive.File) error {
+		parts := strings.Split(af.Name, "/")
+		if md != nil || len(parts) != 2 || parts[1] != "Chart.yaml" {
+			return nil
+		}
+
+		b, err := ioutil.ReadAll(af.Reader)
+		if err != nil {
+			return err
+		}
+
+		md, err = parseChartMetadata(b)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if md == nil {
+		return nil, errors.New("archive does not contain a Chart.yaml")
+	}
+
+	return md, nil
+}
+
+// fetchLocalChart returns the archive of an indexed chart. Chart directories
+// are archived on the fly.
+func (dc *DirClient) fetchLocalChart(name string) ([]byte, error) {
+	_, charts, err := dc.index()
+	if err != nil {
+		return nil, err
+	}
+
+	lc, ok := charts[name]
+	if !ok {
+		return nil, errors.Errorf("chart %s not found in %s", name, dc.dir)
+	}
+
+	if !lc.isDir {
+		return afero.ReadFile(dc.fs, lc.path)
+	}
+
+	return dc.archiveChartDir(lc.path)
+}
+
+// archiveChartDir creates a chart archive from a chart directory. Like
+// `helm package`, files are stored below a directory named after the chart.
+func (dc *DirClient) archiveChartDir(dir string) ([]byte, error) {
+	b, err := afero.ReadFile(dc.fs, filepath.Join(dir, "Chart.yaml"))
+	if err != nil {
+		return nil, err
+	}
+	md, err := parseChartMetadata(b)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*archive.File
+	err = afero.Walk(dc.fs, dir, func(p string, fi os.FileInfo, err error) error {
+		if err != nil || fi.IsDir() {
+			return err
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+
+		data, err := afero.ReadFile(dc.fs, p)
+		if err != nil {
+			return err
+		}
+
+		files = append(files, &archive.File{
+			Name:   path.Join(md.Name, filepath.ToSlash(rel)),
+			Reader: bytes.NewReader(data),
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading chart directory %s", dir)
+	}
+
+	var buf bytes.Buffer
+	var tgz archive.Tgz
+	if err := tgz.Archive(&buf, files); err != nil {
+		return nil, errors.Wrapf(err, "archiving chart directory %s", dir)
+	}
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,162 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package helm
+
+import (
+	"bytes"
+	"io/ioutil"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/util/archive"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withDirClient(t *testing.T, fn func(*DirClient)) {
+	fs := afero.NewMemMapFs()
+
+	index, err := ioutil.ReadFile("testdata/index.yaml")
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "/mirror/index.yaml", index, 0644))
+	require.NoError(t, afero.WriteFile(fs, "/mirror/argo-ci-0.1.1.tgz", []byte("chart"), 0644))
+
+	fn(NewDirClient(fs, "/mirror"))
+}
+
+func TestDirClient_Chart(t *testing.T) {
+	withDirClient(t, func(dc *DirClient) {
+		chart, err := dc.Chart("argo-ci", "")
+		require.NoError(t, err)
+		assert.Equal(t, "0.1.1", chart.Version)
+
+		chart, err = dc.Chart("argo-ci", "0.1.1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"charts/argo-ci-0.1.1.tgz"}, chart.URLs)
+
+		_, err = dc.Chart("argo-ci", "9.9.9")
+		require.Error(t, err)
+
+		_, err = dc.Chart("missing", "")
+		require.Error(t, err)
+	})
+}
+
+func TestDirClient_Fetch(t *testing.T) {
+	withDirClient(t, func(dc *DirClient) {
+		for _, uri := range []string{"charts/argo-ci-0.1.1.tgz", "https://example.com/charts/argo-ci-0.1.1.tgz"} {
+			r, err := dc.Fetch(uri)
+			require.NoError(t, err)
+
+			b, err := ioutil.ReadAll(r)
+			require.NoError(t, err)
+			assert.Equal(t, "chart", string(b))
+			require.NoError(t, r.Close())
+		}
+
+		_, err := dc.Fetch("missing-0.1.0.tgz")
+		require.Error(t, err)
+	})
+}
+
+func TestDirClient_Repository_missing_index(t *testing.T) {
+	dc := NewDirClient(afero.NewMemMapFs(), "/mirror")
+	_, err := dc.Repository()
+	require.Error(t, err)
+}
+
+func withLocalCharts(t *testing.T, fn func(*DirClient)) {
+	fs := afero.NewMemMapFs()
+
+	require.NoError(t, afero.WriteFile(fs, "/charts/mychart/Chart.yaml",
+		[]byte("name: mychart\nversion: 0.1.0\ndescription: My chart\n"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/charts/mychart/templates/service.yaml", []byte("kind: Service"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/charts/notes/README.md", []byte("not a chart"), 0644))
+
+	var buf bytes.Buffer
+	var tgz archive.Tgz
+	err := tgz.Archive(&buf, []*archive.File{
+		{Name: "redis/Chart.yaml", Reader: strings.NewReader("name: redis\nversion: 3.2.0\n")},
+		{Name: "redis/values.yaml", Reader: strings.NewReader("")},
+	})
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, "/charts/redis.tgz", buf.Bytes(), 0644))
+
+	fn(NewDirClient(fs, "/charts"))
+}
+
+func TestDirClient_Repository_local_charts(t *testing.T) {
+	withLocalCharts(t, func(dc *DirClient) {
+		repo, err := dc.Repository()
+		require.NoError(t, err)
+
+		expected := map[string][]RepositoryChart{
+			"mychart": {{Name: "mychart", Version: "0.1.0", Description: "My chart", URLs: []string{"mychart-0.1.0.tgz"}}},
+			"redis":   {{Name: "redis", Version: "3.2.0", URLs: []string{"redis-3.2.0.tgz"}}},
+		}
+		assert.Equal(t, expected, repo.Charts)
+	})
+}
+
+func TestDirClient_Fetch_local_charts(t *testing.T) {
+	withLocalCharts(t, func(dc *DirClient) {
+		cases := []struct {
+			uri      string
+			expected []string
+		}{
+			{
+				uri:      "mychart-0.1.0.tgz",
+				expected: []string{"mychart/Chart.yaml", "mychart/templates/service.yaml"},
+			},
+			{
+				uri:      "redis-3.2.0.tgz",
+				expected: []string{"redis/Chart.yaml", "redis/values.yaml"},
+			},
+		}
+
+		for _, tc := range cases {
+			t.Run(tc.uri, func(t *testing.T) {
+				r, err := dc.Fetch(tc.uri)
+				require.NoError(t, err)
+				defer r.Close()
+
+				var got []string
+				var tgz archive.Tgz
+				err = tgz.Unarchive(r, func(f *archive.File) error {
+					got = append(got, f.Name)
+					return nil
+				})
+				require.NoError(t, err)
+
+				sort.Strings(got)
+				assert.Equal(t, tc.expected, got)
+			})
+		}
+
+		_, err := dc.Fetch("mychart-0.2.0.tgz")
+		require.Error(t, err)
+	})
+}
+
+func TestDirClient_Repository_invalid_chart(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/charts/mychart/Chart.yaml", []byte("name: mychart\n"), 0644))
+
+	_, err := NewDirClient(fs, "/charts").Repository()
+	require.Error(t, err)
+}
@@ -19,6 +19,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	goyaml "github.com/ghodss/yaml"
@@ -140,7 +141,7 @@ func (r *Renderer) Render(repoName, chartName, chartVersion, componentName strin
 		return nil, err
 	}
 
-	rendered, err := r.renderWithHelm(componentName, string(b), chartPath)
+	rendered, err := r.renderWithHelm(componentName, string(b), repoName, chartPath)
 	if err != nil {
 		return nil, errors.Wrap(err, "rendering Helm chart")
 	}
@@ -175,7 +176,7 @@ func (r *Renderer) Render(repoName, chartName, chartVersion, componentName strin
 	return out, nil
 }
 
-func (r *Renderer) renderWithHelm(componentName, raw, chartPath string) (map[string]string, error) {
+func (r *Renderer) renderWithHelm(componentName, raw, repoName, chartPath string) (map[string]string, error) {
 	config := &chart.Config{Raw: raw, Values: map[string]*chart.Value{}}
 
 	c, err := chartutil.LoadDir(chartPath)
@@ -184,7 +185,7 @@ func (r *Renderer) renderWithHelm(componentName, raw, chartPath string) (map[str
 	}
 
 	if req, err := chartutil.LoadRequirements(c); err == nil {
-		if err := checkDependencies(c, req); err != nil {
+		if err := r.loadVendoredDependencies(c, req, repoName); err != nil {
 			return nil, err
 		}
 	} else if err != chartutil.ErrRequirementsNotFound {
@@ -245,9 +246,72 @@ func (r *Renderer) extractChartComponents(componentName string) (*chartutil.Rele
 	return options, caps, nil
 }
 
-func checkDependencies(ch *chart.Chart, reqs *chartutil.Requirements) error {
+// loadVendoredDependencies adds dependencies listed in requirements.yaml which
+// are missing from the chart's charts/ directory from charts vendored in the
+// app. Charts vendored from repoName are preferred.
+func (r *Renderer) loadVendoredDependencies(ch *chart.Chart, reqs *chartutil.Requirements, repoName string) error {
 	missing := []string{}
 
+	for _, dep := range missingDependencies(ch, reqs) {
+		dc, err := r.vendoredChart(repoName, dep)
+		if err != nil {
+			return errors.Wrapf(err, "loading vendored dependency %s", dep.Name)
+		}
+
+		if dc == nil {
+			missing = append(missing, dep.Name)
+			continue
+		}
+
+		ch.Dependencies = append(ch.Dependencies, dc)
+	}
+
+	if len(missing) > 0 {
+		return fmt.Errorf("found in requirements.yaml, but missing in charts/ directory: %s; try reinstalling the package with `ks pkg install`", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// vendoredChart loads the newest vendored chart satisfying a dependency. It
+// returns nil if no vendored chart matches.
+func (r *Renderer) vendoredChart(repoName string, dep *chartutil.Dependency) (*chart.Chart, error) {
+	pattern := filepath.Join(r.app.Root(), "vendor", "*", dep.Name, "helm", "*", dep.Name)
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	// Group versions by the registry they were vendored from.
+	var registries []string
+	versions := make(map[string][]string)
+	for _, m := range matches {
+		versionDir := filepath.Dir(m)
+		registry := filepath.Base(filepath.Dir(filepath.Dir(filepath.Dir(versionDir))))
+		if _, ok := versions[registry]; !ok {
+			registries = append(registries, registry)
+		}
+		versions[registry] = append(versions[registry], filepath.Base(versionDir))
+	}
+
+	sort.SliceStable(registries, func(i, j int) bool {
+		return registries[i] == repoName && registries[j] != repoName
+	})
+
+	for _, registry := range registries {
+		v, err := MatchVersion(versions[registry], dep.Version)
+		if err != nil {
+			continue
+		}
+
+		return chartutil.LoadDir(filepath.Join(r.app.Root(), "vendor", registry, dep.Name, "helm", v, dep.Name))
+	}
+
+	return nil, nil
+}
+
+func missingDependencies(ch *chart.Chart, reqs *chartutil.Requirements) []*chartutil.Dependency {
+	var missing []*chartutil.Dependency
+
 	deps := ch.GetDependencies()
 	for _, r := range reqs.Dependencies {
 		found := false
@@ -258,12 +322,9 @@ func checkDependencies(ch *chart.Chart, reqs *chartutil.Requirements) error {
 			}
 		}
 		if !found {
-			missing = append(missing, r.Name)
+			missing = append(missing, r)
 		}
 	}
 
-	if len(missing) > 0 {
-		return fmt.Errorf("found in requirements.yaml, but missing in charts/ directory: %s", strings.Join(missing, ", "))
-	}
-	return nil
+	return missing
 }
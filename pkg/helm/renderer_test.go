@@ -139,3 +139,85 @@ func TestRenderer_JsonnetNativeFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestRenderer_Render_vendored_dependencies(t *testing.T) {
+	cases := []struct {
+		name          string
+		vendorRedis   bool
+		values        map[string]interface{}
+		expectedKinds []string
+		isErr         bool
+	}{
+		{
+			name:        "dependency enabled",
+			vendorRedis: true,
+			values:      map[string]interface{}{},
+		},
+		{
+			name:        "dependency disabled by condition",
+			vendorRedis: true,
+			values: map[string]interface{}{
+				"redis": map[string]interface{}{"enabled": false},
+			},
+			expectedKinds: []string{"ConfigMap"},
+		},
+		{
+			name:   "dependency not vendored",
+			values: map[string]interface{}{},
+			isErr:  true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpDir, err := ioutil.TempDir("", "TestRenderer_Render_vendored_dependencies")
+			require.NoError(t, err)
+
+			defer os.RemoveAll(tmpDir)
+
+			fs := afero.NewOsFs()
+
+			test.WithAppFs(t, tmpDir, fs, func(a *amocks.App, fs afero.Fs) {
+				test.StageDir(t, fs, "webapp", filepath.Join(a.Root(), "vendor", "charts", "webapp"))
+				if tc.vendorRedis {
+					test.StageDir(t, fs, "redis", filepath.Join(a.Root(), "vendor", "helm-stable", "redis"))
+				}
+
+				envConfig := &app.EnvironmentConfig{
+					KubernetesVersion: "v1.10.3",
+					Destination: &app.EnvironmentDestinationSpec{
+						Namespace: "Default",
+					},
+				}
+				a.On("Environment", "default").Return(envConfig, nil)
+
+				r := NewRenderer(a, "default")
+
+				got, err := r.Render("charts", "webapp", "1.0.0", "componentName", tc.values)
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+
+				require.NoError(t, err)
+
+				var kinds []string
+				for _, obj := range got {
+					m, ok := obj.(map[string]interface{})
+					require.True(t, ok)
+					if kind, ok := m["kind"].(string); ok {
+						kinds = append(kinds, kind)
+					}
+				}
+
+				if tc.expectedKinds != nil {
+					assert.Equal(t, tc.expectedKinds, kinds)
+					return
+				}
+
+				assert.Contains(t, kinds, "ConfigMap")
+				assert.Contains(t, kinds, "StatefulSet")
+			})
+		})
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package helm
+
+import (
+	"sort"
+	"strings"
+
+	msemver "github.com/Masterminds/semver"
+	"github.com/ghodss/yaml"
+	"github.com/pkg/errors"
+	"k8s.io/helm/pkg/chartutil"
+)
+
+const (
+	// RequirementsFile declares the dependencies of a chart.
+	RequirementsFile = "requirements.yaml"
+	// ChartsDir contains the dependencies of a chart.
+	ChartsDir = "charts"
+)
+
+// ParseRequirements parses the contents of a chart's requirements.yaml.
+func ParseRequirements(data []byte) (*chartutil.Requirements, error) {
+	var reqs chartutil.Requirements
+	if err := yaml.Unmarshal(data, &reqs); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling requirements.yaml")
+	}
+
+	return &reqs, nil
+}
+
+// IsLocalRepository returns true if a dependency's repository refers to a
+// chart on the local file system. These dependencies must be bundled with
+// their parent chart.
+func IsLocalRepository(repository string) bool {
+	return strings.HasPrefix(repository, "file://")
+}
+
+// RepositoryAlias returns the name of the repository a dependency refers to
+// with `@name` or `alias:name`.
+func RepositoryAlias(repository string) (string, bool) {
+	switch {
+	case strings.HasPrefix(repository, "@"):
+		return strings.TrimPrefix(repository, "@"), true
+	case strings.HasPrefix(repository, "alias:"):
+		return strings.TrimPrefix(repository, "alias:"), true
+	default:
+		return "", false
+	}
+}
+
+// MatchingChart returns the newest chart in the repository whose version
+// satisfies a version range. If the range is blank, it returns the latest.
+func (hr *Repository) MatchingChart(name, constraint string) (*RepositoryChart, error) {
+	charts, ok := hr.Charts[name]
+	if !ok {
+		return nil, errors.Errorf("chart %q was not found", name)
+	}
+
+	var versions []string
+	for _, chart := range charts {
+		versions = append(versions, chart.Version)
+	}
+
+	v, err := MatchVersion(versions, constraint)
+	if err != nil {
+		return nil, errors.Wrapf(err, "chart %q", name)
+	}
+
+	for _, chart := range charts {
+		if chart.Version == v {
+			return &chart, nil
+		}
+	}
+
+	return nil, errors.Errorf("chart %q with version %q was not found", name, v)
+}
+
+// MatchVersion returns the newest of versions which satisfies a version range.
+// Versions which are not semantic versions are ignored.
+func MatchVersion(versions []string, constraint string) (string, error) {
+	if constraint == "" {
+		constraint = "*"
+	}
+
+	c, err := msemver.NewConstraint(constraint)
+	if err != nil {
+		return "", errors.Wrapf(err, "parsing version range %q", constraint)
+	}
+
+	var matching []*msemver.Version
+	byVersion := make(map[*msemver.Version]string)
+	for _, s := range versions {
+		v, err := msemver.NewVersion(s)
+		if err != nil {
+			continue
+		}
+		if c.Check(v) {
+			matching = append(matching, v)
+			byVersion[v] = s
+		}
+	}
+
+	if len(matching) == 0 {
+		return "", errors.Errorf("no version satisfies %q", constraint)
+	}
+
+	sort.Sort(msemver.Collection(matching))
+	return byVersion[matching[len(matching)-1]], nil
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package helm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchVersion(t *testing.T) {
+	versions := []string{"3.1.0", "3.2.0", "4.0.0", "invalid"}
+
+	cases := []struct {
+		name       string
+		constraint string
+		expected   string
+		isErr      bool
+	}{
+		{name: "no constraint", expected: "4.0.0"},
+		{name: "caret range", constraint: "^3.0.0", expected: "3.2.0"},
+		{name: "exact", constraint: "3.1.0", expected: "3.1.0"},
+		{name: "no match", constraint: "^5.0.0", isErr: true},
+		{name: "invalid constraint", constraint: "not a range", isErr: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := MatchVersion(versions, tc.constraint)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestRepositoryAlias(t *testing.T) {
+	cases := []struct {
+		repo     string
+		expected string
+		ok       bool
+	}{
+		{repo: "@stable", expected: "stable", ok: true},
+		{repo: "alias:stable", expected: "stable", ok: true},
+		{repo: "https://example.com/charts"},
+		{repo: ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.repo, func(t *testing.T) {
+			got, ok := RepositoryAlias(tc.repo)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestRepository_MatchingChart(t *testing.T) {
+	repo := &Repository{
+		Charts: map[string][]RepositoryChart{
+			"redis": {
+				{Name: "redis", Version: "3.1.0"},
+				{Name: "redis", Version: "3.2.0"},
+			},
+		},
+	}
+
+	chart, err := repo.MatchingChart("redis", "~3.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "3.1.0", chart.Version)
+
+	_, err = repo.MatchingChart("mysql", "")
+	require.Error(t, err)
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// RulesDirName is the name of the directory which houses the lint rules of an
+// application.
+const RulesDirName = "lint"
+
+// userRules returns the lint rules of the application. Each Jsonnet file in the
+// rules directory is a function which returns the violation messages of an
+// object.
+func (l *Linter) userRules() ([]Rule, error) {
+	dir := filepath.Join(l.app.Root(), RulesDirName)
+
+	fis, err := afero.ReadDir(l.app.Fs(), dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "reading lint rules")
+	}
+
+	var rules []Rule
+	for _, fi := range fis {
+		if fi.IsDir() || filepath.Ext(fi.Name()) != ".jsonnet" {
+			continue
+		}
+
+		path := filepath.Join(dir, fi.Name())
+		rules = append(rules, Rule{
+			Name:        strings.TrimSuffix(fi.Name(), ".jsonnet"),
+			Description: fmt.Sprintf("Rule defined in %s/%s", RulesDirName, fi.Name()),
+			Severity:    SeverityWarning,
+			Check: func(objects []*unstructured.Unstructured) ([][]string, error) {
+				return l.evaluateRule(path, objects)
+			},
+		})
+	}
+
+	return rules, nil
+}
+
+// evaluateRule applies the rule function in path to each object.
+func (l *Linter) evaluateRule(path string, objects []*unstructured.Unstructured) ([][]string, error) {
+	source, err := afero.ReadFile(l.app.Fs(), path)
+	if err != nil {
+		return nil, err
+	}
+
+	objectsData, err := json.Marshal(objects)
+	if err != nil {
+		return nil, errors.Wrap(err, "encoding objects")
+	}
+
+	vm, cleanup, err := l.vmFn(l.app, l.envName)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	vm.ExtCode(env.ObjectsExtCodeKey, string(objectsData))
+
+	// the rule starts on the first line to keep the positions in errors.
+	snippet := fmt.Sprintf("local rule = (%s\n);\n[rule(object) for object in std.extVar(%q)]",
+		source, env.ObjectsExtCodeKey)
+
+	out, err := vm.EvaluateSnippet(path, snippet)
+	if err != nil {
+		return nil, err
+	}
+
+	var results [][]string
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		return nil, errors.New("rule must be a function which returns an array of violation messages")
+	}
+
+	return results, nil
+}
@@ -0,0 +1,310 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+// Package lint checks the objects of an environment against policy rules.
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/component"
+	"github.com/ksonnet/ksonnet/pkg/env"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/pipeline"
+	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// Severity is the severity of a rule violation.
+type Severity string
+
+const (
+	// SeverityError is the severity of violations which fail linting.
+	SeverityError Severity = "error"
+	// SeverityWarning is the severity of violations which should be fixed.
+	SeverityWarning Severity = "warning"
+	// SeverityInfo is the severity of informational violations.
+	SeverityInfo Severity = "info"
+	// SeverityOff turns a rule off.
+	SeverityOff Severity = "off"
+)
+
+// ParseSeverity parses a severity.
+func ParseSeverity(s string) (Severity, error) {
+	switch sev := Severity(s); sev {
+	case SeverityError, SeverityWarning, SeverityInfo, SeverityOff:
+		return sev, nil
+	default:
+		return "", errors.Errorf("unknown severity %q; valid severities are error, warning, info and off", s)
+	}
+}
+
+// Rule is a lint rule.
+type Rule struct {
+	// Name is the name of the rule.
+	Name string
+	// Description describes what the rule checks.
+	Description string
+	// Severity is the severity of violations of the rule, unless it is
+	// configured otherwise.
+	Severity Severity
+	// Check returns the violation messages of each object, in the order of the
+	// objects.
+	Check func(objects []*unstructured.Unstructured) ([][]string, error)
+}
+
+// Violation is a violation of a rule by an object.
+type Violation struct {
+	Rule      string   `json:"rule"`
+	Severity  Severity `json:"severity"`
+	Component string   `json:"component,omitempty"`
+	Source    string   `json:"source,omitempty"`
+	Kind      string   `json:"kind"`
+	Namespace string   `json:"namespace,omitempty"`
+	Name      string   `json:"name"`
+	Message   string   `json:"message"`
+}
+
+// ObjectName returns the name of the object of a violation, qualified by its
+// kind and namespace.
+func (v *Violation) ObjectName() string {
+	if v.Namespace == "" {
+		return fmt.Sprintf("%s/%s", v.Kind, v.Name)
+	}
+
+	return fmt.Sprintf("%s/%s/%s", v.Kind, v.Namespace, v.Name)
+}
+
+// Linter checks the objects of an environment against the built-in rules and
+// the rules of the application.
+type Linter struct {
+	app     app.App
+	envName string
+
+	objectsFn func(a app.App, envName string) ([]*unstructured.Unstructured, error)
+	vmFn      func(a app.App, envName string) (*jsonnet.VM, func() error, error)
+	sourceFn  func(a app.App, componentName string) string
+}
+
+// New creates an instance of Linter.
+func New(a app.App, envName string) *Linter {
+	return &Linter{
+		app:     a,
+		envName: envName,
+
+		objectsFn: func(a app.App, envName string) ([]*unstructured.Unstructured, error) {
+			return pipeline.New(a, envName).Objects(nil)
+		},
+		vmFn: func(a app.App, envName string) (*jsonnet.VM, func() error, error) {
+			return env.VM(a, envName)
+		},
+		sourceFn: componentSource,
+	}
+}
+
+// Rules returns the rules checked in the environment, with their configured
+// severities. Rules which are turned off are included.
+func (l *Linter) Rules() ([]Rule, error) {
+	config, err := l.config()
+	if err != nil {
+		return nil, err
+	}
+
+	rules := builtinRules(config)
+
+	userRules, err := l.userRules()
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool)
+	for _, r := range rules {
+		names[r.Name] = true
+	}
+
+	for _, r := range userRules {
+		if names[r.Name] {
+			return nil, errors.Errorf("lint rule %q conflicts with a built-in rule", r.Name)
+		}
+		names[r.Name] = true
+		rules = append(rules, r)
+	}
+
+	for name, s := range config.Rules {
+		if !names[name] {
+			log.Warnf("Lint configuration sets the severity of unknown rule %q", name)
+			continue
+		}
+
+		sev, err := ParseSeverity(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "lint rule %q", name)
+		}
+
+		for i := range rules {
+			if rules[i].Name == name {
+				rules[i].Severity = sev
+			}
+		}
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].Name < rules[j].Name
+	})
+
+	return rules, nil
+}
+
+// config returns the lint configuration of the application, overridden by the
+// configuration of the environment.
+func (l *Linter) config() (*app.LintConfig, error) {
+	config, err := l.app.LintConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	e, err := l.app.Environment(l.envName)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := &app.LintConfig{
+		Rules:          make(map[string]string),
+		RequiredLabels: config.RequiredLabels,
+	}
+
+	for k, v := range config.Rules {
+		merged.Rules[k] = v
+	}
+
+	if e.Lint != nil {
+		for k, v := range e.Lint.Rules {
+			merged.Rules[k] = v
+		}
+		if len(e.Lint.RequiredLabels) > 0 {
+			merged.RequiredLabels = e.Lint.RequiredLabels
+		}
+	}
+
+	return merged, nil
+}
+
+// Lint checks the objects of the environment. Violations are sorted by object
+// and rule.
+func (l *Linter) Lint(rules []Rule) ([]Violation, error) {
+	objects, err := l.objectsFn(l.app, l.envName)
+	if err != nil {
+		return nil, err
+	}
+
+	sources := make(map[string]string)
+
+	var violations []Violation
+	for _, rule := range rules {
+		if rule.Severity == SeverityOff {
+			continue
+		}
+
+		log.WithField("env-name", l.envName).Debugf("checking lint rule %s", rule.Name)
+
+		results, err := rule.Check(objects)
+		if err != nil {
+			return nil, errors.Wrapf(err, "checking lint rule %q", rule.Name)
+		}
+
+		for i, obj := range objects {
+			if i >= len(results) || isIgnored(obj, rule.Name) {
+				continue
+			}
+
+			componentName := obj.GetLabels()[metadata.LabelComponent]
+			if _, ok := sources[componentName]; !ok && componentName != "" {
+				sources[componentName] = l.sourceFn(l.app, componentName)
+			}
+
+			for _, message := range results[i] {
+				violations = append(violations, Violation{
+					Rule:      rule.Name,
+					Severity:  rule.Severity,
+					Component: componentName,
+					Source:    sources[componentName],
+					Kind:      obj.GetKind(),
+					Namespace: obj.GetNamespace(),
+					Name:      obj.GetName(),
+					Message:   message,
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(violations, func(i, j int) bool {
+		a, b := violations[i], violations[j]
+		switch {
+		case a.Component != b.Component:
+			return a.Component < b.Component
+		case a.Kind != b.Kind:
+			return a.Kind < b.Kind
+		case a.Namespace != b.Namespace:
+			return a.Namespace < b.Namespace
+		case a.Name != b.Name:
+			return a.Name < b.Name
+		default:
+			return a.Rule < b.Rule
+		}
+	})
+
+	return violations, nil
+}
+
+// isIgnored returns true if the lint ignore annotation of an object lists a
+// rule.
+func isIgnored(obj *unstructured.Unstructured, ruleName string) bool {
+	value, ok := obj.GetAnnotations()[metadata.AnnotationLintIgnore]
+	if !ok {
+		return false
+	}
+
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "*" || name == ruleName {
+			return true
+		}
+	}
+
+	return false
+}
+
+// componentSource returns the path of the file a component is defined in,
+// relative to the application root. It is blank if the file can't be found.
+func componentSource(a app.App, componentName string) string {
+	path, err := component.Path(a, strings.Replace(componentName, ".", "/", -1))
+	if err != nil {
+		log.Debugf("finding source of component %q: %v", componentName, err)
+		return ""
+	}
+
+	rel, err := filepath.Rel(a.Root(), path)
+	if err != nil {
+		return path
+	}
+
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,247 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package lint
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	appmocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/metadata"
+	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func withLinter(t *testing.T, appConfig, envConfig *app.LintConfig, fn func(*Linter, afero.Fs)) {
+	fs := afero.NewMemMapFs()
+	a := &appmocks.App{}
+	a.On("Fs").Return(fs)
+	a.On("Root").Return("/app")
+	a.On("LintConfig").Return(appConfig, nil)
+	a.On("Environment", "default").Return(&app.EnvironmentConfig{Name: "default", Lint: envConfig}, nil)
+
+	l := New(a, "default")
+	l.vmFn = func(app.App, string) (*jsonnet.VM, func() error, error) {
+		return jsonnet.NewVM(), func() error { return nil }, nil
+	}
+	l.sourceFn = func(_ app.App, componentName string) string {
+		return "components/" + componentName + ".jsonnet"
+	}
+
+	fn(l, fs)
+}
+
+func severities(rules []Rule) map[string]Severity {
+	m := make(map[string]Severity)
+	for _, r := range rules {
+		m[r.Name] = r.Severity
+	}
+
+	return m
+}
+
+func TestLinter_Rules(t *testing.T) {
+	appConfig := &app.LintConfig{
+		Rules: map[string]string{
+			RuleLatestImage: "error",
+			RuleProbes:      "off",
+			"unknown":       "error",
+		},
+	}
+	envConfig := &app.LintConfig{
+		Rules: map[string]string{
+			RuleLatestImage: "info",
+			"team-owner":    "error",
+		},
+	}
+
+	withLinter(t, appConfig, envConfig, func(l *Linter, fs afero.Fs) {
+		require.NoError(t, afero.WriteFile(fs, "/app/lint/team-owner.jsonnet", []byte("function(object) []"), 0644))
+		require.NoError(t, afero.WriteFile(fs, "/app/lint/README.md", []byte("not a rule"), 0644))
+
+		rules, err := l.Rules()
+		require.NoError(t, err)
+
+		expected := map[string]Severity{
+			RuleHostPath:         SeverityError,
+			RuleLatestImage:      SeverityInfo,
+			RuleProbes:           SeverityOff,
+			RuleRequiredLabels:   SeverityWarning,
+			RuleResourceRequests: SeverityWarning,
+			"team-owner":         SeverityError,
+		}
+		assert.Equal(t, expected, severities(rules))
+		assert.Equal(t, RuleHostPath, rules[0].Name)
+		assert.Equal(t, "team-owner", rules[len(rules)-1].Name)
+	})
+}
+
+func TestLinter_Rules_errors(t *testing.T) {
+	cases := []struct {
+		name      string
+		appConfig *app.LintConfig
+		ruleFile  string
+	}{
+		{
+			name:      "invalid severity",
+			appConfig: &app.LintConfig{Rules: map[string]string{RuleProbes: "fatal"}},
+		},
+		{
+			name:      "user rule named like a built-in rule",
+			appConfig: &app.LintConfig{},
+			ruleFile:  "/app/lint/probes.jsonnet",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withLinter(t, tc.appConfig, nil, func(l *Linter, fs afero.Fs) {
+				if tc.ruleFile != "" {
+					require.NoError(t, afero.WriteFile(fs, tc.ruleFile, []byte("function(object) []"), 0644))
+				}
+
+				_, err := l.Rules()
+				require.Error(t, err)
+			})
+		})
+	}
+}
+
+func TestLinter_Lint(t *testing.T) {
+	appConfig := &app.LintConfig{
+		Rules: map[string]string{
+			RuleProbes:           "off",
+			RuleResourceRequests: "off",
+			RuleHostPath:         "off",
+		},
+		RequiredLabels: []string{"team"},
+	}
+
+	withLinter(t, appConfig, nil, func(l *Linter, fs afero.Fs) {
+		rule := `function(object)
+  if object.kind == "Service" && !std.objectHas(object.metadata, "namespace") then
+    ["services must set a namespace"]
+  else
+    []
+`
+		require.NoError(t, afero.WriteFile(fs, "/app/lint/service-namespace.jsonnet", []byte(rule), 0644))
+
+		service := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "v1",
+				"kind":       "Service",
+				"metadata": map[string]interface{}{
+					"name": "web",
+					"labels": map[string]interface{}{
+						metadata.LabelComponent: "web",
+					},
+				},
+			},
+		}
+
+		container := compliantContainer()
+		container["image"] = "nginx"
+		web := deployment(container)
+		web.SetLabels(map[string]string{metadata.LabelComponent: "web"})
+		web.SetNamespace("prod")
+
+		ignored := deployment(container)
+		ignored.SetName("ignored")
+		ignored.SetAnnotations(map[string]string{metadata.AnnotationLintIgnore: "required-labels, latest-image"})
+
+		l.objectsFn = func(app.App, string) ([]*unstructured.Unstructured, error) {
+			return []*unstructured.Unstructured{service, ignored, web}, nil
+		}
+
+		rules, err := l.Rules()
+		require.NoError(t, err)
+
+		violations, err := l.Lint(rules)
+		require.NoError(t, err)
+
+		expected := []Violation{
+			{
+				Rule:      RuleLatestImage,
+				Severity:  SeverityWarning,
+				Component: "web",
+				Source:    "components/web.jsonnet",
+				Kind:      "Deployment",
+				Namespace: "prod",
+				Name:      "web",
+				Message:   `container "web" uses image "nginx", which is not pinned to a tag`,
+			},
+			{
+				Rule:      RuleRequiredLabels,
+				Severity:  SeverityWarning,
+				Component: "web",
+				Source:    "components/web.jsonnet",
+				Kind:      "Deployment",
+				Namespace: "prod",
+				Name:      "web",
+				Message:   `label "team" is missing`,
+			},
+			{
+				Rule:      RuleRequiredLabels,
+				Severity:  SeverityWarning,
+				Component: "web",
+				Source:    "components/web.jsonnet",
+				Kind:      "Service",
+				Name:      "web",
+				Message:   `label "team" is missing`,
+			},
+			{
+				Rule:      "service-namespace",
+				Severity:  SeverityWarning,
+				Component: "web",
+				Source:    "components/web.jsonnet",
+				Kind:      "Service",
+				Name:      "web",
+				Message:   "services must set a namespace",
+			},
+		}
+		require.Equal(t, expected, violations)
+
+		var buf bytes.Buffer
+		require.NoError(t, WriteSARIF(&buf, rules[:1], violations[2:]))
+
+		b, err := ioutil.ReadFile(filepath.Join("testdata", "lint.sarif"))
+		require.NoError(t, err)
+		require.Equal(t, string(b), buf.String())
+	})
+}
+
+func TestLinter_Lint_invalid_user_rule(t *testing.T) {
+	withLinter(t, &app.LintConfig{}, nil, func(l *Linter, fs afero.Fs) {
+		require.NoError(t, afero.WriteFile(fs, "/app/lint/invalid.jsonnet", []byte(`function(object) "invalid"`), 0644))
+
+		l.objectsFn = func(app.App, string) ([]*unstructured.Unstructured, error) {
+			return []*unstructured.Unstructured{deployment(compliantContainer())}, nil
+		}
+
+		rules, err := l.Rules()
+		require.NoError(t, err)
+
+		_, err = l.Lint(rules)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `checking lint rule "invalid"`)
+	})
+}
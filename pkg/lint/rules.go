@@ -0,0 +1,215 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// RuleHostPath is the name of the rule which forbids hostPath volumes.
+	RuleHostPath = "host-path"
+	// RuleLatestImage is the name of the rule which forbids images which are
+	// not pinned to a tag or digest.
+	RuleLatestImage = "latest-image"
+	// RuleProbes is the name of the rule which requires readiness and liveness
+	// probes for long-running containers.
+	RuleProbes = "probes"
+	// RuleRequiredLabels is the name of the rule which requires the labels
+	// configured with `requiredLabels`.
+	RuleRequiredLabels = "required-labels"
+	// RuleResourceRequests is the name of the rule which requires containers
+	// to request cpu and memory.
+	RuleResourceRequests = "resource-requests"
+)
+
+// builtinRules returns the built-in rules.
+func builtinRules(config *app.LintConfig) []Rule {
+	return []Rule{
+		objectRule(RuleHostPath, "Pods must not mount hostPath volumes", SeverityError, checkHostPath),
+		objectRule(RuleLatestImage, "Container images must be pinned to a tag other than latest, or a digest", SeverityWarning, checkLatestImage),
+		objectRule(RuleProbes, "Containers of workloads must have readiness and liveness probes", SeverityWarning, checkProbes),
+		objectRule(RuleRequiredLabels, "Objects must have the labels configured with requiredLabels", SeverityWarning,
+			func(obj *unstructured.Unstructured) []string {
+				return checkRequiredLabels(obj, config.RequiredLabels)
+			}),
+		objectRule(RuleResourceRequests, "Containers must request cpu and memory", SeverityWarning, checkResourceRequests),
+	}
+}
+
+// objectRule creates a rule which checks objects one by one.
+func objectRule(name, description string, severity Severity, fn func(*unstructured.Unstructured) []string) Rule {
+	return Rule{
+		Name:        name,
+		Description: description,
+		Severity:    severity,
+		Check: func(objects []*unstructured.Unstructured) ([][]string, error) {
+			results := make([][]string, len(objects))
+			for i, obj := range objects {
+				results[i] = fn(obj)
+			}
+
+			return results, nil
+		},
+	}
+}
+
+func checkHostPath(obj *unstructured.Unstructured) []string {
+	spec := podSpec(obj)
+	if spec == nil {
+		return nil
+	}
+
+	var messages []string
+	for _, volume := range mapSlice(spec["volumes"]) {
+		hostPath, ok := volume["hostPath"].(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		messages = append(messages, fmt.Sprintf("volume %q mounts host path %q", volume["name"], hostPath["path"]))
+	}
+
+	return messages
+}
+
+func checkLatestImage(obj *unstructured.Unstructured) []string {
+	var messages []string
+	for _, container := range containers(obj, true) {
+		image, _ := container["image"].(string)
+		if image == "" || strings.Contains(image, "@") {
+			continue
+		}
+
+		// a colon after the last slash separates the tag; earlier colons
+		// separate registry ports.
+		name := image[strings.LastIndex(image, "/")+1:]
+		i := strings.LastIndex(name, ":")
+
+		switch {
+		case i == -1:
+			messages = append(messages, fmt.Sprintf("container %q uses image %q, which is not pinned to a tag", container["name"], image))
+		case name[i+1:] == "latest":
+			messages = append(messages, fmt.Sprintf("container %q uses image %q with the latest tag", container["name"], image))
+		}
+	}
+
+	return messages
+}
+
+func checkProbes(obj *unstructured.Unstructured) []string {
+	switch obj.GetKind() {
+	case "Deployment", "DaemonSet", "ReplicaSet", "ReplicationController", "StatefulSet":
+	default:
+		return nil
+	}
+
+	var messages []string
+	for _, container := range containers(obj, false) {
+		for _, probe := range []string{"readinessProbe", "livenessProbe"} {
+			if _, ok := container[probe]; !ok {
+				messages = append(messages, fmt.Sprintf("container %q does not have a %s", container["name"], probe))
+			}
+		}
+	}
+
+	return messages
+}
+
+func checkRequiredLabels(obj *unstructured.Unstructured, required []string) []string {
+	labels := obj.GetLabels()
+
+	var messages []string
+	for _, label := range required {
+		if _, ok := labels[label]; !ok {
+			messages = append(messages, fmt.Sprintf("label %q is missing", label))
+		}
+	}
+
+	return messages
+}
+
+func checkResourceRequests(obj *unstructured.Unstructured) []string {
+	var messages []string
+	for _, container := range containers(obj, true) {
+		resources, _ := container["resources"].(map[string]interface{})
+		requests, _ := resources["requests"].(map[string]interface{})
+
+		for _, resource := range []string{"cpu", "memory"} {
+			if _, ok := requests[resource]; !ok {
+				messages = append(messages, fmt.Sprintf("container %q does not request %s", container["name"], resource))
+			}
+		}
+	}
+
+	return messages
+}
+
+// podSpec returns the spec of the pods of an object. It is nil if the object
+// does not create pods.
+func podSpec(obj *unstructured.Unstructured) map[string]interface{} {
+	var fields []string
+	switch obj.GetKind() {
+	case "Pod":
+		fields = []string{"spec"}
+	case "Deployment", "DaemonSet", "Job", "ReplicaSet", "ReplicationController", "StatefulSet":
+		fields = []string{"spec", "template", "spec"}
+	case "CronJob":
+		fields = []string{"spec", "jobTemplate", "spec", "template", "spec"}
+	default:
+		return nil
+	}
+
+	spec, ok, err := unstructured.NestedMap(obj.Object, fields...)
+	if err != nil || !ok {
+		return nil
+	}
+
+	return spec
+}
+
+// containers returns the containers of the pods of an object, optionally
+// including init containers.
+func containers(obj *unstructured.Unstructured, withInit bool) []map[string]interface{} {
+	spec := podSpec(obj)
+	if spec == nil {
+		return nil
+	}
+
+	containers := mapSlice(spec["containers"])
+	if withInit {
+		containers = append(containers, mapSlice(spec["initContainers"])...)
+	}
+
+	return containers
+}
+
+func mapSlice(v interface{}) []map[string]interface{} {
+	items, _ := v.([]interface{})
+
+	var maps []map[string]interface{}
+	for _, item := range items {
+		if m, ok := item.(map[string]interface{}); ok {
+			maps = append(maps, m)
+		}
+	}
+
+	return maps
+}
@@ -0,0 +1,162 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package lint
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func deployment(container map[string]interface{}, volumes ...interface{}) *unstructured.Unstructured {
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "apps/v1",
+			"kind":       "Deployment",
+			"metadata": map[string]interface{}{
+				"name":   "web",
+				"labels": map[string]interface{}{"app": "web"},
+			},
+			"spec": map[string]interface{}{
+				"template": map[string]interface{}{
+					"spec": map[string]interface{}{
+						"containers": []interface{}{container},
+						"volumes":    volumes,
+					},
+				},
+			},
+		},
+	}
+}
+
+func compliantContainer() map[string]interface{} {
+	return map[string]interface{}{
+		"name":  "web",
+		"image": "registry.example.com:5000/web:1.0",
+		"resources": map[string]interface{}{
+			"requests": map[string]interface{}{"cpu": "100m", "memory": "64Mi"},
+		},
+		"readinessProbe": map[string]interface{}{},
+		"livenessProbe":  map[string]interface{}{},
+	}
+}
+
+func Test_builtinRules(t *testing.T) {
+	config := &app.LintConfig{RequiredLabels: []string{"app", "team"}}
+
+	withoutProbes := compliantContainer()
+	delete(withoutProbes, "livenessProbe")
+
+	withoutRequests := compliantContainer()
+	withoutRequests["resources"] = map[string]interface{}{
+		"requests": map[string]interface{}{"cpu": "100m"},
+	}
+
+	cases := []struct {
+		name     string
+		rule     string
+		obj      *unstructured.Unstructured
+		expected []string
+	}{
+		{
+			name: "host path",
+			rule: RuleHostPath,
+			obj: deployment(compliantContainer(), map[string]interface{}{
+				"name":     "docker",
+				"hostPath": map[string]interface{}{"path": "/var/run/docker.sock"},
+			}),
+			expected: []string{`volume "docker" mounts host path "/var/run/docker.sock"`},
+		},
+		{
+			name: "no host path",
+			rule: RuleHostPath,
+			obj: deployment(compliantContainer(), map[string]interface{}{
+				"name":     "cache",
+				"emptyDir": map[string]interface{}{},
+			}),
+		},
+		{
+			name: "latest image",
+			rule: RuleLatestImage,
+			obj: deployment(map[string]interface{}{
+				"name":  "web",
+				"image": "nginx:latest",
+			}),
+			expected: []string{`container "web" uses image "nginx:latest" with the latest tag`},
+		},
+		{
+			name: "untagged image",
+			rule: RuleLatestImage,
+			obj: deployment(map[string]interface{}{
+				"name":  "web",
+				"image": "registry.example.com:5000/nginx",
+			}),
+			expected: []string{`container "web" uses image "registry.example.com:5000/nginx", which is not pinned to a tag`},
+		},
+		{
+			name: "image digest",
+			rule: RuleLatestImage,
+			obj: deployment(map[string]interface{}{
+				"name":  "web",
+				"image": "nginx@sha256:abc",
+			}),
+		},
+		{
+			name:     "probes",
+			rule:     RuleProbes,
+			obj:      deployment(withoutProbes),
+			expected: []string{`container "web" does not have a livenessProbe`},
+		},
+		{
+			name:     "required labels",
+			rule:     RuleRequiredLabels,
+			obj:      deployment(compliantContainer()),
+			expected: []string{`label "team" is missing`},
+		},
+		{
+			name:     "resource requests",
+			rule:     RuleResourceRequests,
+			obj:      deployment(withoutRequests),
+			expected: []string{`container "web" does not request memory`},
+		},
+		{
+			name: "object without pods",
+			rule: RuleResourceRequests,
+			obj: &unstructured.Unstructured{
+				Object: map[string]interface{}{"kind": "Service"},
+			},
+		},
+	}
+
+	rules := make(map[string]Rule)
+	for _, r := range builtinRules(config) {
+		rules[r.Name] = r
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r, ok := rules[tc.rule]
+			require.True(t, ok)
+
+			results, err := r.Check([]*unstructured.Unstructured{tc.obj})
+			require.NoError(t, err)
+			require.Len(t, results, 1)
+			require.Equal(t, tc.expected, results[0])
+		})
+	}
+}
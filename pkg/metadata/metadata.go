@@ -29,6 +29,14 @@ const (
 	// AnnotationManaged annotation holds the pristine object.
 	AnnotationManaged = "ksonnet.io/managed"
 
+	// AnnotationDependsOn annotation lists the components, separated by
+	// commas, that the component of an object depends on.
+	AnnotationDependsOn = "ksonnet.io/depends-on"
+
+	// AnnotationLintIgnore annotation lists the lint rules, separated by
+	// commas, that are not checked for an object. `*` ignores every rule.
+	AnnotationLintIgnore = "ksonnet.io/lint-ignore"
+
 	// LabelDeployManager label signifies an object is deployed with ksonnet.
 	LabelDeployManager = "app.kubernetes.io/deploy-manager"
 
@@ -17,13 +17,8 @@ package openapi
 
 import (
 	"fmt"
-	"path/filepath"
 	"strings"
 
-	"github.com/go-openapi/spec"
-	"github.com/go-openapi/strfmt"
-	"github.com/go-openapi/validate"
-	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/kubespec"
 	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -36,44 +31,12 @@ var (
 
 // ValidateAgainstSchema validates a document against the schema.
 func ValidateAgainstSchema(a app.App, obj *unstructured.Unstructured, envName string) []error {
-	v := newValidateAgainstSchema()
-	return v.run(a, obj, envName)
-}
-
-type validateAgainstSchema struct {
-	definitionName func(*unstructured.Unstructured) (string, error)
-	loadSchema     func(app.App, string, string) (*spec.Schema, error)
-	validate       func(*spec.Schema, interface{}, strfmt.Registry) error
-}
-
-func newValidateAgainstSchema() *validateAgainstSchema {
-	return &validateAgainstSchema{
-		definitionName: definitionName,
-		loadSchema:     loadSchema,
-		validate:       validate.AgainstSchema,
-	}
-}
-
-func (v *validateAgainstSchema) run(a app.App, obj *unstructured.Unstructured, envName string) []error {
-	name, err := v.definitionName(obj)
+	v, err := NewValidator(a, envName)
 	if err != nil {
-		if err == errUnsupportedDefinition {
-			return nil
-		}
-
-		return []error{err}
-	}
-
-	schema, err := v.loadSchema(a, name, envName)
-	if err != nil {
-		return []error{err}
-	}
-
-	if err := v.validate(schema, obj.Object, strfmt.Default); err != nil {
 		return []error{err}
 	}
 
-	return nil
+	return v.Validate(obj)
 }
 
 func definitionName(obj *unstructured.Unstructured) (string, error) {
@@ -108,30 +71,3 @@ func definitionName(obj *unstructured.Unstructured) (string, error) {
 
 	return name, nil
 }
-
-func loadSchema(a app.App, name, envName string) (*spec.Schema, error) {
-	libPath, err := a.LibPath(envName)
-	if err != nil {
-		return nil, err
-	}
-
-	schemaPath := filepath.Join(libPath, "swagger.json")
-	apiSpec, _, err := kubespec.Import(schemaPath)
-	if err != nil {
-		return nil, err
-	}
-
-	schema, ok := apiSpec.Definitions[name]
-	if !ok {
-		return nil, errors.Errorf("unable to find definition for %s", name)
-	}
-
-	options := &spec.ExpandOptions{
-		RelativeBase: schemaPath,
-	}
-	if err := spec.ExpandSchemaWithBasePath(&schema, nil, options); err != nil {
-		return nil, err
-	}
-
-	return &schema, nil
-}
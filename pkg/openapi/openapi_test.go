@@ -18,9 +18,6 @@ package openapi
 import (
 	"testing"
 
-	"github.com/go-openapi/spec"
-	"github.com/go-openapi/strfmt"
-	"github.com/ksonnet/ksonnet/pkg/app"
 	"github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/util/test"
 	"github.com/spf13/afero"
@@ -30,38 +27,26 @@ import (
 
 func TestValidateAgainstSchema(t *testing.T) {
 	test.WithApp(t, "/", func(a *mocks.App, fs afero.Fs) {
+		test.StageFile(t, fs, "swagger.json", "/lib/v1.8.7/swagger.json")
+
 		obj := &unstructured.Unstructured{
 			Object: map[string]interface{}{
 				"apiVersion": "v1",
 				"kind":       "Service",
+				"metadata": map[string]interface{}{
+					"name": "web",
+				},
+				"spec": map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{"port": "80"},
+					},
+				},
 			},
 		}
 
-		stubbedDefinitionName := func(*unstructured.Unstructured) (string, error) {
-			return "name", nil
-		}
-
-		schema := &spec.Schema{}
-		stubbedLoadSchema := func(app.App, string, string) (*spec.Schema, error) {
-			return schema, nil
-		}
-
-		stubbedValidate := func(s *spec.Schema, data interface{}, formats strfmt.Registry) error {
-			require.Equal(t, schema, s)
-			require.Equal(t, obj.Object, data)
-			require.Equal(t, strfmt.Default, formats)
-
-			return nil
-		}
-
-		v := validateAgainstSchema{
-			definitionName: stubbedDefinitionName,
-			loadSchema:     stubbedLoadSchema,
-			validate:       stubbedValidate,
-		}
-
-		errs := v.run(a, obj, "default")
-		require.Nil(t, errs)
+		errs := ValidateAgainstSchema(a, obj, "default")
+		require.Len(t, errs, 1)
+		require.EqualError(t, errs[0], "$.spec.ports[0].port: expected integer, got string")
 	})
 }
 
@@ -0,0 +1,343 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-openapi/spec"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	definitionsPrefix = "#/definitions/"
+
+	intOrStringFormat = "int-or-string"
+)
+
+var (
+	// reIdentifier matches field names which don't have to be quoted in paths.
+	reIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$-]*$`)
+
+	// anyDefinitions are definitions whose values are not validated.
+	anyDefinitions = map[string]bool{
+		"io.k8s.apimachinery.pkg.runtime.RawExtension":                                  true,
+		"io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1beta1.JSON":            true,
+		"io.k8s.apiextensions-apiserver.pkg.apis.apiextensions.v1beta1.JSONSchemaProps": true,
+	}
+
+	// quantityDefinitions are definitions of quantities, which are strings in
+	// the definitions but can also be numbers.
+	quantityDefinitions = map[string]bool{
+		"io.k8s.apimachinery.pkg.api.resource.Quantity": true,
+		"io.k8s.kubernetes.pkg.api.resource.Quantity":   true,
+	}
+)
+
+// ValidationError is an error in a value of an object.
+type ValidationError struct {
+	// Path is the JSONPath of the value.
+	Path string
+	// Message describes the error.
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+// Validator validates objects against OpenAPI definitions. It checks the types
+// of values, required fields, enums, and reports fields which are not defined.
+type Validator struct {
+	definitions spec.Definitions
+}
+
+// NewValidator creates an instance of Validator for the OpenAPI definitions of
+// an environment, which are stored with its ksonnet-lib. It does not
+// communicate with the cluster of the environment.
+func NewValidator(a app.App, envName string) (*Validator, error) {
+	libPath, err := a.LibPath(envName)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := afero.ReadFile(a.Fs(), filepath.Join(libPath, "swagger.json"))
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading OpenAPI definitions for environment %q", envName)
+	}
+
+	return newValidator(b)
+}
+
+func newValidator(b []byte) (*Validator, error) {
+	var swagger spec.Swagger
+	if err := json.Unmarshal(b, &swagger); err != nil {
+		return nil, errors.Wrap(err, "decoding OpenAPI definitions")
+	}
+
+	return &Validator{definitions: swagger.Definitions}, nil
+}
+
+// Validate validates an object. Custom resources are not validated.
+func (v *Validator) Validate(obj *unstructured.Unstructured) []error {
+	name, err := definitionName(obj)
+	if err != nil {
+		if err == errUnsupportedDefinition {
+			return nil
+		}
+
+		return []error{err}
+	}
+
+	schema, ok := v.definitions[name]
+	if !ok {
+		schema, ok = v.definitions[legacyDefinitionName(name)]
+	}
+	if !ok {
+		return []error{errors.Errorf("unable to find definition for %s", name)}
+	}
+
+	return v.validate(&schema, obj.Object, "$")
+}
+
+func (v *Validator) validate(schema *spec.Schema, value interface{}, path string) []error {
+	// null values are the same as missing values.
+	if value == nil {
+		return nil
+	}
+
+	if ref := schema.Ref.String(); ref != "" {
+		name := strings.TrimPrefix(ref, definitionsPrefix)
+		if anyDefinitions[name] {
+			return nil
+		}
+
+		if quantityDefinitions[name] {
+			if !isString(value) && !isNumber(value) {
+				return typeError(path, "quantity", value)
+			}
+			return nil
+		}
+
+		def, ok := v.definitions[name]
+		if !ok {
+			return nil
+		}
+
+		return v.validate(&def, value, path)
+	}
+
+	if schema.Format == intOrStringFormat {
+		if !isString(value) && !isInteger(value) {
+			return typeError(path, "integer or string", value)
+		}
+		return nil
+	}
+
+	if len(schema.Enum) > 0 && !inEnum(schema.Enum, value) {
+		return []error{&ValidationError{Path: path, Message: enumMessage(schema.Enum, value)}}
+	}
+
+	typeName := ""
+	if len(schema.Type) > 0 {
+		typeName = schema.Type[0]
+	} else if len(schema.Properties) > 0 {
+		typeName = "object"
+	}
+
+	switch typeName {
+	case "object":
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return typeError(path, typeName, value)
+		}
+		return v.validateObject(schema, m, path)
+	case "array":
+		items, ok := value.([]interface{})
+		if !ok {
+			return typeError(path, typeName, value)
+		}
+		if schema.Items == nil || schema.Items.Schema == nil {
+			return nil
+		}
+
+		var errs []error
+		for i, item := range items {
+			errs = append(errs, v.validate(schema.Items.Schema, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+		return errs
+	case "string":
+		if !isString(value) {
+			return typeError(path, typeName, value)
+		}
+	case "integer":
+		if !isInteger(value) {
+			return typeError(path, typeName, value)
+		}
+	case "number":
+		if !isNumber(value) {
+			return typeError(path, typeName, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return typeError(path, typeName, value)
+		}
+	}
+
+	return nil
+}
+
+func (v *Validator) validateObject(schema *spec.Schema, m map[string]interface{}, path string) []error {
+	var errs []error
+
+	for _, name := range schema.Required {
+		if m[name] == nil {
+			errs = append(errs, &ValidationError{Path: fieldPath(path, name), Message: "required field is missing"})
+		}
+	}
+
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if prop, ok := schema.Properties[name]; ok {
+			errs = append(errs, v.validate(&prop, m[name], fieldPath(path, name))...)
+			continue
+		}
+
+		if ap := schema.AdditionalProperties; ap != nil {
+			if ap.Schema != nil {
+				errs = append(errs, v.validate(ap.Schema, m[name], fieldPath(path, name))...)
+			}
+			continue
+		}
+
+		// objects without properties are free-form.
+		if len(schema.Properties) > 0 {
+			errs = append(errs, &ValidationError{Path: fieldPath(path, name), Message: "unknown field"})
+		}
+	}
+
+	return errs
+}
+
+// legacyDefinitionName returns the name of a definition in the OpenAPI
+// definitions of Kubernetes 1.7.
+func legacyDefinitionName(name string) string {
+	if strings.HasPrefix(name, "io.k8s.api.core.") {
+		return "io.k8s.kubernetes.pkg.api." + strings.TrimPrefix(name, "io.k8s.api.core.")
+	}
+
+	return "io.k8s.kubernetes.pkg.apis." + strings.TrimPrefix(name, "io.k8s.api.")
+}
+
+func fieldPath(path, name string) string {
+	if reIdentifier.MatchString(name) {
+		return path + "." + name
+	}
+
+	return fmt.Sprintf("%s['%s']", path, strings.Replace(name, "'", `\'`, -1))
+}
+
+func typeError(path, expected string, value interface{}) []error {
+	return []error{&ValidationError{
+		Path:    path,
+		Message: fmt.Sprintf("expected %s, got %s", expected, typeOf(value)),
+	}}
+}
+
+func typeOf(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	default:
+		if isInteger(value) {
+			return "integer"
+		}
+		if isNumber(value) {
+			return "number"
+		}
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func isString(value interface{}) bool {
+	_, ok := value.(string)
+	return ok
+}
+
+func isInteger(value interface{}) bool {
+	switch t := value.(type) {
+	case int, int32, int64:
+		return true
+	case float64:
+		return t == math.Trunc(t)
+	default:
+		return false
+	}
+}
+
+func isNumber(value interface{}) bool {
+	switch value.(type) {
+	case int, int32, int64, float32, float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, e := range enum {
+		if reflect.DeepEqual(e, value) {
+			return true
+		}
+
+		// numbers are decoded as integers or floats.
+		if isNumber(e) && isNumber(value) && fmt.Sprint(e) == fmt.Sprint(value) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func enumMessage(enum []interface{}, value interface{}) string {
+	var values []string
+	for _, e := range enum {
+		values = append(values, fmt.Sprintf("%q", fmt.Sprint(e)))
+	}
+
+	return fmt.Sprintf("value %q is not one of %s", fmt.Sprint(value), strings.Join(values, ", "))
+}
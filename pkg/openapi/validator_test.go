@@ -0,0 +1,220 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package openapi
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-openapi/spec"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func testValidator(t *testing.T) *Validator {
+	b, err := ioutil.ReadFile(filepath.Join("testdata", "swagger.json"))
+	require.NoError(t, err)
+
+	v, err := newValidator(b)
+	require.NoError(t, err)
+
+	return v
+}
+
+func testDeployment() map[string]interface{} {
+	return map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name": "web",
+			"labels": map[string]interface{}{
+				"app.kubernetes.io/name": "web",
+			},
+			"creationTimestamp": nil,
+		},
+		"spec": map[string]interface{}{
+			"replicas": int64(2),
+			"selector": map[string]interface{}{
+				"matchLabels": map[string]interface{}{"app": "web"},
+			},
+			"template": map[string]interface{}{
+				"metadata": map[string]interface{}{
+					"labels": map[string]interface{}{"app": "web"},
+				},
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{
+							"name":  "web",
+							"image": "nginx:1.15",
+							"ports": []interface{}{
+								map[string]interface{}{"containerPort": float64(80)},
+							},
+							"resources": map[string]interface{}{
+								"requests": map[string]interface{}{"cpu": 0.5, "memory": "64Mi"},
+							},
+							"readinessProbe": map[string]interface{}{
+								"httpGet": map[string]interface{}{"path": "/", "port": "http"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func TestValidator_Validate(t *testing.T) {
+	cases := []struct {
+		name     string
+		update   func(map[string]interface{})
+		expected []string
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name: "unknown fields",
+			update: func(m map[string]interface{}) {
+				m["spec"].(map[string]interface{})["replica"] = 2
+				container(m)["imagePullPolicy"] = "Always"
+				container(m)["imag"] = "nginx"
+			},
+			expected: []string{
+				"$.spec.replica: unknown field",
+				"$.spec.template.spec.containers[0].imag: unknown field",
+			},
+		},
+		{
+			name: "missing required fields",
+			update: func(m map[string]interface{}) {
+				delete(m["spec"].(map[string]interface{}), "selector")
+				delete(container(m), "name")
+			},
+			expected: []string{
+				"$.spec.selector: required field is missing",
+				"$.spec.template.spec.containers[0].name: required field is missing",
+			},
+		},
+		{
+			name: "types",
+			update: func(m map[string]interface{}) {
+				m["spec"].(map[string]interface{})["replicas"] = "2"
+				m["spec"].(map[string]interface{})["paused"] = "true"
+				container(m)["ports"] = []interface{}{
+					map[string]interface{}{"containerPort": 80.5},
+				}
+				container(m)["args"] = "--verbose"
+				container(m)["resources"] = map[string]interface{}{
+					"limits": map[string]interface{}{"cpu": true},
+				}
+				container(m)["readinessProbe"] = map[string]interface{}{
+					"httpGet": map[string]interface{}{"port": 8.5},
+				}
+				m["metadata"].(map[string]interface{})["labels"] = map[string]interface{}{
+					"app.kubernetes.io/name": 1,
+				}
+			},
+			expected: []string{
+				"$.metadata.labels['app.kubernetes.io/name']: expected string, got integer",
+				"$.spec.paused: expected boolean, got string",
+				"$.spec.replicas: expected integer, got string",
+				"$.spec.template.spec.containers[0].args: expected array, got string",
+				"$.spec.template.spec.containers[0].ports[0].containerPort: expected integer, got number",
+				"$.spec.template.spec.containers[0].readinessProbe.httpGet.port: expected integer or string, got number",
+				"$.spec.template.spec.containers[0].resources.limits.cpu: expected quantity, got boolean",
+			},
+		},
+	}
+
+	v := testValidator(t)
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := testDeployment()
+			if tc.update != nil {
+				tc.update(m)
+			}
+
+			errs := v.Validate(&unstructured.Unstructured{Object: m})
+
+			var got []string
+			for _, err := range errs {
+				got = append(got, err.Error())
+			}
+
+			require.Equal(t, tc.expected, got)
+		})
+	}
+}
+
+func TestValidator_Validate_definitions(t *testing.T) {
+	v := testValidator(t)
+
+	crd := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "example.com/v1",
+			"kind":       "Widget",
+			"spec":       "anything",
+		},
+	}
+	require.Empty(t, v.Validate(crd))
+
+	unknown := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Widget",
+		},
+	}
+	errs := v.Validate(unknown)
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], "unable to find definition for io.k8s.api.core.v1.Widget")
+}
+
+func TestValidator_validate_enum(t *testing.T) {
+	v := &Validator{}
+
+	schema := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"string"},
+			Enum: []interface{}{"ClusterIP", "NodePort"},
+		},
+	}
+
+	require.Empty(t, v.validate(schema, "NodePort", "$.spec.type"))
+
+	errs := v.validate(schema, "Nodeport", "$.spec.type")
+	require.Len(t, errs, 1)
+	require.EqualError(t, errs[0], `$.spec.type: value "Nodeport" is not one of "ClusterIP", "NodePort"`)
+
+	numbers := &spec.Schema{
+		SchemaProps: spec.SchemaProps{
+			Type: spec.StringOrArray{"integer"},
+			Enum: []interface{}{float64(1), float64(2)},
+		},
+	}
+	require.Empty(t, v.validate(numbers, int64(2), "$.version"))
+}
+
+func Test_legacyDefinitionName(t *testing.T) {
+	require.Equal(t, "io.k8s.kubernetes.pkg.api.v1.Service", legacyDefinitionName("io.k8s.api.core.v1.Service"))
+	require.Equal(t, "io.k8s.kubernetes.pkg.apis.apps.v1beta1.Deployment", legacyDefinitionName("io.k8s.api.apps.v1beta1.Deployment"))
+}
+
+func container(m map[string]interface{}) map[string]interface{} {
+	spec := m["spec"].(map[string]interface{})["template"].(map[string]interface{})["spec"].(map[string]interface{})
+	return spec["containers"].([]interface{})[0].(map[string]interface{})
+}
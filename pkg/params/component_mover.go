@@ -0,0 +1,191 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package params
+
+import (
+	"bytes"
+
+	"github.com/google/go-jsonnet/ast"
+	"github.com/ksonnet/ksonnet-lib/ksonnet-gen/astext"
+	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// ComponentMover moves the param configuration of components in module
+// and environment params libsonnet files. Field values are moved as is,
+// so references to globals or other params keep working.
+type ComponentMover struct {
+}
+
+// NewComponentMover creates an instance of ComponentMover.
+func NewComponentMover() *ComponentMover {
+	cm := &ComponentMover{}
+
+	return cm
+}
+
+// Rename renames the entry for a component in the jsonnet snippet. If the snippet
+// has no entry for the component, it is returned unchanged.
+func (cm *ComponentMover) Rename(from, to, snippet string) (string, error) {
+	if from == "" || to == "" {
+		return "", errors.New("component name was blank")
+	}
+
+	logrus.WithField("component-name", from).Debugf("renaming component params to %q", to)
+
+	n, components, err := cm.componentsObject(snippet)
+	if err != nil {
+		return "", err
+	}
+
+	i, err := cm.index(components, from)
+	if err != nil || i < 0 {
+		return snippet, err
+	}
+
+	field, err := cm.renamedField(components, components.Fields[i], to)
+	if err != nil {
+		return "", err
+	}
+	components.Fields[i] = *field
+
+	return cm.print(n)
+}
+
+// Move moves the entry for a component from the src snippet to the dest snippet.
+// The updated snippets are returned. If src has no entry for the component,
+// both snippets are returned unchanged.
+func (cm *ComponentMover) Move(from, to, src, dest string) (string, string, error) {
+	if from == "" || to == "" {
+		return "", "", errors.New("component name was blank")
+	}
+
+	logrus.WithField("component-name", from).Debugf("moving component params to %q", to)
+
+	srcNode, srcComponents, err := cm.componentsObject(src)
+	if err != nil {
+		return "", "", err
+	}
+
+	destNode, destComponents, err := cm.componentsObject(dest)
+	if err != nil {
+		return "", "", err
+	}
+
+	i, err := cm.index(srcComponents, from)
+	if err != nil || i < 0 {
+		return src, dest, err
+	}
+
+	field, err := cm.renamedField(destComponents, srcComponents.Fields[i], to)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Comments on the field describe its place in the source params.
+	field.Comment = nil
+
+	srcComponents.Fields = append(srcComponents.Fields[:i], srcComponents.Fields[i+1:]...)
+	destComponents.Fields = append(destComponents.Fields, *field)
+
+	updatedSrc, err := cm.print(srcNode)
+	if err != nil {
+		return "", "", err
+	}
+
+	updatedDest, err := cm.print(destNode)
+	if err != nil {
+		return "", "", err
+	}
+
+	return updatedSrc, updatedDest, nil
+}
+
+func (cm *ComponentMover) componentsObject(snippet string) (ast.Node, *astext.Object, error) {
+	n, err := jsonnet.ParseNode("params.libsonnet", snippet)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	obj, err := componentParams(n, "")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	of, err := findField(obj, "components")
+	if err != nil {
+		return nil, nil, errors.Wrap(errUnsupportedEnvParams, "unable to find components field")
+	}
+
+	components, ok := of.Expr2.(*astext.Object)
+	if !ok {
+		return nil, nil, errors.Wrap(errUnsupportedEnvParams, "components field is not an object")
+	}
+
+	return n, components, nil
+}
+
+// index returns the index of the field for a component, or -1 if there is
+// no field for the component.
+func (cm *ComponentMover) index(components *astext.Object, componentName string) (int, error) {
+	for i := range components.Fields {
+		id, err := jsonnet.FieldID(components.Fields[i])
+		if err != nil {
+			return 0, err
+		}
+
+		if id == componentName {
+			return i, nil
+		}
+	}
+
+	return -1, nil
+}
+
+// renamedField creates a copy of a field with a new component name. It is an error
+// if components already has a field with the new name.
+func (cm *ComponentMover) renamedField(components *astext.Object, field astext.ObjectField, componentName string) (*astext.ObjectField, error) {
+	i, err := cm.index(components, componentName)
+	if err != nil {
+		return nil, err
+	}
+
+	if i >= 0 {
+		return nil, errors.Errorf("params for component %q already exist", componentName)
+	}
+
+	of, err := astext.CreateField(componentName)
+	if err != nil {
+		return nil, err
+	}
+
+	of.Hide = field.Hide
+	of.SuperSugar = field.SuperSugar
+	of.Expr2 = field.Expr2
+	of.Comment = field.Comment
+
+	return of, nil
+}
+
+func (cm *ComponentMover) print(n ast.Node) (string, error) {
+	var buf bytes.Buffer
+	if err := jsonnetPrinterFn(&buf, n); err != nil {
+		return "", errors.Wrap(err, "unable to update snippet")
+	}
+
+	return buf.String(), nil
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package params
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComponentMover_Rename(t *testing.T) {
+	cases := []struct {
+		name   string
+		from   string
+		to     string
+		input  string
+		output string
+		isErr  bool
+	}{
+		{
+			name:   "environment params",
+			from:   "frontend.guestbook",
+			to:     "backend.guestbook",
+			input:  filepath.Join("move", "env.libsonnet"),
+			output: filepath.Join("move", "env-renamed.libsonnet"),
+		},
+		{
+			name:   "module params",
+			from:   "guestbook",
+			to:     "guestbook-app",
+			input:  filepath.Join("move", "frontend-params.libsonnet"),
+			output: filepath.Join("move", "frontend-params-renamed.libsonnet"),
+		},
+		{
+			name:   "no params for component",
+			from:   "missing",
+			to:     "other",
+			input:  filepath.Join("move", "env.libsonnet"),
+			output: filepath.Join("move", "env.libsonnet"),
+		},
+		{
+			name:  "params for new name exist",
+			from:  "guestbook",
+			to:    "guestbook-ui",
+			input: filepath.Join("move", "frontend-params.libsonnet"),
+			isErr: true,
+		},
+		{
+			name:  "blank name",
+			from:  "guestbook",
+			input: filepath.Join("move", "frontend-params.libsonnet"),
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			snippet := test.ReadTestData(t, tc.input)
+
+			cm := NewComponentMover()
+
+			got, err := cm.Rename(tc.from, tc.to, snippet)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+
+			expected := test.ReadTestData(t, tc.output)
+			require.Equal(t, expected, got)
+		})
+	}
+}
+
+func TestComponentMover_Move(t *testing.T) {
+	src := test.ReadTestData(t, filepath.Join("move", "frontend-params.libsonnet"))
+	dest := test.ReadTestData(t, filepath.Join("move", "backend-params.libsonnet"))
+
+	cm := NewComponentMover()
+
+	gotSrc, gotDest, err := cm.Move("guestbook", "guestbook-app", src, dest)
+	require.NoError(t, err)
+
+	require.Equal(t, test.ReadTestData(t, filepath.Join("move", "frontend-params-moved.libsonnet")), gotSrc)
+	require.Equal(t, test.ReadTestData(t, filepath.Join("move", "backend-params-moved.libsonnet")), gotDest)
+
+	_, _, err = cm.Move("guestbook", "redis", src, dest)
+	require.Error(t, err)
+}
@@ -24,8 +24,94 @@ import (
 	"github.com/spf13/afero"
 )
 
-// EvaluateEnv evaluates environment parameters.
+// EvaluateEnv evaluates environment parameters. If the environment has parents,
+// their parameters are evaluated first, starting with the root of the hierarchy,
+// and the result of each is the input of the next. The params file of the
+// environment's destination, if any, is evaluated last.
 func EvaluateEnv(a app.App, sourcePath, paramsStr, envName, moduleName string) (string, error) {
+	chain, err := app.EnvironmentChain(a, envName)
+	if err != nil {
+		return "", err
+	}
+
+	for _, e := range chain[:len(chain)-1] {
+		parentPath := filepath.Join(e.MakePath(a.Root()), "params.libsonnet")
+		evaluated, err := evaluateEnv(a, parentPath, paramsStr, envName, moduleName)
+		if err != nil {
+			return "", errors.Wrapf(err, "evaluating parent environment %q", e.Name)
+		}
+
+		paramsStr, err = carryGlobals(paramsStr, evaluated)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	envParams, err := evaluateEnv(a, sourcePath, paramsStr, envName, moduleName)
+	if err != nil {
+		return "", err
+	}
+
+	// Environments with multiple destinations can overlay params per destination.
+	e := chain[len(chain)-1]
+	overlayPath := DestinationParamsPath(a, e)
+	if overlayPath == "" {
+		return envParams, nil
+	}
+
+	envParams, err = carryGlobals(paramsStr, envParams)
+	if err != nil {
+		return "", err
+	}
+
+	envParams, err = evaluateEnv(a, overlayPath, envParams, envName, moduleName)
+	if err != nil {
+		return "", errors.Wrapf(err, "evaluating params for destination %s", e.Destination)
+	}
+
+	return envParams, nil
+}
+
+// DestinationParamsPath returns the path of the params overlay of an
+// environment's destination. It is empty if the destination has none.
+func DestinationParamsPath(a app.App, e *app.EnvironmentConfig) string {
+	if e == nil || e.Destination == nil || e.Destination.Params == "" {
+		return ""
+	}
+
+	return filepath.Join(e.MakePath(a.Root()), filepath.FromSlash(e.Destination.Params))
+}
+
+// DestinationParamsPaths returns the paths of the params overlays of all of
+// an environment's destinations.
+func DestinationParamsPaths(a app.App, e *app.EnvironmentConfig) []string {
+	if e == nil {
+		return nil
+	}
+
+	dests := e.Destinations
+	if len(dests) == 0 && e.Destination != nil {
+		dests = []*app.EnvironmentDestinationSpec{e.Destination}
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	for _, dest := range dests {
+		pinned := *e
+		pinned.Destination = dest
+
+		path := DestinationParamsPath(a, &pinned)
+		if path == "" || seen[path] {
+			continue
+		}
+		seen[path] = true
+		paths = append(paths, path)
+	}
+
+	return paths
+}
+
+func evaluateEnv(a app.App, sourcePath, paramsStr, envName, moduleName string) (string, error) {
 	snippet, err := afero.ReadFile(a.Fs(), sourcePath)
 	if err != nil {
 		return "", err
@@ -51,6 +137,31 @@ func EvaluateEnv(a app.App, sourcePath, paramsStr, envName, moduleName string) (
 	return envParams, nil
 }
 
+// carryGlobals copies global parameters from the params that were the input
+// of an environment evaluation to its output, so they remain available to
+// child environments.
+func carryGlobals(input, evaluated string) (string, error) {
+	vm := jsonnet.NewVM()
+	vm.ExtCode("input", input)
+	vm.ExtCode("evaluated", evaluated)
+
+	output, err := vm.EvaluateSnippet("carry-globals", carryGlobalsSnippet)
+	if err != nil {
+		return "", errors.Wrap(err, "carrying global params")
+	}
+
+	return output, nil
+}
+
+var carryGlobalsSnippet = `
+local input = std.extVar("input");
+local evaluated = std.extVar("evaluated");
+
+if std.objectHas(input, "global") && !std.objectHas(evaluated, "global")
+then evaluated + {global: input.global}
+else evaluated
+`
+
 // modularizeParameters adds a module prefix to component parameters.
 // * Given a root module, it will not update the component name
 // * Given a module nested under root, it will prepend the module: eg: `module apps -> apps.component`
@@ -52,3 +52,81 @@ func TestEvaluateEnv(t *testing.T) {
 		assert.Equal(t, expected, got)
 	})
 }
+
+func TestEvaluateEnv_inherited(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		destination := &app.EnvironmentDestinationSpec{
+			Namespace: "default",
+			Server:    "http://example.com",
+		}
+		parent := &app.EnvironmentConfig{
+			Name:        "parent",
+			Path:        "parent",
+			Destination: destination,
+		}
+		child := &app.EnvironmentConfig{
+			Name:        "child",
+			Path:        "child",
+			Parent:      "parent",
+			Destination: destination,
+		}
+		a.On("Environment", "parent").Return(parent, nil)
+		a.On("Environment", "child").Return(child, nil)
+
+		sourcePath := "/app/environments/child/params.libsonnet"
+		paramsStr := test.ReadTestData(t, filepath.Join("evaluate_env", "component_params.libsonnet"))
+
+		test.StageFile(t, fs, filepath.Join("evaluate_env_inherited", "parent_params.libsonnet"),
+			"/app/environments/parent/params.libsonnet")
+		test.StageFile(t, fs, filepath.Join("evaluate_env_inherited", "child_params.libsonnet"), sourcePath)
+
+		got, err := EvaluateEnv(a, sourcePath, paramsStr, "child", "app.project-1")
+		require.NoError(t, err)
+
+		expected := test.ReadTestData(t, filepath.Join("evaluate_env_inherited", "expected.libsonnet"))
+
+		assert.Equal(t, expected, got)
+	})
+}
+
+func TestEvaluateEnv_destination_params(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		parent := &app.EnvironmentConfig{
+			Name: "parent",
+			Path: "parent",
+			Destination: &app.EnvironmentDestinationSpec{
+				Namespace: "default",
+				Server:    "http://example.com",
+			},
+		}
+		child := &app.EnvironmentConfig{
+			Name:   "child",
+			Path:   "child",
+			Parent: "parent",
+			Destination: &app.EnvironmentDestinationSpec{
+				Name:      "eu",
+				Namespace: "default",
+				Server:    "http://eu.example.com",
+				Params:    "eu/params.libsonnet",
+			},
+		}
+		a.On("Environment", "parent").Return(parent, nil)
+		a.On("Environment", "child").Return(child, nil)
+
+		sourcePath := "/app/environments/child/params.libsonnet"
+		paramsStr := test.ReadTestData(t, filepath.Join("evaluate_env", "component_params.libsonnet"))
+
+		test.StageFile(t, fs, filepath.Join("evaluate_env_inherited", "parent_params.libsonnet"),
+			"/app/environments/parent/params.libsonnet")
+		test.StageFile(t, fs, filepath.Join("evaluate_env_inherited", "child_params.libsonnet"), sourcePath)
+		test.StageFile(t, fs, filepath.Join("evaluate_env_destination", "destination_params.libsonnet"),
+			"/app/environments/child/eu/params.libsonnet")
+
+		got, err := EvaluateEnv(a, sourcePath, paramsStr, "child", "app.project-1")
+		require.NoError(t, err)
+
+		expected := test.ReadTestData(t, filepath.Join("evaluate_env_destination", "expected.libsonnet"))
+
+		assert.Equal(t, expected, got)
+	})
+}
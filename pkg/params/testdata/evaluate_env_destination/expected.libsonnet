@@ -0,0 +1,8 @@
+{
+   "components": {
+      "ds": {
+         "name": "eu",
+         "replicas": 5
+      }
+   }
+}
@@ -0,0 +1,9 @@
+local params = std.extVar('__ksonnet/params');
+
+params + {
+  components+: {
+    "app.project-1.ds"+: {
+      replicas: params.components["app.project-1.ds"].replicas + 2,
+    },
+  },
+}
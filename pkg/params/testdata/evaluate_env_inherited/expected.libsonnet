@@ -0,0 +1,8 @@
+{
+   "components": {
+      "ds": {
+         "name": "parent",
+         "replicas": 5
+      }
+   }
+}
@@ -0,0 +1,10 @@
+local params = std.extVar('__ksonnet/params');
+
+params + {
+  components+: {
+    "app.project-1.ds"+: {
+      name: "parent",
+      replicas: 3,
+    },
+  },
+}
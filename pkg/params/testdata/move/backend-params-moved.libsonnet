@@ -0,0 +1,12 @@
+{
+  global: {},
+  components: {
+    redis: {
+      name: 'redis',
+    },
+    "guestbook-app": {
+      name: 'guestbook',
+      replicas: 1,
+    },
+  },
+}
\ No newline at end of file
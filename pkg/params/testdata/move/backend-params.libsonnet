@@ -0,0 +1,8 @@
+{
+  global: {},
+  components: {
+    redis: {
+      name: "redis",
+    },
+  },
+}
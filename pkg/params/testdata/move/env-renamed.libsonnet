@@ -0,0 +1,20 @@
+local params = std.extVar('__ksonnet/params');
+local globals = import 'globals.libsonnet';
+local envParams = params + {
+  components+: {
+    "backend.guestbook"+: {
+      name: 'guestbook-dev',
+      replicas: params.global.replicas,
+    },
+    redis+: {
+      replicas: 1,
+    },
+  },
+};
+
+{
+  components: {
+    [x]: envParams.components[x] + globals
+    for x in std.objectFields(envParams.components)
+  },
+}
\ No newline at end of file
@@ -0,0 +1,8 @@
+{
+  global: {},
+  components: {
+    "guestbook-ui": {
+      name: 'guestbook-ui',
+    },
+  },
+}
\ No newline at end of file
@@ -0,0 +1,12 @@
+{
+  global: {},
+  components: {
+    "guestbook-app": {
+      name: 'guestbook',
+      replicas: 1,
+    },
+    "guestbook-ui": {
+      name: 'guestbook-ui',
+    },
+  },
+}
\ No newline at end of file
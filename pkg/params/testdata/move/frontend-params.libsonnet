@@ -0,0 +1,12 @@
+{
+  global: {},
+  components: {
+    guestbook: {
+      name: "guestbook",
+      replicas: 1,
+    },
+    "guestbook-ui": {
+      name: "guestbook-ui",
+    },
+  },
+}
@@ -18,6 +18,7 @@ package parts
 import (
 	"fmt"
 
+	msemver "github.com/Masterminds/semver"
 	"github.com/blang/semver"
 	"github.com/ghodss/yaml"
 	"github.com/pkg/errors"
@@ -43,6 +44,7 @@ type Spec struct {
 	Keywords     []string          `json:"keywords"`
 	QuickStart   *QuickStartSpec   `json:"quickStart"`
 	License      string            `json:"license"`
+	Dependencies DependencySpecs   `json:"dependencies,omitempty"`
 }
 
 func Unmarshal(bytes []byte) (*Spec, error) {
@@ -76,6 +78,18 @@ func (s *Spec) validate() error {
 			DefaultAPIVersion)
 	}
 
+	for _, d := range s.Dependencies {
+		if d == nil || d.Name == "" {
+			return errors.Errorf("Library '%s' has a dependency without a name", s.Name)
+		}
+		if d.Version == "" {
+			continue
+		}
+		if _, err := msemver.NewConstraint(d.Version); err != nil {
+			return errors.Wrapf(err, "Library '%s' has an invalid version range for dependency '%s'", s.Name, d.Name)
+		}
+	}
+
 	return nil
 }
 
@@ -102,6 +116,51 @@ type QuickStartSpec struct {
 	Comment       string            `json:"comment"`
 }
 
+// DependencySpec is a dependency of a library on another library.
+type DependencySpec struct {
+	// Name is the name of the library.
+	Name string `json:"name"`
+	// Registry is the registry of the library. It defaults to the registry of
+	// the dependent library.
+	Registry string `json:"registry,omitempty"`
+	// Version is a semver range the version of the library has to satisfy,
+	// e.g. `^1.2.0`. Any version is allowed if it is empty.
+	Version string `json:"version,omitempty"`
+}
+
+// Allows returns true if version satisfies the dependency's version range.
+func (d *DependencySpec) Allows(version string) (bool, error) {
+	if d.Version == "" {
+		return true, nil
+	}
+
+	c, err := msemver.NewConstraint(d.Version)
+	if err != nil {
+		return false, errors.Wrapf(err, "parsing version range %q of dependency %q", d.Version, d.Name)
+	}
+
+	v, err := msemver.NewVersion(version)
+	if err != nil {
+		return false, errors.Wrapf(err, "version %q of library %q is not a semantic version", version, d.Name)
+	}
+
+	return c.Check(v), nil
+}
+
+func (d *DependencySpec) String() string {
+	s := d.Name
+	if d.Registry != "" {
+		s = d.Registry + "/" + s
+	}
+	if d.Version != "" {
+		s = fmt.Sprintf("%s (%s)", s, d.Version)
+	}
+	return s
+}
+
+// DependencySpecs is a list of dependencies.
+type DependencySpecs []*DependencySpec
+
 type Specs []*Spec
 
 type PrototypeRefSpecs []string
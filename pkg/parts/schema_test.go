@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/blang/semver"
+	"github.com/stretchr/testify/require"
 )
 
 func TestApiVersionValidate(t *testing.T) {
@@ -62,3 +63,66 @@ func TestApiVersionValidate(t *testing.T) {
 		}
 	}
 }
+
+func TestUnmarshal_dependencies(t *testing.T) {
+	data := []byte(`apiVersion: 0.0.1
+kind: ksonnet.io/parts
+name: app
+version: 0.1.0
+dependencies:
+- name: k8s-util
+  version: ^1.2.0
+- name: redis
+  registry: incubator
+`)
+
+	spec, err := Unmarshal(data)
+	require.NoError(t, err)
+
+	expected := DependencySpecs{
+		{Name: "k8s-util", Version: "^1.2.0"},
+		{Name: "redis", Registry: "incubator"},
+	}
+	require.Equal(t, expected, spec.Dependencies)
+}
+
+func TestUnmarshal_invalid_dependencies(t *testing.T) {
+	cases := []string{
+		"dependencies:\n- version: ^1.0.0\n",
+		"dependencies:\n- name: k8s-util\n  version: not-a-range\n",
+	}
+
+	for _, tc := range cases {
+		_, err := Unmarshal([]byte("apiVersion: 0.0.1\nname: app\n" + tc))
+		require.Error(t, err, tc)
+	}
+}
+
+func TestDependencySpec_Allows(t *testing.T) {
+	cases := []struct {
+		rng      string
+		version  string
+		expected bool
+		isErr    bool
+	}{
+		{rng: "", version: "anything", expected: true},
+		{rng: "^1.2.0", version: "1.4.0", expected: true},
+		{rng: "^1.2.0", version: "v1.2.1", expected: true},
+		{rng: "^1.2.0", version: "2.0.0", expected: false},
+		{rng: "~1.2.0", version: "1.3.0", expected: false},
+		{rng: ">= 1.0.0, < 3.0.0", version: "2.5.0", expected: true},
+		{rng: "^1.2.0", version: "0123456789abcdef", isErr: true},
+	}
+
+	for _, tc := range cases {
+		d := &DependencySpec{Name: "k8s-util", Version: tc.rng}
+		ok, err := d.Allows(tc.version)
+		if tc.isErr {
+			require.Error(t, err)
+			continue
+		}
+
+		require.NoError(t, err)
+		require.Equal(t, tc.expected, ok, "%s %s", tc.rng, tc.version)
+	}
+}
@@ -36,6 +36,7 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/util/k8s"
 	"github.com/ksonnet/ksonnet/pkg/util/strings"
 	"github.com/pkg/errors"
+	"github.com/spf13/afero"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -58,6 +59,7 @@ type Pipeline struct {
 	buildObjectsFn      func(*Pipeline, []string) ([]*unstructured.Unstructured, error)
 	evaluateEnvFn       func(a app.App, envName, components, paramsStr string, opts ...jsonnet.VMOpt) (string, error)
 	evaluateEnvParamsFn func(a app.App, sourcePath, paramsStr, envName, moduleName string) (string, error)
+	patchesFn           func(a app.App, envName string) ([]*env.Patch, error)
 	stubModuleFn        func(m component.Module) (string, error)
 }
 
@@ -71,6 +73,7 @@ func New(ksApp app.App, envName string, opts ...Opt) *Pipeline {
 		buildObjectsFn:      buildObjects,
 		evaluateEnvFn:       env.Evaluate,
 		evaluateEnvParamsFn: params.EvaluateEnv,
+		patchesFn:           env.Patches,
 		stubModuleFn:        stubModule,
 	}
 
@@ -87,6 +90,8 @@ func (p *Pipeline) Modules() ([]component.Module, error) {
 }
 
 // EnvParameters creates parameters for a namespace given an environment.
+// Parent environments are evaluated first, and the params overlay of the
+// environment's destination last, as they are when components are rendered.
 func (p *Pipeline) EnvParameters(moduleName string, inherited bool) (string, error) {
 	module, err := p.cm.Module(p.app, moduleName)
 	if err != nil {
@@ -98,18 +103,43 @@ func (p *Pipeline) EnvParameters(moduleName string, inherited bool) (string, err
 		return "", err
 	}
 
-	data, err := p.app.EnvironmentParams(p.envName)
+	chain, err := app.EnvironmentChain(p.app, p.envName)
 	if err != nil {
-		return "", errors.Wrapf(err, "retrieve environment params for %s", p.envName)
+		return "", errors.Wrapf(err, "load environment %s", p.envName)
 	}
 
-	envParams := upgradeParams(p.envName, data)
+	// Parent environments are evaluated first, each feeding the next.
+	for _, env := range chain {
+		data, err := p.app.EnvironmentParams(env.Name)
+		if err != nil {
+			return "", errors.Wrapf(err, "retrieve environment params for %s", env.Name)
+		}
 
-	env, err := p.app.Environment(p.envName)
-	if err != nil {
-		return "", errors.Wrapf(err, "load environment %s", p.envName)
+		paramsStr, err = p.evaluateParams(env, upgradeParams(env.Name, data), paramsStr)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	dst := chain[len(chain)-1]
+	if overlayPath := params.DestinationParamsPath(p.app, dst); overlayPath != "" {
+		data, err := afero.ReadFile(p.app.Fs(), overlayPath)
+		if err != nil {
+			return "", errors.Wrapf(err, "retrieve params for destination %s", dst.Destination)
+		}
+
+		paramsStr, err = p.evaluateParams(dst, upgradeParams(dst.Name, string(data)), paramsStr)
+		if err != nil {
+			return "", errors.Wrapf(err, "evaluating params for destination %s", dst.Destination)
+		}
 	}
 
+	return paramsStr, nil
+}
+
+// evaluateParams evaluates a params source of an environment with paramsStr
+// as its input.
+func (p *Pipeline) evaluateParams(env *app.EnvironmentConfig, envParams, paramsStr string) (string, error) {
 	vm := jsonnet.NewVM()
 	vm.AddJPath(
 		env.MakePath(p.app.Root()),
@@ -118,7 +148,7 @@ func (p *Pipeline) EnvParameters(moduleName string, inherited bool) (string, err
 	)
 	vm.ExtCode("__ksonnet/params", paramsStr)
 	log.Debugf("[Pipeline.EnvParameters] Evaluating: %v", envParams)
-	return vm.EvaluateSnippet("snippet", string(envParams))
+	return vm.EvaluateSnippet("snippet", envParams)
 }
 
 func (p *Pipeline) moduleParams(module component.Module, inherited bool) (string, error) {
@@ -227,6 +257,11 @@ func (p *Pipeline) moduleObjects(module component.Module, filter []string) ([]*u
 		return nil, err
 	}
 
+	componentParams, err := evaluatedComponentParams(envParamData)
+	if err != nil {
+		return nil, err
+	}
+
 	ret := make([]runtime.Object, 0, len(m))
 
 	for componentName, v := range m {
@@ -239,7 +274,15 @@ func (p *Pipeline) moduleObjects(module component.Module, filter []string) ([]*u
 			return nil, errors.Errorf("component %q is not an object", componentName)
 		}
 
-		labelComponents(componentObject, componentName)
+		dependencies, err := componentDependencies(componentName, componentParams[componentName])
+		if err != nil {
+			return nil, err
+		}
+
+		eachObject(componentObject, func(obj map[string]interface{}) {
+			labelComponent(obj, componentName)
+			component.SetDependsOn(obj, dependencies)
+		})
 
 		data, err := json.Marshal(componentObject)
 		if err != nil {
@@ -258,7 +301,12 @@ func (p *Pipeline) moduleObjects(module component.Module, filter []string) ([]*u
 		case "jsonnet":
 			patched = string(data)
 		case "yaml":
-			patched, err = params.PatchJSON(string(data), envParamData, componentName)
+			patch, err := yamlPatch(componentName, componentParams[componentName])
+			if err != nil {
+				return nil, err
+			}
+
+			patched, err = params.PatchJSON(string(data), patch, componentName)
 			if err != nil {
 				return nil, errors.Wrap(err, "patching YAML/JSON component")
 			}
@@ -343,10 +391,93 @@ func buildObjects(p *Pipeline, filter []string) ([]*unstructured.Unstructured, e
 		ret = append(ret, objects...)
 	}
 
+	// apply environment patches. When components are filtered, objects targeted
+	// by patches may not have been rendered.
+	patches, err := p.patchesFn(p.app, p.envName)
+	if err != nil {
+		return nil, errors.Wrap(err, "load environment patches")
+	}
+
+	if ret, err = env.ApplyPatches(ret, patches, len(filter) == 0); err != nil {
+		return nil, err
+	}
+
+	// check the dependencies of the components. When components are filtered,
+	// their dependencies may not have been rendered.
+	if _, err = component.GroupByDependencies(ret, len(filter) == 0); err != nil {
+		return nil, err
+	}
+
 	return ret, nil
 }
 
-func labelComponents(m map[string]interface{}, name string) {
+// evaluatedComponentParams returns the parameters of each component from
+// evaluated parameters.
+func evaluatedComponentParams(paramsData string) (map[string]map[string]interface{}, error) {
+	var evaluated struct {
+		Components map[string]map[string]interface{} `json:"components"`
+	}
+
+	if err := json.Unmarshal([]byte(paramsData), &evaluated); err != nil {
+		return nil, errors.Wrap(err, "decoding evaluated params")
+	}
+
+	return evaluated.Components, nil
+}
+
+// componentDependencies returns the components a component depends on, given
+// as a list of component names or a comma separated string.
+func componentDependencies(componentName string, componentParams map[string]interface{}) ([]string, error) {
+	var dependencies []string
+
+	switch t := componentParams[component.ParamDependsOn].(type) {
+	case nil:
+	case string:
+		for _, dependency := range gostrings.Split(t, ",") {
+			if dependency = gostrings.TrimSpace(dependency); dependency != "" {
+				dependencies = append(dependencies, dependency)
+			}
+		}
+	case []interface{}:
+		for _, item := range t {
+			dependency, ok := item.(string)
+			if !ok {
+				return nil, errors.Errorf("%s of component %q must be a list of component names", component.ParamDependsOn, componentName)
+			}
+			dependencies = append(dependencies, dependency)
+		}
+	default:
+		return nil, errors.Errorf("%s of component %q must be a list of component names", component.ParamDependsOn, componentName)
+	}
+
+	return dependencies, nil
+}
+
+// yamlPatch creates the patch applied to a YAML component from its parameters.
+// Parameters which aren't fields of the component's objects are left out.
+func yamlPatch(componentName string, componentParams map[string]interface{}) (string, error) {
+	patchParams := make(map[string]interface{})
+	for k, v := range componentParams {
+		if k == component.ParamDependsOn {
+			continue
+		}
+		patchParams[k] = v
+	}
+
+	data, err := json.Marshal(map[string]interface{}{
+		"components": map[string]interface{}{
+			componentName: patchParams,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return string(data), nil
+}
+
+// eachObject calls fn with an object, or with each of its items if it is a list.
+func eachObject(m map[string]interface{}, fn func(map[string]interface{})) {
 	if m["apiVersion"] == "v1" && m["kind"] == "List" {
 		list, ok := m["items"].([]interface{})
 		if !ok {
@@ -359,13 +490,13 @@ func labelComponents(m map[string]interface{}, name string) {
 				continue
 			}
 
-			labelComponent(itemMap, name)
+			fn(itemMap)
 		}
 
 		return
 	}
 
-	labelComponent(m, name)
+	fn(m)
 }
 
 func labelComponent(m map[string]interface{}, name string) {
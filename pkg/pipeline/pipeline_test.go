@@ -25,10 +25,13 @@ import (
 	appmocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
 	"github.com/ksonnet/ksonnet/pkg/component"
 	cmocks "github.com/ksonnet/ksonnet/pkg/component/mocks"
+	"github.com/ksonnet/ksonnet/pkg/env"
 	"github.com/ksonnet/ksonnet/pkg/metadata"
 	"github.com/ksonnet/ksonnet/pkg/util/jsonnet"
 	"github.com/pkg/errors"
+	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
@@ -89,6 +92,33 @@ func TestPipeline_EnvParameters_inherited(t *testing.T) {
 	})
 }
 
+func TestPipeline_EnvParameters_destination(t *testing.T) {
+	withPipeline(t, func(p *Pipeline, m *cmocks.Manager, a *appmocks.App) {
+		module := &cmocks.Module{}
+		module.On("ResolvedParams", "default").Return(`{"components": {"app": {"replicas": 1, "image": "app:1"}}}`, nil)
+		m.On("Module", p.app, "/").Return(module, nil)
+
+		a.On("EnvironmentParams", "default").Return(
+			`local params = std.extVar("__ksonnet/params"); params + {components+: {app+: {replicas: 2}}}`, nil)
+
+		overlay := `local params = std.extVar("__ksonnet/params"); params + {components+: {app+: {replicas: 3}}}`
+		err := afero.WriteFile(p.app.Fs(), "/environments/default/eu.libsonnet", []byte(overlay), 0644)
+		require.NoError(t, err)
+
+		env := &app.EnvironmentConfig{
+			Path:        "default",
+			Destination: &app.EnvironmentDestinationSpec{Server: "https://eu.example.com", Params: "eu.libsonnet"},
+		}
+		a.On("Environment", "default").Return(env, nil)
+
+		got, err := p.EnvParameters("/", true)
+		require.NoError(t, err)
+
+		expected := `{"components": {"app": {"image": "app:1", "replicas": 3}}}`
+		require.JSONEq(t, expected, got)
+	})
+}
+
 func TestPipeline_Components(t *testing.T) {
 	withPipeline(t, func(p *Pipeline, m *cmocks.Manager, a *appmocks.App) {
 		cpnt := &cmocks.Component{}
@@ -195,6 +225,174 @@ func TestPipeline_Objects(t *testing.T) {
 	})
 }
 
+func TestPipeline_Objects_patched(t *testing.T) {
+	patch := func(name string) *env.Patch {
+		return &env.Patch{
+			Path:   "/environments/default/patches/service.yaml",
+			Target: env.PatchTarget{APIVersion: "v1", Kind: "Service", Name: name},
+			Type:   env.PatchTypeStrategic,
+			Patch:  []byte(`{"spec": {"type": "NodePort"}}`),
+		}
+	}
+
+	cases := []struct {
+		name   string
+		patch  *env.Patch
+		filter []string
+		isErr  bool
+	}{
+		{
+			name:  "patch",
+			patch: patch("my-service"),
+		},
+		{
+			name:  "patch targets missing object",
+			patch: patch("missing"),
+			isErr: true,
+		},
+		{
+			name:   "patch targets object which is filtered out",
+			patch:  patch("missing"),
+			filter: []string{"service"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withPipeline(t, func(p *Pipeline, m *cmocks.Manager, a *appmocks.App) {
+				module := &cmocks.Module{}
+				module.On("Name").Return("")
+				componentMap := map[string]string{"service": "jsonnet"}
+				module.On("Render", "default", mock.Anything).Return(&astext.Object{}, componentMap, nil)
+				module.On("ResolvedParams", "default").Return("", nil)
+
+				m.On("Modules", p.app, "default").Return([]component.Module{module}, nil)
+				a.On("Environment", "default").Return(&app.EnvironmentConfig{Path: "default"}, nil)
+
+				serviceJSON, err := ioutil.ReadFile(filepath.Join("testdata", "components.json"))
+				require.NoError(t, err)
+				p.evaluateEnvFn = func(_ app.App, envName, input, params string, opts ...jsonnet.VMOpt) (string, error) {
+					return string(serviceJSON), nil
+				}
+
+				p.evaluateEnvParamsFn = func(_ app.App, paramsPath, paramData, envName, moduleName string) (string, error) {
+					return `{"components": {}}`, nil
+				}
+
+				p.patchesFn = func(_ app.App, envName string) ([]*env.Patch, error) {
+					return []*env.Patch{tc.patch}, nil
+				}
+
+				got, err := p.Objects(tc.filter)
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				require.Len(t, got, 1)
+				serviceType, _, err := unstructured.NestedString(got[0].Object, "spec", "type")
+				require.NoError(t, err)
+
+				expected := "NodePort"
+				if tc.filter != nil {
+					expected = ""
+				}
+				assert.Equal(t, expected, serviceType)
+			})
+		})
+	}
+}
+
+func TestPipeline_Objects_dependsOn(t *testing.T) {
+	cases := []struct {
+		name          string
+		componentType string
+		params        string
+		filter        []string
+		expected      string
+		isErr         bool
+	}{
+		{
+			name:          "list of components",
+			componentType: "jsonnet",
+			params:        `{"components": {"service": {"dependsOn": ["db"]}}}`,
+			filter:        []string{"service"},
+			expected:      "db",
+		},
+		{
+			name:          "comma separated components",
+			componentType: "jsonnet",
+			params:        `{"components": {"service": {"dependsOn": "db, cache"}}}`,
+			filter:        []string{"service"},
+			expected:      "db,cache",
+		},
+		{
+			name:          "yaml component",
+			componentType: "yaml",
+			params:        `{"components": {"service": {"dependsOn": ["db"]}}}`,
+			filter:        []string{"service"},
+			expected:      "db",
+		},
+		{
+			name:          "no dependencies",
+			componentType: "jsonnet",
+			params:        `{"components": {}}`,
+		},
+		{
+			name:          "dependency not rendered",
+			componentType: "jsonnet",
+			params:        `{"components": {"service": {"dependsOn": ["db"]}}}`,
+			isErr:         true,
+		},
+		{
+			name:          "invalid dependencies",
+			componentType: "jsonnet",
+			params:        `{"components": {"service": {"dependsOn": [1]}}}`,
+			filter:        []string{"service"},
+			isErr:         true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withPipeline(t, func(p *Pipeline, m *cmocks.Manager, a *appmocks.App) {
+				module := &cmocks.Module{}
+				module.On("Name").Return("")
+				componentMap := map[string]string{"service": tc.componentType}
+				module.On("Render", "default", mock.Anything).Return(&astext.Object{}, componentMap, nil)
+				module.On("ResolvedParams", "default").Return("", nil)
+
+				m.On("Modules", p.app, "default").Return([]component.Module{module}, nil)
+				a.On("Environment", "default").Return(&app.EnvironmentConfig{Path: "default"}, nil)
+
+				serviceJSON, err := ioutil.ReadFile(filepath.Join("testdata", "components.json"))
+				require.NoError(t, err)
+				p.evaluateEnvFn = func(_ app.App, envName, input, params string, opts ...jsonnet.VMOpt) (string, error) {
+					return string(serviceJSON), nil
+				}
+
+				p.evaluateEnvParamsFn = func(_ app.App, paramsPath, paramData, envName, moduleName string) (string, error) {
+					return tc.params, nil
+				}
+
+				got, err := p.Objects(tc.filter)
+				if tc.isErr {
+					require.Error(t, err)
+					return
+				}
+				require.NoError(t, err)
+
+				require.Len(t, got, 1)
+				assert.Equal(t, tc.expected, got[0].GetAnnotations()[metadata.AnnotationDependsOn])
+
+				_, ok := got[0].Object[component.ParamDependsOn]
+				assert.False(t, ok)
+			})
+		})
+	}
+}
+
 func TestPipeline_YAML(t *testing.T) {
 	withPipeline(t, func(p *Pipeline, m *cmocks.Manager, a *appmocks.App) {
 		p.buildObjectsFn = func(_ *Pipeline, filter []string) ([]*unstructured.Unstructured, error) {
@@ -296,6 +494,7 @@ func Test_stubModule(t *testing.T) {
 func withPipeline(t *testing.T, fn func(p *Pipeline, m *cmocks.Manager, a *appmocks.App)) {
 	a := &appmocks.App{}
 	a.On("Root").Return("/")
+	a.On("Fs").Return(afero.NewMemMapFs())
 	envName := "default"
 
 	manager := &cmocks.Manager{}
@@ -36,20 +36,33 @@ func Add(a app.App, protocol Protocol, name string, uri string, isOverride bool,
 		URI:      uri,
 	}
 
+	var c *http.Client
+	if protocol == ProtocolGitHub || protocol == ProtocolHelm {
+		if c, err = registryHTTPClient(protocol, uri, httpClient); err != nil {
+			return nil, err
+		}
+	}
+
 	switch protocol {
 	case ProtocolGitHub:
-		var ghc = github.NewGitHub(httpClient)
+		var ghc = github.NewGitHub(c)
 		r, err = githubFactory(a, initSpec, GitHubClient(ghc))
+	case ProtocolGit:
+		r, err = NewGit(a, initSpec)
 	case ProtocolFilesystem:
 		r, err = NewFs(a, initSpec)
 	case ProtocolHelm:
-		var hc *helm.HTTPClient
-		hc, err = helm.NewHTTPClient(initSpec.URI, httpClient)
+		var hc helm.RepositoryClient
+		hc, err = newHelmClient(a, initSpec.URI, c)
 		if err != nil {
 			return nil, errors.Wrap(err, "initializing helm HTTP client")
 		}
 		cc := helm.NewCachingClient(hc)
-		r, err = helmFactory(a, initSpec, cc)
+		var h *Helm
+		if h, err = helmFactory(a, initSpec, cc); err == nil {
+			h.httpClient = httpClient
+			r = h
+		}
 	default:
 		return nil, errors.Errorf("invalid registry protocol %q", protocol)
 	}
@@ -58,6 +71,10 @@ func Add(a app.App, protocol Protocol, name string, uri string, isOverride bool,
 		return nil, errors.Wrap(err, "adding registry")
 	}
 
+	if r, err = WithMirror(a, r); err != nil {
+		return nil, err
+	}
+
 	if ok, err := r.ValidateURI(uri); err != nil || !ok {
 		return nil, errors.Wrap(err, "validating registry URL")
 	}
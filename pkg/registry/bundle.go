@@ -0,0 +1,362 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/helm"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/ksonnet/ksonnet/pkg/util/archive"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// BundleAPIVersion is the API version of a registry bundle spec.
+	BundleAPIVersion = "0.1.0"
+	// BundleKind is the kind of a registry bundle spec.
+	BundleKind = "ksonnet.io/registry-bundle"
+
+	bundleYAMLFile   = "bundle.yaml"
+	helmIndexFile    = "index.yaml"
+	bundlesDirectory = ".ksonnet/bundles"
+)
+
+// BundleSpec describes the registry a bundle was created from.
+type BundleSpec struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Protocol   string `json:"protocol"`
+	URI        string `json:"uri"`
+}
+
+// Bundle writes a snapshot of a registry to w as a tgz archive. The snapshot
+// contains the registry spec and the selected packages. Packages are
+// selected as `name` or `name@version`. If none are selected, the bundle
+// contains every package in the registry.
+func Bundle(a app.App, name string, packages []string, w io.Writer, httpClient *http.Client) error {
+	if a == nil {
+		return errors.New("nil app")
+	}
+
+	registries, err := a.Registries()
+	if err != nil {
+		return err
+	}
+
+	rc, ok := registries[name]
+	if !ok {
+		return errors.Errorf("registry %q does not exist", name)
+	}
+
+	r, err := Locate(a, rc, httpClient)
+	if err != nil {
+		return err
+	}
+
+	selected := make(map[string]string)
+	for _, p := range packages {
+		d, err := pkg.Parse(p)
+		if err != nil {
+			return err
+		}
+		if d.Registry != "" && d.Registry != name {
+			return errors.Errorf("package %q is not in registry %q", p, name)
+		}
+		selected[d.Name] = d.Version
+	}
+
+	var files []*archive.File
+	if h, ok := unwrapMirror(r).(*Helm); ok {
+		files, err = helmBundleFiles(h, selected)
+	} else {
+		files, err = registryBundleFiles(r, selected)
+	}
+	if err != nil {
+		return err
+	}
+
+	bundleSpec := &BundleSpec{
+		APIVersion: BundleAPIVersion,
+		Kind:       BundleKind,
+		Name:       r.Name(),
+		Protocol:   r.Protocol().String(),
+		URI:        r.URI(),
+	}
+
+	data, err := yaml.Marshal(bundleSpec)
+	if err != nil {
+		return errors.Wrap(err, "marshalling bundle spec")
+	}
+
+	files = append([]*archive.File{{Name: bundleYAMLFile, Reader: bytes.NewReader(data)}}, files...)
+
+	var tgz archive.Tgz
+	return tgz.Archive(w, files)
+}
+
+// unwrapMirror returns the registry which serves content for r.
+func unwrapMirror(r Registry) Registry {
+	if m, ok := r.(*mirroredRegistry); ok {
+		return m.Registry
+	}
+
+	return r
+}
+
+// registryBundleFiles returns the registry.yaml and package files for a
+// ksonnet registry.
+func registryBundleFiles(r Registry, selected map[string]string) ([]*archive.File, error) {
+	registrySpec, err := r.FetchRegistrySpec()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving registry spec")
+	}
+
+	names, err := selectedPackages(registrySpec, selected)
+	if err != nil {
+		return nil, err
+	}
+
+	bundleSpec := &Spec{
+		APIVersion: DefaultAPIVersion,
+		Kind:       DefaultKind,
+		Version:    registrySpec.Version,
+		Libraries:  LibraryConfigs{},
+	}
+
+	var files []*archive.File
+	for _, name := range names {
+		onFile := func(relPath string, contents []byte) error {
+			files = append(files, &archive.File{
+				Name:   filepath.ToSlash(relPath),
+				Reader: bytes.NewReader(contents),
+			})
+			return nil
+		}
+		onDir := func(string) error { return nil }
+
+		_, libConfig, err := r.ResolveLibrary(name, name, selected[name], onFile, onDir)
+		if err != nil {
+			return nil, errors.Wrapf(err, "retrieving package %s", name)
+		}
+
+		bundleSpec.Libraries[name] = &LibraryConfig{
+			Path:    name,
+			Version: libConfig.Version,
+		}
+	}
+
+	data, err := bundleSpec.Marshal()
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling registry spec")
+	}
+
+	files = append([]*archive.File{{Name: registryYAMLFile, Reader: bytes.NewReader(data)}}, files...)
+	return files, nil
+}
+
+// selectedPackages returns the sorted names of selected packages. If no
+// packages are selected, all packages in the registry are returned.
+func selectedPackages(registrySpec *Spec, selected map[string]string) ([]string, error) {
+	var names []string
+	if len(selected) == 0 {
+		for name := range registrySpec.Libraries {
+			names = append(names, name)
+		}
+	} else {
+		for name := range selected {
+			if _, ok := registrySpec.Libraries[name]; !ok {
+				return nil, errors.Errorf("package %q does not exist in registry", name)
+			}
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
+
+// helmBundleFiles returns an index.yaml and chart archives for a Helm
+// repository. Chart URLs in the index are rewritten to reference the
+// bundled archives.
+func helmBundleFiles(h *Helm, selected map[string]string) ([]*archive.File, error) {
+	repo, err := h.repositoryClient.Repository()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving repository")
+	}
+
+	registrySpec, err := h.FetchRegistrySpec()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving registry spec")
+	}
+
+	names, err := selectedPackages(registrySpec, selected)
+	if err != nil {
+		return nil, err
+	}
+
+	index := &helm.Repository{
+		Charts: make(map[string][]helm.RepositoryChart),
+	}
+
+	var files []*archive.File
+	for _, name := range names {
+		chart, err := repo.Chart(name, selected[name])
+		if err != nil {
+			return nil, errors.Wrapf(err, "retrieving chart %s", name)
+		}
+
+		bundled := *chart
+		bundled.URLs = nil
+		for _, u := range chart.URLs {
+			rc, err := h.repositoryClient.Fetch(u)
+			if err != nil {
+				return nil, errors.Wrapf(err, "fetching chart %s", name)
+			}
+
+			data, err := ioutil.ReadAll(rc)
+			rc.Close()
+			if err != nil {
+				return nil, errors.Wrapf(err, "reading chart %s", name)
+			}
+
+			fileName := path.Base(u)
+			files = append(files, &archive.File{Name: fileName, Reader: bytes.NewReader(data)})
+			bundled.URLs = append(bundled.URLs, fileName)
+		}
+
+		index.Charts[name] = append(index.Charts[name], bundled)
+	}
+
+	data, err := yaml.Marshal(index)
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling repository index")
+	}
+
+	files = append([]*archive.File{{Name: helmIndexFile, Reader: bytes.NewReader(data)}}, files...)
+	return files, nil
+}
+
+// ExtractBundle extracts a registry bundle to dir and returns its spec.
+func ExtractBundle(fs afero.Fs, r io.Reader, dir string) (*BundleSpec, error) {
+	handler := func(f *archive.File) error {
+		name := path.Clean(filepath.ToSlash(f.Name))
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return errors.Errorf("bundle contains invalid path %q", f.Name)
+		}
+
+		dest := filepath.Join(dir, filepath.FromSlash(name))
+		if err := fs.MkdirAll(filepath.Dir(dest), app.DefaultFolderPermissions); err != nil {
+			return err
+		}
+
+		data, err := ioutil.ReadAll(f.Reader)
+		if err != nil {
+			return err
+		}
+
+		return afero.WriteFile(fs, dest, data, app.DefaultFilePermissions)
+	}
+
+	var tgz archive.Tgz
+	if err := tgz.Unarchive(r, handler); err != nil {
+		return nil, errors.Wrap(err, "extracting registry bundle")
+	}
+
+	data, err := afero.ReadFile(fs, filepath.Join(dir, bundleYAMLFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, errors.New("registry bundle does not contain a bundle.yaml")
+		}
+		return nil, err
+	}
+
+	var bundleSpec BundleSpec
+	if err := yaml.Unmarshal(data, &bundleSpec); err != nil {
+		return nil, errors.Wrap(err, "unmarshalling bundle spec")
+	}
+
+	if bundleSpec.Kind != BundleKind {
+		return nil, errors.Errorf("unexpected bundle kind %q", bundleSpec.Kind)
+	}
+
+	return &bundleSpec, nil
+}
+
+// AddBundle adds a registry from a bundle created by Bundle. The bundle is
+// extracted into the app, so the registry can be used without network
+// access.
+func AddBundle(a app.App, name, bundlePath string, isOverride bool, httpClient *http.Client) (*Spec, error) {
+	if a == nil {
+		return nil, errors.New("nil app")
+	}
+
+	f, err := a.Fs().Open(bundlePath)
+	if err != nil {
+		return nil, errors.Wrap(err, "opening registry bundle")
+	}
+	defer f.Close()
+
+	relDir := path.Join(bundlesDirectory, name)
+	dir := filepath.Join(a.Root(), filepath.FromSlash(relDir))
+
+	exists, err := afero.DirExists(a.Fs(), dir)
+	if err != nil {
+		return nil, err
+	}
+	if exists {
+		if !isOverride {
+			return nil, errors.Errorf("bundle for registry %q already exists", name)
+		}
+		if err = a.Fs().RemoveAll(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	bundleSpec, err := ExtractBundle(a.Fs(), f, dir)
+	if err != nil {
+		a.Fs().RemoveAll(dir)
+		return nil, err
+	}
+
+	logrus.Debugf("extracted bundle of %s (%s) to %s", bundleSpec.Name, bundleSpec.URI, dir)
+
+	protocol := ProtocolFilesystem
+	if Protocol(bundleSpec.Protocol) == ProtocolHelm {
+		protocol = ProtocolHelm
+	}
+
+	spec, err := Add(a, protocol, name, relDir, isOverride, httpClient)
+	if err != nil {
+		a.Fs().RemoveAll(dir)
+		return nil, err
+	}
+
+	return spec, nil
+}
@@ -0,0 +1,146 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/helm"
+	"github.com/ksonnet/ksonnet/pkg/util/archive"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle_fs(t *testing.T) {
+	var buf bytes.Buffer
+
+	withUserConfig(t, "", func() {
+		test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+			test.StageDir(t, fs, "part/incubator", "/work/local")
+			test.StageFile(t, fs, "fs-registry.yaml", "/work/local/registry.yaml")
+
+			registries := app.RegistryConfigs{
+				"local": {Name: "local", Protocol: string(ProtocolFilesystem), URI: "/work/local"},
+			}
+			a.On("Registries").Return(registries, nil)
+
+			err := Bundle(a, "local", []string{"apache"}, &buf, nil)
+			require.NoError(t, err)
+		})
+
+		test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+			err := afero.WriteFile(fs, "/bundles/local.tgz", buf.Bytes(), 0644)
+			require.NoError(t, err)
+
+			expected := &app.RegistryConfig{
+				Name:     "offline",
+				Protocol: string(ProtocolFilesystem),
+				URI:      ".ksonnet/bundles/offline",
+			}
+			a.On("AddRegistry", expected, false).Return(nil)
+
+			spec, err := AddBundle(a, "offline", "/bundles/local.tgz", false, nil)
+			require.NoError(t, err)
+			assert.Contains(t, spec.Libraries, "apache")
+
+			for _, path := range []string{"bundle.yaml", "registry.yaml", "apache/parts.yaml"} {
+				exists, err := afero.Exists(fs, "/app/.ksonnet/bundles/offline/"+path)
+				require.NoError(t, err)
+				assert.True(t, exists, "%s exists", path)
+			}
+
+			_, err = AddBundle(a, "offline", "/bundles/local.tgz", false, nil)
+			require.Error(t, err)
+		})
+	})
+}
+
+func TestBundle_unknown_package(t *testing.T) {
+	withUserConfig(t, "", func() {
+		test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+			test.StageDir(t, fs, "part/incubator", "/work/local")
+			test.StageFile(t, fs, "fs-registry.yaml", "/work/local/registry.yaml")
+
+			registries := app.RegistryConfigs{
+				"local": {Name: "local", Protocol: string(ProtocolFilesystem), URI: "/work/local"},
+			}
+			a.On("Registries").Return(registries, nil)
+
+			var buf bytes.Buffer
+			err := Bundle(a, "local", []string{"missing"}, &buf, nil)
+			require.Error(t, err)
+
+			err = Bundle(a, "other", nil, &buf, nil)
+			require.Error(t, err)
+		})
+	})
+}
+
+func Test_helmBundleFiles(t *testing.T) {
+	test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+		chart := helm.RepositoryChart{
+			Name:    "redis",
+			Version: "1.0.0",
+			URLs:    []string{"https://charts.example.com/redis-1.0.0.tgz"},
+		}
+		rc := &fakeHelmRepositoryClient{
+			entries: &helm.Repository{
+				Charts: map[string][]helm.RepositoryChart{"redis": {chart}},
+			},
+			fetchReader: ioutil.NopCloser(strings.NewReader("chart")),
+		}
+
+		spec := &app.RegistryConfig{
+			Name:     "charts",
+			Protocol: string(ProtocolHelm),
+			URI:      "https://charts.example.com",
+		}
+		h, err := NewHelm(a, spec, rc, nil)
+		require.NoError(t, err)
+
+		files, err := helmBundleFiles(h, nil)
+		require.NoError(t, err)
+		require.Len(t, files, 2)
+
+		assert.Equal(t, "index.yaml", files[0].Name)
+		index, err := ioutil.ReadAll(files[0].Reader)
+		require.NoError(t, err)
+		assert.Contains(t, string(index), "- redis-1.0.0.tgz")
+
+		assert.Equal(t, "redis-1.0.0.tgz", files[1].Name)
+	})
+}
+
+func TestExtractBundle_invalid_path(t *testing.T) {
+	var buf bytes.Buffer
+	files := []*archive.File{
+		{Name: "../evil", Reader: strings.NewReader("evil")},
+	}
+
+	var tgz archive.Tgz
+	require.NoError(t, tgz.Archive(&buf, files))
+
+	fs := afero.NewMemMapFs()
+	_, err := ExtractBundle(fs, &buf, "/bundle")
+	require.Error(t, err)
+}
@@ -18,26 +18,36 @@ package registry
 import (
 	"fmt"
 	"net/http"
+	"path"
 	"path/filepath"
+	"sort"
 	"strings"
 
+	msemver "github.com/Masterminds/semver"
 	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/parts"
 	"github.com/ksonnet/ksonnet/pkg/pkg"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 )
 
-// CacheDependency vendors registry dependencies.
-// TODO: create unit tests for this once mocks for this package are
-// worked out.
-func CacheDependency(a app.App, checker InstalledChecker, d pkg.Descriptor, customName string, force bool, httpClient *http.Client) (*app.LibraryConfig, error) {
+// CacheDependency vendors a registry package and the packages it depends on.
+// Dependencies declared in parts.yaml are resolved recursively, across
+// registries. A dependency which is already installed is kept if its version
+// satisfies the declared range. Only packages installed globally or, if
+// envName is set, in that environment are considered installed. Conflicting
+// requirements are reported before anything is vendored. The library
+// configuration of the package is returned first, followed by those of the
+// dependencies that were resolved.
+func CacheDependency(a app.App, checker InstalledChecker, d pkg.Descriptor, customName, envName string, force bool, httpClient *http.Client) ([]*app.LibraryConfig, error) {
 	logger := log.WithFields(log.Fields{
 		"action":      "registry.CacheDependency",
 		"part":        d.Name,
 		"registry":    d.Registry,
 		"version":     d.Version,
 		"custom-name": customName,
+		"env":         envName,
 	})
 
 	if a == nil {
@@ -54,91 +64,379 @@ func CacheDependency(a app.App, checker InstalledChecker, d pkg.Descriptor, cust
 		return nil, err
 	}
 
-	regRefSpec, exists := registries[d.Registry]
+	installed, err := installedLibraries(a, envName)
+	if err != nil {
+		return nil, err
+	}
+
+	dr := &dependencyResolver{
+		app:        a,
+		checker:    checker,
+		force:      force,
+		httpClient: httpClient,
+		registries: registries,
+		installed:  installed,
+		resolved:   make(map[string]*resolvedLibrary),
+	}
+
+	// Get all packages first, then write to disk. This protects us from
+	// failing with a half-cached dependency graph because of a network
+	// failure or a version conflict.
+	if err := dr.resolve(d, customName, nil, ""); err != nil {
+		return nil, err
+	}
+
+	var libs []*app.LibraryConfig
+	for _, rl := range dr.order {
+		if err := rl.vendor(a); err != nil {
+			return nil, err
+		}
+		libs = append(libs, rl.config)
+	}
+
+	return libs, nil
+}
+
+// resolvedLibrary is a library resolved by a dependencyResolver.
+type resolvedLibrary struct {
+	config *app.LibraryConfig
+	// version is the version declared in the library's parts.yaml.
+	version string
+	// files are the library's files. It is nil if the library is installed
+	// already.
+	files map[string][]byte
+}
+
+// vendor writes the library's files to the vendor directory.
+func (rl *resolvedLibrary) vendor(a app.App) error {
+	if rl.files == nil {
+		return nil
+	}
+
+	log.Infof("Retrieved %d files", len(rl.files))
+
+	vendorRoot := a.VendorPath()
+	for path, content := range rl.files {
+		vendoredPath := versionAndVendorRelPath(rl.config, vendorRoot, path)
+		if vendoredPath == "" {
+			log.Warnf("problem vendoring file: %v", path)
+			continue
+		}
+		dir := filepath.Dir(filepath.FromSlash(vendoredPath))
+
+		log.Debugf("onFile: vendoring file to path: %v", vendoredPath)
+		if err := a.Fs().MkdirAll(dir, app.DefaultFolderPermissions); err != nil {
+			return errors.Wrap(err, "unable to create directory")
+		}
+
+		if err := afero.WriteFile(a.Fs(), vendoredPath, content, app.DefaultFilePermissions); err != nil {
+			return errors.Wrap(err, "unable to create file")
+		}
+	}
+
+	return nil
+}
+
+// dependencyResolver resolves the dependency graph of a package.
+type dependencyResolver struct {
+	app        app.App
+	checker    InstalledChecker
+	force      bool
+	httpClient *http.Client
+	registries app.RegistryConfigs
+	installed  map[string]*app.LibraryConfig
+
+	// resolved holds the resolved libraries by registry and name.
+	resolved map[string]*resolvedLibrary
+	order    []*resolvedLibrary
+}
+
+// resolve resolves a package and its dependencies. If the package is a
+// dependency, dep is its declaration in the parts.yaml of dependent.
+func (dr *dependencyResolver) resolve(d pkg.Descriptor, customName string, dep *parts.DependencySpec, dependent string) error {
+	regRefSpec, exists := dr.registries[d.Registry]
 	if !exists {
-		return nil, fmt.Errorf("registry '%s' does not exist", d.Registry)
+		return fmt.Errorf("registry '%s' does not exist", d.Registry)
 	}
 
-	r, err := Locate(a, regRefSpec, httpClient)
+	r, err := Locate(dr.app, regRefSpec, dr.httpClient)
 	if err != nil {
-		return nil, err
+		return err
+	}
+
+	keys, err := PublicKeys(regRefSpec)
+	if err != nil {
+		return err
 	}
 
 	libSpec, err := r.ResolveLibrarySpec(d.Name, d.Version)
 	if err != nil {
-		return nil, errors.Wrapf(err, "resolving package metadata: %v", d)
+		return errors.Wrapf(err, "resolving package metadata: %v", d)
 	}
 
 	// Check whether this library version is already installed
 	var qualified = d
 	qualified.Version = libSpec.Version
-	ok, err := checker.IsInstalled(qualified)
+	ok, err := dr.checker.IsInstalled(qualified)
 	if err != nil {
-		return nil, errors.Wrapf(err, "checking package installed status: %v", qualified)
+		return errors.Wrapf(err, "checking package installed status: %v", qualified)
 	}
 
-	if ok && !force {
+	rl := &resolvedLibrary{}
+	if ok && !dr.force {
 		// We will reuse the currently installed package files
-		return &app.LibraryConfig{
+		rl.config = &app.LibraryConfig{
 			Registry: d.Registry,
 			Name:     d.Name,
 			Version:  libSpec.Version,
-		}, nil
-	}
-
-	// Get all directories and files first, then write to disk. This
-	// protects us from failing with a half-cached dependency because of
-	// a network failure.
-	directories := []string{}
-	files := map[string][]byte{}
-	_, libRef, err := r.ResolveLibrary(
-		d.Name,
-		customName,
-		d.Version,
-		func(relPath string, contents []byte) error {
-			files[relPath] = contents
-			return nil
-		},
-		func(relPath string) error {
+		}
+		rl.version = libSpec.Version
+	} else {
+		files := map[string][]byte{}
+		spec, libRef, err := r.ResolveLibrary(
+			d.Name,
+			customName,
+			d.Version,
+			func(relPath string, contents []byte) error {
+				files[relPath] = contents
+				return nil
+			},
+			func(relPath string) error {
+				return nil
+			})
+		if err != nil {
+			return errors.Wrap(err, "resolve registry library")
+		}
+
+		// Signed registries are verified before anything is vendored.
+		if len(keys) > 0 {
+			if err := verifyLibrary(r, keys, d.Name, libRef.Version, files); err != nil {
+				return err
+			}
+		}
+
+		// Make triple-sure the library references the correct registry, as it is known in this app.
+		libRef.Registry = d.Registry
+
+		rl.config = libRef
+		rl.version = spec.Version
+		rl.files = files
+	}
+
+	if dep != nil {
+		if err := checkDependencyVersion(dep, rl.version, dependent); err != nil {
+			return err
+		}
+	}
+
+	dr.resolved[d.Registry+"/"+d.Name] = rl
+	dr.order = append(dr.order, rl)
+
+	for _, dep := range libSpec.Dependencies {
+		if err := dr.resolveDependency(d, dep); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// resolveDependency resolves a dependency of a package unless it is
+// resolved or installed already.
+func (dr *dependencyResolver) resolveDependency(d pkg.Descriptor, dep *parts.DependencySpec) error {
+	registryName := dep.Registry
+	if registryName == "" {
+		registryName = d.Registry
+	}
+	dependent := fmt.Sprintf("%s/%s", d.Registry, d.Name)
+
+	if rl, ok := dr.resolved[registryName+"/"+dep.Name]; ok {
+		return checkDependencyVersion(dep, rl.version, dependent)
+	}
+
+	for _, rl := range dr.order {
+		if rl.config.Name == dep.Name {
+			return errors.Errorf("%s requires %s/%s, which conflicts with %s/%s",
+				dependent, registryName, dep.Name, rl.config.Registry, dep.Name)
+		}
+	}
+
+	if lib, ok := dr.installed[dep.Name]; ok {
+		if lib.Registry != registryName {
+			return errors.Errorf("%s requires %s/%s, which conflicts with installed package %s/%s",
+				dependent, registryName, dep.Name, lib.Registry, dep.Name)
+		}
+
+		spec, err := installedSpec(dr.app, lib)
+		if err != nil {
+			return err
+		}
+		if spec == nil {
+			log.Warnf("unable to determine the version of installed package %s/%s", lib.Registry, lib.Name)
 			return nil
-		})
+		}
+
+		return checkDependencyVersion(dep, spec.Version, dependent)
+	}
+
+	log.Infof("Resolving dependency %v of %s", dep, dependent)
+	return dr.resolve(pkg.Descriptor{Registry: registryName, Name: dep.Name}, "", dep, dependent)
+}
+
+// checkDependencyVersion returns an error if version does not satisfy the
+// version range of a dependency. Versions of packages from git based
+// registries are commit SHAs, which can't be checked against a range.
+func checkDependencyVersion(dep *parts.DependencySpec, version, dependent string) error {
+	if dep.Version == "" {
+		return nil
+	}
+
+	if _, err := msemver.NewVersion(version); err != nil {
+		log.Warnf("unable to check version %q of %s against %s, which is required by %s",
+			version, dep.Name, dep.Version, dependent)
+		return nil
+	}
+
+	ok, err := dep.Allows(version)
 	if err != nil {
-		return nil, errors.Wrap(err, "resolve registry library")
+		return errors.Wrapf(err, "checking dependency of %s", dependent)
+	}
+	if !ok {
+		return errors.Errorf("%s requires %s %s, but found version %s", dependent, dep.Name, dep.Version, version)
 	}
 
-	// Make triple-sure the library references the correct registry, as it is known in this app.
-	libRef.Registry = d.Registry
+	return nil
+}
 
-	// Add library to app specification, but wait to write it out until
-	// the end, in case one of the network calls fails.
-	log.Infof("Retrieved %d files", len(files))
+// installedLibraries returns the libraries installed globally or, if envName
+// is set, in that environment by name.
+func installedLibraries(a app.App, envName string) (map[string]*app.LibraryConfig, error) {
+	installed := make(map[string]*app.LibraryConfig)
 
-	for _, dir := range directories {
-		if err = a.Fs().MkdirAll(dir, app.DefaultFolderPermissions); err != nil {
-			return nil, errors.Wrap(err, "unable to create directory")
+	libs, err := a.Libraries()
+	if err != nil {
+		return nil, err
+	}
+	for name, lib := range libs {
+		installed[name] = lib
+	}
+
+	if envName == "" {
+		return installed, nil
+	}
+
+	e, err := a.Environment(envName)
+	if err != nil {
+		return nil, err
+	}
+	for name, lib := range e.Libraries {
+		if _, ok := installed[name]; !ok {
+			installed[name] = lib
 		}
 	}
 
-	vendorRoot := a.VendorPath()
-	for path, content := range files {
-		vendoredPath := versionAndVendorRelPath(libRef, vendorRoot, path)
-		if vendoredPath == "" {
-			log.Warnf("problem vendoring file: %v", path)
-			continue
+	return installed, nil
+}
+
+// installedSpec reads the parts.yaml of an installed library. It returns nil
+// if the library was not vendored with a parts.yaml.
+func installedSpec(a app.App, lib *app.LibraryConfig) (*parts.Spec, error) {
+	specPath := versionAndVendorRelPath(lib, a.VendorPath(), path.Join(lib.Name, partsYAMLFile))
+
+	exists, err := afero.Exists(a.Fs(), specPath)
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	data, err := afero.ReadFile(a.Fs(), specPath)
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := parts.Unmarshal(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading %s", specPath)
+	}
+
+	return spec, nil
+}
+
+// Dependent is an installed library which depends on another library.
+type Dependent struct {
+	// EnvName is the environment the library is installed in. It is empty if
+	// the library is installed globally.
+	EnvName string
+	Library *app.LibraryConfig
+}
+
+// Dependents returns the installed libraries which depend on the library
+// name from registry registryName. Libraries from any registry match if
+// registryName is empty. If envName is set, only the global libraries and the
+// libraries of that environment are checked. Otherwise the libraries of every
+// environment are checked as well.
+func Dependents(a app.App, envName, registryName, name string) ([]Dependent, error) {
+	var dependents []Dependent
+
+	check := func(envName string, libs app.LibraryConfigs) error {
+		var names []string
+		for libName := range libs {
+			names = append(names, libName)
 		}
-		dir := filepath.Dir(filepath.FromSlash(vendoredPath))
+		sort.Strings(names)
 
-		log.Debugf("onFile: vendoring file to path: %v", vendoredPath)
-		if err = a.Fs().MkdirAll(dir, app.DefaultFolderPermissions); err != nil {
-			return nil, errors.Wrap(err, "unable to create directory")
+		for _, libName := range names {
+			lib := libs[libName]
+			spec, err := installedSpec(a, lib)
+			if err != nil {
+				return err
+			}
+			if spec == nil {
+				continue
+			}
+
+			for _, dep := range spec.Dependencies {
+				depRegistry := dep.Registry
+				if depRegistry == "" {
+					depRegistry = lib.Registry
+				}
+				if dep.Name == name && (registryName == "" || depRegistry == registryName) {
+					dependents = append(dependents, Dependent{EnvName: envName, Library: lib})
+					break
+				}
+			}
+		}
+
+		return nil
+	}
+
+	libs, err := a.Libraries()
+	if err != nil {
+		return nil, err
+	}
+	if err := check("", libs); err != nil {
+		return nil, err
+	}
+
+	envs, err := a.Environments()
+	if err != nil {
+		return nil, err
+	}
+
+	var envNames []string
+	for n := range envs {
+		if envName == "" || n == envName {
+			envNames = append(envNames, n)
 		}
+	}
+	sort.Strings(envNames)
 
-		if err = afero.WriteFile(a.Fs(), vendoredPath, content, app.DefaultFilePermissions); err != nil {
-			return nil, errors.Wrap(err, "unable to create file")
+	for _, n := range envNames {
+		if err := check(n, envs[n].Libraries); err != nil {
+			return nil, err
 		}
 	}
 
-	return libRef, nil
+	return dependents, nil
 }
 
 // Convert a relative path like `mysql/parts.yaml` to a versioned, vendored path,
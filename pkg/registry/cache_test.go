@@ -21,6 +21,7 @@ import (
 
 	"github.com/ksonnet/ksonnet/pkg/app"
 	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/parts"
 	"github.com/ksonnet/ksonnet/pkg/pkg"
 	"github.com/ksonnet/ksonnet/pkg/util/test"
 	"github.com/spf13/afero"
@@ -44,6 +45,7 @@ func Test_CacheDependency(t *testing.T) {
 
 		libraries := app.LibraryConfigs{}
 		a.On("Libraries").Return(libraries, nil)
+		a.On("Environments").Return(app.EnvironmentConfigs{}, nil)
 
 		registries := app.RegistryConfigs{
 			"incubator": &app.RegistryConfig{
@@ -71,7 +73,7 @@ func Test_CacheDependency(t *testing.T) {
 			var checker installedChecker
 			d := pkg.Descriptor{Registry: lib.Registry, Name: lib.Name}
 
-			_, err := CacheDependency(a, &checker, d, "", false, nil)
+			_, err := CacheDependency(a, &checker, d, "", "", false, nil)
 			require.NoError(t, err)
 
 			test.AssertExists(t, fs, filepath.Join(a.Root(), "vendor", lib.Registry, lib.Name, "parts.yaml"))
@@ -79,6 +81,281 @@ func Test_CacheDependency(t *testing.T) {
 	})
 }
 
+func withDependencyRegistries(t *testing.T, libs app.LibraryConfigs, fn func(*amocks.App, afero.Fs)) {
+	withDependencyRegistriesEnvs(t, libs, app.EnvironmentConfigs{}, fn)
+}
+
+func withDependencyRegistriesEnvs(t *testing.T, libs app.LibraryConfigs, envs app.EnvironmentConfigs, fn func(*amocks.App, afero.Fs)) {
+	withApp(t, func(a *amocks.App, fs afero.Fs) {
+		a.On("VendorPath").Return("/app/vendor")
+
+		test.StageDir(t, fs, filepath.Join("dependencies", "main"), filepath.Join("/work", "main"))
+		test.StageDir(t, fs, filepath.Join("dependencies", "shared"), filepath.Join("/work", "shared"))
+
+		registries := app.RegistryConfigs{
+			"main": &app.RegistryConfig{
+				Name:     "main",
+				Protocol: string(ProtocolFilesystem),
+				URI:      "/work/main",
+			},
+			"shared": &app.RegistryConfig{
+				Name:     "shared",
+				Protocol: string(ProtocolFilesystem),
+				URI:      "/work/shared",
+			},
+		}
+		a.On("Registries").Return(registries, nil)
+		a.On("Libraries").Return(libs, nil)
+		a.On("Environments").Return(envs, nil)
+		for name, e := range envs {
+			a.On("Environment", name).Return(e, nil)
+		}
+
+		fn(a, fs)
+	})
+}
+
+func libNames(libs []*app.LibraryConfig) []string {
+	var names []string
+	for _, lib := range libs {
+		names = append(names, lib.Registry+"/"+lib.Name)
+	}
+	return names
+}
+
+func Test_CacheDependency_dependencies(t *testing.T) {
+	withDependencyRegistries(t, app.LibraryConfigs{}, func(a *amocks.App, fs afero.Fs) {
+		var checker installedChecker
+		d := pkg.Descriptor{Registry: "main", Name: "app"}
+
+		libs, err := CacheDependency(a, &checker, d, "", "", false, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"main/app", "main/k8s-util", "shared/logging"}, libNames(libs))
+
+		test.AssertExists(t, fs, "/app/vendor/main/app/parts.yaml")
+		test.AssertExists(t, fs, "/app/vendor/main/k8s-util/k8s-util.libsonnet")
+		test.AssertExists(t, fs, "/app/vendor/shared/logging/parts.yaml")
+	})
+}
+
+func Test_CacheDependency_cycle(t *testing.T) {
+	withDependencyRegistries(t, app.LibraryConfigs{}, func(a *amocks.App, fs afero.Fs) {
+		var checker installedChecker
+		d := pkg.Descriptor{Registry: "main", Name: "cycle-a"}
+
+		libs, err := CacheDependency(a, &checker, d, "", "", false, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"main/cycle-a", "main/cycle-b"}, libNames(libs))
+	})
+}
+
+func Test_CacheDependency_conflict(t *testing.T) {
+	withDependencyRegistries(t, app.LibraryConfigs{}, func(a *amocks.App, fs afero.Fs) {
+		var checker installedChecker
+		d := pkg.Descriptor{Registry: "main", Name: "conflict"}
+
+		_, err := CacheDependency(a, &checker, d, "", "", false, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "main/conflict requires k8s-util ^2.0.0, but found version 1.3.0")
+
+		test.AssertNotExists(t, fs, "/app/vendor/main/conflict")
+	})
+}
+
+func Test_CacheDependency_installed(t *testing.T) {
+	libs := app.LibraryConfigs{
+		"k8s-util": &app.LibraryConfig{Name: "k8s-util", Registry: "main"},
+	}
+
+	withDependencyRegistries(t, libs, func(a *amocks.App, fs afero.Fs) {
+		test.StageFile(t, fs, filepath.Join("dependencies", "main", "k8s-util", "parts.yaml"),
+			"/app/vendor/main/k8s-util/parts.yaml")
+
+		var checker installedChecker
+		d := pkg.Descriptor{Registry: "main", Name: "app"}
+
+		installed, err := CacheDependency(a, &checker, d, "", "", false, nil)
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"main/app", "shared/logging"}, libNames(installed))
+		test.AssertNotExists(t, fs, "/app/vendor/main/k8s-util/k8s-util.libsonnet")
+
+		d = pkg.Descriptor{Registry: "main", Name: "conflict"}
+		_, err = CacheDependency(a, &checker, d, "", "", false, nil)
+		require.Error(t, err)
+	})
+}
+
+func Test_CacheDependency_installed_other_environment(t *testing.T) {
+	envs := app.EnvironmentConfigs{
+		"prod": &app.EnvironmentConfig{
+			Name: "prod",
+			Libraries: app.LibraryConfigs{
+				"k8s-util": &app.LibraryConfig{Name: "k8s-util", Registry: "main"},
+			},
+		},
+		"stage": &app.EnvironmentConfig{
+			Name: "stage",
+		},
+	}
+
+	cases := []struct {
+		name     string
+		envName  string
+		expected []string
+		vendored bool
+	}{
+		{
+			name:     "global",
+			expected: []string{"main/app", "main/k8s-util", "shared/logging"},
+			vendored: true,
+		},
+		{
+			name:     "other environment",
+			envName:  "stage",
+			expected: []string{"main/app", "main/k8s-util", "shared/logging"},
+			vendored: true,
+		},
+		{
+			name:     "same environment",
+			envName:  "prod",
+			expected: []string{"main/app", "shared/logging"},
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withDependencyRegistriesEnvs(t, app.LibraryConfigs{}, envs, func(a *amocks.App, fs afero.Fs) {
+				test.StageFile(t, fs, filepath.Join("dependencies", "main", "k8s-util", "parts.yaml"),
+					"/app/vendor/main/k8s-util/parts.yaml")
+
+				var checker installedChecker
+				d := pkg.Descriptor{Registry: "main", Name: "app"}
+
+				libs, err := CacheDependency(a, &checker, d, "", tc.envName, false, nil)
+				require.NoError(t, err)
+
+				assert.Equal(t, tc.expected, libNames(libs))
+
+				libPath := "/app/vendor/main/k8s-util/k8s-util.libsonnet"
+				if tc.vendored {
+					test.AssertExists(t, fs, libPath)
+				} else {
+					test.AssertNotExists(t, fs, libPath)
+				}
+			})
+		})
+	}
+}
+
+func Test_CacheDependency_installed_other_registry(t *testing.T) {
+	libs := app.LibraryConfigs{
+		"k8s-util": &app.LibraryConfig{Name: "k8s-util", Registry: "shared"},
+	}
+
+	withDependencyRegistries(t, libs, func(a *amocks.App, fs afero.Fs) {
+		var checker installedChecker
+		d := pkg.Descriptor{Registry: "main", Name: "app"}
+
+		_, err := CacheDependency(a, &checker, d, "", "", false, nil)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "conflicts with installed package shared/k8s-util")
+	})
+}
+
+func Test_checkDependencyVersion(t *testing.T) {
+	cases := []struct {
+		name    string
+		version string
+		isErr   bool
+	}{
+		{name: "in range", version: "1.3.0"},
+		{name: "out of range", version: "2.0.0", isErr: true},
+		{name: "commit SHA", version: "40285d8a14f1ac5787e405e1023cf0c07f6aa28c"},
+	}
+
+	dep := &parts.DependencySpec{Name: "k8s-util", Version: "^1.2.0"}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkDependencyVersion(dep, tc.version, "main/app")
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func Test_Dependents(t *testing.T) {
+	libs := app.LibraryConfigs{
+		"app":      &app.LibraryConfig{Name: "app", Registry: "main"},
+		"k8s-util": &app.LibraryConfig{Name: "k8s-util", Registry: "main"},
+		"logging":  &app.LibraryConfig{Name: "logging", Registry: "shared"},
+	}
+
+	withDependencyRegistries(t, libs, func(a *amocks.App, fs afero.Fs) {
+		test.StageDir(t, fs, filepath.Join("dependencies", "main"), "/app/vendor/main")
+		test.StageDir(t, fs, filepath.Join("dependencies", "shared"), "/app/vendor/shared")
+
+		dependents, err := Dependents(a, "", "main", "k8s-util")
+		require.NoError(t, err)
+
+		expected := []Dependent{
+			{Library: libs["app"]},
+			{Library: libs["logging"]},
+		}
+		assert.Equal(t, expected, dependents)
+
+		dependents, err = Dependents(a, "", "main", "app")
+		require.NoError(t, err)
+		assert.Empty(t, dependents)
+	})
+}
+
+func Test_Dependents_environments(t *testing.T) {
+	libs := app.LibraryConfigs{
+		"k8s-util": &app.LibraryConfig{Name: "k8s-util", Registry: "main"},
+	}
+	envs := app.EnvironmentConfigs{
+		"prod": &app.EnvironmentConfig{
+			Name: "prod",
+			Libraries: app.LibraryConfigs{
+				"app": &app.LibraryConfig{Name: "app", Registry: "main"},
+			},
+		},
+		"stage": &app.EnvironmentConfig{
+			Name: "stage",
+			Libraries: app.LibraryConfigs{
+				"app": &app.LibraryConfig{Name: "app", Registry: "main"},
+			},
+		},
+	}
+
+	withDependencyRegistriesEnvs(t, libs, envs, func(a *amocks.App, fs afero.Fs) {
+		test.StageDir(t, fs, filepath.Join("dependencies", "main"), "/app/vendor/main")
+
+		dependents, err := Dependents(a, "", "main", "k8s-util")
+		require.NoError(t, err)
+
+		expected := []Dependent{
+			{EnvName: "prod", Library: envs["prod"].Libraries["app"]},
+			{EnvName: "stage", Library: envs["stage"].Libraries["app"]},
+		}
+		assert.Equal(t, expected, dependents)
+
+		dependents, err = Dependents(a, "stage", "main", "k8s-util")
+		require.NoError(t, err)
+
+		expected = []Dependent{
+			{EnvName: "stage", Library: envs["stage"].Libraries["app"]},
+		}
+		assert.Equal(t, expected, dependents)
+	})
+}
+
 func Test_versionAndVendorRelPath(t *testing.T) {
 	tests := []struct {
 		name     string
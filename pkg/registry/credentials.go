@@ -0,0 +1,245 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// RegistryCredentials are the credentials and TLS options used to access a
+// registry. Secrets can be read from environment variables, so they don't
+// have to be stored in the user configuration.
+type RegistryCredentials struct {
+	// Username and Password are used for basic authentication.
+	Username    string `json:"username,omitempty"`
+	UsernameEnv string `json:"usernameEnv,omitempty"`
+	Password    string `json:"password,omitempty"`
+	PasswordEnv string `json:"passwordEnv,omitempty"`
+	// Token is used for bearer token authentication.
+	Token    string `json:"token,omitempty"`
+	TokenEnv string `json:"tokenEnv,omitempty"`
+	// CAFile is a PEM encoded CA bundle used to verify the registry.
+	CAFile string `json:"caFile,omitempty"`
+	// CertFile and KeyFile are a PEM encoded client certificate and key.
+	CertFile string `json:"certFile,omitempty"`
+	KeyFile  string `json:"keyFile,omitempty"`
+	// InsecureSkipVerify disables verification of the registry's certificate.
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
+}
+
+// credentials returns the credentials for a registry URI, or nil if there
+// are none.
+func (c *UserConfig) credentials(uri string) *RegistryCredentials {
+	uri = strings.TrimSuffix(uri, "/")
+	for k, rc := range c.Credentials {
+		if strings.TrimSuffix(k, "/") == uri {
+			return rc
+		}
+	}
+
+	return nil
+}
+
+func valueOrEnv(value, env string) string {
+	if env != "" {
+		return os.Getenv(env)
+	}
+
+	return value
+}
+
+func (rc *RegistryCredentials) username() string {
+	return valueOrEnv(rc.Username, rc.UsernameEnv)
+}
+
+func (rc *RegistryCredentials) password() string {
+	return valueOrEnv(rc.Password, rc.PasswordEnv)
+}
+
+func (rc *RegistryCredentials) token() string {
+	return valueOrEnv(rc.Token, rc.TokenEnv)
+}
+
+func (rc *RegistryCredentials) hasTLSOptions() bool {
+	return rc.CAFile != "" || rc.CertFile != "" || rc.KeyFile != "" || rc.InsecureSkipVerify
+}
+
+// tlsConfig builds a TLS configuration from the credentials. Files are read
+// from fs.
+func (rc *RegistryCredentials) tlsConfig(fs afero.Fs, base *tls.Config) (*tls.Config, error) {
+	config := &tls.Config{}
+	if base != nil {
+		config.InsecureSkipVerify = base.InsecureSkipVerify
+		config.RootCAs = base.RootCAs
+	}
+
+	if rc.InsecureSkipVerify {
+		config.InsecureSkipVerify = true
+	}
+
+	if rc.CAFile != "" {
+		data, err := afero.ReadFile(fs, rc.CAFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading CA bundle")
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.Errorf("no certificates found in CA bundle %s", rc.CAFile)
+		}
+		config.RootCAs = pool
+	}
+
+	if rc.CertFile != "" || rc.KeyFile != "" {
+		if rc.CertFile == "" || rc.KeyFile == "" {
+			return nil, errors.New("client certificates require both certFile and keyFile")
+		}
+
+		certPEM, err := afero.ReadFile(fs, rc.CertFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading client certificate")
+		}
+		keyPEM, err := afero.ReadFile(fs, rc.KeyFile)
+		if err != nil {
+			return nil, errors.Wrap(err, "reading client key")
+		}
+
+		cert, err := tls.X509KeyPair(certPEM, keyPEM)
+		if err != nil {
+			return nil, errors.Wrap(err, "loading client certificate")
+		}
+		config.Certificates = []tls.Certificate{cert}
+	}
+
+	return config, nil
+}
+
+// authTransport adds credentials to requests for a set of hosts. Requests
+// for other hosts, e.g. chart archives served from a CDN, are sent without
+// credentials.
+type authTransport struct {
+	base  http.RoundTripper
+	hosts map[string]bool
+	creds *RegistryCredentials
+}
+
+func (t *authTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !t.hosts[req.URL.Host] {
+		return t.base.RoundTrip(req)
+	}
+
+	// RoundTrippers must not modify the request.
+	r := new(http.Request)
+	*r = *req
+	r.Header = make(http.Header, len(req.Header))
+	for k, v := range req.Header {
+		r.Header[k] = append([]string(nil), v...)
+	}
+
+	if token := t.creds.token(); token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	} else if username := t.creds.username(); username != "" {
+		r.SetBasicAuth(username, t.creds.password())
+	}
+
+	return t.base.RoundTrip(r)
+}
+
+// credentialHosts returns the hosts credentials for a registry are sent to.
+func credentialHosts(protocol Protocol, uri string) (map[string]bool, error) {
+	if !strings.Contains(uri, "://") {
+		uri = "https://" + uri
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing registry URI %q", uri)
+	}
+
+	hosts := map[string]bool{u.Host: true}
+	if protocol == ProtocolGitHub && u.Host == "github.com" {
+		// The registry is read with the GitHub API.
+		hosts["api.github.com"] = true
+	}
+
+	return hosts, nil
+}
+
+// registryHTTPClient returns the HTTP client used to access a registry. If
+// the user configuration has credentials for the registry's URI, they are
+// added to a copy of httpClient.
+func registryHTTPClient(protocol Protocol, uri string, httpClient *http.Client) (*http.Client, error) {
+	config, err := readUserConfig(userConfigFs)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := config.credentials(uri)
+	if creds == nil {
+		return httpClient, nil
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	transport := httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	if creds.hasTLSOptions() {
+		var base *tls.Config
+		if t, ok := transport.(*http.Transport); ok {
+			base = t.TLSClientConfig
+		}
+
+		tlsConfig, err := creds.tlsConfig(userConfigFs, base)
+		if err != nil {
+			return nil, errors.Wrapf(err, "configuring TLS for registry %s", uri)
+		}
+
+		transport = &http.Transport{
+			Proxy:               http.ProxyFromEnvironment,
+			TLSClientConfig:     tlsConfig,
+			TLSHandshakeTimeout: 10 * time.Second,
+			IdleConnTimeout:     90 * time.Second,
+		}
+	}
+
+	hosts, err := credentialHosts(protocol, uri)
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Timeout: httpClient.Timeout,
+		Transport: &authTransport{
+			base:  transport,
+			hosts: hosts,
+			creds: creds,
+		},
+	}, nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func authServer(t *testing.T, fn func(r *http.Request) bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !fn(r) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}))
+}
+
+func TestRegistryHTTPClient_basic_auth(t *testing.T) {
+	ts := authServer(t, func(r *http.Request) bool {
+		username, password, ok := r.BasicAuth()
+		return ok && username == "user" && password == "secret"
+	})
+	defer ts.Close()
+
+	other := authServer(t, func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == ""
+	})
+	defer other.Close()
+
+	require.NoError(t, os.Setenv("KS_TEST_REGISTRY_PASSWORD", "secret"))
+	defer os.Unsetenv("KS_TEST_REGISTRY_PASSWORD")
+
+	config := `
+credentials:
+  ` + ts.URL + `/charts/:
+    username: user
+    passwordEnv: KS_TEST_REGISTRY_PASSWORD
+`
+
+	withUserConfig(t, config, func() {
+		c, err := registryHTTPClient(ProtocolHelm, ts.URL+"/charts", nil)
+		require.NoError(t, err)
+
+		resp, err := c.Get(ts.URL + "/charts/index.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		// Credentials are not sent to other hosts.
+		resp, err = c.Get(other.URL + "/redis-3.0.0.tgz")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestRegistryHTTPClient_token(t *testing.T) {
+	ts := authServer(t, func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == "Bearer token"
+	})
+	defer ts.Close()
+
+	config := `
+credentials:
+  ` + ts.URL + `:
+    token: token
+`
+
+	withUserConfig(t, config, func() {
+		c, err := registryHTTPClient(ProtocolHelm, ts.URL, &http.Client{})
+		require.NoError(t, err)
+
+		resp, err := c.Get(ts.URL + "/index.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	})
+}
+
+func TestRegistryHTTPClient_ca_file(t *testing.T) {
+	ts := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ts.Close()
+
+	config := `
+credentials:
+  ` + ts.URL + `:
+    caFile: /home/user/ca.pem
+`
+
+	withUserConfig(t, config, func() {
+		ca := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: ts.Certificate().Raw})
+		require.NoError(t, afero.WriteFile(userConfigFs, "/home/user/ca.pem", ca, 0644))
+
+		c, err := registryHTTPClient(ProtocolHelm, ts.URL, &http.Client{})
+		require.NoError(t, err)
+
+		resp, err := c.Get(ts.URL + "/index.yaml")
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+		_, err = (&http.Client{}).Get(ts.URL + "/index.yaml")
+		require.Error(t, err)
+	})
+}
+
+func TestRegistryHTTPClient_errors(t *testing.T) {
+	cases := []struct {
+		name   string
+		config string
+	}{
+		{
+			name: "missing CA bundle",
+			config: `
+credentials:
+  https://example.com:
+    caFile: /missing.pem
+`,
+		},
+		{
+			name: "client certificate without key",
+			config: `
+credentials:
+  https://example.com:
+    certFile: /home/user/cert.pem
+`,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withUserConfig(t, tc.config, func() {
+				_, err := registryHTTPClient(ProtocolHelm, "https://example.com", nil)
+				require.Error(t, err)
+			})
+		})
+	}
+}
+
+func TestRegistryHTTPClient_no_credentials(t *testing.T) {
+	withUserConfig(t, "", func() {
+		original := &http.Client{}
+		c, err := registryHTTPClient(ProtocolHelm, "https://example.com", original)
+		require.NoError(t, err)
+		assert.True(t, original == c)
+	})
+}
+
+func Test_credentialHosts(t *testing.T) {
+	hosts, err := credentialHosts(ProtocolGitHub, "github.com/ksonnet/parts/tree/master/incubator")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"github.com": true, "api.github.com": true}, hosts)
+
+	hosts, err = credentialHosts(ProtocolHelm, "https://charts.example.com:8443/stable")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"charts.example.com:8443": true}, hosts)
+}
@@ -52,6 +52,10 @@ func NewFs(a app.App, registryRef *app.RegistryConfig) (*Fs, error) {
 	}
 
 	fs.root = u.Path
+	if a != nil && !filepath.IsAbs(fs.root) {
+		// Relative paths are relative to the app.
+		fs.root = filepath.Join(a.Root(), fs.root)
+	}
 
 	return fs, nil
 }
@@ -148,7 +152,7 @@ func (fs *Fs) ResolveLibrary(partName, partAlias, libRefSpec string, onFile Reso
 
 		// Make path relative to registry root
 		libPath := strings.TrimPrefix(
-			strings.TrimPrefix(path, fs.URI()),
+			strings.TrimPrefix(path, fs.root),
 			separator,
 		)
 		if fi.IsDir() {
@@ -243,3 +247,8 @@ func (fs *Fs) ValidateURI(uri string) (bool, error) {
 
 	return true, nil
 }
+
+// FetchFile reads a file relative to the registry root. The version is ignored.
+func (fs *Fs) FetchFile(relPath, version string) ([]byte, error) {
+	return afero.ReadFile(fs.app.Fs(), filepath.Join(fs.root, filepath.FromSlash(relPath)))
+}
@@ -0,0 +1,552 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/parts"
+	"github.com/ksonnet/ksonnet/pkg/util/git"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	defaultGitBranch = "master"
+	gitMirrorDir     = "repo.git"
+)
+
+// Git is a registry stored in any git repository. The repository is mirrored
+// into the registry cache with the git command line client.
+type Git struct {
+	app     app.App
+	name    string
+	gd      *gitDescriptor
+	spec    *app.RegistryConfig
+	fetched bool
+}
+
+var _ Registry = (*Git)(nil)
+
+// NewGit creates an instance of Git.
+func NewGit(a app.App, registryRef *app.RegistryConfig) (*Git, error) {
+	if registryRef == nil {
+		return nil, errors.New("registry ref is nil")
+	}
+
+	g := &Git{
+		app:  a,
+		name: registryRef.Name,
+		spec: registryRef,
+	}
+
+	gd, err := parseGitURI(g.URI())
+	if err != nil {
+		return nil, err
+	}
+	g.gd = gd
+
+	return g, nil
+}
+
+// Name is the registry name.
+func (g *Git) Name() string {
+	return g.name
+}
+
+// Protocol is the registry protocol.
+func (g *Git) Protocol() Protocol {
+	return Protocol(g.spec.Protocol)
+}
+
+// URI is the registry URI.
+func (g *Git) URI() string {
+	return g.spec.URI
+}
+
+// RegistrySpecDir is the registry directory.
+func (g *Git) RegistrySpecDir() string {
+	return g.Name()
+}
+
+// RegistrySpecFilePath is the path for the registry.yaml
+func (g *Git) RegistrySpecFilePath() string {
+	return path.Join(g.Name(), registryYAMLFile)
+}
+
+// mirrorPath is the path of the bare mirror of the registry's repository.
+func (g *Git) mirrorPath() string {
+	return filepath.Join(registryCacheRoot(g.app), g.Name(), gitMirrorDir)
+}
+
+// repoURL returns the repository URL. Relative local paths are resolved
+// against the application root.
+func (g *Git) repoURL() string {
+	repo := g.gd.repo
+	if isLocalGitPath(repo) && !filepath.IsAbs(repo) {
+		return filepath.Join(g.app.Root(), repo)
+	}
+	return repo
+}
+
+// update clones the repository into the registry cache, or fetches it if it
+// has been cloned already. The repository is fetched at most once.
+func (g *Git) update() error {
+	if g.fetched {
+		return nil
+	}
+
+	log := log.WithField("action", "Git.update")
+	mirror := g.mirrorPath()
+	repo := g.repoURL()
+
+	// git works on the disk, so the cache is created with the os package
+	// rather than the app's file system.
+	if _, err := os.Stat(filepath.Join(mirror, "HEAD")); os.IsNotExist(err) {
+		log.Debugf("cloning %v into %v", repo, mirror)
+		if err = os.MkdirAll(filepath.Dir(mirror), app.DefaultFolderPermissions); err != nil {
+			return err
+		}
+		if _, err = git.Run("", "clone", "--mirror", "--quiet", repo, mirror); err != nil {
+			return err
+		}
+	} else {
+		log.Debugf("fetching %v into %v", repo, mirror)
+		if _, err = git.Run(mirror, "remote", "set-url", "origin", repo); err != nil {
+			return err
+		}
+		if _, err = git.Run(mirror, "fetch", "--prune", "--quiet", "origin"); err != nil {
+			return err
+		}
+	}
+
+	g.fetched = true
+	return nil
+}
+
+// resolveSHA fetches the repository and resolves a branch, tag or commit to
+// the SHA of a commit.
+func (g *Git) resolveSHA(refSpec string) (string, error) {
+	if refSpec == "" {
+		refSpec = g.gd.refSpec
+	}
+
+	if err := g.update(); err != nil {
+		return "", err
+	}
+
+	sha, err := git.Run(g.mirrorPath(), "rev-parse", "--verify", "--quiet", refSpec+"^{commit}")
+	if err != nil {
+		return "", errors.Errorf("unable to find commit for %q in %v", refSpec, g.gd.repo)
+	}
+
+	return strings.TrimSpace(sha), nil
+}
+
+// resolveLatestSHA resolves the SHA currently pointed to by the configured RefSpec.
+func (g *Git) resolveLatestSHA() (string, error) {
+	return g.resolveSHA(g.gd.refSpec)
+}
+
+// objectType returns the type (blob or tree) of a path in a commit.
+func (g *Git) objectType(sha, p string) (string, error) {
+	t, err := git.Run(g.mirrorPath(), "cat-file", "-t", sha+":"+p)
+	if err != nil {
+		return "", errors.Errorf("%q does not exist at %v in registry %q", p, sha, g.Name())
+	}
+
+	return strings.TrimSpace(t), nil
+}
+
+// readFile reads the contents of a file in a commit.
+func (g *Git) readFile(sha, p string) ([]byte, error) {
+	contents, err := git.Run(g.mirrorPath(), "cat-file", "blob", sha+":"+p)
+	if err != nil {
+		return nil, errors.Errorf("%q does not exist at %v in registry %q", p, sha, g.Name())
+	}
+
+	return []byte(contents), nil
+}
+
+// repoPath returns a path relative to the repository root for a path
+// relative to the registry root.
+func (g *Git) repoPath(elem ...string) string {
+	return path.Join(append([]string{g.gd.regRepoPath}, elem...)...)
+}
+
+// FetchRegistrySpec fetches the registry spec (registry.yaml, inventory of packages)
+// This inventory may have been previously cached on disk. If the cache is not stale,
+// it will be used. Otherwise, the spec is read from the fetched repository.
+func (g *Git) FetchRegistrySpec() (*Spec, error) {
+	log := log.WithField("action", "Git.FetchRegistrySpec")
+
+	registrySpecFile := registrySpecFilePath(g.app, g)
+
+	log.Debugf("checking for registry cache: %v", registrySpecFile)
+	registrySpec, exists, err := load(g.app, registrySpecFile)
+	if err != nil {
+		log.Warnf("error loading cache for %v (%v), trying to refresh instead", g.spec.Name, err)
+		exists = false
+	}
+
+	var cachedVersion string
+	if registrySpec != nil {
+		cachedVersion = registrySpec.Version
+	}
+
+	sha, err := g.resolveLatestSHA()
+	if err != nil {
+		errMsg := errors.Wrapf(err, "unable to resolve commit for refspec: %v", g.gd.refSpec)
+		if registrySpec == nil || cachedVersion == "" {
+			return nil, errMsg
+		}
+
+		log.Warnf("%v", errMsg)
+		log.Warnf("falling back to cached version (%v)", cachedVersion)
+		updateLibVersions(registrySpec, g.gd.refSpec)
+		return registrySpec, nil
+	}
+
+	if exists && cachedVersion == sha {
+		log.Debugf("using cache @%v", sha)
+		updateLibVersions(registrySpec, sha)
+		return registrySpec, nil
+	}
+
+	data, err := g.readFile(sha, g.repoPath(registryYAMLFile))
+	if err != nil {
+		return nil, errors.Errorf("could not find valid registry at %v", g.URI())
+	}
+
+	registrySpec, err = Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Version will persisted in registry.yaml cache.
+	// This allows us to check whether the cache is stale.
+	registrySpec.Version = sha
+	updateLibVersions(registrySpec, sha)
+
+	registrySpecBytes, err := registrySpec.Marshal()
+	if err != nil {
+		return nil, err
+	}
+
+	registrySpecDir := filepath.Join(registryCacheRoot(g.app), g.RegistrySpecDir())
+	if err = g.app.Fs().MkdirAll(registrySpecDir, app.DefaultFolderPermissions); err != nil {
+		return nil, err
+	}
+
+	if err = afero.WriteFile(g.app.Fs(), registrySpecFile, registrySpecBytes, app.DefaultFilePermissions); err != nil {
+		return nil, err
+	}
+
+	return registrySpec, nil
+}
+
+// MakeRegistryConfig returns an app registry ref spec.
+func (g *Git) MakeRegistryConfig() *app.RegistryConfig {
+	return g.spec
+}
+
+// ResolveLibrarySpec returns a resolved spec for a part.
+func (g *Git) ResolveLibrarySpec(partName, libRefSpec string) (*parts.Spec, error) {
+	sha, err := g.resolveSHA(libRefSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := g.readFile(sha, g.repoPath(partName, partsYAMLFile))
+	if err != nil {
+		return nil, err
+	}
+
+	spec, err := parts.Unmarshal(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// As for GitHub, the SHA is the correct version, not what is written in the spec file.
+	spec.Version = sha
+
+	return spec, nil
+}
+
+// ResolveLibrary fetches the part and creates a parts spec and library ref spec.
+func (g *Git) ResolveLibrary(partName, partAlias, libRefSpec string, onFile ResolveFile, onDir ResolveDirectory) (*parts.Spec, *app.LibraryConfig, error) {
+	if g == nil {
+		return nil, nil, errors.Errorf("nil receiver")
+	}
+
+	sha, err := g.resolveSHA(libRefSpec)
+	if err != nil {
+		return nil, nil, errors.Wrapf(err, "unable to resolve commit for refspec: %v", libRefSpec)
+	}
+
+	partPath := g.repoPath(partName)
+	t, err := g.objectType(sha, partPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if t != "tree" {
+		return nil, nil, errors.Errorf("Lib ID %q resolves to a file in registry %q", partName, g.Name())
+	}
+
+	// -t lists trees before their contents, so directories are resolved
+	// before the files they contain.
+	out, err := git.Run(g.mirrorPath(), "ls-tree", "-r", "-t", "-z", sha+":"+partPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var partsSpecData []byte
+	for _, entry := range strings.Split(out, "\x00") {
+		if entry == "" {
+			continue
+		}
+
+		item, err := parseTreeEntry(entry)
+		if err != nil {
+			return nil, nil, err
+		}
+		relPath := path.Join(partName, item.path)
+
+		switch {
+		case item.kind == "tree":
+			if err := onDir(relPath); err != nil {
+				return nil, nil, err
+			}
+		case item.kind == "commit":
+			return nil, nil, errors.Errorf("Invalid library %q; ksonnet doesn't support libraries with symlinks or submodules", partName)
+		case item.mode == "120000":
+			// Symlinks are skipped.
+		default:
+			contents, err := git.Run(g.mirrorPath(), "cat-file", "blob", item.object)
+			if err != nil {
+				return nil, nil, err
+			}
+			if item.path == partsYAMLFile {
+				partsSpecData = []byte(contents)
+			}
+			if err := onFile(relPath, []byte(contents)); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if partsSpecData == nil {
+		return nil, nil, errors.Errorf("library %q in registry %q does not contain %s", partName, g.Name(), partsYAMLFile)
+	}
+
+	spec, err := parts.Unmarshal(partsSpecData)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if partAlias == "" {
+		partAlias = partName
+	}
+
+	refSpec := &app.LibraryConfig{
+		Name:     partAlias,
+		Registry: g.Name(),
+		Version:  sha,
+	}
+
+	return spec, refSpec, nil
+}
+
+// CacheRoot returns the root for caching - it removes any leading path segments
+// from a provided path, leaving just the relative path under the registry name.
+// Example:
+//  uri:    https://git.example.com/parts.git//long/path/incubator
+//  path:   long/path/incubator/parts.yaml
+//  output: incubator/parts.yaml
+func (g *Git) CacheRoot(name, relPath string) (string, error) {
+	if g == nil {
+		return "", errors.Errorf("nil receiver")
+	}
+	if g.gd == nil {
+		return "", errors.Errorf("registry %v not correctly initialized - missing gitDescriptor", g.name)
+	}
+
+	rebasedAbs := strings.TrimPrefix(strings.TrimPrefix(relPath, "/"), g.gd.regRepoPath)
+	rebased := strings.TrimPrefix(rebasedAbs, "/")
+	return filepath.Join(name, rebased), nil
+}
+
+// SetURI implements registry.Setter. It sets the URI for the registry.
+func (g *Git) SetURI(uri string) error {
+	if g == nil {
+		return errors.Errorf("nil receiver")
+	}
+	if g.spec == nil {
+		return errors.Errorf("nil spec")
+	}
+
+	gd, err := parseGitURI(uri)
+	if err != nil {
+		return err
+	}
+	if ok, err := g.ValidateURI(uri); err != nil || !ok {
+		return errors.Wrap(err, "validating uri")
+	}
+
+	g.gd = gd
+	g.spec.URI = uri
+	g.fetched = false
+
+	return nil
+}
+
+// ValidateURI implements registry.Validator. A URI is valid if it can be
+// parsed and the repository it points to can be listed with `git ls-remote`.
+func (g *Git) ValidateURI(uri string) (bool, error) {
+	if g == nil {
+		return false, errors.Errorf("nil receiver")
+	}
+
+	gd, err := parseGitURI(uri)
+	if err != nil {
+		return false, errors.Wrap(err, "parsing git registry URI")
+	}
+
+	repo := gd.repo
+	if isLocalGitPath(repo) && !filepath.IsAbs(repo) {
+		repo = filepath.Join(g.app.Root(), repo)
+	}
+
+	if _, err := git.Run("", "ls-remote", "--quiet", repo); err != nil {
+		return false, errors.Wrap(err, "validating git registry URI")
+	}
+
+	return true, nil
+}
+
+type treeEntry struct {
+	mode   string
+	kind   string
+	object string
+	path   string
+}
+
+// parseTreeEntry parses an entry of `git ls-tree` output:
+// `<mode> SP <type> SP <object> TAB <path>`.
+func parseTreeEntry(entry string) (treeEntry, error) {
+	tab := strings.Index(entry, "\t")
+	if tab == -1 {
+		return treeEntry{}, errors.Errorf("invalid git tree entry %q", entry)
+	}
+
+	fields := strings.Fields(entry[:tab])
+	if len(fields) != 3 {
+		return treeEntry{}, errors.Errorf("invalid git tree entry %q", entry)
+	}
+
+	return treeEntry{
+		mode:   fields[0],
+		kind:   fields[1],
+		object: fields[2],
+		path:   entry[tab+1:],
+	}, nil
+}
+
+type gitDescriptor struct {
+	repo        string
+	refSpec     string
+	regRepoPath string
+}
+
+// parseGitURI parses a git registry URI. URIs are of the form
+// `<repository>[//<path-to-registry>][#<ref>]`, e.g.
+// `https://git.example.com/org/parts.git//incubator#v1.0`. The repository
+// can be any URL git understands, including `file://` URLs and scp-like
+// `user@host:path` addresses. The ref defaults to master.
+func parseGitURI(uri string) (*gitDescriptor, error) {
+	uri = strings.TrimSpace(uri)
+	if uri == "" {
+		return nil, errors.New("git registry URI is empty")
+	}
+
+	gd := &gitDescriptor{refSpec: defaultGitBranch}
+
+	if i := strings.LastIndex(uri, "#"); i != -1 {
+		if ref := uri[i+1:]; ref != "" {
+			gd.refSpec = ref
+		}
+		uri = uri[:i]
+	}
+
+	start := 0
+	if i := strings.Index(uri, "://"); i != -1 {
+		start = i + len("://")
+	}
+	if i := strings.Index(uri[start:], "//"); i != -1 {
+		gd.regRepoPath = strings.Trim(uri[start+i+2:], "/")
+		uri = uri[:start+i]
+	}
+
+	if uri == "" {
+		return nil, errors.New("git registry URI must contain a repository")
+	}
+	gd.repo = uri
+
+	return gd, nil
+}
+
+// isLocalGitPath returns true if a repository is a path on the local file
+// system rather than a URL or an scp-like address.
+func isLocalGitPath(repo string) bool {
+	if strings.Contains(repo, "://") {
+		return false
+	}
+
+	colon := strings.Index(repo, ":")
+	return colon == -1 || strings.Contains(repo[:colon], "/")
+}
+
+// IsGitURI returns true if uri looks like the URI of a git repository, i.e.
+// it uses a git specific scheme or the repository ends in `.git`.
+func IsGitURI(uri string) bool {
+	for _, prefix := range []string{"git://", "git@", "ssh://"} {
+		if strings.HasPrefix(uri, prefix) {
+			return true
+		}
+	}
+
+	gd, err := parseGitURI(uri)
+	if err != nil {
+		return false
+	}
+
+	return strings.HasSuffix(strings.TrimSuffix(gd.repo, "/"), ".git")
+}
+
+// FetchFile reads a file relative to the registry root at a version.
+func (g *Git) FetchFile(relPath, version string) ([]byte, error) {
+	sha, err := g.resolveSHA(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return g.readFile(sha, g.repoPath(relPath))
+}
@@ -0,0 +1,398 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/git"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type gitFixture struct {
+	root   string
+	repo   string
+	tagSHA string
+	sha    string
+}
+
+// withGitRegistry creates a bare repository containing the incubator test
+// registry at `incubator`. The tag v0.1 points at the first commit, and
+// master at a second commit.
+func withGitRegistry(t *testing.T, fn func(*mocks.App, gitFixture)) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "git-registry")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	work := filepath.Join(dir, "work")
+	run := func(args ...string) string {
+		out, err := git.Run(work, args...)
+		require.NoError(t, err)
+		return strings.TrimSpace(out)
+	}
+
+	partRoot := filepath.Join("testdata", "part")
+	err = filepath.Walk(partRoot, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		newPath := filepath.Join(work, strings.TrimPrefix(path, partRoot))
+		if fi.IsDir() {
+			return os.MkdirAll(newPath, 0750)
+		}
+
+		data, err := ioutil.ReadFile(path)
+		require.NoError(t, err)
+
+		return ioutil.WriteFile(newPath, data, 0644)
+	})
+	require.NoError(t, err)
+
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "fs-registry.yaml"))
+	require.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(work, "incubator", registryYAMLFile), data, 0644)
+	require.NoError(t, err)
+
+	run("init", "-q")
+	run("checkout", "-q", "-b", "master")
+	run("config", "user.email", "ks@example.com")
+	run("config", "user.name", "ks")
+	run("add", ".")
+	run("commit", "-q", "-m", "initial")
+	run("tag", "v0.1")
+
+	err = ioutil.WriteFile(filepath.Join(work, "incubator", "apache", "README.md"), []byte("updated"), 0644)
+	require.NoError(t, err)
+	run("commit", "-q", "-am", "update")
+
+	f := gitFixture{
+		root:   filepath.Join(dir, "app"),
+		repo:   filepath.Join(dir, "parts.git"),
+		tagSHA: run("rev-parse", "v0.1"),
+		sha:    run("rev-parse", "master"),
+	}
+
+	_, err = git.Run(dir, "clone", "-q", "--bare", work, f.repo)
+	require.NoError(t, err)
+
+	require.NoError(t, os.MkdirAll(f.root, 0750))
+
+	appMock := &mocks.App{}
+	appMock.On("Fs").Return(afero.NewOsFs())
+	appMock.On("Root").Return(f.root)
+
+	fn(appMock, f)
+}
+
+func newTestGit(t *testing.T, a app.App, uri string) *Git {
+	g, err := NewGit(a, &app.RegistryConfig{
+		Name:     "incubator",
+		Protocol: string(ProtocolGit),
+		URI:      uri,
+	})
+	require.NoError(t, err)
+	return g
+}
+
+func TestGit_metadata(t *testing.T) {
+	g := newTestGit(t, nil, "https://git.example.com/parts.git//incubator")
+
+	assert.Equal(t, "incubator", g.Name())
+	assert.Equal(t, ProtocolGit, g.Protocol())
+	assert.Equal(t, "https://git.example.com/parts.git//incubator", g.URI())
+	assert.Equal(t, "incubator", g.RegistrySpecDir())
+	assert.Equal(t, "incubator/registry.yaml", g.RegistrySpecFilePath())
+}
+
+func TestGit_FetchRegistrySpec(t *testing.T) {
+	withGitRegistry(t, func(a *mocks.App, f gitFixture) {
+		g := newTestGit(t, a, "file://"+f.repo+"//incubator")
+
+		spec, err := g.FetchRegistrySpec()
+		require.NoError(t, err)
+		assert.Equal(t, f.sha, spec.Version)
+		require.Contains(t, spec.Libraries, "apache")
+		assert.Equal(t, f.sha, spec.Libraries["apache"].Version)
+
+		cached := filepath.Join(f.root, ".ksonnet", "registries", "incubator", registryYAMLFile)
+		data, err := ioutil.ReadFile(cached)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), f.sha)
+
+		// The cache is reused by a new registry instance.
+		g = newTestGit(t, a, "file://"+f.repo+"//incubator")
+		spec, err = g.FetchRegistrySpec()
+		require.NoError(t, err)
+		assert.Equal(t, f.sha, spec.Version)
+	})
+}
+
+func TestGit_FetchRegistrySpec_ref(t *testing.T) {
+	withGitRegistry(t, func(a *mocks.App, f gitFixture) {
+		g := newTestGit(t, a, f.repo+"//incubator#v0.1")
+
+		spec, err := g.FetchRegistrySpec()
+		require.NoError(t, err)
+		assert.Equal(t, f.tagSHA, spec.Version)
+	})
+}
+
+func TestGit_FetchRegistrySpec_invalid_ref(t *testing.T) {
+	withGitRegistry(t, func(a *mocks.App, f gitFixture) {
+		g := newTestGit(t, a, f.repo+"//incubator#missing")
+
+		_, err := g.FetchRegistrySpec()
+		require.Error(t, err)
+	})
+}
+
+func TestGit_FetchRegistrySpec_no_registry(t *testing.T) {
+	withGitRegistry(t, func(a *mocks.App, f gitFixture) {
+		g := newTestGit(t, a, f.repo)
+
+		_, err := g.FetchRegistrySpec()
+		require.Error(t, err)
+	})
+}
+
+func TestGit_ResolveLibrarySpec(t *testing.T) {
+	withGitRegistry(t, func(a *mocks.App, f gitFixture) {
+		g := newTestGit(t, a, f.repo+"//incubator")
+
+		spec, err := g.ResolveLibrarySpec("apache", "v0.1")
+		require.NoError(t, err)
+		assert.Equal(t, "apache", spec.Name)
+		assert.Equal(t, f.tagSHA, spec.Version)
+
+		spec, err = g.ResolveLibrarySpec("apache", "")
+		require.NoError(t, err)
+		assert.Equal(t, f.sha, spec.Version)
+
+		_, err = g.ResolveLibrarySpec("missing", "")
+		require.Error(t, err)
+	})
+}
+
+func TestGit_ResolveLibrary(t *testing.T) {
+	withGitRegistry(t, func(a *mocks.App, f gitFixture) {
+		g := newTestGit(t, a, f.repo+"//incubator")
+
+		var dirs []string
+		files := map[string]string{}
+
+		onFile := func(relPath string, contents []byte) error {
+			files[relPath] = string(contents)
+			return nil
+		}
+		onDir := func(relPath string) error {
+			dirs = append(dirs, relPath)
+			return nil
+		}
+
+		spec, libCfg, err := g.ResolveLibrary("apache", "web", "v0.1", onFile, onDir)
+		require.NoError(t, err)
+
+		assert.Equal(t, "apache", spec.Name)
+
+		expectedCfg := &app.LibraryConfig{
+			Name:     "web",
+			Registry: "incubator",
+			Version:  f.tagSHA,
+		}
+		assert.Equal(t, expectedCfg, libCfg)
+
+		sort.Strings(dirs)
+		assert.Equal(t, []string{"apache/examples", "apache/prototypes"}, dirs)
+
+		var names []string
+		for name := range files {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		expectedNames := []string{
+			"apache/README.md",
+			"apache/apache.libsonnet",
+			"apache/examples/apache.jsonnet",
+			"apache/examples/generated.yaml",
+			"apache/parts.yaml",
+			"apache/prototypes/apache-simple.jsonnet",
+		}
+		assert.Equal(t, expectedNames, names)
+
+		readme, err := ioutil.ReadFile(filepath.Join("testdata", "part", "incubator", "apache", "README.md"))
+		require.NoError(t, err)
+		assert.Equal(t, string(readme), files["apache/README.md"])
+	})
+}
+
+func TestGit_ResolveLibrary_file(t *testing.T) {
+	withGitRegistry(t, func(a *mocks.App, f gitFixture) {
+		g := newTestGit(t, a, f.repo+"//incubator")
+
+		nop := func(string) error { return nil }
+		onFile := func(string, []byte) error { return nil }
+
+		_, _, err := g.ResolveLibrary("registry.yaml", "", "", onFile, nop)
+		require.Error(t, err)
+	})
+}
+
+func TestGit_ValidateURI(t *testing.T) {
+	withGitRegistry(t, func(a *mocks.App, f gitFixture) {
+		g := newTestGit(t, a, f.repo)
+
+		ok, err := g.ValidateURI("file://" + f.repo + "//incubator#v0.1")
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = g.ValidateURI("file://" + filepath.Join(f.root, "missing.git"))
+		require.Error(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestGit_SetURI(t *testing.T) {
+	withGitRegistry(t, func(a *mocks.App, f gitFixture) {
+		g := newTestGit(t, a, f.repo+"//incubator")
+
+		spec, err := g.FetchRegistrySpec()
+		require.NoError(t, err)
+		assert.Equal(t, f.sha, spec.Version)
+
+		err = g.SetURI(f.repo + "//incubator#v0.1")
+		require.NoError(t, err)
+		assert.Equal(t, f.repo+"//incubator#v0.1", g.MakeRegistryConfig().URI)
+
+		spec, err = g.FetchRegistrySpec()
+		require.NoError(t, err)
+		assert.Equal(t, f.tagSHA, spec.Version)
+
+		err = g.SetURI("")
+		require.Error(t, err)
+	})
+}
+
+func TestGit_CacheRoot(t *testing.T) {
+	g := newTestGit(t, nil, "https://git.example.com/parts.git//long/path/incubator")
+
+	got, err := g.CacheRoot("incubator", "long/path/incubator/parts.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("incubator", "parts.yaml"), got)
+}
+
+func Test_parseGitURI(t *testing.T) {
+	cases := []struct {
+		name     string
+		uri      string
+		expected *gitDescriptor
+		isErr    bool
+	}{
+		{
+			name:     "https",
+			uri:      "https://git.example.com/org/parts.git",
+			expected: &gitDescriptor{repo: "https://git.example.com/org/parts.git", refSpec: "master"},
+		},
+		{
+			name: "https with path and ref",
+			uri:  "https://git.example.com/org/parts.git//incubator/#v1.0",
+			expected: &gitDescriptor{
+				repo: "https://git.example.com/org/parts.git", refSpec: "v1.0", regRepoPath: "incubator",
+			},
+		},
+		{
+			name: "file",
+			uri:  "file:///srv/git/parts.git//nested/incubator",
+			expected: &gitDescriptor{
+				repo: "file:///srv/git/parts.git", refSpec: "master", regRepoPath: "nested/incubator",
+			},
+		},
+		{
+			name: "scp-like",
+			uri:  "git@git.example.com:org/parts.git#release",
+			expected: &gitDescriptor{
+				repo: "git@git.example.com:org/parts.git", refSpec: "release",
+			},
+		},
+		{
+			name: "empty ref",
+			uri:  "/srv/git/parts.git#",
+			expected: &gitDescriptor{
+				repo: "/srv/git/parts.git", refSpec: "master",
+			},
+		},
+		{
+			name:  "empty",
+			uri:   " ",
+			isErr: true,
+		},
+		{
+			name:  "no repository",
+			uri:   "//incubator",
+			isErr: true,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			gd, err := parseGitURI(tc.uri)
+			if tc.isErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, gd)
+		})
+	}
+}
+
+func TestIsGitURI(t *testing.T) {
+	cases := []struct {
+		uri      string
+		expected bool
+	}{
+		{uri: "https://git.example.com/org/parts.git//incubator#v1.0", expected: true},
+		{uri: "file:///srv/git/parts.git", expected: true},
+		{uri: "git@git.example.com:org/parts", expected: true},
+		{uri: "ssh://git.example.com/org/parts", expected: true},
+		{uri: "git://git.example.com/org/parts", expected: true},
+		{uri: "github.com/ksonnet/parts/tree/master/incubator", expected: false},
+		{uri: "https://kubernetes-charts.storage.googleapis.com", expected: false},
+		{uri: "/srv/registry", expected: false},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.uri, func(t *testing.T) {
+			assert.Equal(t, tc.expected, IsGitURI(tc.uri))
+		})
+	}
+}
@@ -695,3 +695,35 @@ func (gh *GitHub) ValidateURI(uri string) (bool, error) {
 
 	return true, nil
 }
+
+// FetchFile reads a file relative to the registry root at a version.
+func (gh *GitHub) FetchFile(relPath, version string) ([]byte, error) {
+	ctx := context.Background()
+
+	var sha string
+	var err error
+	if version == "" {
+		sha, err = gh.resolveLatestSHA()
+	} else {
+		sha, err = gh.ghClient.CommitSHA1(ctx, gh.hd.Repo(), version)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	filePath := strings.Join([]string{gh.hd.regRepoPath, relPath}, "/")
+	file, _, err := gh.ghClient.Contents(ctx, gh.hd.Repo(), filePath, sha)
+	if err != nil {
+		return nil, err
+	}
+	if file == nil {
+		return nil, errors.Errorf("%q is not a file in registry %q", relPath, gh.Name())
+	}
+
+	content, err := file.GetContent()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(content), nil
+}
@@ -17,7 +17,7 @@ package registry
 
 import (
 	"bytes"
-	"io/ioutil"
+	"net/http"
 	"net/url"
 	"path"
 	"path/filepath"
@@ -29,6 +29,7 @@ import (
 	"github.com/ksonnet/ksonnet/pkg/util/archive"
 	ksstrings "github.com/ksonnet/ksonnet/pkg/util/strings"
 	"github.com/pkg/errors"
+	"github.com/spf13/afero"
 )
 
 var (
@@ -46,6 +47,8 @@ type Helm struct {
 	spec             *app.RegistryConfig
 	repositoryClient helm.RepositoryClient
 	unarchiver       archive.Unarchiver
+	// httpClient is used for the repositories of chart dependencies.
+	httpClient *http.Client
 }
 
 // NewHelm creates an instance of Helm.
@@ -126,6 +129,7 @@ func makeChartSpec(chart *helm.RepositoryChart) *parts.Spec {
 		Name:        chart.Name,
 		Version:     chart.Version,
 		Description: chart.Description,
+		Keywords:    chart.Keywords,
 	}
 }
 
@@ -146,36 +150,15 @@ func (h *Helm) ResolveLibrary(partName string, partAlias string, version string,
 		return nil, nil, errors.Wrapf(err, "retrieving chart %s-%s", partName, version)
 	}
 
-	for _, u := range chart.URLs {
-		r, err := h.repositoryClient.Fetch(u)
-		if r != nil {
-			defer r.Close()
-		}
-
-		if err != nil {
-			return nil, nil, err
-		}
-
-		handler := func(f *archive.File) error {
-			var b []byte
-			b, err = ioutil.ReadAll(f.Reader)
-			if err != nil {
-				return err
-			}
-
-			name := path.Join(chart.Name, "helm", chart.Version, f.Name)
-
-			if containsHelmHook(name, b) {
-				// skip this file because it has a helm hook in it
-				return nil
-			}
-
-			return onFile(name, b)
-		}
+	dr := &chartDependencyResolver{
+		helm:    h,
+		clients: make(map[string]helm.RepositoryClient),
+		onFile:  onFile,
+	}
 
-		if err = h.unarchiver.Unarchive(r, handler); err != nil {
-			return nil, nil, err
-		}
+	dir := path.Join(chart.Name, "helm", chart.Version)
+	if err = dr.fetch(h.repositoryClient, chart, dir, 0); err != nil {
+		return nil, nil, err
 	}
 
 	part := makeChartSpec(chart)
@@ -218,11 +201,24 @@ func (h *Helm) Update(version string) (string, error) {
 // ValidateURI implements registry.Validator. A URI is valid if:
 //   * It is a valid URI (RFC 3986)
 //   * It is an absolute URI or an absolute path
+// A local repository is valid if its directory exists.
 func (h *Helm) ValidateURI(uri string) (bool, error) {
 	if h == nil {
 		return false, errors.Errorf("nil receiver")
 	}
 
+	if isLocalHelmURI(uri) && h.app != nil {
+		dir := localHelmPath(h.app, uri)
+		exists, err := afero.DirExists(h.app.Fs(), dir)
+		if err != nil {
+			return false, err
+		}
+		if !exists {
+			return false, errors.Errorf("helm repository directory %q does not exist", dir)
+		}
+		return true, nil
+	}
+
 	_, err := url.ParseRequestURI(uri)
 	if err != nil {
 		return false, err
@@ -260,3 +256,68 @@ func containsHelmHook(name string, b []byte) bool {
 
 	return false
 }
+
+// newHelmClient creates a client for a Helm repository. Repositories in a
+// local directory, like the ones created from bundles, are read from the
+// file system.
+func newHelmClient(a app.App, uri string, httpClient *http.Client) (helm.RepositoryClient, error) {
+	if isLocalHelmURI(uri) && a != nil {
+		return helm.NewDirClient(a.Fs(), localHelmPath(a, uri)), nil
+	}
+
+	if httpClient == nil {
+		return helm.NewHTTPClient(uri, nil)
+	}
+
+	return helm.NewHTTPClient(uri, httpClient)
+}
+
+// IsLocalChartDir returns true if uri is a local directory which is a Helm
+// repository rather than a ksonnet registry: it has no registry.yaml, but an
+// index.yaml, chart directories or chart archives.
+func IsLocalChartDir(a app.App, uri string) bool {
+	if a == nil || !isLocalHelmURI(uri) {
+		return false
+	}
+
+	dir := localHelmPath(a, uri)
+	if ok, _ := afero.Exists(a.Fs(), filepath.Join(dir, registryYAMLFile)); ok {
+		return false
+	}
+
+	repo, err := helm.NewDirClient(a.Fs(), dir).Repository()
+	if err != nil {
+		return false
+	}
+
+	return len(repo.Charts) > 0
+}
+
+// isLocalHelmURI returns true if a Helm repository URI is a path, or a file URI.
+func isLocalHelmURI(uri string) bool {
+	if uri == "" {
+		return false
+	}
+
+	u, err := url.Parse(uri)
+	if err != nil {
+		return false
+	}
+
+	return u.Scheme == "" || u.Scheme == "file"
+}
+
+// localHelmPath returns the directory of a local Helm repository. Relative
+// paths are relative to the app.
+func localHelmPath(a app.App, uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+
+	if filepath.IsAbs(u.Path) {
+		return u.Path
+	}
+
+	return filepath.Join(a.Root(), u.Path)
+}
@@ -0,0 +1,256 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"io/ioutil"
+	"path"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/helm"
+	"github.com/ksonnet/ksonnet/pkg/util/archive"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// maxChartDependencyDepth limits how deeply chart dependencies are nested.
+const maxChartDependencyDepth = 10
+
+// chartDependencyResolver fetches a chart along with the dependencies
+// declared in its requirements.yaml. Dependencies which are not bundled in
+// the chart are fetched from their repositories and vendored in the charts/
+// directory of the chart which depends on them, so Helm renders the complete
+// chart.
+type chartDependencyResolver struct {
+	helm    *Helm
+	clients map[string]helm.RepositoryClient
+	onFile  ResolveFile
+}
+
+// chartArchive describes the chart found in a chart archive.
+type chartArchive struct {
+	// name is the name of the chart's directory in the archive.
+	name string
+	// requirements is the content of the chart's requirements.yaml.
+	requirements []byte
+	// bundled are the entries of the chart's charts/ directory.
+	bundled map[string]bool
+}
+
+// hasBundled returns true if a dependency is bundled in the chart, either
+// as a directory or as an archive.
+func (ca *chartArchive) hasBundled(name string) bool {
+	for entry := range ca.bundled {
+		if entry == name {
+			return true
+		}
+		if strings.HasPrefix(entry, name+"-") && strings.HasSuffix(entry, ".tgz") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// fetch fetches a chart from a repository into dir, and then its dependencies.
+func (dr *chartDependencyResolver) fetch(rc helm.RepositoryClient, chart *helm.RepositoryChart, dir string, depth int) error {
+	if depth > maxChartDependencyDepth {
+		return errors.Errorf("dependencies of chart %s are nested too deeply", chart.Name)
+	}
+
+	for _, u := range chart.URLs {
+		ca, err := dr.fetchArchive(rc, u, dir)
+		if err != nil {
+			return err
+		}
+
+		if err = dr.resolveDependencies(rc, ca, dir, depth); err != nil {
+			return errors.Wrapf(err, "resolving dependencies of chart %s-%s", chart.Name, chart.Version)
+		}
+	}
+
+	return nil
+}
+
+// fetchArchive fetches a chart archive and passes its files to onFile below dir.
+func (dr *chartDependencyResolver) fetchArchive(rc helm.RepositoryClient, u, dir string) (*chartArchive, error) {
+	r, err := rc.Fetch(u)
+	if r != nil {
+		defer r.Close()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ca := &chartArchive{bundled: make(map[string]bool)}
+
+	handler := func(f *archive.File) error {
+		b, err := ioutil.ReadAll(f.Reader)
+		if err != nil {
+			return err
+		}
+
+		elems := strings.Split(path.Clean(f.Name), "/")
+		if ca.name == "" {
+			ca.name = elems[0]
+		}
+		if len(elems) == 2 && elems[1] == helm.RequirementsFile {
+			ca.requirements = b
+		}
+		if len(elems) > 2 && elems[1] == helm.ChartsDir {
+			ca.bundled[elems[2]] = true
+		}
+
+		name := path.Join(dir, f.Name)
+		if containsHelmHook(name, b) {
+			// skip this file because it has a helm hook in it
+			return nil
+		}
+
+		return dr.onFile(name, b)
+	}
+
+	if err = dr.helm.unarchiver.Unarchive(r, handler); err != nil {
+		return nil, err
+	}
+
+	return ca, nil
+}
+
+// resolveDependencies fetches the dependencies of a chart which are not
+// bundled with it. Dependencies without a repository are fetched from the
+// repository of the chart, rc.
+func (dr *chartDependencyResolver) resolveDependencies(rc helm.RepositoryClient, ca *chartArchive, dir string, depth int) error {
+	if ca.requirements == nil {
+		return nil
+	}
+
+	reqs, err := helm.ParseRequirements(ca.requirements)
+	if err != nil {
+		return err
+	}
+
+	chartsDir := path.Join(dir, ca.name, helm.ChartsDir)
+	for _, dep := range reqs.Dependencies {
+		if ca.hasBundled(dep.Name) {
+			continue
+		}
+
+		if helm.IsLocalRepository(dep.Repository) {
+			return errors.Errorf("dependency %s refers to a local chart, which must be bundled in %s/", dep.Name, helm.ChartsDir)
+		}
+
+		depClient := rc
+		if dep.Repository != "" {
+			var err error
+			if depClient, err = dr.client(dep.Repository); err != nil {
+				return errors.Wrapf(err, "locating repository of dependency %s", dep.Name)
+			}
+		}
+
+		repo, err := depClient.Repository()
+		if err != nil {
+			return errors.Wrapf(err, "retrieving repository of dependency %s", dep.Name)
+		}
+
+		chart, err := repo.MatchingChart(dep.Name, dep.Version)
+		if err != nil {
+			return err
+		}
+
+		logrus.Infof("Resolving chart dependency %s-%s", chart.Name, chart.Version)
+
+		if err = dr.fetch(depClient, chart, chartsDir, depth+1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// client returns a client for a dependency's repository. Aliases (`@name` or
+// `alias:name`) refer to Helm registries configured in the app.
+func (dr *chartDependencyResolver) client(repository string) (helm.RepositoryClient, error) {
+	if rc, ok := dr.clients[repository]; ok {
+		return rc, nil
+	}
+
+	rc, err := dr.locateClient(repository)
+	if err != nil {
+		return nil, err
+	}
+
+	dr.clients[repository] = rc
+	return rc, nil
+}
+
+func (dr *chartDependencyResolver) locateClient(repository string) (helm.RepositoryClient, error) {
+	h := dr.helm
+
+	uri := strings.TrimSuffix(repository, "/")
+	if uri == strings.TrimSuffix(h.URI(), "/") {
+		return h.repositoryClient, nil
+	}
+
+	var registries app.RegistryConfigs
+	if h.app != nil {
+		var err error
+		if registries, err = h.app.Registries(); err != nil {
+			return nil, err
+		}
+	}
+
+	var spec *app.RegistryConfig
+	if name, ok := helm.RepositoryAlias(repository); ok {
+		spec, ok = registries[name]
+		if !ok {
+			return nil, errors.Errorf("registry %q does not exist", name)
+		}
+	} else {
+		for _, rc := range registries {
+			if rc.Protocol == string(ProtocolHelm) && strings.TrimSuffix(rc.URI, "/") == uri {
+				spec = rc
+				break
+			}
+		}
+	}
+
+	if spec == nil {
+		// The repository is not configured in the app.
+		spec = &app.RegistryConfig{
+			Name:     h.Name(),
+			Protocol: string(ProtocolHelm),
+			URI:      repository,
+		}
+	}
+
+	if spec.Protocol != string(ProtocolHelm) {
+		return nil, errors.Errorf("registry %q is not a Helm registry", spec.Name)
+	}
+
+	r, err := Locate(h.app, spec, h.httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	dh, ok := unwrapMirror(r).(*Helm)
+	if !ok {
+		return nil, errors.Errorf("registry %q is not a Helm registry", spec.Name)
+	}
+
+	return dh.repositoryClient, nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/helm"
+	"github.com/ksonnet/ksonnet/pkg/util/archive"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stageChart writes a chart archive to a Helm repository directory and
+// returns its index entry.
+func stageChart(t *testing.T, fs afero.Fs, repoDir, name, version string, files map[string]string) helm.RepositoryChart {
+	var archived []*archive.File
+	for p, content := range files {
+		archived = append(archived, &archive.File{
+			Name:   name + "/" + p,
+			Reader: strings.NewReader(content),
+		})
+	}
+	archived = append(archived, &archive.File{
+		Name:   name + "/Chart.yaml",
+		Reader: strings.NewReader("name: " + name + "\nversion: " + version + "\n"),
+	})
+
+	var buf bytes.Buffer
+	var tgz archive.Tgz
+	require.NoError(t, tgz.Archive(&buf, archived))
+
+	fileName := name + "-" + version + ".tgz"
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(repoDir, fileName), buf.Bytes(), 0644))
+
+	return helm.RepositoryChart{Name: name, Version: version, URLs: []string{fileName}}
+}
+
+func stageIndex(t *testing.T, fs afero.Fs, repoDir string, charts ...helm.RepositoryChart) {
+	index := helm.Repository{Charts: make(map[string][]helm.RepositoryChart)}
+	for _, c := range charts {
+		index.Charts[c.Name] = append(index.Charts[c.Name], c)
+	}
+
+	data, err := yaml.Marshal(index)
+	require.NoError(t, err)
+	require.NoError(t, afero.WriteFile(fs, filepath.Join(repoDir, "index.yaml"), data, 0644))
+}
+
+func withChartRepositories(t *testing.T, requirements string, fn func(h *Helm, a *amocks.App, fs afero.Fs)) {
+	withUserConfig(t, "", func() {
+		withApp(t, func(a *amocks.App, fs afero.Fs) {
+			web := stageChart(t, fs, "/repos/main", "web", "1.0.0", map[string]string{
+				"requirements.yaml":        requirements,
+				"charts/common/Chart.yaml": "name: common\nversion: 1.0.0\n",
+			})
+			stageIndex(t, fs, "/repos/main",
+				web,
+				stageChart(t, fs, "/repos/main", "redis", "3.1.0", nil),
+				stageChart(t, fs, "/repos/main", "redis", "3.2.0", nil),
+				stageChart(t, fs, "/repos/main", "redis", "4.0.0", nil),
+			)
+
+			stageIndex(t, fs, "/repos/other",
+				stageChart(t, fs, "/repos/other", "mariadb", "4.3.1", map[string]string{
+					"requirements.yaml": "dependencies:\n- name: util\n  version: ~1.0.0\n",
+				}),
+				stageChart(t, fs, "/repos/other", "util", "1.0.2", nil),
+			)
+
+			registries := app.RegistryConfigs{
+				"main":  {Name: "main", Protocol: string(ProtocolHelm), URI: "/repos/main"},
+				"other": {Name: "other", Protocol: string(ProtocolHelm), URI: "/repos/other"},
+			}
+			a.On("Registries").Return(registries, nil)
+
+			h, err := NewHelm(a, registries["main"], helm.NewDirClient(fs, "/repos/main"), nil)
+			require.NoError(t, err)
+
+			fn(h, a, fs)
+		})
+	})
+}
+
+func TestHelm_ResolveLibrary_dependencies(t *testing.T) {
+	requirements := `dependencies:
+- name: common
+  version: 1.0.0
+- name: redis
+  version: ^3.0.0
+  condition: redis.enabled
+- name: mariadb
+  version: 4.x
+  repository: "@other"
+`
+
+	withChartRepositories(t, requirements, func(h *Helm, a *amocks.App, fs afero.Fs) {
+		var got []string
+		onFile := func(relPath string, contents []byte) error {
+			got = append(got, relPath)
+			return nil
+		}
+		onDir := func(string) error { return nil }
+
+		_, _, err := h.ResolveLibrary("web", "web", "1.0.0", onFile, onDir)
+		require.NoError(t, err)
+
+		sort.Strings(got)
+		expected := []string{
+			"web/helm/1.0.0/web/Chart.yaml",
+			"web/helm/1.0.0/web/charts/common/Chart.yaml",
+			"web/helm/1.0.0/web/charts/mariadb/Chart.yaml",
+			"web/helm/1.0.0/web/charts/mariadb/charts/util/Chart.yaml",
+			"web/helm/1.0.0/web/charts/mariadb/requirements.yaml",
+			"web/helm/1.0.0/web/charts/redis/Chart.yaml",
+			"web/helm/1.0.0/web/requirements.yaml",
+		}
+		assert.Equal(t, expected, got)
+	})
+}
+
+func TestHelm_ResolveLibrary_dependency_errors(t *testing.T) {
+	cases := []struct {
+		name         string
+		requirements string
+	}{
+		{
+			name:         "unknown registry",
+			requirements: "dependencies:\n- name: redis\n  repository: \"@missing\"\n",
+		},
+		{
+			name:         "local chart",
+			requirements: "dependencies:\n- name: redis\n  repository: file://../redis\n",
+		},
+		{
+			name:         "no matching version",
+			requirements: "dependencies:\n- name: redis\n  version: ^5.0.0\n",
+		},
+		{
+			name:         "unknown chart",
+			requirements: "dependencies:\n- name: missing\n",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withChartRepositories(t, tc.requirements, func(h *Helm, a *amocks.App, fs afero.Fs) {
+				onFile := func(string, []byte) error { return nil }
+				onDir := func(string) error { return nil }
+
+				_, _, err := h.ResolveLibrary("web", "web", "1.0.0", onFile, onDir)
+				require.Error(t, err)
+			})
+		})
+	}
+}
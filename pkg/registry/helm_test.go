@@ -415,3 +415,47 @@ func (u *fakeUnarchiver) Unarchive(_ io.Reader, h archive.FileHandler) error {
 
 	return h(f)
 }
+
+func TestHelm_local_charts(t *testing.T) {
+	withUserConfig(t, "", func() {
+		withApp(t, func(a *mocks.App, fs afero.Fs) {
+			require.NoError(t, afero.WriteFile(fs, "/app/charts/mychart/Chart.yaml",
+				[]byte("name: mychart\nversion: 0.1.0\n"), 0644))
+			require.NoError(t, afero.WriteFile(fs, "/app/charts/mychart/templates/service.yaml",
+				[]byte("kind: Service\n"), 0644))
+
+			assert.True(t, IsLocalChartDir(a, "charts"))
+			assert.False(t, IsLocalChartDir(a, "missing"))
+			assert.False(t, IsLocalChartDir(a, "https://example.com/charts"))
+
+			spec := &app.RegistryConfig{
+				Name:     "localcharts",
+				Protocol: string(ProtocolHelm),
+				URI:      "charts",
+			}
+
+			r, err := Locate(a, spec, nil)
+			require.NoError(t, err)
+
+			ok, err := r.ValidateURI(spec.URI)
+			require.NoError(t, err)
+			require.True(t, ok)
+
+			files := make(map[string]string)
+			onFile := func(relPath string, contents []byte) error {
+				files[relPath] = string(contents)
+				return nil
+			}
+			onDir := func(string) error { return nil }
+
+			_, _, err = r.ResolveLibrary("mychart", "mychart", "0.1.0", onFile, onDir)
+			require.NoError(t, err)
+
+			expected := map[string]string{
+				"mychart/helm/0.1.0/mychart/Chart.yaml":             "name: mychart\nversion: 0.1.0\n",
+				"mychart/helm/0.1.0/mychart/templates/service.yaml": "kind: Service\n",
+			}
+			assert.Equal(t, expected, files)
+		})
+	})
+}
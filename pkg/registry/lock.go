@@ -0,0 +1,402 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// LockFile is the name of the file which records vendored packages.
+	LockFile = "ks.lock"
+	// LockAPIVersion is the version of the lock file format.
+	LockAPIVersion = "0.1.0"
+	// LockKind is the kind of the lock file.
+	LockKind = "ksonnet.io/lock"
+
+	digestPrefix = "sha256:"
+)
+
+// Lock records the resolved version and content digest of each vendored package.
+type Lock struct {
+	APIVersion string          `json:"apiVersion"`
+	Kind       string          `json:"kind"`
+	Packages   []LockedPackage `json:"packages"`
+}
+
+// LockedPackage is a package entry in a Lock.
+type LockedPackage struct {
+	Registry string `json:"registry"`
+	Name     string `json:"name"`
+	Version  string `json:"version,omitempty"`
+	Digest   string `json:"digest"`
+}
+
+// Descriptor returns the package descriptor for the entry.
+func (lp LockedPackage) Descriptor() pkg.Descriptor {
+	return pkg.Descriptor{Registry: lp.Registry, Name: lp.Name, Version: lp.Version}
+}
+
+// LockMismatch describes a difference between the lock and the vendored packages.
+type LockMismatch struct {
+	Package pkg.Descriptor
+	Reason  string
+}
+
+func (m LockMismatch) String() string {
+	return fmt.Sprintf("%s: %s", m.Package, m.Reason)
+}
+
+func lockPath(a app.App) string {
+	return filepath.Join(a.Root(), LockFile)
+}
+
+// ReadLock reads the lock of an application. A missing lock is returned as an empty one.
+func ReadLock(a app.App) (*Lock, error) {
+	if a == nil {
+		return nil, errors.New("nil app")
+	}
+
+	l := &Lock{APIVersion: LockAPIVersion, Kind: LockKind}
+
+	data, err := afero.ReadFile(a.Fs(), lockPath(a))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return l, nil
+		}
+		return nil, errors.Wrapf(err, "reading %s", LockFile)
+	}
+
+	if err := yaml.Unmarshal(data, l); err != nil {
+		return nil, errors.Wrapf(err, "parsing %s", LockFile)
+	}
+
+	return l, nil
+}
+
+// LockExists returns true if the application has a lock.
+func LockExists(a app.App) (bool, error) {
+	if a == nil {
+		return false, errors.New("nil app")
+	}
+
+	return afero.Exists(a.Fs(), lockPath(a))
+}
+
+// Write writes the lock to the root of an application.
+func (l *Lock) Write(a app.App) error {
+	if a == nil {
+		return errors.New("nil app")
+	}
+
+	sort.Slice(l.Packages, func(i, j int) bool {
+		return lockKey(l.Packages[i].Descriptor()) < lockKey(l.Packages[j].Descriptor())
+	})
+
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return errors.Wrapf(err, "marshalling %s", LockFile)
+	}
+
+	return afero.WriteFile(a.Fs(), lockPath(a), data, app.DefaultFilePermissions)
+}
+
+// Find finds the entry for a package. It returns nil if the package is not locked.
+func (l *Lock) Find(d pkg.Descriptor) *LockedPackage {
+	key := lockKey(d)
+	for i := range l.Packages {
+		if lockKey(l.Packages[i].Descriptor()) == key {
+			return &l.Packages[i]
+		}
+	}
+
+	return nil
+}
+
+// Set adds or replaces the entry for a package.
+func (l *Lock) Set(lp LockedPackage) {
+	if existing := l.Find(lp.Descriptor()); existing != nil {
+		*existing = lp
+		return
+	}
+
+	l.Packages = append(l.Packages, lp)
+}
+
+// UpdateLock records the libraries installed in an application in its lock.
+// Entries for libraries which are no longer installed are removed. The digests
+// of fetched libraries which were already locked at the same version must match.
+func UpdateLock(a app.App, pm vendorPathResolver, fetched []*app.LibraryConfig) error {
+	if pm == nil {
+		return errors.New("nil vendor path resolver")
+	}
+
+	l, err := ReadLock(a)
+	if err != nil {
+		return err
+	}
+
+	libs, err := lockedLibraries(a)
+	if err != nil {
+		return err
+	}
+
+	isFetched := make(map[string]bool)
+	for _, lib := range fetched {
+		isFetched[lockKey(libDescriptor(lib))] = true
+	}
+
+	var packages []LockedPackage
+	for _, d := range libs {
+		dir, err := pm.VendorPath(d)
+		if err != nil {
+			return err
+		}
+
+		digest, err := Digest(a.Fs(), dir)
+		if err != nil {
+			if os.IsNotExist(errors.Cause(err)) {
+				log.Warnf("Package %s is not vendored; not adding it to %s", d, LockFile)
+				continue
+			}
+			return err
+		}
+
+		existing := l.Find(d)
+		if existing != nil && isFetched[lockKey(d)] && existing.Digest != digest {
+			return errors.Errorf("checksum mismatch for %s: %s has %s, but fetched %s; remove the entry from %s to accept the new content",
+				d, LockFile, existing.Digest, digest, LockFile)
+		}
+		if existing != nil && !isFetched[lockKey(d)] {
+			// Keep the recorded digest so drift can still be detected by verify.
+			digest = existing.Digest
+		}
+
+		packages = append(packages, LockedPackage{
+			Registry: d.Registry,
+			Name:     d.Name,
+			Version:  d.Version,
+			Digest:   digest,
+		})
+	}
+
+	l.Packages = packages
+	return l.Write(a)
+}
+
+// VerifyLock compares the packages vendored in an application with its lock.
+func VerifyLock(a app.App, pm vendorPathResolver) ([]LockMismatch, error) {
+	if pm == nil {
+		return nil, errors.New("nil vendor path resolver")
+	}
+
+	exists, err := LockExists(a)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.Errorf("%s not found; run `ks pkg install` to create it", LockFile)
+	}
+
+	l, err := ReadLock(a)
+	if err != nil {
+		return nil, err
+	}
+
+	libs, err := lockedLibraries(a)
+	if err != nil {
+		return nil, err
+	}
+
+	var mismatches []LockMismatch
+	installed := make(map[string]bool)
+	for _, d := range libs {
+		installed[lockKey(d)] = true
+
+		lp := l.Find(d)
+		if lp == nil {
+			mismatches = append(mismatches, LockMismatch{Package: d, Reason: "not recorded in " + LockFile})
+			continue
+		}
+
+		dir, err := pm.VendorPath(d)
+		if err != nil {
+			return nil, err
+		}
+
+		digest, err := Digest(a.Fs(), dir)
+		if err != nil {
+			if os.IsNotExist(errors.Cause(err)) {
+				mismatches = append(mismatches, LockMismatch{Package: d, Reason: "not vendored"})
+				continue
+			}
+			return nil, err
+		}
+
+		if digest != lp.Digest {
+			mismatches = append(mismatches, LockMismatch{
+				Package: d,
+				Reason:  fmt.Sprintf("vendored content has digest %s, but %s has %s", digest, LockFile, lp.Digest),
+			})
+		}
+	}
+
+	for _, lp := range l.Packages {
+		if installed[lockKey(lp.Descriptor())] {
+			continue
+		}
+		mismatches = append(mismatches, LockMismatch{Package: lp.Descriptor(), Reason: "recorded in " + LockFile + " but not installed"})
+	}
+
+	return mismatches, nil
+}
+
+// CheckLock returns an error if the packages vendored in an application do not match its lock.
+func CheckLock(a app.App, pm vendorPathResolver) error {
+	mismatches, err := VerifyLock(a, pm)
+	if err != nil {
+		return err
+	}
+
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	var lines []string
+	for _, m := range mismatches {
+		lines = append(lines, "  "+m.String())
+	}
+
+	return errors.Errorf("vendored packages do not match %s:\n%s", LockFile, strings.Join(lines, "\n"))
+}
+
+// Digest computes a content digest for a directory. Each file contributes its
+// slash separated path relative to the directory and the SHA-256 of its contents.
+func Digest(fs afero.Fs, dir string) (string, error) {
+	if fs == nil {
+		return "", errors.New("nil filesystem")
+	}
+
+	if _, err := fs.Stat(dir); err != nil {
+		return "", err
+	}
+
+	files := make(map[string][]byte)
+	err := afero.Walk(fs, dir, func(path string, fi os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+
+		data, err := afero.ReadFile(fs, path)
+		if err != nil {
+			return err
+		}
+
+		files[filepath.ToSlash(rel)] = data
+		return nil
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "walking %s", dir)
+	}
+
+	return digestFiles(files), nil
+}
+
+// digestFiles computes a content digest for files keyed by their slash
+// separated path. It matches the Digest of a directory containing the files.
+func digestFiles(files map[string][]byte) string {
+	var paths []string
+	for rel := range files {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+
+	h := sha256.New()
+	for _, rel := range paths {
+		fmt.Fprintf(h, "%x  %s\n", sha256.Sum256(files[rel]), rel)
+	}
+
+	return fmt.Sprintf("%s%x", digestPrefix, h.Sum(nil))
+}
+
+// lockedLibraries returns the descriptors of the libraries installed globally
+// or in any environment, sorted and without duplicates.
+func lockedLibraries(a app.App) ([]pkg.Descriptor, error) {
+	if a == nil {
+		return nil, errors.New("nil app")
+	}
+
+	seen := make(map[string]bool)
+	var result []pkg.Descriptor
+	add := func(libs app.LibraryConfigs) {
+		for _, lib := range libs {
+			d := libDescriptor(lib)
+			if seen[lockKey(d)] {
+				continue
+			}
+			seen[lockKey(d)] = true
+			result = append(result, d)
+		}
+	}
+
+	libs, err := a.Libraries()
+	if err != nil {
+		return nil, err
+	}
+	add(libs)
+
+	envs, err := a.Environments()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range envs {
+		add(e.Libraries)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return lockKey(result[i]) < lockKey(result[j])
+	})
+
+	return result, nil
+}
+
+func libDescriptor(lib *app.LibraryConfig) pkg.Descriptor {
+	return pkg.Descriptor{Registry: lib.Registry, Name: lib.Name, Version: lib.Version}
+}
+
+func lockKey(d pkg.Descriptor) string {
+	return fmt.Sprintf("%s/%s@%s", d.Registry, d.Name, d.Version)
+}
@@ -0,0 +1,167 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func lockVendorPaths() fakeVendorPathResolver {
+	return fakeVendorPathResolver{
+		pathFn: func(d pkg.Descriptor) (string, error) {
+			return filepath.Join("/app/vendor", d.Registry, d.Name+"@"+d.Version), nil
+		},
+	}
+}
+
+func withLockApp(t *testing.T, fn func(*amocks.App, afero.Fs)) {
+	test.WithApp(t, "/app", func(a *amocks.App, fs afero.Fs) {
+		libs := app.LibraryConfigs{
+			"nginx": &app.LibraryConfig{Registry: "incubator", Name: "nginx", Version: "1.0.0"},
+		}
+		envs := app.EnvironmentConfigs{
+			"default": &app.EnvironmentConfig{
+				Name: "default",
+				Libraries: app.LibraryConfigs{
+					"redis": &app.LibraryConfig{Registry: "incubator", Name: "redis", Version: "2.0.0"},
+				},
+			},
+		}
+		a.On("Libraries").Return(libs, nil)
+		a.On("Environments").Return(envs, nil)
+
+		test.StageFile(t, fs, "lock/nginx/parts.yaml", "/app/vendor/incubator/nginx@1.0.0/parts.yaml")
+		test.StageFile(t, fs, "lock/nginx/nginx.libsonnet", "/app/vendor/incubator/nginx@1.0.0/nginx.libsonnet")
+		test.StageFile(t, fs, "lock/redis/parts.yaml", "/app/vendor/incubator/redis@2.0.0/parts.yaml")
+
+		fn(a, fs)
+	})
+}
+
+func TestDigest(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/a/b.txt", []byte("b"), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/a/c/d.txt", []byte("d"), 0644))
+
+	digest, err := Digest(fs, "/a")
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:", digest[:7])
+	assert.Len(t, digest, 7+64)
+
+	other := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(other, "/x/c/d.txt", []byte("d"), 0644))
+	require.NoError(t, afero.WriteFile(other, "/x/b.txt", []byte("b"), 0644))
+
+	same, err := Digest(other, "/x")
+	require.NoError(t, err)
+	assert.Equal(t, digest, same, "digest should not depend on the root or write order")
+
+	require.NoError(t, afero.WriteFile(other, "/x/b.txt", []byte("changed"), 0644))
+	changed, err := Digest(other, "/x")
+	require.NoError(t, err)
+	assert.NotEqual(t, digest, changed)
+
+	_, err = Digest(fs, "/missing")
+	require.Error(t, err)
+}
+
+func TestUpdateLock(t *testing.T) {
+	withLockApp(t, func(a *amocks.App, fs afero.Fs) {
+		err := UpdateLock(a, lockVendorPaths(), nil)
+		require.NoError(t, err)
+
+		l, err := ReadLock(a)
+		require.NoError(t, err)
+		require.Len(t, l.Packages, 2)
+		assert.Equal(t, LockKind, l.Kind)
+		assert.Equal(t, "nginx", l.Packages[0].Name)
+		assert.Equal(t, "1.0.0", l.Packages[0].Version)
+		assert.Equal(t, "redis", l.Packages[1].Name)
+
+		mismatches, err := VerifyLock(a, lockVendorPaths())
+		require.NoError(t, err)
+		assert.Empty(t, mismatches)
+	})
+}
+
+func TestUpdateLock_prunes_removed_packages(t *testing.T) {
+	withLockApp(t, func(a *amocks.App, fs afero.Fs) {
+		l := &Lock{APIVersion: LockAPIVersion, Kind: LockKind}
+		l.Set(LockedPackage{Registry: "incubator", Name: "mysql", Version: "1.0.0", Digest: "sha256:0"})
+		require.NoError(t, l.Write(a))
+
+		require.NoError(t, UpdateLock(a, lockVendorPaths(), nil))
+
+		l, err := ReadLock(a)
+		require.NoError(t, err)
+		assert.Nil(t, l.Find(pkg.Descriptor{Registry: "incubator", Name: "mysql", Version: "1.0.0"}))
+		assert.NotNil(t, l.Find(pkg.Descriptor{Registry: "incubator", Name: "nginx", Version: "1.0.0"}))
+	})
+}
+
+func TestUpdateLock_checksum_mismatch(t *testing.T) {
+	withLockApp(t, func(a *amocks.App, fs afero.Fs) {
+		require.NoError(t, UpdateLock(a, lockVendorPaths(), nil))
+
+		test.StageFile(t, fs, "lock/redis/parts.yaml", "/app/vendor/incubator/nginx@1.0.0/nginx.libsonnet")
+
+		// Content changed for a package which was not fetched: the recorded digest is kept.
+		require.NoError(t, UpdateLock(a, lockVendorPaths(), nil))
+
+		fetched := []*app.LibraryConfig{{Registry: "incubator", Name: "nginx", Version: "1.0.0"}}
+		err := UpdateLock(a, lockVendorPaths(), fetched)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "checksum mismatch for incubator/nginx@1.0.0")
+	})
+}
+
+func TestVerifyLock(t *testing.T) {
+	withLockApp(t, func(a *amocks.App, fs afero.Fs) {
+		_, err := VerifyLock(a, lockVendorPaths())
+		require.Error(t, err, "missing lock")
+
+		require.NoError(t, UpdateLock(a, lockVendorPaths(), nil))
+
+		l, err := ReadLock(a)
+		require.NoError(t, err)
+		l.Set(LockedPackage{Registry: "incubator", Name: "mysql", Version: "1.0.0", Digest: "sha256:0"})
+		require.NoError(t, l.Write(a))
+
+		test.StageFile(t, fs, "lock/redis/parts.yaml", "/app/vendor/incubator/nginx@1.0.0/nginx.libsonnet")
+		require.NoError(t, fs.RemoveAll("/app/vendor/incubator/redis@2.0.0"))
+
+		mismatches, err := VerifyLock(a, lockVendorPaths())
+		require.NoError(t, err)
+		require.Len(t, mismatches, 3)
+		assert.Equal(t, "incubator/nginx@1.0.0", mismatches[0].Package.String())
+		assert.Contains(t, mismatches[0].Reason, "vendored content has digest")
+		assert.Equal(t, "incubator/redis@2.0.0: not vendored", mismatches[1].String())
+		assert.Equal(t, "incubator/mysql@1.0.0: recorded in ks.lock but not installed", mismatches[2].String())
+
+		err = CheckLock(a, lockVendorPaths())
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "vendored packages do not match ks.lock")
+	})
+}
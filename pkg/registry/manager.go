@@ -25,20 +25,45 @@ import (
 	"github.com/pkg/errors"
 )
 
-// Locate locates a registry given a spec.
+// Locate locates a registry given a spec. Registries are redirected to a
+// local mirror if the user configuration maps their URI to one.
 func Locate(a app.App, spec *app.RegistryConfig, httpClient *http.Client) (Registry, error) {
+	r, err := locate(a, spec, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	return WithMirror(a, r)
+}
+
+func locate(a app.App, spec *app.RegistryConfig, httpClient *http.Client) (Registry, error) {
 	switch Protocol(spec.Protocol) {
 	case ProtocolGitHub:
-		var ghc = github.NewGitHub(httpClient)
+		c, err := registryHTTPClient(ProtocolGitHub, spec.URI, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		var ghc = github.NewGitHub(c)
 		return githubFactory(a, spec, GitHubClient(ghc))
+	case ProtocolGit:
+		return NewGit(a, spec)
 	case ProtocolFilesystem:
 		return NewFs(a, spec)
 	case ProtocolHelm:
-		client, err := helm.NewHTTPClient(spec.URI, httpClient)
+		c, err := registryHTTPClient(ProtocolHelm, spec.URI, httpClient)
+		if err != nil {
+			return nil, err
+		}
+		client, err := newHelmClient(a, spec.URI, c)
+		if err != nil {
+			return nil, err
+		}
+		h, err := NewHelm(a, spec, helm.NewCachingClient(client), nil)
 		if err != nil {
 			return nil, err
 		}
-		return NewHelm(a, spec, helm.NewCachingClient(client), nil)
+		h.httpClient = httpClient
+		return h, nil
 	default:
 		return nil, errors.Errorf("invalid registry protocol %q", spec.Protocol)
 	}
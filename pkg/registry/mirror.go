@@ -0,0 +1,222 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/helm"
+	"github.com/ksonnet/ksonnet/pkg/parts"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+var (
+	// userConfigPath returns the path of the ksonnet user configuration.
+	userConfigPath = func() (string, error) {
+		homeDir := os.Getenv("HOME")
+		if homeDir == "" {
+			return "", errors.New("could not find home directory")
+		}
+
+		return filepath.Join(homeDir, ".config", "ksonnet", "config.yaml"), nil
+	}
+
+	// userConfigFs is the file system the user configuration is read from.
+	userConfigFs = afero.NewOsFs()
+)
+
+// UserConfig is the ksonnet user configuration. It is shared by all apps.
+type UserConfig struct {
+	// Mirrors maps registry URIs to local directories which are used in
+	// their place.
+	Mirrors map[string]string `json:"mirrors,omitempty"`
+	// Credentials maps registry URIs to the credentials and TLS options
+	// used to access them.
+	Credentials map[string]*RegistryCredentials `json:"credentials,omitempty"`
+}
+
+// readUserConfig reads the user configuration. A missing configuration is
+// an empty configuration.
+func readUserConfig(fs afero.Fs) (*UserConfig, error) {
+	path, err := userConfigPath()
+	if err != nil {
+		// Without a home directory, there is nothing to configure.
+		return &UserConfig{}, nil
+	}
+
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &UserConfig{}, nil
+		}
+		return nil, errors.Wrap(err, "reading user configuration")
+	}
+
+	var config UserConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return nil, errors.Wrapf(err, "unmarshalling user configuration %s", path)
+	}
+
+	return &config, nil
+}
+
+// mirrorDir returns the mirror directory for a registry URI, or an empty
+// string if the URI is not mirrored.
+func (c *UserConfig) mirrorDir(uri string) (string, error) {
+	uri = strings.TrimSuffix(uri, "/")
+	for k, dir := range c.Mirrors {
+		if strings.TrimSuffix(k, "/") != uri {
+			continue
+		}
+
+		if !filepath.IsAbs(dir) {
+			return "", errors.Errorf("mirror directory %q for %s is not an absolute path", dir, k)
+		}
+
+		return dir, nil
+	}
+
+	return "", nil
+}
+
+// mirroredRegistry is a registry which is served from a local mirror. It
+// reports the identity of the original registry, so app configuration and
+// vendored packages are unaffected by the mirror.
+type mirroredRegistry struct {
+	Registry
+	original Registry
+}
+
+var _ Registry = (*mirroredRegistry)(nil)
+
+// WithMirror redirects a registry to a local mirror directory if its URI is
+// mapped to one in the user configuration. Otherwise, the registry is
+// returned as is.
+func WithMirror(a app.App, r Registry) (Registry, error) {
+	if a == nil || r == nil {
+		return r, nil
+	}
+
+	config, err := readUserConfig(userConfigFs)
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := config.mirrorDir(r.URI())
+	if err != nil || dir == "" {
+		return r, err
+	}
+
+	logrus.WithFields(logrus.Fields{
+		"registry": r.Name(),
+		"uri":      r.URI(),
+		"mirror":   dir,
+	}).Debug("using registry mirror")
+
+	spec := &app.RegistryConfig{
+		Name: r.Name(),
+		URI:  dir,
+	}
+
+	var mirror Registry
+	if r.Protocol() == ProtocolHelm {
+		spec.Protocol = string(ProtocolHelm)
+		rc := helm.NewCachingClient(helm.NewDirClient(a.Fs(), dir))
+		if mirror, err = NewHelm(a, spec, rc, nil); err != nil {
+			return nil, err
+		}
+	} else {
+		spec.Protocol = string(ProtocolFilesystem)
+		if mirror, err = NewFs(a, spec); err != nil {
+			return nil, err
+		}
+	}
+
+	return &mirroredRegistry{Registry: mirror, original: r}, nil
+}
+
+// Name is the name of the original registry.
+func (m *mirroredRegistry) Name() string {
+	return m.original.Name()
+}
+
+// Protocol is the protocol of the original registry.
+func (m *mirroredRegistry) Protocol() Protocol {
+	return m.original.Protocol()
+}
+
+// URI is the URI of the original registry.
+func (m *mirroredRegistry) URI() string {
+	return m.original.URI()
+}
+
+// RegistrySpecDir is the registry directory of the original registry.
+func (m *mirroredRegistry) RegistrySpecDir() string {
+	return m.original.RegistrySpecDir()
+}
+
+// RegistrySpecFilePath is the registry.yaml path of the original registry.
+func (m *mirroredRegistry) RegistrySpecFilePath() string {
+	return m.original.RegistrySpecFilePath()
+}
+
+// MakeRegistryConfig returns the app registry config of the original registry.
+func (m *mirroredRegistry) MakeRegistryConfig() *app.RegistryConfig {
+	return m.original.MakeRegistryConfig()
+}
+
+// ValidateURI validates the mirror directory in place of the original URI.
+func (m *mirroredRegistry) ValidateURI(uri string) (bool, error) {
+	return m.Registry.ValidateURI(m.Registry.URI())
+}
+
+// ResolveLibrary resolves a library from the mirror. A mirror only holds a
+// single version of its registry, so the requested version is kept in the
+// library reference. This way the app configuration and the vendor path are
+// the same as if the library was resolved from the original registry.
+func (m *mirroredRegistry) ResolveLibrary(libID, libAlias, version string, onFile ResolveFile, onDir ResolveDirectory) (*parts.Spec, *app.LibraryConfig, error) {
+	spec, libRef, err := m.Registry.ResolveLibrary(libID, libAlias, version, onFile, onDir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if libRef != nil && libRef.Version == "" {
+		libRef.Version = version
+	}
+
+	return spec, libRef, nil
+}
+
+// FetchFile fetches a file from the mirror.
+func (m *mirroredRegistry) FetchFile(relPath, version string) ([]byte, error) {
+	ff, ok := m.Registry.(FileFetcher)
+	if !ok {
+		return nil, errors.Errorf("registry %q does not support fetching files", m.Name())
+	}
+
+	return ff.FetchFile(relPath, version)
+}
+
+// SetURI sets the URI of the original registry.
+func (m *mirroredRegistry) SetURI(uri string) error {
+	return m.original.SetURI(uri)
+}
@@ -0,0 +1,205 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	ghmocks "github.com/ksonnet/ksonnet/pkg/util/github/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withUserConfig(t *testing.T, config string, fn func()) {
+	ogFs, ogPath := userConfigFs, userConfigPath
+	defer func() {
+		userConfigFs, userConfigPath = ogFs, ogPath
+	}()
+
+	userConfigFs = afero.NewMemMapFs()
+	userConfigPath = func() (string, error) {
+		return "/home/user/.config/ksonnet/config.yaml", nil
+	}
+
+	if config != "" {
+		err := afero.WriteFile(userConfigFs, "/home/user/.config/ksonnet/config.yaml", []byte(config), 0644)
+		require.NoError(t, err)
+	}
+
+	fn()
+}
+
+func TestWithMirror(t *testing.T) {
+	config := `
+mirrors:
+  https://example.com/incubator/: /mirror/incubator
+`
+
+	withUserConfig(t, config, func() {
+		test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+			test.StageDir(t, fs, "part/incubator", "/mirror/incubator")
+			test.StageFile(t, fs, "fs-registry.yaml", "/mirror/incubator/registry.yaml")
+
+			spec := &app.RegistryConfig{
+				Name:     "incubator",
+				Protocol: string(ProtocolFilesystem),
+				URI:      "https://example.com/incubator",
+			}
+			original, err := NewFs(a, spec)
+			require.NoError(t, err)
+
+			r, err := WithMirror(a, original)
+			require.NoError(t, err)
+
+			assert.Equal(t, "incubator", r.Name())
+			assert.Equal(t, ProtocolFilesystem, r.Protocol())
+			assert.Equal(t, "https://example.com/incubator", r.URI())
+			assert.Equal(t, spec, r.MakeRegistryConfig())
+
+			ok, err := r.ValidateURI(r.URI())
+			require.NoError(t, err)
+			assert.True(t, ok)
+
+			registrySpec, err := r.FetchRegistrySpec()
+			require.NoError(t, err)
+			assert.Contains(t, registrySpec.Libraries, "apache")
+
+			partSpec, err := r.ResolveLibrarySpec("apache", "")
+			require.NoError(t, err)
+			assert.Equal(t, "apache", partSpec.Name)
+		})
+	})
+}
+
+func TestWithMirror_pinned_github_package(t *testing.T) {
+	config := `
+mirrors:
+  github.com/ksonnet/parts/tree/master/incubator: /mirror/incubator
+`
+
+	withUserConfig(t, config, func() {
+		withApp(t, func(a *mocks.App, fs afero.Fs) {
+			test.StageDir(t, fs, "part/incubator", "/mirror/incubator")
+			test.StageFile(t, fs, "fs-registry.yaml", "/mirror/incubator/registry.yaml")
+
+			// The GitHub client has no expectations, so any request fails.
+			githubFactory = func(a app.App, spec *app.RegistryConfig, opts ...GitHubOpt) (*GitHub, error) {
+				return NewGitHub(a, spec, GitHubClient(&ghmocks.GitHub{}))
+			}
+
+			a.On("VendorPath").Return("/app/vendor")
+			a.On("Libraries").Return(app.LibraryConfigs{}, nil)
+			a.On("Environments").Return(app.EnvironmentConfigs{}, nil)
+			a.On("Registries").Return(app.RegistryConfigs{
+				"incubator": &app.RegistryConfig{
+					Name:     "incubator",
+					Protocol: string(ProtocolGitHub),
+					URI:      "github.com/ksonnet/parts/tree/master/incubator",
+				},
+			}, nil)
+
+			var checker installedChecker
+			d := pkg.Descriptor{Registry: "incubator", Name: "apache", Version: "40285d8a14f1ac5787e405e1023cf0c07f6aa28c"}
+
+			libs, err := CacheDependency(a, &checker, d, "", "", false, nil)
+			require.NoError(t, err)
+
+			expected := []*app.LibraryConfig{
+				{
+					Name:     "apache",
+					Registry: "incubator",
+					Version:  "40285d8a14f1ac5787e405e1023cf0c07f6aa28c",
+				},
+			}
+			assert.Equal(t, expected, libs)
+
+			test.AssertExists(t, fs, "/app/vendor/incubator/apache@40285d8a14f1ac5787e405e1023cf0c07f6aa28c/parts.yaml")
+		})
+	})
+}
+
+func TestWithMirror_not_mirrored(t *testing.T) {
+	withUserConfig(t, "", func() {
+		test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+			spec := &app.RegistryConfig{
+				Name:     "incubator",
+				Protocol: string(ProtocolFilesystem),
+				URI:      "https://example.com/incubator",
+			}
+			original, err := NewFs(a, spec)
+			require.NoError(t, err)
+
+			r, err := WithMirror(a, original)
+			require.NoError(t, err)
+			assert.Equal(t, original, r)
+		})
+	})
+}
+
+func TestWithMirror_helm(t *testing.T) {
+	config := `
+mirrors:
+  https://charts.example.com: /mirror/charts
+`
+
+	withUserConfig(t, config, func() {
+		test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+			spec := &app.RegistryConfig{
+				Name:     "charts",
+				Protocol: string(ProtocolHelm),
+				URI:      "https://charts.example.com",
+			}
+			original, err := NewHelm(a, spec, &fakeHelmRepositoryClient{}, nil)
+			require.NoError(t, err)
+
+			r, err := WithMirror(a, original)
+			require.NoError(t, err)
+
+			m, ok := r.(*mirroredRegistry)
+			require.True(t, ok)
+			require.IsType(t, &Helm{}, m.Registry)
+			assert.Equal(t, ProtocolHelm, r.Protocol())
+			assert.Equal(t, "charts", r.Name())
+		})
+	})
+}
+
+func TestWithMirror_relative_dir(t *testing.T) {
+	config := `
+mirrors:
+  https://example.com/incubator: mirror/incubator
+`
+
+	withUserConfig(t, config, func() {
+		test.WithApp(t, "/app", func(a *mocks.App, fs afero.Fs) {
+			spec := &app.RegistryConfig{
+				Name:     "incubator",
+				Protocol: string(ProtocolFilesystem),
+				URI:      "https://example.com/incubator",
+			}
+			original, err := NewFs(a, spec)
+			require.NoError(t, err)
+
+			_, err = WithMirror(a, original)
+			require.Error(t, err)
+		})
+	})
+}
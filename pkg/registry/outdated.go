@@ -0,0 +1,182 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+
+	"github.com/blang/semver"
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+)
+
+// OutdatedLibrary is an installed library whose registry offers another version.
+type OutdatedLibrary struct {
+	// EnvName is the environment the library is installed in. It is empty
+	// for libraries installed globally.
+	EnvName string
+	Library *app.LibraryConfig
+	// Current is the installed version.
+	Current string
+	// Latest is the latest version offered by the registry.
+	Latest string
+}
+
+// Outdated compares the libraries installed in an application, globally or in
+// any environment, with the latest versions offered by their registries.
+func Outdated(a app.App, httpClient *http.Client) ([]OutdatedLibrary, error) {
+	if a == nil {
+		return nil, errors.New("nil app")
+	}
+
+	registries, err := a.Registries()
+	if err != nil {
+		return nil, err
+	}
+
+	resolvers := make(map[string]LibrarySpecResolver)
+	latest := make(map[string]string)
+	latestVersion := func(lib *app.LibraryConfig) (string, error) {
+		key := lib.Registry + "/" + lib.Name
+		if v, ok := latest[key]; ok {
+			return v, nil
+		}
+
+		r, ok := resolvers[lib.Registry]
+		if !ok {
+			regRefSpec, exists := registries[lib.Registry]
+			if !exists {
+				return "", errors.Errorf("library %s references invalid registry: %s", lib.Name, lib.Registry)
+			}
+
+			r, err = Locate(a, regRefSpec, httpClient)
+			if err != nil {
+				return "", err
+			}
+			resolvers[lib.Registry] = r
+		}
+
+		spec, err := r.ResolveLibrarySpec(lib.Name, "")
+		if err != nil {
+			return "", errors.Wrapf(err, "resolving latest version of %s", key)
+		}
+
+		latest[key] = spec.Version
+		return spec.Version, nil
+	}
+
+	var result []OutdatedLibrary
+	check := func(envName string, libs app.LibraryConfigs) error {
+		for _, lib := range libs {
+			current := lib.Version
+			if current == "" {
+				// Libraries from file system registries don't record a version.
+				spec, err := installedSpec(a, lib)
+				if err != nil {
+					return err
+				}
+				if spec != nil {
+					current = spec.Version
+				}
+			}
+
+			v, err := latestVersion(lib)
+			if err != nil {
+				return err
+			}
+
+			if current == "" || v == "" || !isNewerVersion(v, current) {
+				continue
+			}
+
+			result = append(result, OutdatedLibrary{
+				EnvName: envName,
+				Library: lib,
+				Current: current,
+				Latest:  v,
+			})
+		}
+
+		return nil
+	}
+
+	libs, err := a.Libraries()
+	if err != nil {
+		return nil, err
+	}
+	if err := check("", libs); err != nil {
+		return nil, err
+	}
+
+	envs, err := a.Environments()
+	if err != nil {
+		return nil, err
+	}
+	for name, e := range envs {
+		if err := check(name, ownLibraries(e, envs)); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].EnvName != result[j].EnvName {
+			return result[i].EnvName < result[j].EnvName
+		}
+		if result[i].Library.Registry != result[j].Library.Registry {
+			return result[i].Library.Registry < result[j].Library.Registry
+		}
+		return result[i].Library.Name < result[j].Library.Name
+	})
+
+	return result, nil
+}
+
+// ownLibraries returns the libraries of an environment which are not
+// inherited unchanged from its parent, as those are checked for the parent.
+func ownLibraries(e *app.EnvironmentConfig, envs app.EnvironmentConfigs) app.LibraryConfigs {
+	parent, ok := envs[e.Parent]
+	if e.Parent == "" || !ok {
+		return e.Libraries
+	}
+
+	libs := app.LibraryConfigs{}
+	for name, lib := range e.Libraries {
+		if pl, ok := parent.Libraries[name]; ok && reflect.DeepEqual(pl, lib) {
+			continue
+		}
+		libs[name] = lib
+	}
+
+	return libs
+}
+
+// isNewerVersion returns true if version v is newer than current. Semantic
+// versions are compared by precedence. Other versions, like git refs, are
+// newer if they differ.
+func isNewerVersion(v, current string) bool {
+	sv, err := semver.ParseTolerant(v)
+	if err != nil {
+		return v != current
+	}
+	sc, err := semver.ParseTolerant(current)
+	if err != nil {
+		return v != current
+	}
+
+	return sv.GT(sc)
+}
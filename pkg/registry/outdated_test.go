@@ -0,0 +1,134 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutdated(t *testing.T) {
+	withApp(t, func(a *amocks.App, fs afero.Fs) {
+		a.On("VendorPath").Return("/app/vendor")
+
+		test.StageDir(t, fs, filepath.Join("dependencies", "main"), filepath.Join("/work", "main"))
+		test.StageDir(t, fs, filepath.Join("dependencies", "shared"), filepath.Join("/work", "shared"))
+		test.StageDir(t, fs, filepath.Join("dependencies", "shared"), "/app/vendor/shared")
+
+		registries := app.RegistryConfigs{
+			"main": &app.RegistryConfig{
+				Name:     "main",
+				Protocol: string(ProtocolFilesystem),
+				URI:      "/work/main",
+			},
+			"shared": &app.RegistryConfig{
+				Name:     "shared",
+				Protocol: string(ProtocolFilesystem),
+				URI:      "/work/shared",
+			},
+		}
+		k8sUtil := &app.LibraryConfig{Registry: "main", Name: "k8s-util", Version: "1.0.0"}
+		libs := app.LibraryConfigs{
+			"k8s-util": k8sUtil,
+			// Up to date, according to its vendored parts.yaml.
+			"logging": &app.LibraryConfig{Registry: "shared", Name: "logging"},
+		}
+		envK8sUtil := &app.LibraryConfig{Registry: "main", Name: "k8s-util", Version: "1.2.0"}
+		usK8sUtil := &app.LibraryConfig{Registry: "main", Name: "k8s-util", Version: "1.1.0"}
+		envs := app.EnvironmentConfigs{
+			// Pinned to a version newer than the latest one.
+			"canary": &app.EnvironmentConfig{
+				Name:      "canary",
+				Libraries: app.LibraryConfigs{"k8s-util": &app.LibraryConfig{Registry: "main", Name: "k8s-util", Version: "1.10.0"}},
+			},
+			"prod": &app.EnvironmentConfig{
+				Name:      "prod",
+				Libraries: app.LibraryConfigs{"k8s-util": envK8sUtil},
+			},
+			// Inherits the library of prod.
+			"prod-eu": &app.EnvironmentConfig{
+				Name:      "prod-eu",
+				Parent:    "prod",
+				Libraries: app.LibraryConfigs{"k8s-util": &app.LibraryConfig{Registry: "main", Name: "k8s-util", Version: "1.2.0"}},
+			},
+			// Overrides the library of prod.
+			"prod-us": &app.EnvironmentConfig{
+				Name:      "prod-us",
+				Parent:    "prod",
+				Libraries: app.LibraryConfigs{"k8s-util": usK8sUtil},
+			},
+			"staging": &app.EnvironmentConfig{
+				Name:      "staging",
+				Libraries: app.LibraryConfigs{"k8s-util": &app.LibraryConfig{Registry: "main", Name: "k8s-util", Version: "1.3.0"}},
+			},
+		}
+		a.On("Registries").Return(registries, nil)
+		a.On("Libraries").Return(libs, nil)
+		a.On("Environments").Return(envs, nil)
+
+		outdated, err := Outdated(a, nil)
+		require.NoError(t, err)
+
+		expected := []OutdatedLibrary{
+			{Library: k8sUtil, Current: "1.0.0", Latest: "1.3.0"},
+			{EnvName: "prod", Library: envK8sUtil, Current: "1.2.0", Latest: "1.3.0"},
+			{EnvName: "prod-us", Library: usK8sUtil, Current: "1.1.0", Latest: "1.3.0"},
+		}
+		assert.Equal(t, expected, outdated)
+	})
+}
+
+func TestOutdated_invalid_registry(t *testing.T) {
+	withApp(t, func(a *amocks.App, fs afero.Fs) {
+		a.On("Registries").Return(app.RegistryConfigs{}, nil)
+		a.On("Libraries").Return(app.LibraryConfigs{
+			"nginx": &app.LibraryConfig{Registry: "missing", Name: "nginx", Version: "1.0.0"},
+		}, nil)
+
+		_, err := Outdated(a, nil)
+		require.Error(t, err)
+	})
+}
+
+func Test_isNewerVersion(t *testing.T) {
+	cases := []struct {
+		v        string
+		current  string
+		expected bool
+	}{
+		{v: "1.3.0", current: "1.2.0", expected: true},
+		{v: "1.3.0", current: "1.3.0"},
+		{v: "1.3.0", current: "1.10.0"},
+		{v: "v1.3.0", current: "1.2.0", expected: true},
+		{v: "1.3.0", current: "1.3.0-beta.1", expected: true},
+		{v: "40285d8a", current: "0123abcd", expected: true},
+		{v: "40285d8a", current: "40285d8a"},
+		{v: "1.3.0", current: "40285d8a", expected: true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.v+" "+tc.current, func(t *testing.T) {
+			assert.Equal(t, tc.expected, isNewerVersion(tc.v, tc.current))
+		})
+	}
+}
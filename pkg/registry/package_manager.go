@@ -415,7 +415,7 @@ func (m *packageManager) loadPackage(protocol Protocol, pkgName, registryName, v
 			return nil, errors.Wrap(err, "loading helm package")
 		}
 		return h, nil
-	case ProtocolFilesystem, ProtocolGitHub:
+	case ProtocolFilesystem, ProtocolGit, ProtocolGitHub:
 		l, err := pkg.NewLocal(m.app, pkgName, registryName, version, installChecker)
 		if err != nil {
 			return nil, errors.Wrapf(err, "loading %q package", protocol)
@@ -647,7 +647,7 @@ func (m *packageManager) VendorPath(d pkg.Descriptor) (string, error) {
 			return "", errors.Errorf("could not resolve path for descriptor: %v", d)
 		}
 		return path, nil
-	case ProtocolFilesystem, ProtocolGitHub:
+	case ProtocolFilesystem, ProtocolGit, ProtocolGitHub:
 		path := pkg.LocalVendorPath(m.app, d)
 		if path == "" {
 			return "", errors.Errorf("could not resolve path for descriptor: %v", d)
@@ -192,6 +192,9 @@ type Specs []*Spec
 type LibraryConfig struct {
 	Version string `json:"version"`
 	Path    string `json:"path"`
+	// Digest is the digest of the library's file tree, recorded by
+	// `ks registry sign`.
+	Digest string `json:"digest,omitempty"`
 }
 
 // LibraryConfigs maps LibraryConfigs to a name.
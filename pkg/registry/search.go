@@ -0,0 +1,334 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/ksonnet/ksonnet/pkg/parts"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+)
+
+const (
+	// searchIndexFile is the name of the cached search index.
+	searchIndexFile = "search-index.json"
+)
+
+// SearchIndex is an index of the packages in an app's registries.
+type SearchIndex struct {
+	// Registries maps the names of the indexed registries to their URIs. The
+	// cached index is rebuilt when the app's registries change.
+	Registries map[string]string `json:"registries"`
+	Packages   []*SearchEntry    `json:"packages"`
+}
+
+// SearchEntry describes a package in the search index.
+type SearchEntry struct {
+	Registry    string                `json:"registry"`
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	Keywords    []string              `json:"keywords,omitempty"`
+	Prototypes  []string              `json:"prototypes,omitempty"`
+	Versions    []string              `json:"versions,omitempty"`
+	QuickStart  *parts.QuickStartSpec `json:"quickStart,omitempty"`
+}
+
+// SearchResult is a package matching a search query.
+type SearchResult struct {
+	*SearchEntry
+	// Score is the relevance of the package. Higher is more relevant.
+	Score int
+}
+
+func searchIndexPath(a app.App) string {
+	return filepath.Join(registryCacheRoot(a), searchIndexFile)
+}
+
+// LoadSearchIndex loads the search index of an app's registries. The index
+// is cached in the app, and is rebuilt if refresh is true or if the app's
+// registries changed since it was built.
+func LoadSearchIndex(a app.App, httpClient *http.Client, refresh bool) (*SearchIndex, error) {
+	registries, err := a.Registries()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving registries")
+	}
+
+	current := make(map[string]string)
+	for name, cfg := range registries {
+		current[name] = cfg.URI
+	}
+
+	path := searchIndexPath(a)
+	if !refresh {
+		idx, err := readSearchIndex(a.Fs(), path)
+		if err != nil {
+			return nil, err
+		}
+
+		if idx != nil && reflect.DeepEqual(idx.Registries, current) {
+			return idx, nil
+		}
+	}
+
+	idx, err := BuildSearchIndex(a, httpClient)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(idx, "", "  ")
+	if err != nil {
+		return nil, errors.Wrap(err, "marshalling search index")
+	}
+
+	if err = a.Fs().MkdirAll(filepath.Dir(path), app.DefaultFolderPermissions); err != nil {
+		return nil, errors.Wrap(err, "creating registry cache directory")
+	}
+
+	if err = afero.WriteFile(a.Fs(), path, data, app.DefaultFilePermissions); err != nil {
+		return nil, errors.Wrap(err, "writing search index")
+	}
+
+	return idx, nil
+}
+
+// readSearchIndex reads a cached search index. It returns nil if there is
+// no cached index.
+func readSearchIndex(fs afero.Fs, path string) (*SearchIndex, error) {
+	data, err := afero.ReadFile(fs, path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrap(err, "reading search index")
+	}
+
+	var idx SearchIndex
+	if err := json.Unmarshal(data, &idx); err != nil {
+		// A corrupt cache is rebuilt.
+		logrus.WithError(err).Debug("ignoring invalid search index")
+		return nil, nil
+	}
+
+	return &idx, nil
+}
+
+// BuildSearchIndex indexes the packages of an app's registries. Registries
+// which can't be reached are skipped with a warning, so one unavailable
+// registry doesn't prevent searching the others. Skipped registries are not
+// recorded in the index, so a cached index is rebuilt to retry them.
+func BuildSearchIndex(a app.App, httpClient *http.Client) (*SearchIndex, error) {
+	registries, err := a.Registries()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving registries")
+	}
+
+	var names []string
+	for name := range registries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	idx := &SearchIndex{
+		Registries: make(map[string]string),
+		Packages:   []*SearchEntry{},
+	}
+
+	for _, name := range names {
+		cfg := registries[name]
+		log := logrus.WithField("registry", name)
+
+		r, err := Locate(a, cfg, httpClient)
+		if err != nil {
+			log.WithError(err).Warn("skipping registry")
+			continue
+		}
+
+		entries, err := indexRegistry(r)
+		if err != nil {
+			log.WithError(err).Warn("skipping registry")
+			continue
+		}
+
+		idx.Registries[name] = cfg.URI
+		idx.Packages = append(idx.Packages, entries...)
+	}
+
+	return idx, nil
+}
+
+// indexRegistry returns the search entries for the packages in a registry.
+func indexRegistry(r Registry) ([]*SearchEntry, error) {
+	if h, ok := unwrapMirror(r).(*Helm); ok {
+		return indexHelm(r.Name(), h)
+	}
+
+	spec, err := r.FetchRegistrySpec()
+	if err != nil {
+		return nil, errors.Wrap(err, "fetching registry spec")
+	}
+
+	var names []string
+	for name := range spec.Libraries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var entries []*SearchEntry
+	for _, name := range names {
+		lib := spec.Libraries[name]
+
+		entry := &SearchEntry{
+			Registry: r.Name(),
+			Name:     name,
+		}
+		if lib.Version != "" {
+			entry.Versions = []string{lib.Version}
+		}
+
+		ps, err := r.ResolveLibrarySpec(name, lib.Version)
+		if err != nil {
+			logrus.WithError(err).WithField("package", name).Warn("indexing package without its metadata")
+		} else {
+			entry.Description = ps.Description
+			entry.Keywords = ps.Keywords
+			entry.Prototypes = ps.Prototypes
+			entry.QuickStart = ps.QuickStart
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// indexHelm indexes a Helm repository from its index, which lists every
+// version of every chart.
+func indexHelm(registryName string, h *Helm) ([]*SearchEntry, error) {
+	repo, err := h.repositoryClient.Repository()
+	if err != nil {
+		return nil, errors.Wrap(err, "retrieving repository")
+	}
+
+	var entries []*SearchEntry
+	for _, chart := range repo.Latest() {
+		entry := &SearchEntry{
+			Registry:    registryName,
+			Name:        chart.Name,
+			Description: chart.Description,
+			Keywords:    chart.Keywords,
+			Prototypes:  []string{fmt.Sprintf("io.ksonnet.pkg.%s-%s", registryName, chart.Name)},
+		}
+
+		// Latest sorted the versions newest first.
+		for _, c := range repo.Charts[chart.Name] {
+			entry.Versions = append(entry.Versions, c.Version)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name < entries[j].Name
+	})
+
+	return entries, nil
+}
+
+// Search returns the packages matching a query, most relevant first. Every
+// word of the query has to match the package's name, keywords, prototypes
+// or description. An empty query matches every package.
+func (idx *SearchIndex) Search(query string) []SearchResult {
+	terms := strings.Fields(strings.ToLower(query))
+
+	var results []SearchResult
+	for _, entry := range idx.Packages {
+		score := 0
+		matches := true
+		for _, term := range terms {
+			s := entry.score(term)
+			if s == 0 {
+				matches = false
+				break
+			}
+			score += s
+		}
+
+		if matches {
+			results = append(results, SearchResult{SearchEntry: entry, Score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		ri, rj := results[i], results[j]
+		if ri.Score != rj.Score {
+			return ri.Score > rj.Score
+		}
+		if ri.Name != rj.Name {
+			return ri.Name < rj.Name
+		}
+		return ri.Registry < rj.Registry
+	})
+
+	return results
+}
+
+// score scores how well a single lower case term matches the entry. Matches
+// on the name are the most relevant, followed by keywords, prototypes and
+// the description.
+func (e *SearchEntry) score(term string) int {
+	score := 0
+
+	name := strings.ToLower(e.Name)
+	switch {
+	case name == term:
+		score += 100
+	case strings.HasPrefix(name, term):
+		score += 50
+	case strings.Contains(name, term):
+		score += 25
+	}
+
+	for _, k := range e.Keywords {
+		k = strings.ToLower(k)
+		if k == term {
+			score += 20
+		} else if strings.Contains(k, term) {
+			score += 10
+		}
+	}
+
+	for _, p := range e.Prototypes {
+		if strings.Contains(strings.ToLower(p), term) {
+			score += 10
+		}
+	}
+
+	if strings.Contains(strings.ToLower(e.Description), term) {
+		score += 5
+	}
+
+	return score
+}
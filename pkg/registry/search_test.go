@@ -0,0 +1,183 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/parts"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withSearchRegistries(t *testing.T, fn func(a *amocks.App, fs afero.Fs)) {
+	withUserConfig(t, "", func() {
+		withApp(t, func(a *amocks.App, fs afero.Fs) {
+			test.StageDir(t, fs, "part/incubator", "/incubator")
+			test.StageFile(t, fs, "fs-registry.yaml", "/incubator/registry.yaml")
+
+			for _, v := range []string{"3.1.0", "3.2.0"} {
+				chart := "name: redis\nversion: " + v + "\ndescription: Open source key-value store\nkeywords:\n- database\n"
+				require.NoError(t, afero.WriteFile(fs, "/charts/redis-"+v+"/Chart.yaml", []byte(chart), 0644))
+			}
+
+			registries := app.RegistryConfigs{
+				"incubator": {Name: "incubator", Protocol: string(ProtocolFilesystem), URI: "/incubator"},
+				"charts":    {Name: "charts", Protocol: string(ProtocolHelm), URI: "/charts"},
+			}
+			a.On("Registries").Return(registries, nil)
+
+			fn(a, fs)
+		})
+	})
+}
+
+func TestBuildSearchIndex(t *testing.T) {
+	withSearchRegistries(t, func(a *amocks.App, fs afero.Fs) {
+		idx, err := BuildSearchIndex(a, nil)
+		require.NoError(t, err)
+
+		expected := &SearchIndex{
+			Registries: map[string]string{
+				"charts":    "/charts",
+				"incubator": "/incubator",
+			},
+			Packages: []*SearchEntry{
+				{
+					Registry:    "charts",
+					Name:        "redis",
+					Description: "Open source key-value store",
+					Keywords:    []string{"database"},
+					Prototypes:  []string{"io.ksonnet.pkg.charts-redis"},
+					Versions:    []string{"3.2.0", "3.1.0"},
+				},
+				{
+					Registry:    "incubator",
+					Name:        "apache",
+					Description: "part description",
+					Keywords:    []string{"apache", "server", "http"},
+					QuickStart: &parts.QuickStartSpec{
+						Prototype:     "io.ksonnet.pkg.apache-simple",
+						ComponentName: "apache",
+						Flags: map[string]string{
+							"name":      "apache",
+							"namespace": "default",
+						},
+						Comment: "Run a simple Apache server",
+					},
+				},
+			},
+		}
+		assert.Equal(t, expected, idx)
+	})
+}
+
+func TestLoadSearchIndex(t *testing.T) {
+	withSearchRegistries(t, func(a *amocks.App, fs afero.Fs) {
+		idx, err := LoadSearchIndex(a, nil, false)
+		require.NoError(t, err)
+		require.Len(t, idx.Packages, 2)
+
+		path := "/app/.ksonnet/registries/search-index.json"
+		test.AssertExists(t, fs, path)
+
+		// The cached index is used while the registries are unchanged.
+		idx.Packages = idx.Packages[:1]
+		data, err := json.Marshal(idx)
+		require.NoError(t, err)
+		require.NoError(t, afero.WriteFile(fs, path, data, 0644))
+
+		idx, err = LoadSearchIndex(a, nil, false)
+		require.NoError(t, err)
+		require.Len(t, idx.Packages, 1)
+
+		idx, err = LoadSearchIndex(a, nil, true)
+		require.NoError(t, err)
+		require.Len(t, idx.Packages, 2)
+	})
+}
+
+func TestLoadSearchIndex_registries_changed(t *testing.T) {
+	withSearchRegistries(t, func(a *amocks.App, fs afero.Fs) {
+		stale := `{"registries": {"incubator": "/old"}, "packages": []}`
+		require.NoError(t, afero.WriteFile(fs, "/app/.ksonnet/registries/search-index.json", []byte(stale), 0644))
+
+		idx, err := LoadSearchIndex(a, nil, false)
+		require.NoError(t, err)
+		require.Len(t, idx.Packages, 2)
+	})
+}
+
+func TestLoadSearchIndex_unreachable_registry(t *testing.T) {
+	withSearchRegistries(t, func(a *amocks.App, fs afero.Fs) {
+		require.NoError(t, fs.Rename("/charts", "/charts-offline"))
+
+		idx, err := LoadSearchIndex(a, nil, false)
+		require.NoError(t, err)
+		require.Len(t, idx.Packages, 1)
+		assert.Equal(t, map[string]string{"incubator": "/incubator"}, idx.Registries)
+
+		// The registry is retried by the next search.
+		require.NoError(t, fs.Rename("/charts-offline", "/charts"))
+
+		idx, err = LoadSearchIndex(a, nil, false)
+		require.NoError(t, err)
+		require.Len(t, idx.Packages, 2)
+	})
+}
+
+func TestSearchIndex_Search(t *testing.T) {
+	idx := &SearchIndex{
+		Packages: []*SearchEntry{
+			{Registry: "incubator", Name: "redis", Description: "Redis server", Keywords: []string{"database"}},
+			{Registry: "stable", Name: "redis-ha", Description: "Highly available Redis"},
+			{Registry: "incubator", Name: "mysql", Description: "MySQL server", Keywords: []string{"database", "sql"}},
+			{Registry: "incubator", Name: "apache", Prototypes: []string{"io.ksonnet.pkg.apache-simple"}},
+		},
+	}
+
+	names := func(results []SearchResult) []string {
+		var out []string
+		for _, r := range results {
+			out = append(out, r.Registry+"/"+r.Name)
+		}
+		return out
+	}
+
+	cases := []struct {
+		query    string
+		expected []string
+	}{
+		{query: "redis", expected: []string{"incubator/redis", "stable/redis-ha"}},
+		{query: "database", expected: []string{"incubator/mysql", "incubator/redis"}},
+		{query: "Server", expected: []string{"incubator/mysql", "incubator/redis"}},
+		{query: "sql database", expected: []string{"incubator/mysql"}},
+		{query: "simple", expected: []string{"incubator/apache"}},
+		{query: "postgres"},
+		{query: "", expected: []string{"incubator/apache", "incubator/mysql", "incubator/redis", "stable/redis-ha"}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.query, func(t *testing.T) {
+			assert.Equal(t, tc.expected, names(idx.Search(tc.query)))
+		})
+	}
+}
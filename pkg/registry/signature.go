@@ -0,0 +1,206 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+	"golang.org/x/crypto/ed25519"
+)
+
+const (
+	// registrySignatureFile is the signature of registry.yaml, in the registry
+	// root. registry.yaml lists the digest of each package's file tree, so the
+	// packages are signed with it.
+	registrySignatureFile = "registry.yaml.sig"
+)
+
+// FileFetcher fetches a file from a registry, at a version of the registry.
+// Registries which support signatures implement it.
+type FileFetcher interface {
+	FetchFile(relPath, version string) ([]byte, error)
+}
+
+// PublicKeys decodes the trusted public keys of a registry.
+func PublicKeys(rc *app.RegistryConfig) ([]ed25519.PublicKey, error) {
+	if rc == nil {
+		return nil, nil
+	}
+
+	var keys []ed25519.PublicKey
+	for _, s := range rc.PublicKeys {
+		b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+		if err != nil || len(b) != ed25519.PublicKeySize {
+			return nil, errors.Errorf("invalid public key %q for registry %q", s, rc.Name)
+		}
+		keys = append(keys, ed25519.PublicKey(b))
+	}
+
+	return keys, nil
+}
+
+// GenerateKey generates an ed25519 key pair for signing registries. Both
+// keys are base64 encoded.
+func GenerateKey() (publicKey, privateKey string, err error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", errors.Wrap(err, "generating key")
+	}
+
+	return base64.StdEncoding.EncodeToString(pub), base64.StdEncoding.EncodeToString(priv), nil
+}
+
+// decodePrivateKey decodes a base64 encoded ed25519 private key.
+func decodePrivateKey(s string) (ed25519.PrivateKey, error) {
+	b, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(b) != ed25519.PrivateKeySize {
+		return nil, errors.New("invalid private key")
+	}
+
+	return ed25519.PrivateKey(b), nil
+}
+
+// sign returns the base64 encoded signature of message.
+func sign(key ed25519.PrivateKey, message []byte) []byte {
+	sig := ed25519.Sign(key, message)
+	return []byte(base64.StdEncoding.EncodeToString(sig) + "\n")
+}
+
+// verifySignature returns an error unless sig is a signature of message by
+// one of keys.
+func verifySignature(keys []ed25519.PublicKey, message, sig []byte) error {
+	b, err := base64.StdEncoding.DecodeString(string(bytes.TrimSpace(sig)))
+	if err != nil {
+		return errors.New("malformed signature")
+	}
+
+	for _, key := range keys {
+		if ed25519.Verify(key, message, b) {
+			return nil
+		}
+	}
+
+	return errors.New("signature does not match any trusted key")
+}
+
+// packageDigest is the digest of a package's file tree. files are keyed by
+// their path relative to the registry root, and are in the package's
+// directory, which is named after the package.
+func packageDigest(name string, files map[string][]byte) string {
+	tree := make(map[string][]byte)
+	for p, data := range files {
+		tree[strings.TrimPrefix(filepath.ToSlash(p), name+"/")] = data
+	}
+
+	return digestFiles(tree)
+}
+
+// verifyLibrary verifies the signature of registry.yaml and the digest it
+// lists for a package resolved from a registry. files are the package's files
+// keyed by their path relative to the registry root. version is the version
+// of the registry the package was resolved at.
+func verifyLibrary(r Registry, keys []ed25519.PublicKey, name, version string, files map[string][]byte) error {
+	ff, ok := r.(FileFetcher)
+	if !ok {
+		return errors.Errorf("registry %q does not support signatures", r.Name())
+	}
+
+	registryData, err := ff.FetchFile(registryYAMLFile, version)
+	if err != nil {
+		return errors.Wrapf(err, "fetching %s of registry %q", registryYAMLFile, r.Name())
+	}
+	registrySig, err := ff.FetchFile(registrySignatureFile, version)
+	if err != nil {
+		return errors.Errorf("registry %q is not signed", r.Name())
+	}
+	if err = verifySignature(keys, registryData, registrySig); err != nil {
+		return errors.Wrapf(err, "verifying %s of registry %q", registryYAMLFile, r.Name())
+	}
+
+	registrySpec, err := Unmarshal(registryData)
+	if err != nil {
+		return err
+	}
+	libRef, ok := registrySpec.Libraries[name]
+	if !ok || libRef == nil {
+		return errors.Errorf("package %q is not listed in the signed %s of registry %q", name, registryYAMLFile, r.Name())
+	}
+	if libRef.Digest == "" {
+		return errors.Errorf("package %s/%s is not signed", r.Name(), name)
+	}
+	if packageDigest(name, files) != libRef.Digest {
+		return errors.Errorf("package %s/%s does not match the digest in the signed %s", r.Name(), name, registryYAMLFile)
+	}
+
+	return nil
+}
+
+// Sign records the digests of the packages of a registry in a local
+// directory in its registry.yaml, and signs the registry.yaml with a base64
+// encoded ed25519 private key. It returns the paths of the files it wrote.
+func Sign(fs afero.Fs, dir, privateKey string) ([]string, error) {
+	key, err := decodePrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	registryPath := filepath.Join(dir, registryYAMLFile)
+	registryData, err := afero.ReadFile(fs, registryPath)
+	if err != nil {
+		return nil, errors.Wrap(err, "reading registry spec")
+	}
+
+	registrySpec, err := Unmarshal(registryData)
+	if err != nil {
+		return nil, err
+	}
+
+	for name, libRef := range registrySpec.Libraries {
+		if libRef == nil {
+			libRef = &LibraryConfig{}
+			registrySpec.Libraries[name] = libRef
+		}
+
+		// Packages are always resolved from the directory named after them.
+		digest, err := Digest(fs, filepath.Join(dir, name))
+		if err != nil {
+			return nil, errors.Wrapf(err, "computing digest of package %s", name)
+		}
+		libRef.Digest = digest
+	}
+
+	registryData, err = registrySpec.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	if err = afero.WriteFile(fs, registryPath, registryData, app.DefaultFilePermissions); err != nil {
+		return nil, err
+	}
+
+	sigPath := filepath.Join(dir, registrySignatureFile)
+	if err = afero.WriteFile(fs, sigPath, sign(key, registryData), app.DefaultFilePermissions); err != nil {
+		return nil, err
+	}
+
+	return []string{registryPath, sigPath}, nil
+}
@@ -0,0 +1,190 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package registry
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/ksonnet/ksonnet/pkg/app"
+	amocks "github.com/ksonnet/ksonnet/pkg/app/mocks"
+	"github.com/ksonnet/ksonnet/pkg/pkg"
+	"github.com/ksonnet/ksonnet/pkg/util/test"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withSignedRegistry(t *testing.T, fn func(a *amocks.App, fs afero.Fs, publicKey, privateKey string)) {
+	withApp(t, func(a *amocks.App, fs afero.Fs) {
+		a.On("VendorPath").Return("/app/vendor")
+		a.On("Libraries").Return(app.LibraryConfigs{}, nil)
+		a.On("Environments").Return(app.EnvironmentConfigs{}, nil)
+
+		test.StageDir(t, fs, "incubator", filepath.Join("/work", "incubator"))
+
+		publicKey, privateKey, err := GenerateKey()
+		require.NoError(t, err)
+
+		written, err := Sign(fs, "/work/incubator", privateKey)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"/work/incubator/registry.yaml", "/work/incubator/registry.yaml.sig"}, written)
+
+		fn(a, fs, publicKey, privateKey)
+	})
+}
+
+func signedRegistries(keys ...string) app.RegistryConfigs {
+	return app.RegistryConfigs{
+		"incubator": &app.RegistryConfig{
+			Name:       "incubator",
+			Protocol:   string(ProtocolFilesystem),
+			URI:        "/work/incubator",
+			PublicKeys: keys,
+		},
+	}
+}
+
+func Test_CacheDependency_signed(t *testing.T) {
+	withSignedRegistry(t, func(a *amocks.App, fs afero.Fs, publicKey, _ string) {
+		a.On("Registries").Return(signedRegistries(publicKey), nil)
+
+		var checker installedChecker
+		d := pkg.Descriptor{Registry: "incubator", Name: "apache"}
+
+		_, err := CacheDependency(a, &checker, d, "", "", false, nil)
+		require.NoError(t, err)
+
+		test.AssertExists(t, fs, "/app/vendor/incubator/apache/parts.yaml")
+	})
+}
+
+func Test_CacheDependency_signature_failures(t *testing.T) {
+	otherKey, _, err := GenerateKey()
+	require.NoError(t, err)
+
+	cases := []struct {
+		name   string
+		key    func(publicKey string) string
+		modify func(t *testing.T, fs afero.Fs, privateKey string)
+	}{
+		{
+			name: "modified package",
+			modify: func(t *testing.T, fs afero.Fs, _ string) {
+				err := afero.WriteFile(fs, "/work/incubator/apache/README.md", []byte("changed"), 0644)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "added file",
+			modify: func(t *testing.T, fs afero.Fs, _ string) {
+				err := afero.WriteFile(fs, "/work/incubator/apache/extra.libsonnet", []byte("{}"), 0644)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "unsigned package",
+			modify: func(t *testing.T, fs afero.Fs, privateKey string) {
+				data := []byte("apiVersion: '0.1'\nkind: ksonnet.io/registry\nlibraries:\n  apache:\n    path: apache\n")
+				key, err := decodePrivateKey(privateKey)
+				require.NoError(t, err)
+				require.NoError(t, afero.WriteFile(fs, "/work/incubator/registry.yaml", data, 0644))
+				require.NoError(t, afero.WriteFile(fs, "/work/incubator/registry.yaml.sig", sign(key, data), 0644))
+			},
+		},
+		{
+			name: "package moved from another directory",
+			modify: func(t *testing.T, fs afero.Fs, _ string) {
+				require.NoError(t, fs.RemoveAll("/work/incubator/apache"))
+				test.StageDir(t, fs, filepath.Join("incubator", "nginx"), "/work/incubator/apache")
+			},
+		},
+		{
+			name: "previously signed package",
+			modify: func(t *testing.T, fs afero.Fs, privateKey string) {
+				old, err := afero.ReadFile(fs, "/work/incubator/apache/README.md")
+				require.NoError(t, err)
+
+				err = afero.WriteFile(fs, "/work/incubator/apache/README.md", []byte("new release"), 0644)
+				require.NoError(t, err)
+				_, err = Sign(fs, "/work/incubator", privateKey)
+				require.NoError(t, err)
+
+				err = afero.WriteFile(fs, "/work/incubator/apache/README.md", old, 0644)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "modified registry",
+			modify: func(t *testing.T, fs afero.Fs, _ string) {
+				err := afero.WriteFile(fs, "/work/incubator/registry.yaml", []byte("apiVersion: '0.1'\nkind: ksonnet.io/registry\nlibraries:\n  apache:\n    path: apache\n"), 0644)
+				require.NoError(t, err)
+			},
+		},
+		{
+			name: "untrusted key",
+			key:  func(string) string { return otherKey },
+		},
+		{
+			name: "invalid key",
+			key:  func(string) string { return "invalid" },
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			withSignedRegistry(t, func(a *amocks.App, fs afero.Fs, publicKey, privateKey string) {
+				key := publicKey
+				if tc.key != nil {
+					key = tc.key(publicKey)
+				}
+				a.On("Registries").Return(signedRegistries(key), nil)
+
+				if tc.modify != nil {
+					tc.modify(t, fs, privateKey)
+				}
+
+				var checker installedChecker
+				d := pkg.Descriptor{Registry: "incubator", Name: "apache"}
+
+				_, err := CacheDependency(a, &checker, d, "", "", false, nil)
+				require.Error(t, err)
+
+				test.AssertNotExists(t, fs, "/app/vendor/incubator/apache")
+			})
+		})
+	}
+}
+
+func Test_verifyLibrary_unsupported(t *testing.T) {
+	withApp(t, func(a *amocks.App, fs afero.Fs) {
+		spec := &app.RegistryConfig{
+			Name:     "charts",
+			Protocol: string(ProtocolHelm),
+			URI:      "https://charts.example.com",
+		}
+		h, err := NewHelm(a, spec, &fakeHelmRepositoryClient{}, nil)
+		require.NoError(t, err)
+
+		publicKey, _, err := GenerateKey()
+		require.NoError(t, err)
+		keys, err := PublicKeys(&app.RegistryConfig{PublicKeys: []string{publicKey}})
+		require.NoError(t, err)
+
+		err = verifyLibrary(h, keys, "redis", "", map[string][]byte{})
+		require.Error(t, err)
+	})
+}
@@ -27,6 +27,11 @@ type File struct {
 // returns an error if there is one.
 type FileHandler func(*File) error
 
+// Archiver archives files and writes the archive to a writer.
+type Archiver interface {
+	Archive(io.Writer, []*File) error
+}
+
 // Unarchiver unarchives a reader and processes files using the
 // FileHandler.
 type Unarchiver interface {
@@ -20,6 +20,7 @@ import (
 	"compress/gzip"
 	"errors"
 	"io"
+	"io/ioutil"
 )
 
 // Tgz handles gzip'd tar archives.
@@ -53,7 +54,7 @@ func (t *Tgz) Unarchive(r io.Reader, handler FileHandler) error {
 		name := header.Name
 
 		switch header.Typeflag {
-		case 0:
+		case tar.TypeReg, tar.TypeRegA:
 			tf := &File{
 				Name:   name,
 				Reader: tarReader,
@@ -66,3 +67,41 @@ func (t *Tgz) Unarchive(r io.Reader, handler FileHandler) error {
 	}
 	return nil
 }
+
+// Archive tars and gzips files, and writes the archive to w.
+func (t *Tgz) Archive(w io.Writer, files []*File) error {
+	if w == nil {
+		return errors.New("gzip writer is nil")
+	}
+
+	gzWriter := gzip.NewWriter(w)
+	tarWriter := tar.NewWriter(gzWriter)
+
+	for _, f := range files {
+		b, err := ioutil.ReadAll(f.Reader)
+		if err != nil {
+			return err
+		}
+
+		header := &tar.Header{
+			Name:     f.Name,
+			Mode:     0644,
+			Size:     int64(len(b)),
+			Typeflag: tar.TypeReg,
+		}
+
+		if err := tarWriter.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if _, err := tarWriter.Write(b); err != nil {
+			return err
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		return err
+	}
+
+	return gzWriter.Close()
+}
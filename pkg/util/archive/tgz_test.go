@@ -16,7 +16,9 @@
 package archive
 
 import (
+	"bytes"
 	"errors"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"strings"
@@ -107,3 +109,34 @@ func Test_UnTgz_handler_failed(t *testing.T) {
 	err = tgz.Unarchive(f, handler)
 	require.Error(t, err)
 }
+
+func Test_Tgz_Archive(t *testing.T) {
+	files := []*File{
+		{Name: "registry.yaml", Reader: strings.NewReader("kind: ksonnet.io/registry\n")},
+		{Name: "nginx/parts.yaml", Reader: strings.NewReader("name: nginx\n")},
+	}
+
+	var buf bytes.Buffer
+	tgz := &Tgz{}
+	err := tgz.Archive(&buf, files)
+	require.NoError(t, err)
+
+	contents := make(map[string]string)
+	handler := func(tf *File) error {
+		b, err := ioutil.ReadAll(tf.Reader)
+		if err != nil {
+			return err
+		}
+		contents[tf.Name] = string(b)
+		return nil
+	}
+
+	err = tgz.Unarchive(&buf, handler)
+	require.NoError(t, err)
+
+	expected := map[string]string{
+		"registry.yaml":    "kind: ksonnet.io/registry\n",
+		"nginx/parts.yaml": "name: nginx\n",
+	}
+	require.Equal(t, expected, contents)
+}
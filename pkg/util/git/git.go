@@ -0,0 +1,72 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+// Package git runs the git command line client.
+package git
+
+import (
+	"bytes"
+	"os/exec"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Run runs git with args in dir and returns its standard output.
+func Run(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			msg = err.Error()
+		}
+		return "", errors.Errorf("git %s: %s", strings.Join(args, " "), msg)
+	}
+
+	return stdout.String(), nil
+}
+
+// Status is the state of a git working tree.
+type Status struct {
+	// Branch is the checked out branch. It is empty if HEAD is detached.
+	Branch string
+	// Clean is true if the working tree has no uncommitted changes.
+	Clean bool
+}
+
+// WorkTreeStatus returns the status of the working tree containing dir.
+func WorkTreeStatus(dir string) (Status, error) {
+	changes, err := Run(dir, "status", "--porcelain")
+	if err != nil {
+		return Status{}, err
+	}
+
+	// symbolic-ref fails if HEAD is detached.
+	branch, err := Run(dir, "symbolic-ref", "-q", "--short", "HEAD")
+	if err != nil {
+		branch = ""
+	}
+
+	return Status{
+		Branch: strings.TrimSpace(branch),
+		Clean:  strings.TrimSpace(changes) == "",
+	}, nil
+}
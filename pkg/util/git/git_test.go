@@ -0,0 +1,73 @@
+// Copyright 2018 The ksonnet authors
+//
+//
+//    Licensed under the Apache License, Version 2.0 (the "License");
+//    you may not use this file except in compliance with the License.
+//    You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+//    Unless required by applicable law or agreed to in writing, software
+//    distributed under the License is distributed on an "AS IS" BASIS,
+//    WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+//    See the License for the specific language governing permissions and
+//    limitations under the License.
+
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkTreeStatus(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "git")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	run := func(args ...string) {
+		_, err := Run(dir, args...)
+		require.NoError(t, err)
+	}
+
+	run("init", "-q")
+	run("checkout", "-q", "-b", "release")
+	run("config", "user.email", "ks@example.com")
+	run("config", "user.name", "ks")
+
+	err = ioutil.WriteFile(filepath.Join(dir, "app.yaml"), []byte("apiVersion: 0.2.0\n"), 0644)
+	require.NoError(t, err)
+
+	status, err := WorkTreeStatus(dir)
+	require.NoError(t, err)
+	require.Equal(t, Status{Branch: "release", Clean: false}, status)
+
+	run("add", "app.yaml")
+	run("commit", "-q", "-m", "initial")
+
+	status, err = WorkTreeStatus(dir)
+	require.NoError(t, err)
+	require.Equal(t, Status{Branch: "release", Clean: true}, status)
+}
+
+func TestWorkTreeStatus_not_a_repository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir, err := ioutil.TempDir("", "git")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	_, err = WorkTreeStatus(dir)
+	require.Error(t, err)
+}